@@ -40,6 +40,7 @@ func init() {
 	configCmd.AddCommand(configValidateCmd)
 	configCmd.AddCommand(configShowCmd)
 	configCmd.AddCommand(configInitCmd)
+	configCmd.AddCommand(configEnvCmd)
 
 	// Config init flags
 	configInitCmd.Flags().StringVarP(&flagOutput, "output", "o", "", "Output path (default: ~/.agentcomms/config.json)")
@@ -193,6 +194,18 @@ Examples:
 	},
 }
 
+var configEnvCmd = &cobra.Command{
+	Use:   "env",
+	Short: "Show the effective environment configuration",
+	Long: `Shows the configuration the serve command would use, built from defaults
+and AGENTCOMMS_* environment variables. Secrets are masked. The SOURCE column
+shows which environment variable supplied each value, or "default".`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runConfigEnv()
+	},
+}
+
 // runSend sends a message to an agent.
 func runSend(agentID, message string) error {
 	client := daemon.DefaultClient()
@@ -415,6 +428,12 @@ func runValidateJSON(configPath string) error {
 
 	// Server info
 	fmt.Printf("Server port: %d\n", cfg.Server.Port)
+	if cfg.Server.BindAddr != "" {
+		fmt.Printf("Bind address: %s\n", cfg.Server.BindAddr)
+	}
+	if cfg.Server.Transport != "" {
+		fmt.Printf("Transport: %s\n", cfg.Server.Transport)
+	}
 
 	// Check agents
 	fmt.Printf("Agents: %d configured\n", len(cfg.Agents))
@@ -594,6 +613,27 @@ func runConfigShow() error {
 	return nil
 }
 
+// runConfigEnv prints the effective environment configuration with secrets masked.
+func runConfigEnv() error {
+	// Print the configuration even when invalid; that is when it is most useful
+	cfg, validateErr := config.LoadFromEnv()
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tVALUE\tSOURCE")
+	for _, s := range cfg.Redacted() {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", s.Name, s.Value, s.Source)
+	}
+	w.Flush()
+
+	if validateErr != nil {
+		fmt.Println("\nStatus: INVALID")
+		fmt.Printf("\nValidation error: %v\n", validateErr)
+		return nil
+	}
+	fmt.Println("\nStatus: VALID")
+	return nil
+}
+
 // checkTmuxSession checks if a tmux session exists.
 func checkTmuxSession(session string) bool {
 	cmd := exec.Command("tmux", "has-session", "-t", session) //nolint:gosec
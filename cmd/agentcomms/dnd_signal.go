@@ -0,0 +1,14 @@
+//go:build !windows
+
+package main
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// notifyDNDToggle relays SIGUSR1, which toggles do-not-disturb, to ch.
+func notifyDNDToggle(ch chan<- os.Signal) {
+	signal.Notify(ch, syscall.SIGUSR1)
+}
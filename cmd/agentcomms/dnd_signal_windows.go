@@ -0,0 +1,7 @@
+package main
+
+import "os"
+
+// notifyDNDToggle does nothing on Windows, which has no SIGUSR1; use the
+// /dnd endpoint instead.
+func notifyDNDToggle(chan<- os.Signal) {}
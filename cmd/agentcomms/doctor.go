@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/plexusone/agentcomms/internal/doctor"
+	"github.com/plexusone/agentcomms/internal/tunnel"
+	"github.com/plexusone/agentcomms/pkg/config"
+)
+
+// ngrokCheckTimeout bounds how long the doctor waits for an ngrok session.
+const ngrokCheckTimeout = 30 * time.Second
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check the services the MCP server depends on",
+	Long: `Checks the configuration the serve command would use and the external
+services it depends on:
+  - Configuration validity
+  - Outbound connectivity to the voice providers
+  - Phone provider credentials and that the phone number is on the account
+  - ElevenLabs, Deepgram, and OpenAI API keys, and that the voice exists
+  - The ngrok authtoken, or the cloudflared binary
+
+Each check prints a hint on how to fix it when it fails. Stop any running
+agentcomms server first: the ngrok check opens a tunnel session, which can
+conflict with one that is already open.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runDoctor()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+}
+
+// runDoctor runs the dependency checks and prints a checklist.
+func runDoctor() error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Check the configuration even when invalid; the doctor reports why
+	cfg, _ := config.LoadFromEnv()
+
+	fmt.Println("Checking agentcomms dependencies...")
+	fmt.Println()
+
+	results := doctor.New(cfg, ngrokStarter(cfg)).Run(ctx)
+	for _, r := range results {
+		line := fmt.Sprintf("[%s] %s", doctorMark(r.Status), r.Name)
+		if r.Detail != "" {
+			line += ": " + r.Detail
+		}
+		fmt.Println(line)
+		if r.Status == doctor.StatusFail && r.Hint != "" {
+			fmt.Printf("       -> %s\n", r.Hint)
+		}
+	}
+
+	if failed := doctor.Failed(results); failed > 0 {
+		fmt.Printf("\nStatus: %d of %d checks failed\n", failed, len(results))
+		return fmt.Errorf("%d checks failed", failed)
+	}
+	fmt.Println("\nStatus: OK")
+	return nil
+}
+
+func doctorMark(s doctor.Status) string {
+	switch s {
+	case doctor.StatusPass:
+		return " OK "
+	case doctor.StatusFail:
+		return "FAIL"
+	default:
+		return "SKIP"
+	}
+}
+
+// ngrokStarter returns a function that opens an ngrok tunnel the same way
+// serve does, and stops it once it is up.
+func ngrokStarter(cfg *config.Config) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		ctx, cancel := context.WithTimeout(ctx, ngrokCheckTimeout)
+		defer cancel()
+
+		ng, err := tunnel.NewNgrok(tunnel.NgrokConfig{
+			AuthToken: cfg.NgrokAuthToken,
+			Domain:    cfg.NgrokDomain,
+		})
+		if err != nil {
+			return err
+		}
+		// Nothing is served; the check only needs the session to open
+		if _, err := ng.Start(ctx, "http://127.0.0.1"); err != nil {
+			if ctx.Err() != nil {
+				return fmt.Errorf("ngrok did not start within %s", ngrokCheckTimeout)
+			}
+			return err
+		}
+		return ng.Stop()
+	}
+}
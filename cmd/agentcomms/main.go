@@ -21,6 +21,15 @@
 //	export AGENTCOMMS_USER_PHONE_NUMBER=+15559876543
 //	export NGROK_AUTHTOKEN=your_ngrok_token
 //
+//	# Or use a cloudflared tunnel instead of ngrok (no account needed)
+//	export AGENTCOMMS_TUNNEL=cloudflared
+//
+//	# Or skip tunneling on a host with its own public hostname
+//	export AGENTCOMMS_PUBLIC_URL=https://calls.example.com
+//
+//	# Serve MCP over stdio instead of HTTP (needs AGENTCOMMS_PUBLIC_URL for voice)
+//	export AGENTCOMMS_TRANSPORT=stdio
+//
 //	# Chat (optional)
 //	export AGENTCOMMS_DISCORD_ENABLED=true
 //	export AGENTCOMMS_DISCORD_TOKEN=your_discord_token
@@ -28,19 +37,26 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
-	"strings"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
 	"syscall"
+	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	mcpkit "github.com/plexusone/mcpkit/runtime"
 	"github.com/spf13/cobra"
 
 	"github.com/plexusone/agentcomms/internal/daemon"
+	"github.com/plexusone/agentcomms/internal/tunnel"
 	"github.com/plexusone/agentcomms/pkg/chat"
 	"github.com/plexusone/agentcomms/pkg/config"
 	"github.com/plexusone/agentcomms/pkg/tools"
@@ -162,6 +178,34 @@ func runServe() error {
 			return fmt.Errorf("failed to create voice manager: %w", err)
 		}
 		defer func() { _ = voiceManager.Close() }()
+
+		// Persist scheduled calls and the daily call budget across restarts
+		storePath := cfg.CallStorePath
+		if storePath == "" {
+			homeDir, err := os.UserHomeDir()
+			if err != nil {
+				return fmt.Errorf("failed to get home directory: %w", err)
+			}
+			storePath = filepath.Join(homeDir, ".agentcomms", "calls.json")
+		}
+		if err := voiceManager.SetStore(voice.NewFileCallStore(storePath)); err != nil {
+			return fmt.Errorf("failed to load call store: %w", err)
+		}
+
+		// SIGUSR1 toggles do-not-disturb without a restart
+		dndCh := make(chan os.Signal, 1)
+		notifyDNDToggle(dndCh)
+		defer signal.Stop(dndCh)
+		go func() {
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-dndCh:
+					voiceManager.ToggleDoNotDisturb()
+				}
+			}
+		}()
 	}
 
 	// Create chat manager if chat is enabled
@@ -180,50 +224,488 @@ func runServe() error {
 	}
 
 	// Register MCP tools
-	tools.RegisterTools(rt, voiceManager, chatManager)
+	if err := tools.RegisterTools(rt, voiceManager, chatManager, cfg.EnabledTools); err != nil {
+		return fmt.Errorf("failed to register tools: %w", err)
+	}
 
-	// Start HTTP server with ngrok for webhooks (required for voice)
-	httpOpts := &mcpkit.HTTPServerOptions{
-		Addr: fmt.Sprintf(":%d", cfg.Port),
-		Path: "/mcp",
+	// Keep the budget and quiet hours in tool descriptions current
+	if voiceManager != nil && cfg.DescriptionRefreshMS > 0 {
+		go refreshToolDescriptions(ctx, rt, voiceManager, cfg.EnabledTools, time.Duration(cfg.DescriptionRefreshMS)*time.Millisecond)
 	}
 
-	// Only set up ngrok if voice is enabled (needs webhooks)
-	if cfg.VoiceEnabled() && cfg.NgrokAuthToken != "" {
-		httpOpts.Ngrok = &mcpkit.NgrokOptions{
-			Authtoken: cfg.NgrokAuthToken,
-			Domain:    cfg.NgrokDomain,
+	// The public URL is known once the tunnel is up; the webhook routes
+	// read it on every request
+	var webhookURL atomic.Pointer[string]
+	currentURL := func() string {
+		if u := webhookURL.Load(); u != nil {
+			return *u
 		}
-		httpOpts.OnReady = func(result *mcpkit.HTTPServerResult) {
+		return ""
+	}
+
+	srvOpts := serverOptions{
+		addr:    cfg.ListenAddr(),
+		handler: newServeMux(cfg, rt, voiceManager, currentURL),
+	}
+
+	// Only set up a tunnel if voice is enabled (needs webhooks). Setup
+	// that fails in OnReady reports on fatalErrCh and cancels ctx.
+	fatalErrCh := make(chan error, 1)
+	if cfg.VoiceEnabled() {
+		// stopInit stops the running voice initialization, if any
+		stopInit := func() {}
+		defer func() { stopInit() }()
+
+		// tunnelReady is closed once the first public URL is known
+		tunnelReady := make(chan struct{})
+		var tunnelReadyOnce sync.Once
+
+		// onPublicURL initializes voice once the tunnel's public URL is known
+		onPublicURL := func(localURL, publicURL string) {
+			tunnelReadyOnce.Do(func() { close(tunnelReady) })
 			logger.Info("MCP server ready",
-				"local_url", result.LocalURL,
-				"public_url", result.PublicURL,
+				"local_url", localURL,
+				"public_url", publicURL,
 			)
+			if publicURL == currentURL() && voiceManager.InitError() == nil {
+				return // restarted on the same URL; voice is set up or starting
+			}
 
-			// Initialize voice manager with public URL
-			if voiceManager != nil {
-				if err := voiceManager.Initialize(result.PublicURL); err != nil {
-					logger.Warn("failed to initialize voice manager", "error", err)
+			// Initialize voice manager with public URL in the background,
+			// retrying provider failures, so the server is not held up by a
+			// provider outage. Without RequireProviders the server keeps
+			// serving chat and every call reports why voice is unavailable.
+			stopInit()
+			initCtx, stop := context.WithCancel(ctx)
+			done := make(chan struct{})
+			stopInit = func() {
+				stop()
+				<-done
+			}
+			go func() {
+				defer close(done)
+				if err := initVoice(initCtx, voiceManager, publicURL); err != nil && initCtx.Err() == nil {
+					if cfg.RequireProviders {
+						select {
+						case fatalErrCh <- fmt.Errorf("failed to initialize voice providers: %w", err):
+						default: // another setup failure is already being reported
+						}
+						cancel()
+						return
+					}
+					logger.Error("failed to initialize voice providers; calls will fail until the server restarts", "error", err)
 				}
+			}()
+			webhookURL.Store(&publicURL)
+			logger.Info("Twilio webhooks configured",
+				"voice_url", publicURL+voice.VoicePath,
+				"stream_url", publicURL+voice.MediaStreamPath,
+				"status_url", publicURL+voice.StatusPath,
+			)
+		}
 
-				// Set up webhook routes for Twilio
-				setupTwilioWebhooks(voiceManager, result.PublicURL)
+		switch {
+		case cfg.PublicURL != "":
+			// Static public URL: the host is reachable directly, no tunnel needed
+			srvOpts.onReady = func(localAddr string) {
+				onPublicURL("http://"+localAddr, cfg.PublicURL)
+			}
+		default:
+			tun, err := newTunnel(cfg)
+			if err != nil {
+				return err
+			}
+			defer func() { _ = tun.Stop() }()
+
+			// The tunnel survives server restarts on the same local port
+			srvOpts.onReady = func(localAddr string) {
+				localURL := "http://" + localAddr
+				publicURL := tun.URL()
+				if publicURL == "" {
+					var err error
+					publicURL, err = startTunnel(ctx, tun, cfg.Tunnel, localURL)
+					if err != nil {
+						select {
+						case fatalErrCh <- err:
+						default: // the ready timeout is already being reported
+						}
+						cancel()
+						return
+					}
+				}
+				onPublicURL(localURL, publicURL)
 			}
 		}
+
+		// A tunnel that never comes up would leave the server running with
+		// no way to place calls
+		if cfg.PublicURL == "" && cfg.TunnelReadyTimeoutMS > 0 {
+			timeout := time.Duration(cfg.TunnelReadyTimeoutMS) * time.Millisecond
+			go func() {
+				if err := waitTunnelReady(ctx, tunnelReady, cfg.Tunnel, timeout); err != nil {
+					select {
+					case fatalErrCh <- err:
+					default: // another setup failure is already being reported
+					}
+					cancel()
+				}
+			}()
+		}
 	} else {
-		httpOpts.OnReady = func(result *mcpkit.HTTPServerResult) {
+		srvOpts.onReady = func(localAddr string) {
 			logger.Info("MCP server ready (chat only)",
-				"local_url", result.LocalURL,
+				"local_url", "http://"+localAddr,
 			)
 		}
 	}
 
+	if cfg.Transport == config.TransportStdio {
+		return serveStdio(ctx, rt, srvOpts, fatalErrCh, cfg.VoiceEnabled())
+	}
+
 	// Run the MCP server (blocks until context cancelled)
-	_, err = rt.ServeHTTP(ctx, httpOpts)
+	return serveHTTP(ctx, srvOpts, fatalErrCh, restartPolicy{
+		attempts: cfg.ServeRestarts,
+		backoff:  time.Duration(cfg.ServeRestartBackoffMS) * time.Millisecond,
+	})
+}
+
+// Voice provider initialization retry settings.
+const (
+	initAttempts        = 5
+	initRetryBackoff    = 2 * time.Second
+	maxInitRetryBackoff = 30 * time.Second
+)
+
+// initVoice initializes the voice manager for publicURL, retrying failures
+// with exponential backoff since they are often transient, such as a TTS or
+// STT provider outage. It returns the last error once the attempts run out
+// or ctx is cancelled.
+func initVoice(ctx context.Context, m *voice.Manager, publicURL string) error {
+	backoff := initRetryBackoff
+	for attempt := 1; ; attempt++ {
+		err := m.Initialize(publicURL)
+		if err == nil {
+			if attempt > 1 {
+				logger.Info("voice providers initialized", "attempt", attempt)
+			}
+			return nil
+		}
+		if attempt >= initAttempts {
+			return err
+		}
+		logger.Warn("failed to initialize voice providers, retrying",
+			"attempt", attempt,
+			"error", err,
+			"retry_in", backoff,
+		)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, maxInitRetryBackoff)
+	}
+}
+
+// waitTunnelReady waits for ready to be closed and returns an error if it
+// is not within timeout. It returns nil if ctx is cancelled first, so a
+// shutdown signal ends the wait.
+func waitTunnelReady(ctx context.Context, ready <-chan struct{}, name string, timeout time.Duration) error {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-ready:
+		return nil
+	case <-ctx.Done():
+		return nil
+	case <-timer.C:
+		return fmt.Errorf("%s tunnel failed to become ready within %s; check the network or raise AGENTCOMMS_TUNNEL_READY_TIMEOUT_MS", name, timeout)
+	}
+}
+
+// newTunnel creates the tunnel selected by cfg.Tunnel.
+func newTunnel(cfg *config.Config) (tunnel.Tunnel, error) {
+	switch {
+	case cfg.Tunnel == config.TunnelCloudflared:
+		cf, err := tunnel.NewCloudflared(tunnel.CloudflaredConfig{
+			Path:     cfg.CloudflaredPath,
+			Token:    cfg.CloudflaredToken,
+			Hostname: cfg.CloudflaredHostname,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create cloudflared tunnel: %w", err)
+		}
+		return cf, nil
+	case cfg.NgrokAuthToken != "":
+		ng, err := tunnel.NewNgrok(tunnel.NgrokConfig{
+			AuthToken: cfg.NgrokAuthToken,
+			Domain:    cfg.NgrokDomain,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create ngrok tunnel: %w", err)
+		}
+		return ng, nil
+	default:
+		// Validate requires the token for ngrok; without any tunnel
+		// Twilio could never reach the webhooks
+		return nil, fmt.Errorf("voice calls need a public URL: set AGENTCOMMS_NGROK_AUTHTOKEN, AGENTCOMMS_TUNNEL=cloudflared, or AGENTCOMMS_PUBLIC_URL")
+	}
+}
+
+// startTunnel starts tun for localURL and returns its public URL.
+//
+// Startup failures the tunnel classifies, such as ngrok being unreachable,
+// are retried with exponential backoff, except for auth failures which are
+// reported immediately.
+func startTunnel(ctx context.Context, tun tunnel.Tunnel, name, localURL string) (string, error) {
+	backoff := tunnelRetryBackoff
+	for attempt := 1; ; attempt++ {
+		publicURL, err := tun.Start(ctx, localURL)
+		if err == nil {
+			return publicURL, nil
+		}
+
+		var startErr *tunnel.StartupError
+		if !errors.As(err, &startErr) {
+			return "", fmt.Errorf("failed to start %s tunnel: %w", name, err)
+		}
+		if !startErr.Retryable() || attempt >= tunnelStartAttempts {
+			return "", startErr
+		}
+		logger.Warn("tunnel failed to start, retrying",
+			"tunnel", name,
+			"attempt", attempt,
+			"reason", startErr.Hint(),
+			"error", err,
+			"retry_in", backoff,
+		)
+		select {
+		case <-ctx.Done():
+			return "", startErr
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// healthPath serves healthHandler.
+const healthPath = "/healthz"
+
+// healthResponse is the JSON body served at healthPath.
+type healthResponse struct {
+	Status string `json:"status"`
+	DND    bool   `json:"dnd"` // do-not-disturb is on and no calls are placed
+}
+
+// refreshToolDescriptions re-registers the voice tools every interval until
+// ctx is done, so their descriptions show the current call constraints.
+func refreshToolDescriptions(ctx context.Context, rt *mcpkit.Runtime, voiceManager *voice.Manager, enabled []string, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := tools.RefreshToolDescriptions(rt, voiceManager, enabled); err != nil {
+				logger.Warn("failed to refresh tool descriptions", "error", err)
+			}
+		}
+	}
+}
+
+// healthHandler reports that the server is up and whether do-not-disturb
+// is on. voiceManager is nil when voice is disabled.
+func healthHandler(voiceManager *voice.Manager) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(healthResponse{
+			Status: "ok",
+			DND:    voiceManager != nil && voiceManager.DoNotDisturb(),
+		})
+	})
+}
+
+// Tunnel startup retry settings.
+const (
+	tunnelStartAttempts = 3
+	tunnelRetryBackoff  = 2 * time.Second
+)
+
+// Server restart settings.
+const (
+	// maxRestartBackoff caps the doubling wait between restarts.
+	maxRestartBackoff = time.Minute
+
+	// restartResetAfter is how long a restarted server must run before
+	// its next failure counts as the first again.
+	restartResetAfter = 5 * time.Minute
+)
+
+// restartPolicy bounds how often serveHTTP restarts a server that failed
+// after it was ready.
+type restartPolicy struct {
+	attempts int           // restarts allowed in a row; 0 exits on the first failure
+	backoff  time.Duration // wait before the first restart, doubled after each
+}
+
+// mcpPath serves MCP over streamable HTTP.
+const mcpPath = "/mcp"
+
+// serverOptions configures the HTTP server.
+type serverOptions struct {
+	addr    string       // listen address
+	handler http.Handler // routes served on addr
+
+	// onReady, if set, is called with the listener's address once it is
+	// open, before requests are served.
+	onReady func(localAddr string)
+}
+
+// listenAndServe serves opts.handler on opts.addr until ctx is cancelled,
+// which shuts the server down gracefully, or the server fails.
+func listenAndServe(ctx context.Context, opts serverOptions) error {
+	ln, err := net.Listen("tcp", opts.addr)
+	if err != nil {
+		return fmt.Errorf("listening on %s: %w", opts.addr, err)
+	}
+	srv := &http.Server{
+		Handler:           opts.handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	stop := context.AfterFunc(ctx, func() {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+	})
+	defer stop()
+
+	if opts.onReady != nil {
+		opts.onReady(ln.Addr().String())
+	}
+	if err := srv.Serve(ln); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// serveHTTP runs the MCP HTTP server until ctx is cancelled.
+//
+// Startup failures, such as the port being in use, are configuration
+// errors and are returned as-is. A server that fails after it was ready is
+// restarted according to restarts. Shutdown signals
+// cancel ctx and stop the loop at any point; an error on fatalErrCh is
+// returned instead.
+func serveHTTP(ctx context.Context, opts serverOptions, fatalErrCh <-chan error, restarts restartPolicy) error {
+	// Track readiness so startup failures can be told apart from later errors
+	var ready atomic.Bool
+	onReady := opts.onReady
+	opts.onReady = func(localAddr string) {
+		ready.Store(true)
+		if onReady != nil {
+			onReady(localAddr)
+		}
+	}
+
+	// wait sleeps for d, returning false if ctx is cancelled first.
+	wait := func(d time.Duration) bool {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(d):
+			return true
+		}
+	}
+
+	// stopped returns the error that cancelled ctx, if one was reported.
+	stopped := func() error {
+		select {
+		case fatalErr := <-fatalErrCh:
+			return fatalErr
+		default:
+			return nil
+		}
+	}
+
+	restartAttempt, restartBackoff := 0, restarts.backoff
+	for {
+		ready.Store(false)
+		started := time.Now()
+		err := listenAndServe(ctx, opts)
+		if fatalErr := stopped(); fatalErr != nil {
+			return fatalErr
+		}
+		if err == nil || ctx.Err() != nil {
+			return nil
+		}
+
+		if !ready.Load() {
+			return fmt.Errorf("server error: %w", err)
+		}
+
+		// The server was up, so the failure is likely transient
+		if time.Since(started) >= restartResetAfter {
+			restartAttempt, restartBackoff = 0, restarts.backoff
+		}
+		restartAttempt++
+		if restartAttempt > restarts.attempts {
+			return fmt.Errorf("server error: %w", err)
+		}
+		logger.Warn("server stopped unexpectedly, restarting",
+			"attempt", restartAttempt,
+			"max_attempts", restarts.attempts,
+			"error", err,
+			"retry_in", restartBackoff,
+		)
+		if !wait(restartBackoff) {
+			return stopped()
+		}
+		restartBackoff = min(restartBackoff*2, maxRestartBackoff)
+	}
+}
+
+// serveStdio runs the MCP server over stdin and stdout until the client
+// disconnects or ctx is cancelled. With voice enabled, the webhooks and
+// health check are still served over HTTP on opts.addr, without MCP or a
+// tunnel; Validate requires a public URL that reaches that address.
+func serveStdio(ctx context.Context, rt *mcpkit.Runtime, opts serverOptions, fatalErrCh <-chan error, webhooks bool) error {
+	if webhooks {
+		ln, err := net.Listen("tcp", opts.addr)
+		if err != nil {
+			return fmt.Errorf("failed to listen for webhooks: %w", err)
+		}
+		srv := &http.Server{
+			Handler:           opts.handler,
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+		serveErr := make(chan error, 1)
+		go func() { serveErr <- srv.Serve(ln) }()
+		defer func() {
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			_ = srv.Shutdown(shutdownCtx)
+		}()
+		go func() {
+			if err := <-serveErr; err != nil && !errors.Is(err, http.ErrServerClosed) {
+				logger.Error("webhook server stopped", "error", err)
+			}
+		}()
+
+		if opts.onReady != nil {
+			opts.onReady(ln.Addr().String())
+		}
+	}
+
+	err := rt.ServeStdio(ctx)
+	select {
+	case fatalErr := <-fatalErrCh:
+		return fatalErr
+	default:
+	}
 	if err != nil && ctx.Err() == nil {
 		return fmt.Errorf("server error: %w", err)
 	}
-
 	return nil
 }
 
@@ -251,58 +733,50 @@ func runDaemon() error {
 	return d.Start(ctx)
 }
 
-// setupTwilioWebhooks sets up HTTP handlers for Twilio webhooks.
-func setupTwilioWebhooks(manager *voice.Manager, publicURL string) {
-	twilioTransport := manager.Transport()
-	if twilioTransport == nil {
-		logger.Warn("transport not available for webhook setup")
-		return
+// newServeMux returns the routes of the HTTP server: MCP unless it runs
+// over stdio, the health check, and the voice routes when voiceManager is
+// non-nil. publicURL returns the current public base URL.
+func newServeMux(cfg *config.Config, rt *mcpkit.Runtime, voiceManager *voice.Manager, publicURL func() string) *http.ServeMux {
+	mux := http.NewServeMux()
+	if cfg.Transport != config.TransportStdio {
+		mux.Handle(mcpPath, rt.StreamableHTTPHandler(nil))
 	}
+	if voiceManager != nil {
+		registerVoiceRoutes(mux, cfg, voiceManager, publicURL)
+	}
+	mux.Handle(healthPath, voice.AllowOrigins(healthHandler(voiceManager), cfg.CORSOrigins))
+	return mux
+}
 
-	// Handle Twilio Media Streams WebSocket connections
-	http.HandleFunc("/media-stream", func(w http.ResponseWriter, r *http.Request) {
-		if err := twilioTransport.HandleWebSocket(w, r, "/media-stream"); err != nil {
+// registerVoiceRoutes registers the Twilio webhooks and, with a trigger
+// token, the trigger and do-not-disturb endpoints on mux. publicURL returns
+// the current public base URL, which is empty until the tunnel is up.
+func registerVoiceRoutes(mux *http.ServeMux, cfg *config.Config, manager *voice.Manager, publicURL func() string) {
+	// Handle Twilio Media Streams WebSocket connections. The transport is
+	// looked up per request since it is set up once the public URL is
+	// known, and a server restart may re-initialize it.
+	mux.HandleFunc(voice.MediaStreamPath, func(w http.ResponseWriter, r *http.Request) {
+		twilioTransport := manager.Transport()
+		if twilioTransport == nil {
+			http.Error(w, "Voice not initialized", http.StatusServiceUnavailable)
+			return
+		}
+		if err := twilioTransport.HandleWebSocket(w, r, voice.MediaStreamPath); err != nil {
 			logger.Error("WebSocket error", "error", err)
 			http.Error(w, "WebSocket error", http.StatusInternalServerError)
 		}
 	})
 
-	// Handle Twilio voice webhook (for incoming calls)
-	http.HandleFunc("/voice", func(w http.ResponseWriter, r *http.Request) {
-		// Return TwiML to connect to Media Streams
-		w.Header().Set("Content-Type", "application/xml")
-		_, _ = fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?>
-<Response>
-    <Connect>
-        <Stream url="%s/media-stream">
-            <Parameter name="direction" value="both"/>
-        </Stream>
-    </Connect>
-</Response>`, publicURL)
-	})
+	// Handle Twilio voice webhook for incoming calls and the calls we dial
+	mux.Handle(voice.VoicePath, manager.VoiceHandler(publicURL, cfg.RequireAccept))
 
 	// Handle Twilio status callbacks
-	http.HandleFunc("/status", func(w http.ResponseWriter, r *http.Request) {
-		// Limit body and parse status callback (G120)
-		r.Body = http.MaxBytesReader(w, r.Body, 1<<20)
-		if err := r.ParseForm(); err != nil {
-			http.Error(w, "Bad request", http.StatusBadRequest)
-			return
-		}
-		// Log status update (use Form.Get after ParseForm)
-		callSID := r.Form.Get("CallSid")
-		callSID = strings.ReplaceAll(callSID, "\n", "")
-		callSID = strings.ReplaceAll(callSID, "\r", "")
-		callStatus := r.Form.Get("CallStatus")
-		callStatus = strings.ReplaceAll(callStatus, "\n", "")
-		callStatus = strings.ReplaceAll(callStatus, "\r", "")
-		logger.Info("call status update", "call_sid", callSID, "status", callStatus)
-		w.WriteHeader(http.StatusOK)
-	})
+	mux.Handle(voice.StatusPath, manager.StatusHandler())
 
-	logger.Info("Twilio webhooks configured",
-		"voice_url", publicURL+"/voice",
-		"stream_url", publicURL+"/media-stream",
-		"status_url", publicURL+"/status",
-	)
+	// External systems such as CI place calls through the trigger
+	// endpoint; it only needs the manager, not the public URL
+	if cfg.TriggerToken != "" {
+		mux.Handle(voice.TriggerCallPath, voice.AllowOrigins(manager.TriggerCallHandler(cfg.TriggerToken), cfg.CORSOrigins))
+		mux.Handle(voice.DNDPath, voice.AllowOrigins(manager.DNDHandler(cfg.TriggerToken), cfg.CORSOrigins))
+	}
 }
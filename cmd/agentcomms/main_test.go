@@ -0,0 +1,192 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	mcpkit "github.com/plexusone/mcpkit/runtime"
+
+	"github.com/plexusone/agentcomms/pkg/config"
+	"github.com/plexusone/agentcomms/pkg/voice"
+)
+
+// startTestServer serves the routes runServe uses on a free local port, the
+// same way serveHTTP does, and returns the server's base URL.
+func startTestServer(t *testing.T, cfg *config.Config) string {
+	t.Helper()
+
+	m, err := voice.New(cfg)
+	if err != nil {
+		t.Fatalf("voice.New() error = %v", err)
+	}
+	t.Cleanup(func() { _ = m.Close() })
+
+	rt := mcpkit.New(&mcp.Implementation{Name: "agentcomms-test", Version: version}, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	ready := make(chan string, 1)
+	done := make(chan error, 1)
+	go func() {
+		done <- listenAndServe(ctx, serverOptions{
+			addr:    "127.0.0.1:0",
+			handler: newServeMux(cfg, rt, m, func() string { return "" }),
+			onReady: func(localAddr string) { ready <- localAddr },
+		})
+	}()
+	t.Cleanup(func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("listenAndServe() error = %v", err)
+		}
+	})
+
+	select {
+	case localAddr := <-ready:
+		return "http://" + localAddr
+	case err := <-done:
+		t.Fatalf("listenAndServe() error = %v", err)
+		return ""
+	}
+}
+
+func testServeConfig() *config.Config {
+	cfg := config.DefaultConfig()
+	cfg.TriggerToken = "secret"
+	return cfg
+}
+
+func TestServer_TriggerCall(t *testing.T) {
+	baseURL := startTestServer(t, testServeConfig())
+
+	tests := []struct {
+		name  string
+		token string
+		body  string
+		want  int
+	}{
+		{name: "no token", body: `{"message":"hi"}`, want: http.StatusUnauthorized},
+		{name: "no message", token: "secret", body: `{}`, want: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPost, baseURL+voice.TriggerCallPath, strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("POST %s error = %v", voice.TriggerCallPath, err)
+			}
+			_ = resp.Body.Close()
+			if resp.StatusCode != tt.want {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.want)
+			}
+		})
+	}
+}
+
+func TestServer_NoTriggerToken(t *testing.T) {
+	cfg := testServeConfig()
+	cfg.TriggerToken = ""
+	baseURL := startTestServer(t, cfg)
+
+	resp, err := http.Post(baseURL+voice.TriggerCallPath, "application/json", strings.NewReader(`{"message":"hi"}`))
+	if err != nil {
+		t.Fatalf("POST %s error = %v", voice.TriggerCallPath, err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("status = %d, want %d without a trigger token", resp.StatusCode, http.StatusNotFound)
+	}
+}
+
+func TestServer_DND(t *testing.T) {
+	baseURL := startTestServer(t, testServeConfig())
+
+	req, err := http.NewRequest(http.MethodPost, baseURL+voice.DNDPath, strings.NewReader(`{"enabled":true}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer secret")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("POST %s error = %v", voice.DNDPath, err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("POST %s status = %d, want %d", voice.DNDPath, resp.StatusCode, http.StatusOK)
+	}
+
+	// The health check on the same server reports the new state
+	resp, err = http.Get(baseURL + healthPath)
+	if err != nil {
+		t.Fatalf("GET %s error = %v", healthPath, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	var health healthResponse
+	if err := json.NewDecoder(resp.Body).Decode(&health); err != nil {
+		t.Fatalf("decoding health response: %v", err)
+	}
+	if !health.DND {
+		t.Error("health dnd = false after enabling do-not-disturb, want true")
+	}
+
+	resp, err = http.Get(baseURL + voice.DNDPath)
+	if err != nil {
+		t.Fatalf("GET %s error = %v", voice.DNDPath, err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("GET %s without token status = %d, want %d", voice.DNDPath, resp.StatusCode, http.StatusUnauthorized)
+	}
+}
+
+func TestServer_CORSPreflight(t *testing.T) {
+	cfg := testServeConfig()
+	cfg.CORSOrigins = []string{"https://dashboard.example.com"}
+	baseURL := startTestServer(t, cfg)
+
+	for _, path := range []string{voice.TriggerCallPath, voice.DNDPath, healthPath} {
+		t.Run(path, func(t *testing.T) {
+			tests := []struct {
+				origin    string
+				wantCode  int
+				wantAllow string
+			}{
+				{origin: "https://dashboard.example.com", wantCode: http.StatusNoContent, wantAllow: "https://dashboard.example.com"},
+				{origin: "https://evil.example.com", wantAllow: ""},
+			}
+			for _, tt := range tests {
+				req, err := http.NewRequest(http.MethodOptions, baseURL+path, nil)
+				if err != nil {
+					t.Fatal(err)
+				}
+				req.Header.Set("Origin", tt.origin)
+				req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+				req.Header.Set("Access-Control-Request-Headers", "authorization, content-type")
+				resp, err := http.DefaultClient.Do(req)
+				if err != nil {
+					t.Fatalf("OPTIONS %s error = %v", path, err)
+				}
+				_ = resp.Body.Close()
+
+				if got := resp.Header.Get("Access-Control-Allow-Origin"); got != tt.wantAllow {
+					t.Errorf("origin %s: Access-Control-Allow-Origin = %q, want %q", tt.origin, got, tt.wantAllow)
+				}
+				if tt.wantCode != 0 && resp.StatusCode != tt.wantCode {
+					t.Errorf("origin %s: status = %d, want %d", tt.origin, resp.StatusCode, tt.wantCode)
+				}
+				if tt.wantAllow != "" && !strings.Contains(resp.Header.Get("Access-Control-Allow-Headers"), "Authorization") {
+					t.Errorf("origin %s: Access-Control-Allow-Headers = %q, want it to allow Authorization",
+						tt.origin, resp.Header.Get("Access-Control-Allow-Headers"))
+				}
+			}
+		})
+	}
+}
@@ -21,35 +21,77 @@
 //
 //	# Generate to a specific directory
 //	go run ./cmd/generate-plugin claude ./output
+//
+//	# Run the MCP server from an installed binary
+//	go run ./cmd/generate-plugin -command "$(go env GOPATH)/bin/agentcomms" claude
+//
+//	# Check the generated Claude Code plugin with the marketplace validator
+//	go run ./cmd/generate-plugin -validate claude ./output
+//
+//	# Leave out the notification hook and reword the stop hook
+//	go run ./cmd/generate-plugin -notification-hook=false -stop-prompt @stop-prompt.md claude
+//
+// Flags go before the tool and directory arguments.
 package main
 
 import (
+	"flag"
+	"fmt"
 	"log"
 	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
 
 	"github.com/plexusone/assistantkit/bundle"
 	"github.com/plexusone/assistantkit/hooks/core"
+	"github.com/plexusone/assistantkit/publish/claude"
 )
 
+// defaultCommand runs the server binary from the generated plugin directory.
+const defaultCommand = "./agentcomms"
+
 func main() {
+	// Parse flags
+	command := flag.String("command", defaultServerCommand(), "MCP server command: an absolute path, a name on PATH, or a path relative to the plugin (env AGENTCOMMS_BINARY_PATH)")
+	validate := flag.Bool("validate", false, "Validate the generated Claude Code plugin (claude target only)")
+	hooks := defaultHookOptions()
+	flag.BoolVar(&hooks.stop, "stop-hook", hooks.stop, "Generate the OnStop hook")
+	flag.BoolVar(&hooks.notification, "notification-hook", hooks.notification, "Generate the OnNotification hook")
+	stopPrompt := flag.String("stop-prompt", "", "OnStop hook prompt, or @file to read it from a file")
+	notificationPrompt := flag.String("notification-prompt", "", "OnNotification hook prompt, or @file to read it from a file")
+	flag.Parse()
+
+	if err := validateCommand(*command); err != nil {
+		log.Fatalf("Invalid -command: %v", err)
+	}
+
+	var err error
+	if hooks.stopPrompt, err = promptFlag(*stopPrompt, hooks.stopPrompt); err != nil {
+		log.Fatalf("Invalid -stop-prompt: %v", err)
+	}
+	if hooks.notificationPrompt, err = promptFlag(*notificationPrompt, hooks.notificationPrompt); err != nil {
+		log.Fatalf("Invalid -notification-prompt: %v", err)
+	}
+
 	// Parse arguments
 	tool := "claude"
 	outputDir := "."
 
-	if len(os.Args) > 1 {
-		tool = os.Args[1]
+	if flag.NArg() > 0 {
+		tool = flag.Arg(0)
 	}
-	if len(os.Args) > 2 {
-		outputDir = os.Args[2]
+	if flag.NArg() > 1 {
+		outputDir = flag.Arg(1)
 	}
 
 	// Create the bundle
-	b := createBundle()
+	b := createBundle(*command, hooks)
 
 	// Generate
 	log.Printf("Generating %s integration files to %s...\n", tool, outputDir) //nolint:gosec // G706: Values from validated CLI args
 
-	var err error
 	if tool == "all" {
 		err = b.GenerateAll(outputDir)
 	} else {
@@ -61,10 +103,82 @@ func main() {
 	}
 
 	log.Printf("Integration files generated successfully!")
+
+	if *validate {
+		if tool != "claude" {
+			log.Printf("Skipping validation: only the claude target can be validated")
+			return
+		}
+		// Validation is local; no GitHub token is needed
+		if err := claude.NewPublisher("").Validate(outputDir); err != nil {
+			log.Fatalf("Validation failed: %v", err)
+		}
+		log.Printf("Validation passed!")
+	}
+}
+
+// defaultServerCommand returns the MCP server command from the environment,
+// or defaultCommand.
+func defaultServerCommand() string {
+	for _, key := range []string{"AGENTCOMMS_BINARY_PATH", "AGENTCALL_BINARY_PATH"} {
+		if v := os.Getenv(key); v != "" {
+			return v
+		}
+	}
+	return defaultCommand
+}
+
+// validateCommand checks that the MCP server command can be run. Absolute
+// paths must be executable files and bare names must be on PATH. Paths
+// relative to the plugin, and paths using variables the assistant expands,
+// are resolved at run time and are left as given.
+func validateCommand(command string) error {
+	switch {
+	case strings.TrimSpace(command) == "":
+		return fmt.Errorf("command is empty")
+	case strings.Contains(command, "$"):
+		return nil
+	case filepath.IsAbs(command):
+		info, err := os.Stat(command)
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() || (runtime.GOOS != "windows" && info.Mode().Perm()&0o111 == 0) {
+			return fmt.Errorf("%s is not an executable file", command)
+		}
+		return nil
+	case !strings.ContainsRune(command, '/') && !strings.ContainsRune(command, filepath.Separator):
+		if _, err := exec.LookPath(command); err != nil {
+			return fmt.Errorf("%s not found on PATH", command)
+		}
+		return nil
+	default:
+		return nil
+	}
+}
+
+// promptFlag returns a hook prompt given on the command line: the flag's
+// text, the contents of the file named after an "@", or def if unset.
+func promptFlag(value, def string) (string, error) {
+	switch {
+	case value == "":
+		return def, nil
+	case strings.HasPrefix(value, "@"):
+		data, err := os.ReadFile(value[1:])
+		if err != nil {
+			return "", err
+		}
+		value = string(data)
+	}
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", fmt.Errorf("prompt is empty")
+	}
+	return value, nil
 }
 
 // createBundle builds the agentcomms bundle with all components.
-func createBundle() *bundle.Bundle {
+func createBundle(command string, hooks hookOptions) *bundle.Bundle {
 	b := bundle.New("agentcomms", "0.2.0", "Voice calling and chat messaging for AI assistants")
 	b.Plugin.Author = "plexusone"
 	b.Plugin.License = "MIT"
@@ -74,7 +188,7 @@ func createBundle() *bundle.Bundle {
 	// Add MCP server
 	//nolint:gosec // G101: These are env var templates, not credentials
 	b.AddMCPServer("agentcomms", bundle.MCPServer{
-		Command: "./agentcomms",
+		Command: command,
 		Env: map[string]string{
 			// Voice (optional)
 			"AGENTCOMMS_PHONE_ACCOUNT_SID": "${AGENTCOMMS_PHONE_ACCOUNT_SID}",
@@ -103,7 +217,9 @@ func createBundle() *bundle.Bundle {
 	b.AddCommand(createMessageCommand())
 
 	// Add hooks
-	b.SetHooks(createHooks())
+	if cfg := createHooks(hooks); cfg != nil {
+		b.SetHooks(cfg)
+	}
 
 	// Add agents (for Kiro CLI)
 	b.AddAgent(createVoiceAgent())
@@ -151,19 +267,32 @@ Start a new call to the user. Use when beginning a conversation.
 Continue an active call with another message. Use for multi-turn conversations.
 
 **Example:**
-` + "```json\n{\n  \"call_id\": \"call-1-123456\",\n  \"message\": \"Should I also add refresh token support, or is the basic JWT implementation sufficient?\"\n}\n```" + `
+` + "```json\n{\n  \"call_id\": \"3f6c1c2e-8a4b-4d2f-9c1e-5b7a0d9e2f41\",\n  \"message\": \"Should I also add refresh token support, or is the basic JWT implementation sufficient?\"\n}\n```" + `
 
 ### speak_to_user
 Speak without waiting for a response. Use for acknowledgments before time-consuming operations.
 
 **Example:**
-` + "```json\n{\n  \"call_id\": \"call-1-123456\",\n  \"message\": \"Let me search through the codebase for that. Give me a moment...\"\n}\n```" + `
+` + "```json\n{\n  \"call_id\": \"3f6c1c2e-8a4b-4d2f-9c1e-5b7a0d9e2f41\",\n  \"message\": \"Let me search through the codebase for that. Give me a moment...\"\n}\n```" + `
 
 ### end_call
 End the call with an optional goodbye message.
 
 **Example:**
-` + "```json\n{\n  \"call_id\": \"call-1-123456\",\n  \"message\": \"Perfect! I'll get started on the tests. Talk soon!\"\n}\n```" + `
+` + "```json\n{\n  \"call_id\": \"3f6c1c2e-8a4b-4d2f-9c1e-5b7a0d9e2f41\",\n  \"message\": \"Perfect! I'll get started on the tests. Talk soon!\"\n}\n```" + `
+
+## Expressive Audio Tags
+
+When the server is configured with audio tags enabled (ElevenLabs v3), you can add inline tags to any spoken message to control delivery:
+
+- Emotion: [excited], [curious], [sarcastic], [calm]
+- Reactions: [laughs], [sighs], [clears throat]
+- Delivery: [whispers], [pauses]
+
+**Example:**
+` + "```json\n{\n  \"call_id\": \"3f6c1c2e-8a4b-4d2f-9c1e-5b7a0d9e2f41\",\n  \"message\": \"[excited] All the tests pass! [pauses] Want to hear what I changed?\"\n}\n```" + `
+
+Use tags sparingly. If the active voice model does not support them, they are removed before speaking, so they are always safe to include.
 
 ## Best Practices
 
@@ -338,16 +467,26 @@ func createMessageCommand() *bundle.Command {
 	return cmd
 }
 
-// createHooks creates the hooks configuration.
-func createHooks() *bundle.Config {
-	cfg := bundle.NewHooksConfig()
+// hookOptions selects the generated hooks and their prompts.
+type hookOptions struct {
+	stop               bool
+	notification       bool
+	stopPrompt         string
+	notificationPrompt string
+}
 
-	// Add hook for OnStop event
-	cfg.AddHook(
-		core.OnStop,
-		core.Hook{
-			Type: "prompt",
-			Prompt: `The user has stopped the current operation. Consider whether this is an appropriate time to communicate:
+// defaultHookOptions generates both hooks with the default prompts.
+func defaultHookOptions() hookOptions {
+	return hookOptions{
+		stop:               true,
+		notification:       true,
+		stopPrompt:         defaultStopPrompt,
+		notificationPrompt: defaultNotificationPrompt,
+	}
+}
+
+// defaultStopPrompt is injected when the user stops the current operation.
+const defaultStopPrompt = `The user has stopped the current operation. Consider whether this is an appropriate time to communicate:
 
 - If you completed significant work, offer to call or message them
 - If you're blocked and need clarification, suggest calling to discuss
@@ -357,24 +496,34 @@ Choose the appropriate channel:
 - **Phone call**: For urgent matters or complex discussions
 - **Chat message**: For status updates or sharing links/code
 
-If communication seems appropriate, use the relevant tool. Otherwise, continue working or wait for instructions.`,
-		},
-	)
+If communication seems appropriate, use the relevant tool. Otherwise, continue working or wait for instructions.`
 
-	// Add hook for notification events
-	cfg.AddHook(
-		core.OnNotification,
-		core.Hook{
-			Type: "prompt",
-			Prompt: `You received a notification that may indicate you're stuck or need user input.
+// defaultNotificationPrompt is injected on notification events.
+const defaultNotificationPrompt = `You received a notification that may indicate you're stuck or need user input.
 
 If you've been working for a while without progress or need a decision that's blocking further work, consider:
 - **Phone call**: For urgent decisions or complex discussions
 - **Chat message**: For non-urgent updates or questions
 
-Use the appropriate tool if communication is warranted.`,
-		},
-	)
+Use the appropriate tool if communication is warranted.`
+
+// createHooks creates the hooks configuration, or nil if opts disables
+// every hook.
+func createHooks(opts hookOptions) *bundle.Config {
+	if !opts.stop && !opts.notification {
+		return nil
+	}
+	cfg := bundle.NewHooksConfig()
+
+	// Add hook for OnStop event
+	if opts.stop {
+		cfg.AddHook(core.OnStop, core.Hook{Type: "prompt", Prompt: opts.stopPrompt})
+	}
+
+	// Add hook for notification events
+	if opts.notification {
+		cfg.AddHook(core.OnNotification, core.Hook{Type: "prompt", Prompt: opts.notificationPrompt})
+	}
 
 	return cfg
 }
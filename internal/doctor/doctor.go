@@ -0,0 +1,467 @@
+// Package doctor checks that the external services the MCP server depends
+// on are reachable and accept the configured credentials, so setup
+// problems show up as a checklist instead of failed calls.
+package doctor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/plexusone/agentcomms/internal/tunnel"
+	"github.com/plexusone/agentcomms/pkg/config"
+)
+
+// Status is the outcome of a check.
+type Status string
+
+// Check statuses.
+const (
+	StatusPass Status = "pass"
+	StatusFail Status = "fail"
+	StatusSkip Status = "skip" // not needed by the configuration, or blocked by an earlier failure
+)
+
+// Result is the outcome of one check.
+type Result struct {
+	Name   string
+	Status Status
+	Detail string // what was found
+	Hint   string // how to fix a failure
+}
+
+// Default service endpoints.
+const (
+	twilioAPIURL     = "https://api.twilio.com"
+	telnyxAPIURL     = "https://api.telnyx.com"
+	elevenLabsAPIURL = "https://api.elevenlabs.io"
+	deepgramAPIURL   = "https://api.deepgram.com"
+	openAIAPIURL     = "https://api.openai.com"
+)
+
+// requestTimeout bounds each request to a provider.
+const requestTimeout = 15 * time.Second
+
+// Doctor runs the checks for a configuration.
+type Doctor struct {
+	cfg    *config.Config
+	client *http.Client
+
+	// Service base URLs, replaced in tests.
+	twilioURL     string
+	telnyxURL     string
+	elevenLabsURL string
+	deepgramURL   string
+	openAIURL     string
+
+	// startNgrok opens and closes an ngrok session with the configured
+	// authtoken. ngrok has no API to check an authtoken without one.
+	startNgrok func(ctx context.Context) error
+}
+
+// New creates a Doctor for cfg. startNgrok is called for the ngrok check;
+// if nil, the check is skipped.
+func New(cfg *config.Config, startNgrok func(ctx context.Context) error) *Doctor {
+	return &Doctor{
+		cfg:           cfg,
+		client:        &http.Client{Timeout: requestTimeout},
+		twilioURL:     twilioBaseURL(cfg.TwilioRegion, cfg.TwilioEdge),
+		telnyxURL:     telnyxAPIURL,
+		elevenLabsURL: elevenLabsAPIURL,
+		deepgramURL:   deepgramAPIURL,
+		openAIURL:     openAIAPIURL,
+		startNgrok:    startNgrok,
+	}
+}
+
+// Run runs every check in order and returns the results. A failed check
+// does not stop the others, so one run shows everything that needs fixing.
+func (d *Doctor) Run(ctx context.Context) []Result {
+	results := []Result{d.checkConfig()}
+	if !d.cfg.VoiceEnabled() {
+		return append(results, Result{
+			Name:   "Voice",
+			Status: StatusSkip,
+			Detail: "no phone settings; voice checks skipped",
+		})
+	}
+
+	results = append(results, d.checkConnectivity(ctx))
+	if d.cfg.PhoneProvider == "telnyx" {
+		results = append(results, d.checkTelnyx(ctx)...)
+	} else {
+		results = append(results, d.checkTwilio(ctx)...)
+	}
+	if d.cfg.NeedsElevenLabs() {
+		results = append(results, d.checkElevenLabs(ctx)...)
+	}
+	if d.cfg.NeedsDeepgram() {
+		results = append(results, d.checkDeepgram(ctx))
+	}
+	if d.cfg.NeedsOpenAI() {
+		results = append(results, d.checkOpenAI(ctx))
+	}
+	return append(results, d.checkTunnel(ctx))
+}
+
+// Failed returns the number of failed checks in results.
+func Failed(results []Result) int {
+	n := 0
+	for _, r := range results {
+		if r.Status == StatusFail {
+			n++
+		}
+	}
+	return n
+}
+
+func (d *Doctor) checkConfig() Result {
+	r := Result{Name: "Configuration"}
+	if err := d.cfg.Validate(); err != nil {
+		r.Status = StatusFail
+		r.Detail = err.Error()
+		r.Hint = "Run 'agentcomms config env' to see each setting and where it came from"
+		return r
+	}
+	r.Status = StatusPass
+	return r
+}
+
+// services returns the base URLs of the services the configuration uses.
+func (d *Doctor) services() []string {
+	var urls []string
+	if d.cfg.PhoneProvider == "telnyx" {
+		urls = append(urls, d.telnyxURL)
+	} else {
+		urls = append(urls, d.twilioURL)
+	}
+	if d.cfg.NeedsElevenLabs() {
+		urls = append(urls, d.elevenLabsURL)
+	}
+	if d.cfg.NeedsDeepgram() {
+		urls = append(urls, d.deepgramURL)
+	}
+	if d.cfg.NeedsOpenAI() {
+		urls = append(urls, d.openAIURL)
+	}
+	return urls
+}
+
+func (d *Doctor) checkConnectivity(ctx context.Context) Result {
+	r := Result{Name: "Outbound connectivity"}
+	var reached, unreachable []string
+	for _, base := range d.services() {
+		resp, err := d.get(ctx, base, nil)
+		if err != nil {
+			unreachable = append(unreachable, hostOf(base))
+			continue
+		}
+		resp.Body.Close()
+		reached = append(reached, hostOf(base))
+	}
+
+	if len(unreachable) > 0 {
+		r.Status = StatusFail
+		r.Detail = "cannot reach " + strings.Join(unreachable, ", ")
+		r.Hint = "Check your internet connection, proxy settings (HTTPS_PROXY), and firewall"
+		return r
+	}
+	r.Status = StatusPass
+	r.Detail = "reached " + strings.Join(reached, ", ")
+	return r
+}
+
+// twilioDefaultEdges are the edges Twilio uses for a region when none is set.
+var twilioDefaultEdges = map[string]string{
+	"us1": "ashburn",
+	"ie1": "dublin",
+	"au1": "sydney",
+}
+
+// twilioBaseURL returns the Twilio REST API base URL for a region and edge.
+func twilioBaseURL(region, edge string) string {
+	if region == "" && edge == "" {
+		return twilioAPIURL
+	}
+	if region == "" {
+		region = "us1"
+	}
+	if edge == "" {
+		edge = twilioDefaultEdges[region]
+	}
+	return fmt.Sprintf("https://api.%s.%s.twilio.com", edge, region)
+}
+
+func (d *Doctor) checkTwilio(ctx context.Context) []Result {
+	creds := Result{Name: "Twilio credentials"}
+	number := Result{Name: "Twilio phone number"}
+	if d.cfg.PhoneAccountSID == "" || d.cfg.PhoneAuthToken == "" {
+		creds.Status, creds.Detail = StatusSkip, "account SID or auth token not set"
+		number.Status, number.Detail = StatusSkip, "needs Twilio credentials"
+		return []Result{creds, number}
+	}
+
+	auth := func(req *http.Request) { req.SetBasicAuth(d.cfg.PhoneAccountSID, d.cfg.PhoneAuthToken) }
+	accountURL := d.twilioURL + "/2010-04-01/Accounts/" + url.PathEscape(d.cfg.PhoneAccountSID)
+
+	var account struct {
+		FriendlyName string `json:"friendly_name"`
+		Status       string `json:"status"`
+	}
+	if err := d.getJSON(ctx, accountURL+".json", auth, &account); err != nil {
+		creds.Status, creds.Detail = StatusFail, err.Error()
+		creds.Hint = hintFor(err, "Check AGENTCOMMS_PHONE_ACCOUNT_SID and AGENTCOMMS_PHONE_AUTH_TOKEN against https://console.twilio.com; accounts outside us1 need the auth token for AGENTCOMMS_TWILIO_REGION")
+		number.Status, number.Detail = StatusSkip, "needs valid Twilio credentials"
+		return []Result{creds, number}
+	}
+	if account.Status != "" && account.Status != "active" {
+		creds.Status = StatusFail
+		creds.Detail = fmt.Sprintf("account %q is %s", account.FriendlyName, account.Status)
+		creds.Hint = "Reactivate the account in https://console.twilio.com"
+	} else {
+		creds.Status = StatusPass
+		creds.Detail = fmt.Sprintf("account %q", account.FriendlyName)
+	}
+
+	var numbers struct {
+		IncomingPhoneNumbers []struct {
+			PhoneNumber string `json:"phone_number"`
+		} `json:"incoming_phone_numbers"`
+	}
+	numbersURL := accountURL + "/IncomingPhoneNumbers.json?PhoneNumber=" + url.QueryEscape(d.cfg.PhoneNumber)
+	if err := d.getJSON(ctx, numbersURL, auth, &numbers); err != nil {
+		number.Status, number.Detail = StatusFail, err.Error()
+		number.Hint = hintFor(err, "")
+		return []Result{creds, number}
+	}
+	if len(numbers.IncomingPhoneNumbers) == 0 {
+		number.Status = StatusFail
+		number.Detail = fmt.Sprintf("%s is not a number on this account", d.cfg.PhoneNumber)
+		number.Hint = "Set AGENTCOMMS_PHONE_NUMBER to a number listed under Phone Numbers in https://console.twilio.com, in E.164 format"
+		return []Result{creds, number}
+	}
+	number.Status, number.Detail = StatusPass, d.cfg.PhoneNumber
+	return []Result{creds, number}
+}
+
+func (d *Doctor) checkTelnyx(ctx context.Context) []Result {
+	creds := Result{Name: "Telnyx API key"}
+	number := Result{Name: "Telnyx phone number"}
+	if d.cfg.PhoneAuthToken == "" {
+		creds.Status, creds.Detail = StatusSkip, "API key not set"
+		number.Status, number.Detail = StatusSkip, "needs a Telnyx API key"
+		return []Result{creds, number}
+	}
+
+	var numbers struct {
+		Data []struct {
+			PhoneNumber string `json:"phone_number"`
+		} `json:"data"`
+	}
+	numbersURL := d.telnyxURL + "/v2/phone_numbers?filter[phone_number]=" + url.QueryEscape(d.cfg.PhoneNumber)
+	err := d.getJSON(ctx, numbersURL, bearer(d.cfg.PhoneAuthToken), &numbers)
+	if err != nil {
+		creds.Status, creds.Detail = StatusFail, err.Error()
+		creds.Hint = hintFor(err, "Set AGENTCOMMS_PHONE_AUTH_TOKEN to an API key from https://portal.telnyx.com")
+		number.Status, number.Detail = StatusSkip, "needs a valid Telnyx API key"
+		return []Result{creds, number}
+	}
+	creds.Status = StatusPass
+
+	if len(numbers.Data) == 0 {
+		number.Status = StatusFail
+		number.Detail = fmt.Sprintf("%s is not a number on this account", d.cfg.PhoneNumber)
+		number.Hint = "Set AGENTCOMMS_PHONE_NUMBER to a number listed under Numbers in https://portal.telnyx.com, in E.164 format"
+		return []Result{creds, number}
+	}
+	number.Status, number.Detail = StatusPass, d.cfg.PhoneNumber
+	return []Result{creds, number}
+}
+
+func (d *Doctor) checkElevenLabs(ctx context.Context) []Result {
+	key := Result{Name: "ElevenLabs API key"}
+	if d.cfg.ElevenLabsAPIKey == "" {
+		key.Status, key.Detail = StatusSkip, "API key not set"
+		return []Result{key}
+	}
+
+	var voices struct {
+		Voices []struct {
+			VoiceID string `json:"voice_id"`
+			Name    string `json:"name"`
+		} `json:"voices"`
+	}
+	auth := func(req *http.Request) { req.Header.Set("xi-api-key", d.cfg.ElevenLabsAPIKey) }
+	if err := d.getJSON(ctx, d.elevenLabsURL+"/v1/voices", auth, &voices); err != nil {
+		key.Status, key.Detail = StatusFail, err.Error()
+		key.Hint = hintFor(err, "Set AGENTCOMMS_ELEVENLABS_API_KEY to a key from https://elevenlabs.io/app/settings/api-keys with voices_read access")
+		return []Result{key}
+	}
+	key.Status = StatusPass
+	if d.cfg.TTSProvider != config.ProviderElevenLabs {
+		return []Result{key}
+	}
+
+	voice := Result{Name: "ElevenLabs voice"}
+	for _, v := range voices.Voices {
+		if v.VoiceID == d.cfg.TTSVoice || strings.EqualFold(v.Name, d.cfg.TTSVoice) {
+			voice.Status, voice.Detail = StatusPass, fmt.Sprintf("%s (%s)", v.Name, v.VoiceID)
+			return []Result{key, voice}
+		}
+	}
+	voice.Status = StatusFail
+	voice.Detail = fmt.Sprintf("voice %q not found in the account's %d voices", d.cfg.TTSVoice, len(voices.Voices))
+	voice.Hint = "Set AGENTCOMMS_TTS_VOICE to a voice ID from https://elevenlabs.io/app/voice-lab, or add the voice to your library"
+	return []Result{key, voice}
+}
+
+func (d *Doctor) checkDeepgram(ctx context.Context) Result {
+	r := Result{Name: "Deepgram API key"}
+	if d.cfg.DeepgramAPIKey == "" {
+		r.Status, r.Detail = StatusSkip, "API key not set"
+		return r
+	}
+	auth := func(req *http.Request) { req.Header.Set("Authorization", "Token "+d.cfg.DeepgramAPIKey) }
+	if err := d.getJSON(ctx, d.deepgramURL+"/v1/projects", auth, nil); err != nil {
+		r.Status, r.Detail = StatusFail, err.Error()
+		r.Hint = hintFor(err, "Set AGENTCOMMS_DEEPGRAM_API_KEY to a key from https://console.deepgram.com")
+		return r
+	}
+	r.Status = StatusPass
+	return r
+}
+
+func (d *Doctor) checkOpenAI(ctx context.Context) Result {
+	r := Result{Name: "OpenAI API key"}
+	if d.cfg.OpenAIAPIKey == "" {
+		r.Status, r.Detail = StatusSkip, "API key not set"
+		return r
+	}
+	if err := d.getJSON(ctx, d.openAIURL+"/v1/models", bearer(d.cfg.OpenAIAPIKey), nil); err != nil {
+		r.Status, r.Detail = StatusFail, err.Error()
+		r.Hint = hintFor(err, "Set AGENTCOMMS_OPENAI_API_KEY to a key from https://platform.openai.com/api-keys")
+		return r
+	}
+	r.Status = StatusPass
+	return r
+}
+
+func (d *Doctor) checkTunnel(ctx context.Context) Result {
+	switch {
+	case d.cfg.PublicURL != "":
+		return Result{Name: "Tunnel", Status: StatusSkip, Detail: "AGENTCOMMS_PUBLIC_URL is set; no tunnel is started"}
+	case d.cfg.Transport == config.TransportStdio:
+		return Result{Name: "Tunnel", Status: StatusSkip, Detail: "no tunnel is started with the stdio transport"}
+	case d.cfg.Tunnel == config.TunnelCloudflared:
+		r := Result{Name: "cloudflared"}
+		path := d.cfg.CloudflaredPath
+		if path == "" {
+			path = "cloudflared"
+		}
+		found, err := exec.LookPath(path)
+		if err != nil {
+			r.Status, r.Detail = StatusFail, err.Error()
+			r.Hint = "Install cloudflared (https://developers.cloudflare.com/cloudflare-one/connections/connect-networks/downloads/) or set AGENTCOMMS_CLOUDFLARED_PATH"
+			return r
+		}
+		r.Status, r.Detail = StatusPass, found
+		return r
+	}
+
+	r := Result{Name: "ngrok authtoken"}
+	if d.cfg.NgrokAuthToken == "" || d.startNgrok == nil {
+		r.Status, r.Detail = StatusSkip, "authtoken not set"
+		return r
+	}
+	if err := d.startNgrok(ctx); err != nil {
+		// The ngrok tunnel classifies its own startup errors
+		var startErr *tunnel.StartupError
+		if !errors.As(err, &startErr) {
+			startErr = tunnel.ClassifyNgrokError(err)
+		}
+		r.Status, r.Detail = StatusFail, startErr.Err.Error()
+		r.Hint = startErr.Hint()
+		return r
+	}
+	r.Status, r.Detail = StatusPass, "tunnel session started"
+	return r
+}
+
+// statusError is a provider response with an unexpected status code.
+type statusError struct {
+	code int
+}
+
+func (e *statusError) Error() string {
+	switch e.code {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return fmt.Sprintf("rejected the credentials (HTTP %d)", e.code)
+	default:
+		return fmt.Sprintf("unexpected response (HTTP %d)", e.code)
+	}
+}
+
+// hintFor returns the hint for a failed request: authHint when the
+// provider rejected the credentials, a connectivity hint when it could not
+// be reached, and nothing otherwise.
+func hintFor(err error, authHint string) string {
+	var se *statusError
+	if errors.As(err, &se) {
+		if se.code == http.StatusUnauthorized || se.code == http.StatusForbidden {
+			return authHint
+		}
+		return ""
+	}
+	return "Could not reach the service; see the outbound connectivity check"
+}
+
+func bearer(token string) func(*http.Request) {
+	return func(req *http.Request) { req.Header.Set("Authorization", "Bearer "+token) }
+}
+
+// get sends a GET request, applying auth if set.
+func (d *Doctor) get(ctx context.Context, rawURL string, auth func(*http.Request)) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	if auth != nil {
+		auth(req)
+	}
+	return d.client.Do(req)
+}
+
+// getJSON sends a GET request and decodes a 200 response into v, if set.
+func (d *Doctor) getJSON(ctx context.Context, rawURL string, auth func(*http.Request), v any) error {
+	resp, err := d.get(ctx, rawURL, auth)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return &statusError{code: resp.StatusCode}
+	}
+	if v == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("invalid response: %w", err)
+	}
+	return nil
+}
+
+func hostOf(rawURL string) string {
+	if u, err := url.Parse(rawURL); err == nil && u.Host != "" {
+		return u.Host
+	}
+	return rawURL
+}
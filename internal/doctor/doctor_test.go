@@ -0,0 +1,302 @@
+package doctor
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/plexusone/agentcomms/internal/tunnel"
+	"github.com/plexusone/agentcomms/pkg/config"
+)
+
+func testConfig() *config.Config {
+	cfg := config.DefaultConfig()
+	cfg.PhoneAccountSID = "AC123"
+	cfg.PhoneAuthToken = "token"
+	cfg.PhoneNumber = "+15551234567"
+	cfg.UserPhoneNumber = "+15559876543"
+	cfg.ElevenLabsAPIKey = "el-key"
+	cfg.DeepgramAPIKey = "dg-key"
+	cfg.NgrokAuthToken = "ngrok-token"
+	return cfg
+}
+
+// fakeServices serves the provider endpoints the checks call, accepting the
+// credentials from testConfig.
+func fakeServices(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/2010-04-01/Accounts/AC123.json", func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, _ := r.BasicAuth(); user != "AC123" || pass != "token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"friendly_name": "Dev", "status": "active"}`))
+	})
+	mux.HandleFunc("/2010-04-01/Accounts/AC123/IncomingPhoneNumbers.json", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("PhoneNumber") != "+15551234567" {
+			_, _ = w.Write([]byte(`{"incoming_phone_numbers": []}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"incoming_phone_numbers": [{"phone_number": "+15551234567"}]}`))
+	})
+	mux.HandleFunc("/v1/voices", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("xi-api-key") != "el-key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"voices": [{"voice_id": "21m00Tcm4TlvDq8ikWAM", "name": "Rachel"}]}`))
+	})
+	mux.HandleFunc("/v1/projects", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Token dg-key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"projects": []}`))
+	})
+	mux.HandleFunc("/v2/phone_numbers", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Query().Get("filter[phone_number]") != "+15551234567" {
+			_, _ = w.Write([]byte(`{"data": []}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data": [{"phone_number": "+15551234567"}]}`))
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func newTestDoctor(t *testing.T, cfg *config.Config, ngrokErr error) *Doctor {
+	t.Helper()
+	srv := fakeServices(t)
+	d := New(cfg, func(context.Context) error { return ngrokErr })
+	d.client = srv.Client()
+	d.twilioURL = srv.URL
+	d.telnyxURL = srv.URL
+	d.elevenLabsURL = srv.URL
+	d.deepgramURL = srv.URL
+	d.openAIURL = srv.URL
+	return d
+}
+
+// byName indexes results by check name.
+func byName(results []Result) map[string]Result {
+	m := make(map[string]Result, len(results))
+	for _, r := range results {
+		m[r.Name] = r
+	}
+	return m
+}
+
+func TestRun_AllPass(t *testing.T) {
+	results := newTestDoctor(t, testConfig(), nil).Run(context.Background())
+
+	want := []string{
+		"Configuration",
+		"Outbound connectivity",
+		"Twilio credentials",
+		"Twilio phone number",
+		"ElevenLabs API key",
+		"ElevenLabs voice",
+		"Deepgram API key",
+		"ngrok authtoken",
+	}
+	if len(results) != len(want) {
+		t.Fatalf("got %d results, want %d: %+v", len(results), len(want), results)
+	}
+	for i, r := range results {
+		if r.Name != want[i] {
+			t.Errorf("results[%d].Name = %q, want %q", i, r.Name, want[i])
+		}
+		if r.Status != StatusPass {
+			t.Errorf("%s: status = %s (%s), want pass", r.Name, r.Status, r.Detail)
+		}
+	}
+	if n := Failed(results); n != 0 {
+		t.Errorf("Failed() = %d, want 0", n)
+	}
+}
+
+func TestRun_Failures(t *testing.T) {
+	tests := []struct {
+		name     string
+		modify   func(*config.Config)
+		ngrokErr error
+		check    string
+		wantHint string
+		skipped  []string
+	}{
+		{
+			name:     "bad Twilio token",
+			modify:   func(c *config.Config) { c.PhoneAuthToken = "wrong" },
+			check:    "Twilio credentials",
+			wantHint: "AGENTCOMMS_PHONE_AUTH_TOKEN",
+			skipped:  []string{"Twilio phone number"},
+		},
+		{
+			name:     "number not on the account",
+			modify:   func(c *config.Config) { c.PhoneNumber = "+15550000000" },
+			check:    "Twilio phone number",
+			wantHint: "AGENTCOMMS_PHONE_NUMBER",
+		},
+		{
+			name:     "bad ElevenLabs key",
+			modify:   func(c *config.Config) { c.ElevenLabsAPIKey = "wrong" },
+			check:    "ElevenLabs API key",
+			wantHint: "AGENTCOMMS_ELEVENLABS_API_KEY",
+		},
+		{
+			name:     "unknown voice",
+			modify:   func(c *config.Config) { c.TTSVoice = "Bella" },
+			check:    "ElevenLabs voice",
+			wantHint: "AGENTCOMMS_TTS_VOICE",
+		},
+		{
+			name:     "bad Deepgram key",
+			modify:   func(c *config.Config) { c.DeepgramAPIKey = "wrong" },
+			check:    "Deepgram API key",
+			wantHint: "AGENTCOMMS_DEEPGRAM_API_KEY",
+		},
+		{
+			name:     "bad ngrok token",
+			ngrokErr: errors.New("failed to start tunnel: ERR_NGROK_105: authentication failed"),
+			check:    "ngrok authtoken",
+			wantHint: "NGROK_AUTHTOKEN",
+		},
+		{
+			name:     "ngrok session limit from the tunnel",
+			ngrokErr: tunnel.ClassifyNgrokError(errors.New("ERR_NGROK_108: your account is limited to 1 simultaneous session")),
+			check:    "ngrok authtoken",
+			wantHint: "stop other ngrok agents",
+		},
+		{
+			name:     "invalid configuration",
+			modify:   func(c *config.Config) { c.NgrokAuthToken = "" },
+			check:    "Configuration",
+			wantHint: "config env",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig()
+			if tt.modify != nil {
+				tt.modify(cfg)
+			}
+			results := byName(newTestDoctor(t, cfg, tt.ngrokErr).Run(context.Background()))
+
+			r := results[tt.check]
+			if r.Status != StatusFail {
+				t.Fatalf("%s: status = %s, want fail", tt.check, r.Status)
+			}
+			if !strings.Contains(r.Hint, tt.wantHint) {
+				t.Errorf("%s: hint = %q, want to mention %q", tt.check, r.Hint, tt.wantHint)
+			}
+			for _, name := range tt.skipped {
+				if got := results[name].Status; got != StatusSkip {
+					t.Errorf("%s: status = %s, want skip", name, got)
+				}
+			}
+		})
+	}
+}
+
+func TestRun_Unreachable(t *testing.T) {
+	d := newTestDoctor(t, testConfig(), nil)
+	d.deepgramURL = "http://127.0.0.1:1" // nothing listens on port 1
+	results := byName(d.Run(context.Background()))
+
+	conn := results["Outbound connectivity"]
+	if conn.Status != StatusFail || !strings.Contains(conn.Detail, "127.0.0.1:1") {
+		t.Errorf("connectivity = %+v, want unreachable host reported", conn)
+	}
+	if r := results["Deepgram API key"]; r.Status != StatusFail || !strings.Contains(r.Hint, "connectivity") {
+		t.Errorf("Deepgram = %+v, want connectivity hint", r)
+	}
+	if r := results["Twilio credentials"]; r.Status != StatusPass {
+		t.Errorf("Twilio = %+v, want other services still checked", r)
+	}
+}
+
+func TestRun_Telnyx(t *testing.T) {
+	cfg := testConfig()
+	cfg.PhoneProvider = "telnyx"
+	results := byName(newTestDoctor(t, cfg, nil).Run(context.Background()))
+
+	for _, name := range []string{"Telnyx API key", "Telnyx phone number"} {
+		if got := results[name].Status; got != StatusPass {
+			t.Errorf("%s: status = %s, want pass", name, got)
+		}
+	}
+	if _, ok := results["Twilio credentials"]; ok {
+		t.Error("Twilio checked with the telnyx provider")
+	}
+}
+
+func TestRun_Tunnel(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*config.Config)
+		check  string
+		want   Status
+	}{
+		{"public URL", func(c *config.Config) { c.PublicURL = "https://calls.example.com" }, "Tunnel", StatusSkip},
+		{"cloudflared missing", func(c *config.Config) {
+			c.Tunnel = config.TunnelCloudflared
+			c.CloudflaredPath = "/nonexistent/cloudflared"
+		}, "cloudflared", StatusFail},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig()
+			tt.modify(cfg)
+			ngrokCalled := false
+			d := newTestDoctor(t, cfg, nil)
+			d.startNgrok = func(context.Context) error {
+				ngrokCalled = true
+				return nil
+			}
+			results := byName(d.Run(context.Background()))
+
+			if got := results[tt.check].Status; got != tt.want {
+				t.Errorf("%s: status = %s, want %s", tt.check, got, tt.want)
+			}
+			if ngrokCalled {
+				t.Error("ngrok started without the ngrok tunnel")
+			}
+		})
+	}
+}
+
+func TestRun_VoiceDisabled(t *testing.T) {
+	results := newTestDoctor(t, config.DefaultConfig(), nil).Run(context.Background())
+	if len(results) != 2 || results[1].Status != StatusSkip {
+		t.Errorf("results = %+v, want configuration and a skipped voice check", results)
+	}
+}
+
+func TestTwilioBaseURL(t *testing.T) {
+	tests := []struct {
+		region, edge string
+		want         string
+	}{
+		{"", "", "https://api.twilio.com"},
+		{"ie1", "", "https://api.dublin.ie1.twilio.com"},
+		{"", "tokyo", "https://api.tokyo.us1.twilio.com"},
+		{"au1", "sydney", "https://api.sydney.au1.twilio.com"},
+	}
+
+	for _, tt := range tests {
+		if got := twilioBaseURL(tt.region, tt.edge); got != tt.want {
+			t.Errorf("twilioBaseURL(%q, %q) = %q, want %q", tt.region, tt.edge, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package tunnel
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CloudflaredConfig holds configuration for the cloudflared tunnel.
+type CloudflaredConfig struct {
+	// Path is the cloudflared binary (default: "cloudflared" on PATH).
+	Path string
+
+	// Token is a named-tunnel token. If empty, a free quick tunnel on
+	// trycloudflare.com is used, which needs no account.
+	Token string
+
+	// Hostname is the public hostname routed to the named tunnel.
+	// Required when Token is set.
+	Hostname string
+
+	// StartTimeout bounds how long Start waits for the tunnel to connect
+	// (default: 30s).
+	StartTimeout time.Duration
+}
+
+// Cloudflared runs a cloudflared subprocess as a tunnel.
+type Cloudflared struct {
+	config CloudflaredConfig
+
+	mu  sync.Mutex
+	cmd *exec.Cmd
+	url string
+}
+
+// quickTunnelURLRegex matches the URL cloudflared prints for quick tunnels.
+var quickTunnelURLRegex = regexp.MustCompile(`https://[a-z0-9-]+\.trycloudflare\.com`)
+
+// namedTunnelReadyMarker is logged once a named tunnel has a live connection.
+const namedTunnelReadyMarker = "Registered tunnel connection"
+
+// NewCloudflared creates a new cloudflared tunnel.
+func NewCloudflared(cfg CloudflaredConfig) (*Cloudflared, error) {
+	if cfg.Path == "" {
+		cfg.Path = "cloudflared"
+	}
+	if cfg.Token != "" && cfg.Hostname == "" {
+		return nil, fmt.Errorf("cloudflared hostname is required for named tunnels")
+	}
+	if cfg.StartTimeout <= 0 {
+		cfg.StartTimeout = 30 * time.Second
+	}
+
+	return &Cloudflared{config: cfg}, nil
+}
+
+// Start launches cloudflared and waits until the tunnel is connected.
+func (c *Cloudflared) Start(ctx context.Context, localURL string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.cmd != nil {
+		return "", fmt.Errorf("cloudflared tunnel already started")
+	}
+
+	args := []string{"tunnel", "--no-autoupdate"}
+	if c.config.Token != "" {
+		args = append(args, "run", "--token", c.config.Token, "--url", localURL)
+	} else {
+		args = append(args, "--url", localURL)
+	}
+
+	// cloudflared must outlive Start, so it is not bound to ctx; Stop ends it.
+	cmd := exec.Command(c.config.Path, args...) //nolint:gosec // binary path comes from local config
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return "", fmt.Errorf("failed to capture cloudflared output: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return "", fmt.Errorf("failed to start cloudflared: %w", err)
+	}
+	c.cmd = cmd
+
+	ready := make(chan string, 1)
+	go c.scanOutput(stderr, ready)
+
+	timer := time.NewTimer(c.config.StartTimeout)
+	defer timer.Stop()
+
+	select {
+	case url, ok := <-ready:
+		if !ok {
+			c.stopLocked()
+			return "", fmt.Errorf("cloudflared exited before the tunnel was ready")
+		}
+		c.url = url
+		return url, nil
+	case <-timer.C:
+		c.stopLocked()
+		return "", fmt.Errorf("cloudflared tunnel not ready after %s", c.config.StartTimeout)
+	case <-ctx.Done():
+		c.stopLocked()
+		return "", ctx.Err()
+	}
+}
+
+// scanOutput watches cloudflared's log output for the ready signal and
+// keeps draining it so the subprocess never blocks on a full pipe.
+func (c *Cloudflared) scanOutput(r io.Reader, ready chan<- string) {
+	sent := false
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if sent {
+			continue
+		}
+		if url, ok := c.readyURL(scanner.Text()); ok {
+			ready <- url
+			sent = true
+		}
+	}
+	if !sent {
+		close(ready)
+	}
+}
+
+// readyURL reports whether a log line signals the tunnel is ready, and
+// returns the public URL if so.
+func (c *Cloudflared) readyURL(line string) (string, bool) {
+	if c.config.Token != "" {
+		if strings.Contains(line, namedTunnelReadyMarker) {
+			return "https://" + c.config.Hostname, true
+		}
+		return "", false
+	}
+	return parseQuickTunnelURL(line)
+}
+
+// parseQuickTunnelURL extracts a trycloudflare.com URL from a log line.
+func parseQuickTunnelURL(line string) (string, bool) {
+	url := quickTunnelURLRegex.FindString(line)
+	return url, url != ""
+}
+
+// Stop stops the cloudflared subprocess.
+func (c *Cloudflared) Stop() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.stopLocked()
+}
+
+// URL returns the public URL of the running tunnel, or "" if it is not running.
+func (c *Cloudflared) URL() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.url
+}
+
+// stopLocked kills the subprocess. The caller must hold c.mu.
+func (c *Cloudflared) stopLocked() error {
+	if c.cmd == nil || c.cmd.Process == nil {
+		return nil
+	}
+	err := c.cmd.Process.Kill()
+	_ = c.cmd.Wait()
+	c.cmd = nil
+	c.url = ""
+	return err
+}
@@ -0,0 +1,67 @@
+package tunnel
+
+import "testing"
+
+func TestNewCloudflared(t *testing.T) {
+	c, err := NewCloudflared(CloudflaredConfig{})
+	if err != nil {
+		t.Fatalf("NewCloudflared() error = %v", err)
+	}
+	if c.config.Path != "cloudflared" {
+		t.Errorf("Path = %q, want %q", c.config.Path, "cloudflared")
+	}
+
+	if _, err := NewCloudflared(CloudflaredConfig{Token: "tok"}); err == nil {
+		t.Error("expected error for named tunnel without hostname")
+	}
+}
+
+func TestParseQuickTunnelURL(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want string
+		ok   bool
+	}{
+		{
+			name: "banner line",
+			line: "2024-01-01T00:00:00Z INF |  https://happy-cat-example.trycloudflare.com                                |",
+			want: "https://happy-cat-example.trycloudflare.com",
+			ok:   true,
+		},
+		{
+			name: "unrelated line",
+			line: "2024-01-01T00:00:00Z INF Starting tunnel tunnelID=",
+			ok:   false,
+		},
+		{
+			name: "other https url",
+			line: "Visit https://developers.cloudflare.com for docs",
+			ok:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseQuickTunnelURL(tt.line)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("parseQuickTunnelURL() = (%q, %v), want (%q, %v)", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestCloudflaredReadyURL_Named(t *testing.T) {
+	c, err := NewCloudflared(CloudflaredConfig{Token: "tok", Hostname: "calls.example.com"})
+	if err != nil {
+		t.Fatalf("NewCloudflared() error = %v", err)
+	}
+
+	if _, ok := c.readyURL("INF Starting metrics server"); ok {
+		t.Error("expected non-ready line to be ignored")
+	}
+	url, ok := c.readyURL("INF Registered tunnel connection connIndex=0")
+	if !ok || url != "https://calls.example.com" {
+		t.Errorf("readyURL() = (%q, %v), want (%q, true)", url, ok, "https://calls.example.com")
+	}
+}
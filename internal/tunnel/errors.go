@@ -0,0 +1,119 @@
+package tunnel
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// StartupErrorKind classifies why a tunnel failed to start.
+type StartupErrorKind string
+
+// Startup error kinds.
+const (
+	StartupErrorAuth    StartupErrorKind = "auth"
+	StartupErrorQuota   StartupErrorKind = "quota"
+	StartupErrorNetwork StartupErrorKind = "network"
+	StartupErrorUnknown StartupErrorKind = "unknown"
+)
+
+// StartupError reports a tunnel that failed to start, with a hint on how to fix it.
+type StartupError struct {
+	Provider string
+	Kind     StartupErrorKind
+	Err      error
+}
+
+// Error implements the error interface.
+func (e *StartupError) Error() string {
+	return fmt.Sprintf("%s tunnel failed to start: %s (%v)", e.Provider, e.Hint(), e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *StartupError) Unwrap() error {
+	return e.Err
+}
+
+// Retryable reports whether starting again may succeed. Auth failures
+// never fix themselves, so they are not retried.
+func (e *StartupError) Retryable() bool {
+	return e.Kind != StartupErrorAuth
+}
+
+// Hint returns a user-facing explanation for the failure.
+func (e *StartupError) Hint() string {
+	switch e.Kind {
+	case StartupErrorAuth:
+		return "authentication failed; check NGROK_AUTHTOKEN (see https://dashboard.ngrok.com/get-started/your-authtoken)"
+	case StartupErrorQuota:
+		return "account limit reached; stop other ngrok agents using this token or upgrade your ngrok plan"
+	case StartupErrorNetwork:
+		return "could not reach the tunnel service; check your internet connection, proxy, and firewall"
+	default:
+		return "unexpected error"
+	}
+}
+
+// ngrokAuthMarkers identify ngrok authentication failures. Only ngrok's own
+// error codes are matched; free text such as "authtoken" also shows up in
+// unrelated messages.
+var ngrokAuthMarkers = []string{
+	"ERR_NGROK_105",  // invalid authtoken
+	"ERR_NGROK_106",  // authtoken format
+	"ERR_NGROK_107",  // authtoken revoked
+	"ERR_NGROK_4018", // authtoken required
+}
+
+// ngrokQuotaMarkers identify ngrok account limit failures.
+var ngrokQuotaMarkers = []string{
+	"ERR_NGROK_108", // simultaneous session limit
+	"ERR_NGROK_725", // bandwidth limit
+}
+
+// networkMarkers identify connectivity failures.
+var networkMarkers = []string{
+	"dial tcp",
+	"no such host",
+	"connection refused",
+	"connection reset",
+	"network is unreachable",
+	"i/o timeout",
+	"tls handshake",
+}
+
+// ClassifyNgrokError wraps an ngrok startup error with its failure kind.
+func ClassifyNgrokError(err error) *StartupError {
+	return &StartupError{
+		Provider: "ngrok",
+		Kind:     classify(err),
+		Err:      err,
+	}
+}
+
+// classify determines the startup error kind from an error.
+func classify(err error) StartupErrorKind {
+	msg := strings.ToLower(err.Error())
+	switch {
+	case containsAny(msg, ngrokAuthMarkers):
+		return StartupErrorAuth
+	case containsAny(msg, ngrokQuotaMarkers):
+		return StartupErrorQuota
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) || containsAny(msg, networkMarkers) {
+		return StartupErrorNetwork
+	}
+	return StartupErrorUnknown
+}
+
+// containsAny reports whether msg contains any of the markers (case-insensitive).
+func containsAny(msg string, markers []string) bool {
+	for _, marker := range markers {
+		if strings.Contains(msg, strings.ToLower(marker)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,79 @@
+package tunnel
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+)
+
+func TestClassifyNgrokError(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		kind      StartupErrorKind
+		retryable bool
+	}{
+		{
+			name:      "invalid authtoken",
+			err:       errors.New("failed to connect session: authentication failed: The authtoken you specified is not valid. ERR_NGROK_105"),
+			kind:      StartupErrorAuth,
+			retryable: false,
+		},
+		{
+			name:      "session limit",
+			err:       errors.New("Your account is limited to 1 simultaneous ngrok agent sessions. ERR_NGROK_108"),
+			kind:      StartupErrorQuota,
+			retryable: true,
+		},
+		{
+			name:      "dns failure",
+			err:       errors.New("dial tcp: lookup connect.ngrok-agent.com: no such host"),
+			kind:      StartupErrorNetwork,
+			retryable: true,
+		},
+		{
+			name:      "net error",
+			err:       fmt.Errorf("session: %w", &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("boom")}),
+			kind:      StartupErrorNetwork,
+			retryable: true,
+		},
+		{
+			name:      "rate limit from elsewhere",
+			err:       errors.New("webhook: rate limit exceeded, too many requests"),
+			kind:      StartupErrorUnknown,
+			retryable: true,
+		},
+		{
+			name:      "authtoken mentioned without a code",
+			err:       errors.New("reading authtoken from config: unexpected EOF"),
+			kind:      StartupErrorUnknown,
+			retryable: true,
+		},
+		{
+			name:      "unknown",
+			err:       errors.New("something odd"),
+			kind:      StartupErrorUnknown,
+			retryable: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ClassifyNgrokError(tt.err)
+			if got.Kind != tt.kind {
+				t.Errorf("Kind = %q, want %q", got.Kind, tt.kind)
+			}
+			if got.Retryable() != tt.retryable {
+				t.Errorf("Retryable() = %v, want %v", got.Retryable(), tt.retryable)
+			}
+			if !errors.Is(got, tt.err) {
+				t.Error("expected StartupError to wrap the original error")
+			}
+			if !strings.Contains(got.Error(), got.Hint()) {
+				t.Errorf("Error() = %q, want it to include the hint", got.Error())
+			}
+		})
+	}
+}
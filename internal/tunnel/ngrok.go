@@ -0,0 +1,101 @@
+package tunnel
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"sync"
+	"time"
+
+	"golang.ngrok.com/ngrok"
+	"golang.ngrok.com/ngrok/config"
+)
+
+// NgrokConfig holds configuration for the ngrok tunnel.
+type NgrokConfig struct {
+	// AuthToken authenticates the ngrok agent session.
+	AuthToken string
+
+	// Domain is an optional reserved ngrok domain (requires a paid plan).
+	// If empty, ngrok assigns a random one.
+	Domain string
+}
+
+// Ngrok runs an in-process ngrok agent session as a tunnel. Requests that
+// arrive on the ngrok endpoint are proxied to the local URL.
+type Ngrok struct {
+	config NgrokConfig
+
+	mu  sync.Mutex
+	srv *http.Server
+	url string
+}
+
+// NewNgrok creates a new ngrok tunnel.
+func NewNgrok(cfg NgrokConfig) (*Ngrok, error) {
+	if cfg.AuthToken == "" {
+		return nil, fmt.Errorf("ngrok authtoken is required")
+	}
+	return &Ngrok{config: cfg}, nil
+}
+
+// Start opens the ngrok endpoint and forwards it to localURL. Failures to
+// reach ngrok are returned as a *StartupError.
+func (n *Ngrok) Start(ctx context.Context, localURL string) (string, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.srv != nil {
+		return "", fmt.Errorf("ngrok tunnel already started")
+	}
+	target, err := url.Parse(localURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid local URL %q: %w", localURL, err)
+	}
+
+	endpoint := config.HTTPEndpoint()
+	if n.config.Domain != "" {
+		endpoint = config.HTTPEndpoint(config.WithDomain(n.config.Domain))
+	}
+	ln, err := ngrok.Listen(ctx, endpoint, ngrok.WithAuthtoken(n.config.AuthToken))
+	if err != nil {
+		return "", ClassifyNgrokError(err)
+	}
+
+	// The agent session reconnects on its own after network drops, so Serve
+	// only returns once Stop closes the listener. The proxy also carries
+	// the WebSocket upgrades of media streams.
+	srv := &http.Server{
+		Handler:           httputil.NewSingleHostReverseProxy(target),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() { _ = srv.Serve(ln) }()
+
+	n.srv = srv
+	// The listener address is the public hostname without a scheme
+	n.url = "https://" + ln.Addr().String()
+	return n.url, nil
+}
+
+// Stop closes the ngrok endpoint and its agent session.
+func (n *Ngrok) Stop() error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.srv == nil {
+		return nil
+	}
+	err := n.srv.Close()
+	n.srv = nil
+	n.url = ""
+	return err
+}
+
+// URL returns the public URL of the running tunnel, or "" if it is not running.
+func (n *Ngrok) URL() string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.url
+}
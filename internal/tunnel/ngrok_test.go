@@ -0,0 +1,30 @@
+package tunnel
+
+import "testing"
+
+func TestNewNgrok(t *testing.T) {
+	n, err := NewNgrok(NgrokConfig{AuthToken: "tok", Domain: "calls.ngrok.app"})
+	if err != nil {
+		t.Fatalf("NewNgrok() error = %v", err)
+	}
+	if n.URL() != "" {
+		t.Errorf("URL() = %q before Start, want empty", n.URL())
+	}
+	if err := n.Stop(); err != nil {
+		t.Errorf("Stop() before Start error = %v", err)
+	}
+
+	if _, err := NewNgrok(NgrokConfig{}); err == nil {
+		t.Error("expected error without authtoken")
+	}
+}
+
+func TestNgrokStart_InvalidLocalURL(t *testing.T) {
+	n, err := NewNgrok(NgrokConfig{AuthToken: "tok"})
+	if err != nil {
+		t.Fatalf("NewNgrok() error = %v", err)
+	}
+	if _, err := n.Start(t.Context(), "http://[::1"); err == nil {
+		t.Error("expected error for an unparseable local URL")
+	}
+}
@@ -0,0 +1,29 @@
+// Package tunnel provides tunnels that expose the local HTTP server at a
+// public URL so phone providers can reach the webhook endpoints.
+//
+// ngrok runs in-process through the ngrok agent SDK (see Ngrok), and
+// cloudflared runs as a subprocess alongside the server (see Cloudflared).
+// Both forward to the server's local address, so the server itself is the
+// same whichever tunnel is used.
+package tunnel
+
+import "context"
+
+// Tunnel exposes a local URL at a public URL.
+type Tunnel interface {
+	// Start opens the tunnel to localURL and returns the public base URL
+	// (e.g., "https://example.trycloudflare.com") once it is reachable.
+	Start(ctx context.Context, localURL string) (string, error)
+
+	// Stop tears down the tunnel.
+	Stop() error
+
+	// URL returns the public base URL, or "" if the tunnel is not running.
+	URL() string
+}
+
+// Compile-time checks that the tunnels implement Tunnel.
+var (
+	_ Tunnel = (*Cloudflared)(nil)
+	_ Tunnel = (*Ngrok)(nil)
+)
@@ -2,15 +2,36 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"net"
+	"net/url"
 	"os"
+	"strconv"
 	"strings"
 )
 
 // Config holds all configuration for the agentcomms server.
 type Config struct {
 	// Server settings
-	Port int
+	Port      int
+	BindAddr  string // host or IP to listen on (empty = all interfaces)
+	Transport string // MCP transport: "http" (default) or "stdio"
+	// ServeRestarts is how many times in a row the MCP server is restarted
+	// after failing while running (0 = exit on the first failure).
+	// ServeRestartBackoffMS is the wait before the first restart, doubled
+	// after each.
+	ServeRestarts         int
+	ServeRestartBackoffMS int
+
+	// EnabledTools limits the MCP tools registered to these names; empty
+	// registers all of them.
+	EnabledTools []string
+
+	// CORSOrigins are the browser origins, such as a dashboard's, allowed
+	// to call the trigger, do-not-disturb, and health endpoints; "*" allows
+	// any. Empty keeps them same-origin. Webhooks never allow other origins.
+	CORSOrigins []string
 
 	// Phone provider settings (Twilio)
 	PhoneProvider   string // "twilio" or "telnyx"
@@ -18,11 +39,88 @@ type Config struct {
 	PhoneAuthToken  string
 	PhoneNumber     string // E.164 format, e.g., +15551234567
 	UserPhoneNumber string // E.164 format
+	CallerIDName    string // optional caller ID name (CNAM) shown to the user where supported
+	TwilioRegion    string // optional Twilio Region, e.g. "ie1" (default: us1)
+	TwilioEdge      string // optional Twilio edge location, e.g. "dublin" (default: ashburn)
+	PreferredCodec  string // call audio codec, used where the phone provider supports it (default: mulaw)
+	TwiMLTemplate   string // optional file with the TwiML that connects answered calls to the media stream
+	QuietHours      string // daily local-time window with no calls, e.g. "22:00-07:00"
+
+	// Daily call budget over a rolling 24 hours (0 = unlimited)
+	MaxCallsPerDay  int
+	MaxDailyCostUSD float64 // estimated telephony cost of ended calls
+
+	// CallCostPerMinuteUSD is the telephony rate used to estimate call
+	// costs, billed per started minute after answer.
+	CallCostPerMinuteUSD float64
+
+	// MaxMessageChars rejects longer messages before they are spoken (0 =
+	// no limit).
+	MaxMessageChars int
+
+	// Per-call caps on TTS characters synthesized and time spent
+	// transcribing the user, for providers that bill by either (0 = no
+	// limit). A call that reaches one is ended before its next turn.
+	MaxTTSCharsPerCall   int
+	MaxSTTSecondsPerCall int
+
+	// DescriptionRefreshMS is how often tool descriptions are rebuilt from
+	// the live budget and quiet hours (0 = only at startup).
+	DescriptionRefreshMS int
+
+	// ConferenceNumbers may be dialed into conferences besides the user's
+	// own number.
+	ConferenceNumbers []string
+
+	// Tenants of a hosted deployment. When set, MCP requests must carry a
+	// tenant's API key, and its calls use its own numbers and voice.
+	Tenants []Tenant
+
+	// Persistence
+	CallStorePath  string // JSON file for scheduled calls (default: ~/.agentcomms/calls.json)
+	TranscriptSink string // file path or http(s) URL receiving each conversation turn as JSON lines
+
+	// Role names written for the assistant's and the user's turns in
+	// transcripts sent to the sink and the call-ended webhook
+	TranscriptAssistantRole string
+	TranscriptUserRole      string
+
+	// Call-ended webhook
+	CallEndedWebhook       string // URL receiving a JSON summary after end_call
+	CallEndedWebhookSecret string // HMAC-SHA256 key for signing the summary (optional)
+
+	// Trigger endpoint: bearer token for POST /trigger-call (empty disables it)
+	TriggerToken string
+
+	// Sentiment of user replies: "local" for the built-in heuristic, an
+	// http(s) URL for an external API, or empty to turn analysis off.
+	Sentiment string
+
+	// PronunciationDict is a JSON file mapping terms to how they should be
+	// spoken; its terms are also boosted in speech recognition.
+	PronunciationDict string
 
 	// Voice enhancements
-	EnableRecording    bool   // Enable call recording
-	SMSFallbackEnabled bool   // Send SMS when call not answered
-	SMSFallbackMessage string // Custom SMS message (use {message} for original message)
+	EnableRecording     bool   // Enable call recording
+	SMSFallbackEnabled  bool   // Send SMS when call not answered
+	SMSFallbackMessage  string // Custom SMS message (use {message} for original message)
+	RequireAccept       bool   // Require the callee to press 1 before the call connects
+	RequireConfirmation bool   // Hold initiate_call until the agent calls confirm_call
+	KeepAlive           bool   // Stream silence between turns so the media stream is not dropped
+	RequireProviders    bool   // Exit if the voice providers fail to initialize instead of serving without calls
+
+	// RecordingConsent is announced on recorded calls before any other
+	// audio, for two-party-consent jurisdictions.
+	RecordingConsent      string
+	RecordingConsentVoice string // TTS voice for the announcement (default: the call's voice)
+
+	// AudioDumpDir, if set, receives each call's audio as the user and
+	// the assistant heard it, in separate files, for debugging.
+	AudioDumpDir string
+
+	// AudioDir holds the pre-rendered audio files that may be played on a
+	// call instead of TTS. Files outside it cannot be played.
+	AudioDir string
 
 	// SMS transport settings
 	SMSEnabled bool // Enable inbound SMS as a chat transport
@@ -47,18 +145,116 @@ type Config struct {
 	// TTS settings (provider-agnostic)
 	TTSVoice string // Voice ID (provider-specific)
 	TTSModel string // Model ID (provider-specific)
+	// TTSEnableTags selects a model that understands inline audio tags such as
+	// [excited]; tags are stripped before synthesis on other models.
+	TTSEnableTags bool
+	// TTSStreamRetries is how many times the rest of a message is
+	// re-synthesized after the TTS stream fails partway.
+	TTSStreamRetries int
+	// SpeakingRate scales TTS speed (1.0 = normal) on providers that support
+	// it; see the voice package for per-provider limits.
+	SpeakingRate float64
+	// TTSContinuityTurns is how many previous assistant turns are passed to
+	// the TTS provider as context so intonation carries across messages
+	// (0 = off). Only ElevenLabs uses it.
+	TTSContinuityTurns int
+	// TrimSilence drops near-silent audio at the start and end of each
+	// message so the assistant does not pause before speaking. Samples up
+	// to TrimSilenceThreshold (16-bit linear amplitude) count as silent,
+	// and TrimSilencePadMS of the silence is kept so quiet onsets and
+	// trailing sounds are not clipped.
+	TrimSilence          bool
+	TrimSilenceThreshold int
+	TrimSilencePadMS     int
+	// TTSFrameMS re-frames TTS audio into chunks of this many milliseconds,
+	// sent as fast as they play, so the media stream gets an even flow
+	// instead of the provider's bursts (0 = send audio as it arrives). 20
+	// matches Twilio's media frames.
+	TTSFrameMS int
+	// TTSPrefetch lets the agent hint the next message so it is
+	// synthesized while the user answers. Discarded prefetches still use
+	// TTS credits.
+	TTSPrefetch bool
+	// TTSCache keeps synthesized audio in memory so repeated phrases such
+	// as greetings and goodbyes are not synthesized again. The least
+	// recently used audio is dropped once the cache holds more than
+	// TTSCacheMaxBytes.
+	TTSCache         bool
+	TTSCacheMaxBytes int
 
 	// STT settings (provider-agnostic)
 	STTModel             string // Model ID (provider-specific)
 	STTLanguage          string // BCP-47 language code (e.g., "en-US")
 	STTSilenceDurationMS int    // milliseconds of silence to detect end of speech
+	AggregateFinalsMS    int    // keep listening this long after a final transcript to join follow-on speech (0 = off)
+	AdaptiveSilence      bool   // learn the silence that ends a reply from the caller's pauses, replacing AggregateFinalsMS
+	AdaptiveSilenceMinMS int    // shortest silence AdaptiveSilence may wait for
+	AdaptiveSilenceMaxMS int    // longest silence AdaptiveSilence may wait for
+	MaxUtteranceMS       int    // finalize a reply after this much speech, counted from the first word (0 = no limit)
+	STTPersistConnection bool   // keep one STT stream open for the whole call instead of reconnecting every turn
+	STTContextKeywords   bool   // boost terms from recent assistant turns in STT
+	STTContextKeywordMax int    // most terms boosted from the conversation
+	LanguageDetection    bool   // flag replies that seem to be in another language than the call's STT language
+
+	// GoodbyePhrases mark a reply as the user wanting to end the call. The
+	// match is a hint to the agent; the call is never hung up automatically.
+	GoodbyePhrases []string
+
+	// YesPhrases and NoPhrases are the answers confirm_with_user accepts
+	// as yes and no. ConfirmReprompts is how many times it asks again
+	// after an unclear answer before giving up.
+	YesPhrases       []string
+	NoPhrases        []string
+	ConfirmReprompts int
+
+	// Tunnel selection
+	Tunnel    string // "ngrok" (default) or "cloudflared"
+	PublicURL string // static public https URL; when set, no tunnel is started
 
 	// ngrok settings
 	NgrokAuthToken string
 	NgrokDomain    string // optional custom domain
 
+	// cloudflared settings
+	CloudflaredPath     string // cloudflared binary (default: "cloudflared" on PATH)
+	CloudflaredToken    string // optional named-tunnel token; empty uses a quick tunnel
+	CloudflaredHostname string // public hostname of the named tunnel (required with token)
+
+	// TunnelReadyTimeoutMS is how long the server waits for the tunnel to
+	// come up before it exits with an error (0 = no limit).
+	TunnelReadyTimeoutMS int
+
 	// Timeouts
 	TranscriptTimeoutMS int
+	PostSpeechDelayMS   int // ignore caller audio this long after speaking so TTS playback is not transcribed
+	EchoGuardMS         int // discard utterances starting while our speech plays or within this long after (0 = off)
+	AMDWaitMS           int // enable answering machine detection and wait up to this long for it before speaking (0 = off)
+	StatusPollMS        int // how often a ringing call's status is checked when the provider does not push it
+
+	// ResumeWindowMS keeps an answered call that drops without end_call
+	// this long so it can be re-dialed with its conversation (0 = off)
+	ResumeWindowMS int
+
+	// MaxCallLifetimeMS is how long a call may last before it is hung up
+	// and removed as abandoned (0 = no limit)
+	MaxCallLifetimeMS int
+
+	// RedialAttempts is how many more times a call that rings out or is
+	// busy is dialed before giving up, RedialDelayMS apart (0 = off)
+	RedialAttempts int
+	RedialDelayMS  int
+
+	// Greeting detection: before the first message, wait for the callee's
+	// "hello?" to end so the assistant does not talk over it
+	WaitForGreeting   bool
+	GreetingWaitMS    int // longest wait for the greeting to start and end
+	GreetingSilenceMS int // silence after speech that ends the greeting
+
+	// Connect tone: a short beep played once the call is answered, so the
+	// callee knows the assistant is on the line before it speaks
+	ConnectTone   bool
+	ConnectToneHz int // tone frequency
+	ConnectToneMS int // tone length
 
 	// Chat provider settings
 	WhatsAppEnabled bool
@@ -95,26 +291,207 @@ const (
 	ProviderOpenAI     = "openai"
 )
 
+// ElevenLabsTagModel is the ElevenLabs model used when audio tags are enabled
+// and no model is set explicitly.
+const ElevenLabsTagModel = "eleven_v3"
+
+// Speaking rate limits accepted by Validate. Providers may clamp further.
+const (
+	MinSpeakingRate = 0.5
+	MaxSpeakingRate = 2.0
+)
+
+// Silence trimming defaults. The threshold is in 16-bit linear amplitude;
+// 100 is roughly -50 dBFS, well below quiet speech.
+const (
+	DefaultTrimSilenceThreshold = 100
+	DefaultTrimSilencePadMS     = 100
+	MaxTrimSilenceThreshold     = 32767
+)
+
+// Adaptive end-of-speech defaults. The silence starts at
+// STTSilenceDurationMS and stays within these bounds.
+const (
+	DefaultAdaptiveSilenceMinMS = 300
+	DefaultAdaptiveSilenceMaxMS = 2000
+)
+
+// Connect tone defaults and the frequencies Validate accepts, which stay
+// within the 300-3400 Hz a phone line carries.
+const (
+	DefaultConnectToneHz = 880
+	DefaultConnectToneMS = 200
+	MinConnectToneHz     = 300
+	MaxConnectToneHz     = 3400
+)
+
+// DefaultTTSCacheMaxBytes is the default TTS cache size, about 17 minutes
+// of 8 kHz mu-law audio.
+const DefaultTTSCacheMaxBytes = 8 << 20
+
+// DefaultRecordingConsent is announced on recorded calls.
+const DefaultRecordingConsent = "This call is being recorded."
+
+// DefaultCallCostPerMinuteUSD is a rough telephony rate for estimating call
+// costs. TTS and STT usage is not included.
+const DefaultCallCostPerMinuteUSD = 0.03
+
+// confirm_with_user re-prompt limits.
+const (
+	DefaultConfirmReprompts = 2
+	MaxConfirmReprompts     = 5
+)
+
+// DefaultMaxCallLifetimeMS matches Twilio's default call time limit of four
+// hours; a call still listed after that is stuck.
+const DefaultMaxCallLifetimeMS = 4 * 3600000
+
+// Redial defaults and limits.
+const (
+	DefaultRedialDelayMS = 30000
+	MaxRedialAttempts    = 5
+)
+
+// STT context keyword limits.
+const (
+	DefaultSTTContextKeywordMax = 20
+	MaxSTTContextKeywordMax     = 100
+)
+
+// Audio codec constants. Mu-law works with every phone provider.
+const (
+	CodecMulaw = "mulaw"
+	CodecOpus  = "opus"
+)
+
+// MCP transport constants. With stdio the MCP server runs over standard
+// input and output; voice webhooks are still served over HTTP on Port and
+// need a PublicURL, since no tunnel is started.
+const (
+	TransportHTTP  = "http"
+	TransportStdio = "stdio"
+)
+
+// Tunnel constants.
+const (
+	TunnelNgrok       = "ngrok"
+	TunnelCloudflared = "cloudflared"
+)
+
+// DefaultGoodbyePhrases returns the phrases that, near the end of a reply,
+// suggest the user wants to hang up.
+func DefaultGoodbyePhrases() []string {
+	return []string{
+		"bye",
+		"goodbye",
+		"bye bye",
+		"talk to you later",
+		"talk later",
+		"that's all",
+		"that's it",
+		"hang up",
+		"gotta go",
+		"have to go",
+	}
+}
+
+// DefaultYesPhrases returns the phrases confirm_with_user accepts as yes.
+func DefaultYesPhrases() []string {
+	return []string{
+		"yes",
+		"yeah",
+		"yep",
+		"yup",
+		"sure",
+		"correct",
+		"right",
+		"that's right",
+		"affirmative",
+		"confirm",
+		"confirmed",
+		"go ahead",
+		"do it",
+		"please do",
+		"okay",
+		"ok",
+		"absolutely",
+		"definitely",
+		"sounds good",
+		"no problem",
+	}
+}
+
+// DefaultNoPhrases returns the phrases confirm_with_user accepts as no.
+func DefaultNoPhrases() []string {
+	return []string{
+		"no",
+		"nope",
+		"nah",
+		"negative",
+		"don't",
+		"do not",
+		"don't do it",
+		"not yet",
+		"cancel",
+		"stop",
+		"wait",
+		"hold on",
+		"wrong",
+		"incorrect",
+	}
+}
+
 // DefaultConfig returns a Config with sensible defaults.
 func DefaultConfig() *Config {
 	return &Config{
-		Port:                 3333,
-		PhoneProvider:        "twilio",
-		TTSProvider:          ProviderElevenLabs, // Default to ElevenLabs for TTS
-		STTProvider:          ProviderDeepgram,   // Default to Deepgram for STT
-		TTSVoice:             "Rachel",           // ElevenLabs default voice
-		TTSModel:             "eleven_turbo_v2_5",
-		STTModel:             "nova-2",
-		STTLanguage:          "en-US",
-		STTSilenceDurationMS: 800,
-		TranscriptTimeoutMS:  180000, // 3 minutes
-		WhatsAppDBPath:       "./whatsapp.db",
-		EnableRecording:      false,
-		SMSFallbackEnabled:   false,
-		SMSFallbackMessage:   "I tried calling but couldn't reach you. Here's my message: {message}",
-		SMSEnabled:           false,
-		WebhookEnabled:       false,
-		WebhookPort:          3334,
+		Port:                    3333,
+		Transport:               TransportHTTP,
+		ServeRestarts:           5,
+		ServeRestartBackoffMS:   1000,
+		PhoneProvider:           "twilio",
+		PreferredCodec:          CodecMulaw,
+		TTSProvider:             ProviderElevenLabs, // Default to ElevenLabs for TTS
+		STTProvider:             ProviderDeepgram,   // Default to Deepgram for STT
+		TTSVoice:                "Rachel",           // ElevenLabs default voice
+		TTSModel:                "eleven_turbo_v2_5",
+		SpeakingRate:            1.0,
+		TTSStreamRetries:        1,
+		TrimSilenceThreshold:    DefaultTrimSilenceThreshold,
+		STTContextKeywordMax:    DefaultSTTContextKeywordMax,
+		TrimSilencePadMS:        DefaultTrimSilencePadMS,
+		TTSCacheMaxBytes:        DefaultTTSCacheMaxBytes,
+		STTModel:                "nova-2",
+		STTLanguage:             "en-US",
+		STTSilenceDurationMS:    800,
+		AdaptiveSilenceMinMS:    DefaultAdaptiveSilenceMinMS,
+		AdaptiveSilenceMaxMS:    DefaultAdaptiveSilenceMaxMS,
+		Tunnel:                  TunnelNgrok,
+		TranscriptTimeoutMS:     180000, // 3 minutes
+		TunnelReadyTimeoutMS:    120000, // 2 minutes
+		PostSpeechDelayMS:       0,
+		EchoGuardMS:             0,
+		StatusPollMS:            500,
+		TranscriptAssistantRole: "assistant",
+		TranscriptUserRole:      "user",
+		RedialDelayMS:           DefaultRedialDelayMS,
+		MaxCallLifetimeMS:       DefaultMaxCallLifetimeMS,
+		GreetingWaitMS:          3000,
+		GreetingSilenceMS:       500,
+		ConnectToneHz:           DefaultConnectToneHz,
+		ConnectToneMS:           DefaultConnectToneMS,
+		GoodbyePhrases:          DefaultGoodbyePhrases(),
+		YesPhrases:              DefaultYesPhrases(),
+		NoPhrases:               DefaultNoPhrases(),
+		ConfirmReprompts:        DefaultConfirmReprompts,
+		CallCostPerMinuteUSD:    DefaultCallCostPerMinuteUSD,
+		WhatsAppDBPath:          "./whatsapp.db",
+		EnableRecording:         false,
+		RecordingConsent:        DefaultRecordingConsent,
+		SMSFallbackEnabled:      false,
+		SMSFallbackMessage:      "I tried calling but couldn't reach you. Here's my message: {message}",
+		SMSEnabled:              false,
+		WebhookEnabled:          false,
+		WebhookPort:             3334,
 	}
 }
 
@@ -122,13 +499,21 @@ func DefaultConfig() *Config {
 // Supports both AGENTCOMMS_ and legacy AGENTCALL_ prefixes with AGENTCOMMS_ taking precedence.
 func LoadFromEnv() (*Config, error) {
 	cfg := DefaultConfig()
+	var invalid envErrors
 
 	// Server port
-	if port := getEnvWithFallback("AGENTCOMMS_PORT", "AGENTCALL_PORT"); port != "" {
-		var p int
-		if _, err := fmt.Sscanf(port, "%d", &p); err == nil {
-			cfg.Port = p
-		}
+	invalid.envInt(&cfg.Port, "AGENTCOMMS_PORT", "AGENTCALL_PORT")
+	cfg.BindAddr = getEnvWithFallback("AGENTCOMMS_BIND_ADDR", "AGENTCALL_BIND_ADDR")
+	if transport := getEnvWithFallback("AGENTCOMMS_TRANSPORT", "AGENTCALL_TRANSPORT"); transport != "" {
+		cfg.Transport = transport
+	}
+	invalid.envInt(&cfg.ServeRestarts, "AGENTCOMMS_SERVE_RESTARTS", "AGENTCALL_SERVE_RESTARTS")
+	invalid.envInt(&cfg.ServeRestartBackoffMS, "AGENTCOMMS_SERVE_RESTART_BACKOFF_MS", "AGENTCALL_SERVE_RESTART_BACKOFF_MS")
+	if names := getEnvWithFallback("AGENTCOMMS_ENABLED_TOOLS", "AGENTCALL_ENABLED_TOOLS"); names != "" {
+		cfg.EnabledTools = splitList(names)
+	}
+	if origins := getEnvWithFallback("AGENTCOMMS_CORS_ORIGINS", "AGENTCALL_CORS_ORIGINS"); origins != "" {
+		cfg.CORSOrigins = splitList(origins)
 	}
 
 	// Phone provider
@@ -139,17 +524,66 @@ func LoadFromEnv() (*Config, error) {
 	cfg.PhoneAuthToken = getEnvWithFallback("AGENTCOMMS_PHONE_AUTH_TOKEN", "AGENTCALL_PHONE_AUTH_TOKEN")
 	cfg.PhoneNumber = getEnvWithFallback("AGENTCOMMS_PHONE_NUMBER", "AGENTCALL_PHONE_NUMBER")
 	cfg.UserPhoneNumber = getEnvWithFallback("AGENTCOMMS_USER_PHONE_NUMBER", "AGENTCALL_USER_PHONE_NUMBER")
+	cfg.CallerIDName = getEnvWithFallback("AGENTCOMMS_CALLER_ID_NAME", "AGENTCALL_CALLER_ID_NAME")
+	cfg.TwilioRegion = getEnvWithFallback("AGENTCOMMS_TWILIO_REGION", "AGENTCALL_TWILIO_REGION")
+	cfg.TwilioEdge = getEnvWithFallback("AGENTCOMMS_TWILIO_EDGE", "AGENTCALL_TWILIO_EDGE")
+	cfg.TwiMLTemplate = getEnvWithFallback("AGENTCOMMS_TWIML_TEMPLATE", "AGENTCALL_TWIML_TEMPLATE")
+	if codec := getEnvWithFallback("AGENTCOMMS_PREFERRED_CODEC", "AGENTCALL_PREFERRED_CODEC"); codec != "" {
+		cfg.PreferredCodec = codec
+	}
+	cfg.QuietHours = getEnvWithFallback("AGENTCOMMS_QUIET_HOURS", "AGENTCALL_QUIET_HOURS")
+	invalid.envInt(&cfg.MaxCallsPerDay, "AGENTCOMMS_MAX_CALLS_PER_DAY", "AGENTCALL_MAX_CALLS_PER_DAY")
+	invalid.envFloat(&cfg.MaxDailyCostUSD, "AGENTCOMMS_MAX_DAILY_COST_USD", "AGENTCALL_MAX_DAILY_COST_USD")
+	invalid.envFloat(&cfg.CallCostPerMinuteUSD, "AGENTCOMMS_CALL_COST_PER_MINUTE_USD", "AGENTCALL_CALL_COST_PER_MINUTE_USD")
+	invalid.envInt(&cfg.MaxMessageChars, "AGENTCOMMS_MAX_MESSAGE_CHARS", "AGENTCALL_MAX_MESSAGE_CHARS")
+	invalid.envInt(&cfg.MaxTTSCharsPerCall, "AGENTCOMMS_MAX_TTS_CHARS_PER_CALL", "AGENTCALL_MAX_TTS_CHARS_PER_CALL")
+	invalid.envInt(&cfg.MaxSTTSecondsPerCall, "AGENTCOMMS_MAX_STT_SECONDS_PER_CALL", "AGENTCALL_MAX_STT_SECONDS_PER_CALL")
+	invalid.envInt(&cfg.DescriptionRefreshMS, "AGENTCOMMS_DESCRIPTION_REFRESH_MS", "AGENTCALL_DESCRIPTION_REFRESH_MS")
+	if numbers := getEnvWithFallback("AGENTCOMMS_CONFERENCE_NUMBERS", "AGENTCALL_CONFERENCE_NUMBERS"); numbers != "" {
+		cfg.ConferenceNumbers = splitList(numbers)
+	}
+	cfg.CallStorePath = getEnvWithFallback("AGENTCOMMS_CALL_STORE_PATH", "AGENTCALL_CALL_STORE_PATH")
+	cfg.TranscriptSink = getEnvWithFallback("AGENTCOMMS_TRANSCRIPT_SINK", "AGENTCALL_TRANSCRIPT_SINK")
+	if role := getEnvWithFallback("AGENTCOMMS_TRANSCRIPT_ASSISTANT_ROLE", "AGENTCALL_TRANSCRIPT_ASSISTANT_ROLE"); role != "" {
+		cfg.TranscriptAssistantRole = role
+	}
+	if role := getEnvWithFallback("AGENTCOMMS_TRANSCRIPT_USER_ROLE", "AGENTCALL_TRANSCRIPT_USER_ROLE"); role != "" {
+		cfg.TranscriptUserRole = role
+	}
+	cfg.CallEndedWebhook = getEnvWithFallback("AGENTCOMMS_CALL_ENDED_WEBHOOK", "AGENTCALL_CALL_ENDED_WEBHOOK")
+	cfg.CallEndedWebhookSecret = getEnvWithFallback("AGENTCOMMS_CALL_ENDED_WEBHOOK_SECRET", "AGENTCALL_CALL_ENDED_WEBHOOK_SECRET")
+	cfg.TriggerToken = getEnvWithFallback("AGENTCOMMS_TRIGGER_TOKEN", "AGENTCALL_TRIGGER_TOKEN")
+	cfg.Sentiment = getEnvWithFallback("AGENTCOMMS_SENTIMENT", "AGENTCALL_SENTIMENT")
+	cfg.PronunciationDict = getEnvWithFallback("AGENTCOMMS_PRONUNCIATION_DICT", "AGENTCALL_PRONUNCIATION_DICT")
 
 	// Voice enhancements
 	if enabled := os.Getenv("AGENTCOMMS_ENABLE_RECORDING"); enabled == "true" || enabled == "1" {
 		cfg.EnableRecording = true
 	}
+	if consent := getEnvWithFallback("AGENTCOMMS_RECORDING_CONSENT", "AGENTCALL_RECORDING_CONSENT"); consent != "" {
+		cfg.RecordingConsent = consent
+	}
+	cfg.RecordingConsentVoice = getEnvWithFallback("AGENTCOMMS_RECORDING_CONSENT_VOICE", "AGENTCALL_RECORDING_CONSENT_VOICE")
+	cfg.AudioDumpDir = getEnvWithFallback("AGENTCOMMS_AUDIO_DUMP_DIR", "AGENTCALL_AUDIO_DUMP_DIR")
+	cfg.AudioDir = getEnvWithFallback("AGENTCOMMS_AUDIO_DIR", "AGENTCALL_AUDIO_DIR")
 	if enabled := os.Getenv("AGENTCOMMS_SMS_FALLBACK_ENABLED"); enabled == "true" || enabled == "1" {
 		cfg.SMSFallbackEnabled = true
 	}
 	if msg := os.Getenv("AGENTCOMMS_SMS_FALLBACK_MESSAGE"); msg != "" {
 		cfg.SMSFallbackMessage = msg
 	}
+	if enabled := getEnvWithFallback("AGENTCOMMS_REQUIRE_ACCEPT", "AGENTCALL_REQUIRE_ACCEPT"); enabled == "true" || enabled == "1" {
+		cfg.RequireAccept = true
+	}
+	if enabled := getEnvWithFallback("AGENTCOMMS_REQUIRE_CONFIRMATION", "AGENTCALL_REQUIRE_CONFIRMATION"); enabled == "true" || enabled == "1" {
+		cfg.RequireConfirmation = true
+	}
+	if enabled := getEnvWithFallback("AGENTCOMMS_KEEPALIVE", "AGENTCALL_KEEPALIVE"); enabled == "true" || enabled == "1" {
+		cfg.KeepAlive = true
+	}
+	if enabled := getEnvWithFallback("AGENTCOMMS_REQUIRE_PROVIDERS", "AGENTCALL_REQUIRE_PROVIDERS"); enabled == "true" || enabled == "1" {
+		cfg.RequireProviders = true
+	}
 
 	// SMS transport
 	if enabled := os.Getenv("AGENTCOMMS_SMS_ENABLED"); enabled == "true" || enabled == "1" {
@@ -160,12 +594,7 @@ func LoadFromEnv() (*Config, error) {
 	if enabled := os.Getenv("AGENTCOMMS_WEBHOOK_ENABLED"); enabled == "true" || enabled == "1" {
 		cfg.WebhookEnabled = true
 	}
-	if port := os.Getenv("AGENTCOMMS_WEBHOOK_PORT"); port != "" {
-		var p int
-		if _, err := fmt.Sscanf(port, "%d", &p); err == nil {
-			cfg.WebhookPort = p
-		}
-	}
+	invalid.envInt(&cfg.WebhookPort, "AGENTCOMMS_WEBHOOK_PORT", "")
 
 	// Voice provider selection
 	if ttsProvider := getEnvWithFallback("AGENTCOMMS_TTS_PROVIDER", "AGENTCALL_TTS_PROVIDER"); ttsProvider != "" {
@@ -197,8 +626,29 @@ func LoadFromEnv() (*Config, error) {
 	if voice := getEnvWithFallback("AGENTCOMMS_TTS_VOICE", "AGENTCALL_TTS_VOICE"); voice != "" {
 		cfg.TTSVoice = voice
 	}
+	invalid.envFloat(&cfg.SpeakingRate, "AGENTCOMMS_SPEAKING_RATE", "AGENTCALL_SPEAKING_RATE")
+	invalid.envInt(&cfg.TTSStreamRetries, "AGENTCOMMS_TTS_STREAM_RETRIES", "AGENTCALL_TTS_STREAM_RETRIES")
+	invalid.envInt(&cfg.TTSContinuityTurns, "AGENTCOMMS_TTS_CONTINUITY", "AGENTCALL_TTS_CONTINUITY")
+	if enabled := getEnvWithFallback("AGENTCOMMS_TRIM_SILENCE", "AGENTCALL_TRIM_SILENCE"); enabled == "true" || enabled == "1" {
+		cfg.TrimSilence = true
+	}
+	invalid.envInt(&cfg.TrimSilenceThreshold, "AGENTCOMMS_TRIM_SILENCE_THRESHOLD", "AGENTCALL_TRIM_SILENCE_THRESHOLD")
+	invalid.envInt(&cfg.TrimSilencePadMS, "AGENTCOMMS_TRIM_SILENCE_PAD_MS", "AGENTCALL_TRIM_SILENCE_PAD_MS")
+	invalid.envInt(&cfg.TTSFrameMS, "AGENTCOMMS_TTS_FRAME_MS", "AGENTCALL_TTS_FRAME_MS")
+	if enabled := getEnvWithFallback("AGENTCOMMS_TTS_PREFETCH", "AGENTCALL_TTS_PREFETCH"); enabled == "true" || enabled == "1" {
+		cfg.TTSPrefetch = true
+	}
+	if enabled := getEnvWithFallback("AGENTCOMMS_TTS_CACHE", "AGENTCALL_TTS_CACHE"); enabled == "true" || enabled == "1" {
+		cfg.TTSCache = true
+	}
+	invalid.envInt(&cfg.TTSCacheMaxBytes, "AGENTCOMMS_TTS_CACHE_MAX_BYTES", "AGENTCALL_TTS_CACHE_MAX_BYTES")
+	if enabled := getEnvWithFallback("AGENTCOMMS_TTS_ENABLE_TAGS", "AGENTCALL_TTS_ENABLE_TAGS"); enabled == "true" || enabled == "1" {
+		cfg.TTSEnableTags = true
+	}
 	if model := getEnvWithFallback("AGENTCOMMS_TTS_MODEL", "AGENTCALL_TTS_MODEL"); model != "" {
 		cfg.TTSModel = model
+	} else if cfg.TTSEnableTags && cfg.TTSProvider == ProviderElevenLabs {
+		cfg.TTSModel = ElevenLabsTagModel
 	}
 
 	// STT settings
@@ -208,12 +658,40 @@ func LoadFromEnv() (*Config, error) {
 	if lang := getEnvWithFallback("AGENTCOMMS_STT_LANGUAGE", "AGENTCALL_STT_LANGUAGE"); lang != "" {
 		cfg.STTLanguage = lang
 	}
-	if silence := getEnvWithFallback("AGENTCOMMS_STT_SILENCE_DURATION_MS", "AGENTCALL_STT_SILENCE_DURATION_MS"); silence != "" {
-		var s int
-		if _, err := fmt.Sscanf(silence, "%d", &s); err == nil {
-			cfg.STTSilenceDurationMS = s
-		}
+	invalid.envInt(&cfg.STTSilenceDurationMS, "AGENTCOMMS_STT_SILENCE_DURATION_MS", "AGENTCALL_STT_SILENCE_DURATION_MS")
+	invalid.envInt(&cfg.AggregateFinalsMS, "AGENTCOMMS_AGGREGATE_FINALS_MS", "AGENTCALL_AGGREGATE_FINALS_MS")
+	if enabled := getEnvWithFallback("AGENTCOMMS_STT_ADAPTIVE_SILENCE", "AGENTCALL_STT_ADAPTIVE_SILENCE"); enabled == "true" || enabled == "1" {
+		cfg.AdaptiveSilence = true
+	}
+	invalid.envInt(&cfg.AdaptiveSilenceMinMS, "AGENTCOMMS_STT_ADAPTIVE_SILENCE_MIN_MS", "AGENTCALL_STT_ADAPTIVE_SILENCE_MIN_MS")
+	invalid.envInt(&cfg.AdaptiveSilenceMaxMS, "AGENTCOMMS_STT_ADAPTIVE_SILENCE_MAX_MS", "AGENTCALL_STT_ADAPTIVE_SILENCE_MAX_MS")
+	invalid.envInt(&cfg.MaxUtteranceMS, "AGENTCOMMS_MAX_UTTERANCE_MS", "AGENTCALL_MAX_UTTERANCE_MS")
+	if enabled := getEnvWithFallback("AGENTCOMMS_STT_PERSIST_CONNECTION", "AGENTCALL_STT_PERSIST_CONNECTION"); enabled == "true" || enabled == "1" {
+		cfg.STTPersistConnection = true
+	}
+	if enabled := getEnvWithFallback("AGENTCOMMS_STT_CONTEXT_KEYWORDS", "AGENTCALL_STT_CONTEXT_KEYWORDS"); enabled == "true" || enabled == "1" {
+		cfg.STTContextKeywords = true
+	}
+	invalid.envInt(&cfg.STTContextKeywordMax, "AGENTCOMMS_STT_CONTEXT_KEYWORD_MAX", "AGENTCALL_STT_CONTEXT_KEYWORD_MAX")
+	if enabled := getEnvWithFallback("AGENTCOMMS_STT_LANGUAGE_DETECTION", "AGENTCALL_STT_LANGUAGE_DETECTION"); enabled == "true" || enabled == "1" {
+		cfg.LanguageDetection = true
+	}
+	if phrases := getEnvWithFallback("AGENTCOMMS_GOODBYE_PHRASES", "AGENTCALL_GOODBYE_PHRASES"); phrases != "" {
+		cfg.GoodbyePhrases = splitList(phrases)
+	}
+	if phrases := getEnvWithFallback("AGENTCOMMS_YES_PHRASES", "AGENTCALL_YES_PHRASES"); phrases != "" {
+		cfg.YesPhrases = splitList(phrases)
 	}
+	if phrases := getEnvWithFallback("AGENTCOMMS_NO_PHRASES", "AGENTCALL_NO_PHRASES"); phrases != "" {
+		cfg.NoPhrases = splitList(phrases)
+	}
+	invalid.envInt(&cfg.ConfirmReprompts, "AGENTCOMMS_CONFIRM_REPROMPTS", "AGENTCALL_CONFIRM_REPROMPTS")
+
+	// Tunnel selection
+	if tunnel := getEnvWithFallback("AGENTCOMMS_TUNNEL", "AGENTCALL_TUNNEL"); tunnel != "" {
+		cfg.Tunnel = tunnel
+	}
+	cfg.PublicURL = strings.TrimSuffix(getEnvWithFallback("AGENTCOMMS_PUBLIC_URL", "AGENTCALL_PUBLIC_URL"), "/")
 
 	// ngrok
 	cfg.NgrokAuthToken = getEnvWithFallback("AGENTCOMMS_NGROK_AUTHTOKEN", "AGENTCALL_NGROK_AUTHTOKEN")
@@ -222,13 +700,32 @@ func LoadFromEnv() (*Config, error) {
 	}
 	cfg.NgrokDomain = getEnvWithFallback("AGENTCOMMS_NGROK_DOMAIN", "AGENTCALL_NGROK_DOMAIN")
 
+	// cloudflared
+	cfg.CloudflaredPath = getEnvWithFallback("AGENTCOMMS_CLOUDFLARED_PATH", "AGENTCALL_CLOUDFLARED_PATH")
+	cfg.CloudflaredToken = getEnvWithFallback("AGENTCOMMS_CLOUDFLARED_TOKEN", "AGENTCALL_CLOUDFLARED_TOKEN")
+	cfg.CloudflaredHostname = getEnvWithFallback("AGENTCOMMS_CLOUDFLARED_HOSTNAME", "AGENTCALL_CLOUDFLARED_HOSTNAME")
+	invalid.envInt(&cfg.TunnelReadyTimeoutMS, "AGENTCOMMS_TUNNEL_READY_TIMEOUT_MS", "AGENTCALL_TUNNEL_READY_TIMEOUT_MS")
+
 	// Transcript timeout
-	if timeout := getEnvWithFallback("AGENTCOMMS_TRANSCRIPT_TIMEOUT_MS", "AGENTCALL_TRANSCRIPT_TIMEOUT_MS"); timeout != "" {
-		var t int
-		if _, err := fmt.Sscanf(timeout, "%d", &t); err == nil {
-			cfg.TranscriptTimeoutMS = t
-		}
+	invalid.envInt(&cfg.TranscriptTimeoutMS, "AGENTCOMMS_TRANSCRIPT_TIMEOUT_MS", "AGENTCALL_TRANSCRIPT_TIMEOUT_MS")
+	invalid.envInt(&cfg.PostSpeechDelayMS, "AGENTCOMMS_POST_SPEECH_DELAY_MS", "AGENTCALL_POST_SPEECH_DELAY_MS")
+	invalid.envInt(&cfg.EchoGuardMS, "AGENTCOMMS_ECHO_GUARD_MS", "AGENTCALL_ECHO_GUARD_MS")
+	invalid.envInt(&cfg.AMDWaitMS, "AGENTCOMMS_AMD_WAIT_MS", "AGENTCALL_AMD_WAIT_MS")
+	invalid.envInt(&cfg.StatusPollMS, "AGENTCOMMS_STATUS_POLL_MS", "AGENTCALL_STATUS_POLL_MS")
+	invalid.envInt(&cfg.ResumeWindowMS, "AGENTCOMMS_RESUME_WINDOW_MS", "AGENTCALL_RESUME_WINDOW_MS")
+	invalid.envInt(&cfg.MaxCallLifetimeMS, "AGENTCOMMS_MAX_CALL_LIFETIME_MS", "AGENTCALL_MAX_CALL_LIFETIME_MS")
+	invalid.envInt(&cfg.RedialAttempts, "AGENTCOMMS_REDIAL_ATTEMPTS", "AGENTCALL_REDIAL_ATTEMPTS")
+	invalid.envInt(&cfg.RedialDelayMS, "AGENTCOMMS_REDIAL_DELAY_MS", "AGENTCALL_REDIAL_DELAY_MS")
+	if enabled := getEnvWithFallback("AGENTCOMMS_WAIT_FOR_GREETING", "AGENTCALL_WAIT_FOR_GREETING"); enabled == "true" || enabled == "1" {
+		cfg.WaitForGreeting = true
+	}
+	invalid.envInt(&cfg.GreetingWaitMS, "AGENTCOMMS_GREETING_WAIT_MS", "AGENTCALL_GREETING_WAIT_MS")
+	invalid.envInt(&cfg.GreetingSilenceMS, "AGENTCOMMS_GREETING_SILENCE_MS", "AGENTCALL_GREETING_SILENCE_MS")
+	if enabled := getEnvWithFallback("AGENTCOMMS_CONNECT_TONE", "AGENTCALL_CONNECT_TONE"); enabled == "true" || enabled == "1" {
+		cfg.ConnectTone = true
 	}
+	invalid.envInt(&cfg.ConnectToneHz, "AGENTCOMMS_CONNECT_TONE_HZ", "AGENTCALL_CONNECT_TONE_HZ")
+	invalid.envInt(&cfg.ConnectToneMS, "AGENTCOMMS_CONNECT_TONE_MS", "AGENTCALL_CONNECT_TONE_MS")
 
 	// Chat providers - WhatsApp
 	if enabled := os.Getenv("AGENTCOMMS_WHATSAPP_ENABLED"); enabled == "true" || enabled == "1" {
@@ -304,12 +801,12 @@ func LoadFromEnv() (*Config, error) {
 		cfg.IRCPassword = os.Getenv("IRC_PASSWORD") // fallback
 	}
 	if channels := os.Getenv("AGENTCOMMS_IRC_CHANNELS"); channels != "" {
-		cfg.IRCChannels = splitChannels(channels)
+		cfg.IRCChannels = splitList(channels)
 	}
 	// Default to TLS enabled unless explicitly disabled
 	cfg.IRCUseTLS = os.Getenv("AGENTCOMMS_IRC_USE_TLS") != "false"
 
-	return cfg, cfg.Validate()
+	return cfg, errors.Join(invalid.err(), cfg.Validate())
 }
 
 // getEnvWithFallback returns the value of the primary env var, or falls back to secondary.
@@ -323,17 +820,69 @@ func getEnvWithFallback(primary, secondary string) string {
 	return ""
 }
 
-// splitChannels parses a comma-separated list of IRC channels.
-func splitChannels(s string) []string {
+// envErrors collects numeric environment variables that failed to parse,
+// so a typo is reported instead of silently keeping the default.
+type envErrors []string
+
+// lookupEnv returns the name and value of the first of primary and secondary
+// that is set.
+func lookupEnv(primary, secondary string) (string, string) {
+	if val := os.Getenv(primary); val != "" {
+		return primary, val
+	}
+	if secondary != "" {
+		return secondary, os.Getenv(secondary)
+	}
+	return primary, ""
+}
+
+// envInt sets dst from an integer environment variable, if set.
+func (e *envErrors) envInt(dst *int, primary, secondary string) {
+	name, val := lookupEnv(primary, secondary)
+	if val == "" {
+		return
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(val))
+	if err != nil {
+		*e = append(*e, fmt.Sprintf("%s=%q is not an integer", name, val))
+		return
+	}
+	*dst = n
+}
+
+// envFloat sets dst from a numeric environment variable, if set.
+func (e *envErrors) envFloat(dst *float64, primary, secondary string) {
+	name, val := lookupEnv(primary, secondary)
+	if val == "" {
+		return
+	}
+	f, err := strconv.ParseFloat(strings.TrimSpace(val), 64)
+	if err != nil {
+		*e = append(*e, fmt.Sprintf("%s=%q is not a number", name, val))
+		return
+	}
+	*dst = f
+}
+
+// err returns the collected parse errors, or nil if there are none.
+func (e envErrors) err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return fmt.Errorf("invalid environment variables: %v", []string(e))
+}
+
+// splitList parses a comma-separated list, dropping empty entries.
+func splitList(s string) []string {
 	parts := strings.Split(s, ",")
-	channels := make([]string, 0, len(parts))
+	items := make([]string, 0, len(parts))
 	for _, p := range parts {
 		p = strings.TrimSpace(p)
 		if p != "" {
-			channels = append(channels, p)
+			items = append(items, p)
 		}
 	}
-	return channels
+	return items
 }
 
 // Validate checks that required configuration is present.
@@ -356,7 +905,44 @@ func (c *Config) Validate() error {
 			missing = append(missing, "AGENTCOMMS_USER_PHONE_NUMBER")
 		}
 
+		if _, err := ParseQuietHours(c.QuietHours); err != nil {
+			errors = append(errors, err.Error())
+		}
+		if c.MaxCallsPerDay < 0 {
+			errors = append(errors, fmt.Sprintf("invalid max calls per day %d (must be 0 or more)", c.MaxCallsPerDay))
+		}
+		if c.MaxDailyCostUSD < 0 {
+			errors = append(errors, fmt.Sprintf("invalid max daily cost %g (must be 0 or more)", c.MaxDailyCostUSD))
+		}
+		if c.CallCostPerMinuteUSD <= 0 {
+			errors = append(errors, fmt.Sprintf("invalid call cost per minute %g (must be more than 0)", c.CallCostPerMinuteUSD))
+		}
+		if c.MaxMessageChars < 0 {
+			errors = append(errors, fmt.Sprintf("invalid max message chars %d (must be 0 or more)", c.MaxMessageChars))
+		}
+		if c.MaxTTSCharsPerCall < 0 {
+			errors = append(errors, fmt.Sprintf("invalid max TTS chars per call %d (must be 0 or more)", c.MaxTTSCharsPerCall))
+		}
+		if c.MaxSTTSecondsPerCall < 0 {
+			errors = append(errors, fmt.Sprintf("invalid max STT seconds per call %d (must be 0 or more)", c.MaxSTTSecondsPerCall))
+		}
+
+		if err := validateTwilioLocation(c.TwilioRegion, c.TwilioEdge); err != nil {
+			errors = append(errors, err.Error())
+		}
+		errors = append(errors, validateTenants(c.Tenants)...)
+		if len(c.Tenants) > 0 && c.Transport == TransportStdio {
+			// Tenants are identified by a request header, which stdio lacks
+			errors = append(errors, "tenants require AGENTCOMMS_TRANSPORT=http")
+		}
+		if c.PreferredCodec != CodecMulaw && c.PreferredCodec != CodecOpus {
+			errors = append(errors, fmt.Sprintf("invalid preferred codec %q (must be %q or %q)", c.PreferredCodec, CodecMulaw, CodecOpus))
+		}
+
 		// Validate provider selection
+		if c.PhoneProvider != "twilio" && c.PhoneProvider != "telnyx" {
+			errors = append(errors, fmt.Sprintf("invalid phone provider %q (must be \"twilio\" or \"telnyx\")", c.PhoneProvider))
+		}
 		validProviders := map[string]bool{ProviderElevenLabs: true, ProviderDeepgram: true, ProviderOpenAI: true}
 		if !validProviders[c.TTSProvider] {
 			errors = append(errors, fmt.Sprintf("invalid TTS provider %q (must be %q, %q, or %q)", c.TTSProvider, ProviderElevenLabs, ProviderDeepgram, ProviderOpenAI))
@@ -365,6 +951,43 @@ func (c *Config) Validate() error {
 			errors = append(errors, fmt.Sprintf("invalid STT provider %q (must be %q, %q, or %q)", c.STTProvider, ProviderElevenLabs, ProviderDeepgram, ProviderOpenAI))
 		}
 
+		if c.SpeakingRate < MinSpeakingRate || c.SpeakingRate > MaxSpeakingRate {
+			errors = append(errors, fmt.Sprintf("invalid speaking rate %g (must be between %g and %g)", c.SpeakingRate, MinSpeakingRate, MaxSpeakingRate))
+		}
+		if c.TrimSilenceThreshold < 0 || c.TrimSilenceThreshold > MaxTrimSilenceThreshold {
+			errors = append(errors, fmt.Sprintf("invalid trim silence threshold %d (must be between 0 and %d)", c.TrimSilenceThreshold, MaxTrimSilenceThreshold))
+		}
+		if c.TTSCache && c.TTSCacheMaxBytes <= 0 {
+			errors = append(errors, fmt.Sprintf("invalid AGENTCOMMS_TTS_CACHE_MAX_BYTES %d (must be more than 0)", c.TTSCacheMaxBytes))
+		}
+		if c.STTContextKeywordMax < 1 || c.STTContextKeywordMax > MaxSTTContextKeywordMax {
+			errors = append(errors, fmt.Sprintf("invalid AGENTCOMMS_STT_CONTEXT_KEYWORD_MAX %d (must be between 1 and %d)", c.STTContextKeywordMax, MaxSTTContextKeywordMax))
+		}
+		if c.ConfirmReprompts < 0 || c.ConfirmReprompts > MaxConfirmReprompts {
+			errors = append(errors, fmt.Sprintf("invalid AGENTCOMMS_CONFIRM_REPROMPTS %d (must be between 0 and %d)", c.ConfirmReprompts, MaxConfirmReprompts))
+		}
+		if c.RedialAttempts < 0 || c.RedialAttempts > MaxRedialAttempts {
+			errors = append(errors, fmt.Sprintf("invalid AGENTCOMMS_REDIAL_ATTEMPTS %d (must be between 0 and %d)", c.RedialAttempts, MaxRedialAttempts))
+		}
+		if c.ConnectToneHz < MinConnectToneHz || c.ConnectToneHz > MaxConnectToneHz {
+			errors = append(errors, fmt.Sprintf("invalid AGENTCOMMS_CONNECT_TONE_HZ %d (must be between %d and %d)", c.ConnectToneHz, MinConnectToneHz, MaxConnectToneHz))
+		}
+
+		errors = append(errors, validateMillis(c.msSettings(), fromEnv)...)
+		if c.AdaptiveSilenceMinMS > c.AdaptiveSilenceMaxMS {
+			errors = append(errors, fmt.Sprintf("invalid AGENTCOMMS_STT_ADAPTIVE_SILENCE_MIN_MS %d (must not exceed AGENTCOMMS_STT_ADAPTIVE_SILENCE_MAX_MS %d)", c.AdaptiveSilenceMinMS, c.AdaptiveSilenceMaxMS))
+		}
+
+		if c.CallEndedWebhook != "" && !strings.HasPrefix(c.CallEndedWebhook, "http://") && !strings.HasPrefix(c.CallEndedWebhook, "https://") {
+			errors = append(errors, fmt.Sprintf("invalid call-ended webhook %q (must be an http(s) URL)", c.CallEndedWebhook))
+		}
+		if c.TranscriptAssistantRole == c.TranscriptUserRole {
+			errors = append(errors, fmt.Sprintf("invalid transcript roles: assistant and user are both %q", c.TranscriptUserRole))
+		}
+		if c.Sentiment != "" && c.Sentiment != "local" && !strings.HasPrefix(c.Sentiment, "http://") && !strings.HasPrefix(c.Sentiment, "https://") {
+			errors = append(errors, fmt.Sprintf("invalid sentiment %q (must be \"local\" or an http(s) URL)", c.Sentiment))
+		}
+
 		// Check API keys based on selected providers
 		if c.NeedsElevenLabs() && c.ElevenLabsAPIKey == "" {
 			missing = append(missing, "AGENTCOMMS_ELEVENLABS_API_KEY or ELEVENLABS_API_KEY")
@@ -376,12 +999,44 @@ func (c *Config) Validate() error {
 			missing = append(missing, "AGENTCOMMS_OPENAI_API_KEY or OPENAI_API_KEY")
 		}
 
-		// ngrok required for voice
-		if c.NgrokAuthToken == "" {
-			missing = append(missing, "AGENTCOMMS_NGROK_AUTHTOKEN or NGROK_AUTHTOKEN")
+		// A public URL or tunnel is required for voice webhooks; only check the selected one
+		switch {
+		case c.PublicURL != "":
+			if err := validatePublicURL(c.PublicURL); err != nil {
+				errors = append(errors, err.Error())
+			}
+		case c.Transport == TransportStdio:
+			// No tunnel is started over stdio
+			missing = append(missing, "AGENTCOMMS_PUBLIC_URL (required with AGENTCOMMS_TRANSPORT=stdio)")
+		case c.Tunnel == TunnelNgrok:
+			if c.NgrokAuthToken == "" {
+				missing = append(missing, "AGENTCOMMS_NGROK_AUTHTOKEN or NGROK_AUTHTOKEN")
+			}
+		case c.Tunnel == TunnelCloudflared:
+			if c.CloudflaredToken != "" && c.CloudflaredHostname == "" {
+				missing = append(missing, "AGENTCOMMS_CLOUDFLARED_HOSTNAME")
+			}
+		default:
+			errors = append(errors, fmt.Sprintf("invalid tunnel %q (must be %q or %q)", c.Tunnel, TunnelNgrok, TunnelCloudflared))
 		}
 	}
 
+	if c.ServeRestarts < 0 {
+		errors = append(errors, fmt.Sprintf("invalid AGENTCOMMS_SERVE_RESTARTS %d (must be 0 or more)", c.ServeRestarts))
+	}
+	errors = append(errors, validateMillis([]msSetting{{restartBackoffMS, c.ServeRestartBackoffMS}}, fromEnv)...)
+	if err := validateBindAddr(c.BindAddr); err != nil {
+		errors = append(errors, err.Error())
+	}
+	for _, origin := range c.CORSOrigins {
+		if err := validateCORSOrigin(origin); err != nil {
+			errors = append(errors, err.Error())
+		}
+	}
+	if c.Transport != TransportHTTP && c.Transport != TransportStdio {
+		errors = append(errors, fmt.Sprintf("invalid transport %q (must be %q or %q)", c.Transport, TransportHTTP, TransportStdio))
+	}
+
 	// Chat provider validation
 	if c.DiscordEnabled && c.DiscordToken == "" {
 		missing = append(missing, "AGENTCOMMS_DISCORD_TOKEN or DISCORD_TOKEN")
@@ -419,6 +1074,72 @@ func (c *Config) Validate() error {
 	return nil
 }
 
+// validatePublicURL checks that a static public URL is a well-formed https base URL.
+func validatePublicURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return fmt.Errorf("invalid public URL %q: %v", raw, err)
+	}
+	if u.Scheme != "https" || u.Host == "" {
+		return fmt.Errorf("invalid public URL %q (must be an https URL such as https://calls.example.com)", raw)
+	}
+	if u.RawQuery != "" || u.Fragment != "" {
+		return fmt.Errorf("invalid public URL %q (must not include a query or fragment)", raw)
+	}
+	return nil
+}
+
+// validateBindAddr checks that a bind address is empty, an IP address, or a
+// hostname.
+func validateBindAddr(host string) error {
+	if host == "" || net.ParseIP(strings.Trim(host, "[]")) != nil {
+		return nil
+	}
+	if len(host) > 253 {
+		return fmt.Errorf("invalid bind address %q (must be an IP address or hostname)", host)
+	}
+	for _, label := range strings.Split(strings.TrimSuffix(host, "."), ".") {
+		if !validHostLabel(label) {
+			return fmt.Errorf("invalid bind address %q (must be an IP address or hostname)", host)
+		}
+	}
+	return nil
+}
+
+// validateCORSOrigin checks that origin is "*" or a browser origin: an
+// http(s) scheme and host, with an optional port and nothing after it.
+func validateCORSOrigin(origin string) error {
+	if origin == "*" {
+		return nil
+	}
+	u, err := url.Parse(origin)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || strings.Contains(u.Host, "*") ||
+		u.User != nil || (u.Path != "" && u.Path != "/") || u.RawQuery != "" || u.Fragment != "" {
+		return fmt.Errorf("invalid CORS origin %q (must be \"*\" or a scheme and host such as https://dashboard.example.com)", origin)
+	}
+	return nil
+}
+
+// validHostLabel reports whether label is a valid hostname label: 1-63
+// letters, digits, and hyphens, not starting or ending with a hyphen.
+func validHostLabel(label string) bool {
+	if label == "" || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+		return false
+	}
+	for _, r := range label {
+		if (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') && (r < '0' || r > '9') && r != '-' {
+			return false
+		}
+	}
+	return true
+}
+
+// ListenAddr returns the address the MCP server listens on, e.g.
+// "127.0.0.1:3333", or ":3333" to listen on all interfaces.
+func (c *Config) ListenAddr() string {
+	return net.JoinHostPort(strings.Trim(c.BindAddr, "[]"), strconv.Itoa(c.Port))
+}
+
 // VoiceEnabled returns true if voice calling is configured.
 func (c *Config) VoiceEnabled() bool {
 	return c.PhoneAccountSID != "" || c.PhoneAuthToken != "" || c.PhoneNumber != ""
@@ -444,6 +1165,13 @@ func (c *Config) NeedsOpenAI() bool {
 	return c.TTSProvider == ProviderOpenAI || c.STTProvider == ProviderOpenAI
 }
 
+// TTSSupportsTags returns true if the configured TTS model understands inline
+// audio tags such as [excited]. It depends only on the model; TTSEnableTags
+// just picks a tag-capable model when none is set.
+func (c *Config) TTSSupportsTags() bool {
+	return c.TTSProvider == ProviderElevenLabs && strings.HasPrefix(c.TTSModel, ElevenLabsTagModel)
+}
+
 // TTSAPIKey returns the API key for the configured TTS provider.
 func (c *Config) TTSAPIKey() string {
 	switch c.TTSProvider {
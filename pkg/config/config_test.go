@@ -0,0 +1,833 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidatePublicURL(t *testing.T) {
+	tests := []struct {
+		url     string
+		wantErr bool
+	}{
+		{"https://calls.example.com", false},
+		{"https://calls.example.com:8443/agent", false},
+		{"http://calls.example.com", true},
+		{"calls.example.com", true},
+		{"https://", true},
+		{"https://calls.example.com/?a=b", true},
+		{"://bad", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			err := validatePublicURL(tt.url)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validatePublicURL(%q) error = %v, wantErr %v", tt.url, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateBindAddr(t *testing.T) {
+	tests := []struct {
+		host    string
+		wantErr bool
+	}{
+		{"", false},
+		{"127.0.0.1", false},
+		{"0.0.0.0", false},
+		{"::1", false},
+		{"[::1]", false},
+		{"localhost", false},
+		{"my-host.example.com", false},
+		{"bad host", true},
+		{"-host", true},
+		{"a..b", true},
+		{"127.0.0.1:3333", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			err := validateBindAddr(tt.host)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateBindAddr(%q) error = %v, wantErr %v", tt.host, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateCORSOrigin(t *testing.T) {
+	tests := []struct {
+		origin  string
+		wantErr bool
+	}{
+		{"*", false},
+		{"https://dashboard.example.com", false},
+		{"http://localhost:5173/", false},
+		{"dashboard.example.com", true},
+		{"ftp://dashboard.example.com", true},
+		{"https://dashboard.example.com/app", true},
+		{"https://user@dashboard.example.com", true},
+		{"https://*.example.com", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.origin, func(t *testing.T) {
+			err := validateCORSOrigin(tt.origin)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateCORSOrigin(%q) error = %v, wantErr %v", tt.origin, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestListenAddr(t *testing.T) {
+	tests := []struct {
+		bindAddr string
+		want     string
+	}{
+		{"", ":3333"},
+		{"127.0.0.1", "127.0.0.1:3333"},
+		{"::1", "[::1]:3333"},
+		{"[::1]", "[::1]:3333"},
+	}
+
+	for _, tt := range tests {
+		cfg := &Config{BindAddr: tt.bindAddr, Port: 3333}
+		if got := cfg.ListenAddr(); got != tt.want {
+			t.Errorf("ListenAddr() with %q = %q, want %q", tt.bindAddr, got, tt.want)
+		}
+	}
+}
+
+func TestValidate_PublicURLSkipsTunnel(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.PhoneAccountSID = "AC123"
+	cfg.PhoneAuthToken = "token"
+	cfg.PhoneNumber = "+15551234567"
+	cfg.UserPhoneNumber = "+15559876543"
+	cfg.ElevenLabsAPIKey = "el-key"
+	cfg.DeepgramAPIKey = "dg-key"
+	cfg.PublicURL = "https://calls.example.com"
+
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() error = %v, want nil without ngrok token", err)
+	}
+
+	cfg.PublicURL = "http://calls.example.com"
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate() expected error for non-https public URL")
+	}
+}
+
+func TestValidate_Transport(t *testing.T) {
+	cfg := validVoiceConfig()
+	cfg.Transport = "sse"
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), `invalid transport "sse"`) {
+		t.Errorf("Validate() error = %v, want invalid transport", err)
+	}
+
+	// Over stdio no tunnel is started, even with an ngrok token
+	cfg.Transport = TransportStdio
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "AGENTCOMMS_PUBLIC_URL") {
+		t.Errorf("Validate() error = %v, want public URL required", err)
+	}
+
+	cfg.PublicURL = "https://calls.example.com"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() error = %v, want nil with a public URL", err)
+	}
+
+	// Tenants are identified by a header, which requests over stdio lack
+	cfg.Tenants = testTenants()
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "tenants require") {
+		t.Errorf("Validate() error = %v, want tenants rejected over stdio", err)
+	}
+
+	// Chat only needs no public URL
+	chatOnly := DefaultConfig()
+	chatOnly.Transport = TransportStdio
+	if err := chatOnly.Validate(); err != nil {
+		t.Errorf("Validate() error = %v, want nil without voice", err)
+	}
+}
+
+func TestLoadFromEnv_TTSEnableTags(t *testing.T) {
+	t.Setenv("AGENTCOMMS_TTS_ENABLE_TAGS", "true")
+	t.Setenv("AGENTCOMMS_TTS_PROVIDER", ProviderElevenLabs)
+	t.Setenv("AGENTCOMMS_TTS_MODEL", "")
+	t.Setenv("AGENTCALL_TTS_MODEL", "")
+
+	cfg, _ := LoadFromEnv()
+	if cfg.TTSModel != ElevenLabsTagModel {
+		t.Errorf("TTSModel = %q, want %q", cfg.TTSModel, ElevenLabsTagModel)
+	}
+	if !cfg.TTSSupportsTags() {
+		t.Error("TTSSupportsTags() = false, want true")
+	}
+
+	// An explicit model wins; tags are then stripped if it lacks support
+	t.Setenv("AGENTCOMMS_TTS_MODEL", "eleven_turbo_v2_5")
+	cfg, _ = LoadFromEnv()
+	if cfg.TTSModel != "eleven_turbo_v2_5" {
+		t.Errorf("TTSModel = %q, want explicit model", cfg.TTSModel)
+	}
+	if cfg.TTSSupportsTags() {
+		t.Error("TTSSupportsTags() = true for a model without tag support")
+	}
+
+	// Support follows the model, not the flag
+	t.Setenv("AGENTCOMMS_TTS_ENABLE_TAGS", "")
+	t.Setenv("AGENTCOMMS_TTS_MODEL", ElevenLabsTagModel)
+	cfg, _ = LoadFromEnv()
+	if !cfg.TTSSupportsTags() {
+		t.Error("TTSSupportsTags() = false for an explicit tag model without the flag")
+	}
+}
+
+func TestValidate_SpeakingRate(t *testing.T) {
+	tests := []struct {
+		rate    float64
+		wantErr bool
+	}{
+		{1.0, false},
+		{0.5, false},
+		{2.0, false},
+		{0.3, true},
+		{2.5, true},
+	}
+
+	for _, tt := range tests {
+		cfg := DefaultConfig()
+		cfg.PhoneAccountSID = "AC123"
+		cfg.PhoneAuthToken = "token"
+		cfg.PhoneNumber = "+15551234567"
+		cfg.UserPhoneNumber = "+15559876543"
+		cfg.ElevenLabsAPIKey = "el-key"
+		cfg.DeepgramAPIKey = "dg-key"
+		cfg.NgrokAuthToken = "ngrok-token"
+		cfg.SpeakingRate = tt.rate
+
+		if err := cfg.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("Validate() with rate %g error = %v, wantErr %v", tt.rate, err, tt.wantErr)
+		}
+	}
+}
+
+func TestLoadFromEnv_GoodbyePhrases(t *testing.T) {
+	t.Setenv("AGENTCOMMS_GOODBYE_PHRASES", "")
+	t.Setenv("AGENTCALL_GOODBYE_PHRASES", "")
+	cfg, _ := LoadFromEnv()
+	if len(cfg.GoodbyePhrases) == 0 {
+		t.Error("GoodbyePhrases is empty, want defaults")
+	}
+
+	t.Setenv("AGENTCALL_GOODBYE_PHRASES", "ciao, later ,,see ya")
+	cfg, _ = LoadFromEnv()
+	want := []string{"ciao", "later", "see ya"}
+	if len(cfg.GoodbyePhrases) != len(want) {
+		t.Fatalf("GoodbyePhrases = %q, want %q", cfg.GoodbyePhrases, want)
+	}
+	for i := range want {
+		if cfg.GoodbyePhrases[i] != want[i] {
+			t.Errorf("GoodbyePhrases[%d] = %q, want %q", i, cfg.GoodbyePhrases[i], want[i])
+		}
+	}
+}
+
+func TestLoadFromEnv_EnabledTools(t *testing.T) {
+	t.Setenv("AGENTCOMMS_ENABLED_TOOLS", "")
+	t.Setenv("AGENTCALL_ENABLED_TOOLS", "")
+	cfg, _ := LoadFromEnv()
+	if cfg.EnabledTools != nil {
+		t.Errorf("EnabledTools = %q, want all tools", cfg.EnabledTools)
+	}
+
+	t.Setenv("AGENTCALL_ENABLED_TOOLS", "initiate_call, ,end_call")
+	cfg, _ = LoadFromEnv()
+	if got := strings.Join(cfg.EnabledTools, ","); got != "initiate_call,end_call" {
+		t.Errorf("EnabledTools = %q, want [initiate_call end_call]", cfg.EnabledTools)
+	}
+}
+
+func TestLoadFromEnv_TrimSilence(t *testing.T) {
+	t.Setenv("AGENTCALL_TRIM_SILENCE", "1")
+	t.Setenv("AGENTCOMMS_TRIM_SILENCE_PAD_MS", "40")
+
+	cfg, _ := LoadFromEnv()
+	if !cfg.TrimSilence || cfg.TrimSilencePadMS != 40 || cfg.TrimSilenceThreshold != DefaultTrimSilenceThreshold {
+		t.Errorf("TrimSilence = %v, pad %d, threshold %d; want enabled, 40, default", cfg.TrimSilence, cfg.TrimSilencePadMS, cfg.TrimSilenceThreshold)
+	}
+}
+
+func TestLoadFromEnv_WaitForGreeting(t *testing.T) {
+	t.Setenv("AGENTCALL_WAIT_FOR_GREETING", "true")
+	t.Setenv("AGENTCOMMS_GREETING_SILENCE_MS", "300")
+
+	cfg, _ := LoadFromEnv()
+	if !cfg.WaitForGreeting || cfg.GreetingSilenceMS != 300 || cfg.GreetingWaitMS != 3000 {
+		t.Errorf("WaitForGreeting = %v, silence %d, wait %d; want enabled, 300, 3000", cfg.WaitForGreeting, cfg.GreetingSilenceMS, cfg.GreetingWaitMS)
+	}
+}
+
+func TestLoadFromEnv_RecordingConsent(t *testing.T) {
+	cfg, _ := LoadFromEnv()
+	if cfg.RecordingConsent != DefaultRecordingConsent {
+		t.Errorf("RecordingConsent = %q, want the default", cfg.RecordingConsent)
+	}
+
+	t.Setenv("AGENTCALL_RECORDING_CONSENT", "Esta llamada está siendo grabada.")
+	t.Setenv("AGENTCOMMS_RECORDING_CONSENT_VOICE", "pNInz6obpgDQGcFmaJgB")
+	cfg, _ = LoadFromEnv()
+	if cfg.RecordingConsent != "Esta llamada está siendo grabada." || cfg.RecordingConsentVoice != "pNInz6obpgDQGcFmaJgB" {
+		t.Errorf("RecordingConsent, voice = %q, %q", cfg.RecordingConsent, cfg.RecordingConsentVoice)
+	}
+}
+
+func TestLoadFromEnv_AdaptiveSilence(t *testing.T) {
+	t.Setenv("AGENTCOMMS_STT_ADAPTIVE_SILENCE", "true")
+	t.Setenv("AGENTCALL_STT_ADAPTIVE_SILENCE_MAX_MS", "1500")
+
+	cfg, _ := LoadFromEnv()
+	if !cfg.AdaptiveSilence || cfg.AdaptiveSilenceMinMS != DefaultAdaptiveSilenceMinMS || cfg.AdaptiveSilenceMaxMS != 1500 {
+		t.Errorf("AdaptiveSilence = %v, min %d, max %d; want enabled, default, 1500", cfg.AdaptiveSilence, cfg.AdaptiveSilenceMinMS, cfg.AdaptiveSilenceMaxMS)
+	}
+}
+
+func TestValidate_AdaptiveSilenceBounds(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.PhoneAccountSID = "AC123"
+	cfg.PhoneAuthToken = "token"
+	cfg.PhoneNumber = "+15551234567"
+	cfg.UserPhoneNumber = "+15559876543"
+	cfg.ElevenLabsAPIKey = "el-key"
+	cfg.DeepgramAPIKey = "dg-key"
+	cfg.NgrokAuthToken = "ngrok-token"
+	cfg.AdaptiveSilenceMinMS = 1000
+	cfg.AdaptiveSilenceMaxMS = 500
+
+	err := cfg.Validate()
+	if err == nil || !strings.Contains(err.Error(), "must not exceed AGENTCOMMS_STT_ADAPTIVE_SILENCE_MAX_MS") {
+		t.Errorf("Validate() error = %v, want the bounds rejected", err)
+	}
+}
+
+func TestLoadFromEnv_ConnectTone(t *testing.T) {
+	t.Setenv("AGENTCALL_CONNECT_TONE", "1")
+	t.Setenv("AGENTCOMMS_CONNECT_TONE_HZ", "1000")
+
+	cfg, _ := LoadFromEnv()
+	if !cfg.ConnectTone || cfg.ConnectToneHz != 1000 || cfg.ConnectToneMS != DefaultConnectToneMS {
+		t.Errorf("ConnectTone = %v, %d Hz, %d ms; want enabled, 1000, default", cfg.ConnectTone, cfg.ConnectToneHz, cfg.ConnectToneMS)
+	}
+}
+
+func TestValidate_ConnectToneHz(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.PhoneAccountSID = "AC123"
+	cfg.PhoneAuthToken = "token"
+	cfg.PhoneNumber = "+15551234567"
+	cfg.UserPhoneNumber = "+15559876543"
+	cfg.ElevenLabsAPIKey = "el-key"
+	cfg.DeepgramAPIKey = "dg-key"
+	cfg.NgrokAuthToken = "ngrok-token"
+	cfg.ConnectToneHz = 8000
+
+	err := cfg.Validate()
+	if err == nil || !strings.Contains(err.Error(), "AGENTCOMMS_CONNECT_TONE_HZ") {
+		t.Errorf("Validate() error = %v, want the frequency rejected", err)
+	}
+}
+
+func TestLoadFromEnv_ResumeWindow(t *testing.T) {
+	if cfg := DefaultConfig(); cfg.ResumeWindowMS != 0 {
+		t.Errorf("default ResumeWindowMS = %d, want off", cfg.ResumeWindowMS)
+	}
+
+	t.Setenv("AGENTCALL_RESUME_WINDOW_MS", "60000")
+	cfg, _ := LoadFromEnv()
+	if cfg.ResumeWindowMS != 60000 {
+		t.Errorf("ResumeWindowMS = %d, want 60000", cfg.ResumeWindowMS)
+	}
+}
+
+func TestLoadFromEnv_Redial(t *testing.T) {
+	t.Setenv("AGENTCALL_REDIAL_ATTEMPTS", "2")
+	t.Setenv("AGENTCOMMS_REDIAL_DELAY_MS", "5000")
+	cfg, _ := LoadFromEnv()
+	if cfg.RedialAttempts != 2 || cfg.RedialDelayMS != 5000 {
+		t.Errorf("RedialAttempts, RedialDelayMS = %d, %d; want 2, 5000", cfg.RedialAttempts, cfg.RedialDelayMS)
+	}
+}
+
+func TestLoadFromEnv_UsageCaps(t *testing.T) {
+	t.Setenv("AGENTCALL_MAX_TTS_CHARS_PER_CALL", "5000")
+	t.Setenv("AGENTCOMMS_MAX_STT_SECONDS_PER_CALL", "600")
+	cfg, _ := LoadFromEnv()
+	if cfg.MaxTTSCharsPerCall != 5000 || cfg.MaxSTTSecondsPerCall != 600 {
+		t.Errorf("MaxTTSCharsPerCall, MaxSTTSecondsPerCall = %d, %d; want 5000, 600", cfg.MaxTTSCharsPerCall, cfg.MaxSTTSecondsPerCall)
+	}
+}
+
+func TestLoadFromEnv_CORSOrigins(t *testing.T) {
+	if cfg := DefaultConfig(); len(cfg.CORSOrigins) != 0 {
+		t.Errorf("default CORSOrigins = %q, want none", cfg.CORSOrigins)
+	}
+
+	t.Setenv("AGENTCALL_CORS_ORIGINS", "https://dashboard.example.com, http://localhost:5173")
+	cfg, _ := LoadFromEnv()
+	if len(cfg.CORSOrigins) != 2 || cfg.CORSOrigins[1] != "http://localhost:5173" {
+		t.Errorf("CORSOrigins = %q", cfg.CORSOrigins)
+	}
+}
+
+func TestLoadFromEnv_TranscriptRoles(t *testing.T) {
+	if cfg := DefaultConfig(); cfg.TranscriptAssistantRole != "assistant" || cfg.TranscriptUserRole != "user" {
+		t.Errorf("default roles = %q, %q; want assistant, user", cfg.TranscriptAssistantRole, cfg.TranscriptUserRole)
+	}
+
+	t.Setenv("AGENTCALL_TRANSCRIPT_ASSISTANT_ROLE", "agent")
+	t.Setenv("AGENTCOMMS_TRANSCRIPT_USER_ROLE", "customer")
+	cfg, _ := LoadFromEnv()
+	if cfg.TranscriptAssistantRole != "agent" || cfg.TranscriptUserRole != "customer" {
+		t.Errorf("roles = %q, %q; want agent, customer", cfg.TranscriptAssistantRole, cfg.TranscriptUserRole)
+	}
+}
+
+func TestLoadFromEnv_AudioDumpDir(t *testing.T) {
+	t.Setenv("AGENTCALL_AUDIO_DUMP_DIR", "/tmp/agentcomms-audio")
+
+	cfg, _ := LoadFromEnv()
+	if cfg.AudioDumpDir != "/tmp/agentcomms-audio" {
+		t.Errorf("AudioDumpDir = %q", cfg.AudioDumpDir)
+	}
+}
+
+func TestLoadFromEnv_AudioDir(t *testing.T) {
+	t.Setenv("AGENTCOMMS_AUDIO_DIR", "/srv/agentcomms/audio")
+
+	cfg, _ := LoadFromEnv()
+	if cfg.AudioDir != "/srv/agentcomms/audio" {
+		t.Errorf("AudioDir = %q", cfg.AudioDir)
+	}
+}
+
+func TestValidate_MillisNamesEnvVar(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.PhoneAccountSID = "AC123"
+	cfg.PhoneAuthToken = "token"
+	cfg.PhoneNumber = "+15551234567"
+	cfg.UserPhoneNumber = "+15559876543"
+	cfg.ElevenLabsAPIKey = "el-key"
+	cfg.DeepgramAPIKey = "dg-key"
+	cfg.NgrokAuthToken = "ngrok-token"
+	cfg.EchoGuardMS = 20000
+
+	err := cfg.Validate()
+	if err == nil || !strings.Contains(err.Error(), "invalid AGENTCOMMS_ECHO_GUARD_MS 20000") {
+		t.Errorf("Validate() error = %v, want the env var named", err)
+	}
+}
+
+func TestValidateTwilioLocation(t *testing.T) {
+	tests := []struct {
+		region, edge string
+		wantErr      bool
+	}{
+		{"", "", false},
+		{"ie1", "dublin", false},
+		{"au1", "sydney", false},
+		{"us1", "roaming", false},
+		{"eu1", "", true},
+		{"", "paris", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.region+"/"+tt.edge, func(t *testing.T) {
+			err := validateTwilioLocation(tt.region, tt.edge)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateTwilioLocation(%q, %q) error = %v, wantErr %v", tt.region, tt.edge, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidate_Millis(t *testing.T) {
+	tests := []struct {
+		name    string
+		set     func(*Config)
+		wantErr bool
+	}{
+		{"defaults", func(*Config) {}, false},
+		{"zero transcript timeout", func(c *Config) { c.TranscriptTimeoutMS = 0 }, true},
+		{"negative transcript timeout", func(c *Config) { c.TranscriptTimeoutMS = -1 }, true},
+		{"huge transcript timeout", func(c *Config) { c.TranscriptTimeoutMS = 24 * 3600000 }, true},
+		{"zero silence duration", func(c *Config) { c.STTSilenceDurationMS = 0 }, true},
+		{"negative echo guard", func(c *Config) { c.EchoGuardMS = -500 }, true},
+		{"zero echo guard", func(c *Config) { c.EchoGuardMS = 0 }, false},
+		{"negative restarts", func(c *Config) { c.ServeRestarts = -1 }, true},
+		{"tiny restart backoff", func(c *Config) { c.ServeRestartBackoffMS = 10 }, true},
+		{"no tunnel ready timeout", func(c *Config) { c.TunnelReadyTimeoutMS = 0 }, false},
+		{"negative tunnel ready timeout", func(c *Config) { c.TunnelReadyTimeoutMS = -1 }, true},
+		{"resume window", func(c *Config) { c.ResumeWindowMS = 60000 }, false},
+		{"huge resume window", func(c *Config) { c.ResumeWindowMS = 24 * 3600000 }, true},
+		{"same transcript roles", func(c *Config) { c.TranscriptAssistantRole = "user" }, true},
+		{"no call lifetime limit", func(c *Config) { c.MaxCallLifetimeMS = 0 }, false},
+		{"negative call lifetime", func(c *Config) { c.MaxCallLifetimeMS = -1 }, true},
+		{"redials", func(c *Config) { c.RedialAttempts = 2 }, false},
+		{"too many redials", func(c *Config) { c.RedialAttempts = MaxRedialAttempts + 1 }, true},
+		{"no redial delay", func(c *Config) { c.RedialDelayMS = 0 }, true},
+		{"twilio TTS frames", func(c *Config) { c.TTSFrameMS = 20 }, false},
+		{"huge TTS frames", func(c *Config) { c.TTSFrameMS = 1000 }, true},
+		{"zero status poll", func(c *Config) { c.StatusPollMS = 0 }, true},
+		{"busy status poll", func(c *Config) { c.StatusPollMS = 10 }, true},
+		{"slow status poll", func(c *Config) { c.StatusPollMS = 2000 }, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.PhoneAccountSID = "AC123"
+			cfg.PhoneAuthToken = "token"
+			cfg.PhoneNumber = "+15551234567"
+			cfg.UserPhoneNumber = "+15559876543"
+			cfg.ElevenLabsAPIKey = "el-key"
+			cfg.DeepgramAPIKey = "dg-key"
+			cfg.NgrokAuthToken = "ngrok-token"
+			tt.set(cfg)
+
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestToLegacyConfig_UnsetMillisKeepDefaults(t *testing.T) {
+	u := &UnifiedConfig{Voice: &VoiceConfig{}}
+	cfg := u.ToLegacyConfig()
+
+	def := DefaultConfig()
+	if cfg.TranscriptTimeoutMS != def.TranscriptTimeoutMS {
+		t.Errorf("TranscriptTimeoutMS = %d, want default %d", cfg.TranscriptTimeoutMS, def.TranscriptTimeoutMS)
+	}
+	if cfg.STTSilenceDurationMS != def.STTSilenceDurationMS {
+		t.Errorf("STTSilenceDurationMS = %d, want default %d", cfg.STTSilenceDurationMS, def.STTSilenceDurationMS)
+	}
+}
+
+func TestLoadFromEnv_MalformedNumbers(t *testing.T) {
+	tests := []struct {
+		name, value string
+		wantErr     string
+	}{
+		{"AGENTCALL_PORT", "abc", "AGENTCALL_PORT"},
+		{"AGENTCOMMS_WEBHOOK_PORT", "80x", "AGENTCOMMS_WEBHOOK_PORT"},
+		{"AGENTCOMMS_TRANSCRIPT_TIMEOUT_MS", "3m", "AGENTCOMMS_TRANSCRIPT_TIMEOUT_MS"},
+		{"AGENTCOMMS_SPEAKING_RATE", "fast", "AGENTCOMMS_SPEAKING_RATE"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(tt.name, tt.value)
+
+			cfg, err := LoadFromEnv()
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("LoadFromEnv() error = %v, want mention of %s", err, tt.wantErr)
+			}
+			if def := DefaultConfig(); cfg.Port != def.Port || cfg.SpeakingRate != def.SpeakingRate {
+				t.Error("malformed value changed the config")
+			}
+		})
+	}
+}
+
+func TestLoadFromEnv_Numbers(t *testing.T) {
+	t.Setenv("AGENTCOMMS_PORT", " 4000 ")
+	t.Setenv("AGENTCOMMS_BIND_ADDR", "127.0.0.1")
+	t.Setenv("AGENTCOMMS_TRANSPORT", "stdio")
+	t.Setenv("AGENTCOMMS_SPEAKING_RATE", "1.25")
+	t.Setenv("AGENTCOMMS_MAX_DAILY_COST_USD", "2.5")
+
+	cfg, err := LoadFromEnv()
+	if err != nil && strings.Contains(err.Error(), "invalid environment variables") {
+		t.Fatalf("LoadFromEnv() error = %v", err)
+	}
+	if cfg.Port != 4000 {
+		t.Errorf("Port = %d, want 4000", cfg.Port)
+	}
+	if cfg.BindAddr != "127.0.0.1" {
+		t.Errorf("BindAddr = %q, want 127.0.0.1", cfg.BindAddr)
+	}
+	if cfg.Transport != TransportStdio {
+		t.Errorf("Transport = %q, want stdio", cfg.Transport)
+	}
+	if cfg.SpeakingRate != 1.25 {
+		t.Errorf("SpeakingRate = %g, want 1.25", cfg.SpeakingRate)
+	}
+	if cfg.MaxDailyCostUSD != 2.5 {
+		t.Errorf("MaxDailyCostUSD = %g, want 2.5", cfg.MaxDailyCostUSD)
+	}
+}
+
+func TestValidate_Providers(t *testing.T) {
+	tests := []struct {
+		name    string
+		set     func(*Config)
+		wantErr bool
+	}{
+		{"openai tts", func(c *Config) { c.TTSProvider, c.OpenAIAPIKey = ProviderOpenAI, "sk-key" }, false},
+		{"openai tts without key", func(c *Config) { c.TTSProvider = ProviderOpenAI }, true},
+		{"openai stt", func(c *Config) { c.STTProvider, c.OpenAIAPIKey = ProviderOpenAI, "sk-key" }, false},
+		{"unknown stt", func(c *Config) { c.STTProvider = "whisper" }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.PhoneAccountSID = "AC123"
+			cfg.PhoneAuthToken = "token"
+			cfg.PhoneNumber = "+15551234567"
+			cfg.UserPhoneNumber = "+15559876543"
+			cfg.ElevenLabsAPIKey = "el-key"
+			cfg.DeepgramAPIKey = "dg-key"
+			cfg.NgrokAuthToken = "ngrok-token"
+			tt.set(cfg)
+
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// validVoiceConfig returns a config that passes Validate with the default
+// providers and an ngrok tunnel.
+func validVoiceConfig() *Config {
+	cfg := DefaultConfig()
+	cfg.PhoneAccountSID = "AC123"
+	cfg.PhoneAuthToken = "token"
+	cfg.PhoneNumber = "+15551234567"
+	cfg.UserPhoneNumber = "+15559876543"
+	cfg.ElevenLabsAPIKey = "el-key"
+	cfg.DeepgramAPIKey = "dg-key"
+	cfg.NgrokAuthToken = "ngrok-token"
+	return cfg
+}
+
+func TestValidate_Defaults(t *testing.T) {
+	if err := validVoiceConfig().Validate(); err != nil {
+		t.Errorf("Validate() error = %v, want nil", err)
+	}
+}
+
+func TestValidate_ProviderKeyMatrix(t *testing.T) {
+	const (
+		elevenLabsKey = "AGENTCOMMS_ELEVENLABS_API_KEY"
+		deepgramKey   = "AGENTCOMMS_DEEPGRAM_API_KEY"
+		openAIKey     = "AGENTCOMMS_OPENAI_API_KEY"
+	)
+	keyFor := map[string]string{
+		ProviderElevenLabs: elevenLabsKey,
+		ProviderDeepgram:   deepgramKey,
+		ProviderOpenAI:     openAIKey,
+	}
+	providers := []string{ProviderElevenLabs, ProviderDeepgram, ProviderOpenAI}
+
+	for _, tts := range providers {
+		for _, stt := range providers {
+			t.Run(tts+"+"+stt, func(t *testing.T) {
+				want := map[string]bool{keyFor[tts]: true, keyFor[stt]: true}
+
+				cfg := validVoiceConfig()
+				cfg.TTSProvider, cfg.STTProvider = tts, stt
+				cfg.ElevenLabsAPIKey, cfg.DeepgramAPIKey = "", ""
+
+				err := cfg.Validate()
+				if err == nil {
+					t.Fatal("Validate() error = nil, want missing API keys")
+				}
+				if !strings.Contains(err.Error(), "missing required environment variables") {
+					t.Fatalf("Validate() error = %v, want missing variables", err)
+				}
+				for _, key := range []string{elevenLabsKey, deepgramKey, openAIKey} {
+					if got := strings.Contains(err.Error(), key); got != want[key] {
+						t.Errorf("Validate() error = %v; mentions %s = %v, want %v", err, key, got, want[key])
+					}
+				}
+
+				// Only the keys the selected providers use are needed
+				if want[elevenLabsKey] {
+					cfg.ElevenLabsAPIKey = "el-key"
+				}
+				if want[deepgramKey] {
+					cfg.DeepgramAPIKey = "dg-key"
+				}
+				if want[openAIKey] {
+					cfg.OpenAIAPIKey = "sk-key"
+				}
+				if err := cfg.Validate(); err != nil {
+					t.Errorf("Validate() with %v keys error = %v, want nil", want, err)
+				}
+			})
+		}
+	}
+}
+
+func TestValidate_ProviderNeeds(t *testing.T) {
+	tests := []struct {
+		tts, stt                     string
+		elevenLabs, deepgram, openAI bool
+	}{
+		{ProviderElevenLabs, ProviderDeepgram, true, true, false},
+		{ProviderElevenLabs, ProviderElevenLabs, true, false, false},
+		{ProviderDeepgram, ProviderDeepgram, false, true, false},
+		{ProviderOpenAI, ProviderOpenAI, false, false, true},
+		{ProviderOpenAI, ProviderDeepgram, false, true, true},
+		{ProviderDeepgram, ProviderElevenLabs, true, true, false},
+		{"", "", false, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tts+"+"+tt.stt, func(t *testing.T) {
+			cfg := &Config{TTSProvider: tt.tts, STTProvider: tt.stt}
+			if got := cfg.NeedsElevenLabs(); got != tt.elevenLabs {
+				t.Errorf("NeedsElevenLabs() = %v, want %v", got, tt.elevenLabs)
+			}
+			if got := cfg.NeedsDeepgram(); got != tt.deepgram {
+				t.Errorf("NeedsDeepgram() = %v, want %v", got, tt.deepgram)
+			}
+			if got := cfg.NeedsOpenAI(); got != tt.openAI {
+				t.Errorf("NeedsOpenAI() = %v, want %v", got, tt.openAI)
+			}
+		})
+	}
+}
+
+func TestValidate_MissingVoiceSettings(t *testing.T) {
+	tests := []struct {
+		name  string
+		unset func(*Config)
+		want  string
+	}{
+		{"account sid", func(c *Config) { c.PhoneAccountSID = "" }, "AGENTCOMMS_PHONE_ACCOUNT_SID"},
+		{"auth token", func(c *Config) { c.PhoneAuthToken = "" }, "AGENTCOMMS_PHONE_AUTH_TOKEN"},
+		{"phone number", func(c *Config) { c.PhoneNumber = "" }, "AGENTCOMMS_PHONE_NUMBER"},
+		{"user phone number", func(c *Config) { c.UserPhoneNumber = "" }, "AGENTCOMMS_USER_PHONE_NUMBER"},
+		{"elevenlabs key", func(c *Config) { c.ElevenLabsAPIKey = "" }, "AGENTCOMMS_ELEVENLABS_API_KEY"},
+		{"deepgram key", func(c *Config) { c.DeepgramAPIKey = "" }, "AGENTCOMMS_DEEPGRAM_API_KEY"},
+		{"ngrok token", func(c *Config) { c.NgrokAuthToken = "" }, "AGENTCOMMS_NGROK_AUTHTOKEN"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validVoiceConfig()
+			tt.unset(cfg)
+
+			err := cfg.Validate()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Validate() error = %v, want it to name %s", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidate_MissingAllListsEach(t *testing.T) {
+	cfg := validVoiceConfig()
+	cfg.PhoneAuthToken = ""
+	cfg.UserPhoneNumber = ""
+	cfg.DeepgramAPIKey = ""
+
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("Validate() error = nil, want missing variables")
+	}
+	for _, want := range []string{"AGENTCOMMS_PHONE_AUTH_TOKEN", "AGENTCOMMS_USER_PHONE_NUMBER", "AGENTCOMMS_DEEPGRAM_API_KEY"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Validate() error = %v, want it to name %s", err, want)
+		}
+	}
+}
+
+func TestValidate_InvalidProviderNames(t *testing.T) {
+	tests := []struct {
+		name string
+		set  func(*Config)
+		want string
+	}{
+		{"unknown tts", func(c *Config) { c.TTSProvider = "polly" }, `invalid TTS provider "polly"`},
+		{"empty tts", func(c *Config) { c.TTSProvider = "" }, `invalid TTS provider ""`},
+		{"tts is case sensitive", func(c *Config) { c.TTSProvider = "ElevenLabs" }, `invalid TTS provider "ElevenLabs"`},
+		{"unknown stt", func(c *Config) { c.STTProvider = "whisper" }, `invalid STT provider "whisper"`},
+		{"empty stt", func(c *Config) { c.STTProvider = "" }, `invalid STT provider ""`},
+		{"unknown phone", func(c *Config) { c.PhoneProvider = "vonage" }, `invalid phone provider "vonage"`},
+		{"empty phone", func(c *Config) { c.PhoneProvider = "" }, `invalid phone provider ""`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validVoiceConfig()
+			tt.set(cfg)
+
+			err := cfg.Validate()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Validate() error = %v, want %s", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidate_Telnyx(t *testing.T) {
+	cfg := validVoiceConfig()
+	cfg.PhoneProvider = "telnyx"
+
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() error = %v, want nil", err)
+	}
+}
+
+func TestValidate_InvalidProviderReportedBeforeMissing(t *testing.T) {
+	cfg := validVoiceConfig()
+	cfg.TTSProvider = "polly"
+	cfg.PhoneNumber = ""
+
+	err := cfg.Validate()
+	if err == nil || !strings.Contains(err.Error(), "configuration errors") {
+		t.Errorf("Validate() error = %v, want configuration errors first", err)
+	}
+}
+
+func TestValidate_VoiceDisabledSkipsProviders(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.TTSProvider = "polly"
+	cfg.STTProvider = ProviderOpenAI
+	cfg.PhoneProvider = "vonage"
+
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() without voice error = %v, want nil", err)
+	}
+}
+
+func TestValidate_AnyPhoneSettingEnablesVoice(t *testing.T) {
+	tests := []struct {
+		name string
+		set  func(*Config)
+	}{
+		{"account sid", func(c *Config) { c.PhoneAccountSID = "AC123" }},
+		{"auth token", func(c *Config) { c.PhoneAuthToken = "token" }},
+		{"phone number", func(c *Config) { c.PhoneNumber = "+15551234567" }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			tt.set(cfg)
+
+			err := cfg.Validate()
+			if err == nil || !strings.Contains(err.Error(), "AGENTCOMMS_ELEVENLABS_API_KEY") {
+				t.Errorf("Validate() error = %v, want provider keys required", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package config
+
+import "fmt"
+
+// msRange is the range Validate accepts for a millisecond setting, with the
+// setting's names in the environment and in the config file so errors point
+// at the place the value came from.
+type msRange struct {
+	env, file string
+	min, max  int
+}
+
+// Millisecond settings, shared by Config.Validate and UnifiedConfig.Validate.
+var (
+	transcriptTimeoutMS  = msRange{"AGENTCOMMS_TRANSCRIPT_TIMEOUT_MS", "voice.transcript_timeout_ms", 1000, 3600000}
+	silenceDurationMS    = msRange{"AGENTCOMMS_STT_SILENCE_DURATION_MS", "voice.stt.silence_duration_ms", 100, 10000}
+	aggregateFinalsMS    = msRange{"AGENTCOMMS_AGGREGATE_FINALS_MS", "voice.stt.aggregate_finals_ms", 0, 10000}
+	adaptiveSilenceMinMS = msRange{"AGENTCOMMS_STT_ADAPTIVE_SILENCE_MIN_MS", "voice.stt.adaptive_silence_min_ms", 100, 10000}
+	adaptiveSilenceMaxMS = msRange{"AGENTCOMMS_STT_ADAPTIVE_SILENCE_MAX_MS", "voice.stt.adaptive_silence_max_ms", 100, 10000}
+	maxUtteranceMS       = msRange{"AGENTCOMMS_MAX_UTTERANCE_MS", "voice.stt.max_utterance_ms", 0, 3600000}
+	postSpeechDelayMS    = msRange{"AGENTCOMMS_POST_SPEECH_DELAY_MS", "voice.stt.post_speech_delay_ms", 0, 10000}
+	echoGuardMS          = msRange{"AGENTCOMMS_ECHO_GUARD_MS", "voice.stt.echo_guard_ms", 0, 10000}
+	trimSilencePadMS     = msRange{"AGENTCOMMS_TRIM_SILENCE_PAD_MS", "voice.tts.trim_silence_pad_ms", 0, 2000}
+	ttsFrameMS           = msRange{"AGENTCOMMS_TTS_FRAME_MS", "voice.tts.frame_ms", 0, 200}
+	amdWaitMS            = msRange{"AGENTCOMMS_AMD_WAIT_MS", "voice.phone.amd_wait_ms", 0, 60000}
+	statusPollMS         = msRange{"AGENTCOMMS_STATUS_POLL_MS", "voice.phone.status_poll_ms", 50, 10000}
+	greetingWaitMS       = msRange{"AGENTCOMMS_GREETING_WAIT_MS", "voice.phone.greeting_wait_ms", 500, 10000}
+	greetingSilenceMS    = msRange{"AGENTCOMMS_GREETING_SILENCE_MS", "voice.phone.greeting_silence_ms", 100, 3000}
+	connectToneMS        = msRange{"AGENTCOMMS_CONNECT_TONE_MS", "voice.phone.connect_tone_ms", 50, 2000}
+	resumeWindowMS       = msRange{"AGENTCOMMS_RESUME_WINDOW_MS", "voice.resume_window_ms", 0, 3600000}
+	maxCallLifetimeMS    = msRange{"AGENTCOMMS_MAX_CALL_LIFETIME_MS", "voice.max_call_lifetime_ms", 0, 24 * 3600000}
+	redialDelayMS        = msRange{"AGENTCOMMS_REDIAL_DELAY_MS", "voice.redial_delay_ms", 1000, 600000}
+	descriptionRefreshMS = msRange{"AGENTCOMMS_DESCRIPTION_REFRESH_MS", "voice.description_refresh_ms", 0, 3600000}
+	tunnelReadyTimeoutMS = msRange{"AGENTCOMMS_TUNNEL_READY_TIMEOUT_MS", "voice.tunnel_ready_timeout_ms", 0, 3600000}
+	restartBackoffMS     = msRange{"AGENTCOMMS_SERVE_RESTART_BACKOFF_MS", "server.restart_backoff_ms", 100, 600000}
+)
+
+// msSetting is the value of a millisecond setting.
+type msSetting struct {
+	msRange
+	value int
+}
+
+// Sources of a setting, for naming it in validation errors.
+const (
+	fromEnv  = false
+	fromFile = true
+)
+
+// validateMillis returns an error message for each setting outside its
+// range, naming the config file field if file is set and the environment
+// variable otherwise. A zero or negative timeout would make timers fire
+// immediately, and very large values leave a call hanging.
+func validateMillis(settings []msSetting, file bool) []string {
+	var errors []string
+	for _, s := range settings {
+		if s.value < s.min || s.value > s.max {
+			name := s.env
+			if file {
+				name = s.file
+			}
+			errors = append(errors, fmt.Sprintf("invalid %s %d (must be between %d and %d milliseconds)", name, s.value, s.min, s.max))
+		}
+	}
+	return errors
+}
+
+// msSettings returns the millisecond settings of c that depend on voice.
+func (c *Config) msSettings() []msSetting {
+	return []msSetting{
+		{transcriptTimeoutMS, c.TranscriptTimeoutMS},
+		{silenceDurationMS, c.STTSilenceDurationMS},
+		{aggregateFinalsMS, c.AggregateFinalsMS},
+		{adaptiveSilenceMinMS, c.AdaptiveSilenceMinMS},
+		{adaptiveSilenceMaxMS, c.AdaptiveSilenceMaxMS},
+		{maxUtteranceMS, c.MaxUtteranceMS},
+		{postSpeechDelayMS, c.PostSpeechDelayMS},
+		{echoGuardMS, c.EchoGuardMS},
+		{trimSilencePadMS, c.TrimSilencePadMS},
+		{ttsFrameMS, c.TTSFrameMS},
+		{amdWaitMS, c.AMDWaitMS},
+		{statusPollMS, c.StatusPollMS},
+		{greetingWaitMS, c.GreetingWaitMS},
+		{greetingSilenceMS, c.GreetingSilenceMS},
+		{connectToneMS, c.ConnectToneMS},
+		{resumeWindowMS, c.ResumeWindowMS},
+		{maxCallLifetimeMS, c.MaxCallLifetimeMS},
+		{redialDelayMS, c.RedialDelayMS},
+		{descriptionRefreshMS, c.DescriptionRefreshMS},
+		{tunnelReadyTimeoutMS, c.TunnelReadyTimeoutMS},
+	}
+}
@@ -0,0 +1,43 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// QuietHours is a daily window, in local time, during which no calls are
+// placed. The zero value is an empty window.
+type QuietHours struct {
+	start, end int // minutes after midnight; end may be before start to wrap midnight
+}
+
+// ParseQuietHours parses a window such as "22:00-07:00". An empty string
+// returns an empty window.
+func ParseQuietHours(s string) (QuietHours, error) {
+	if s == "" {
+		return QuietHours{}, nil
+	}
+
+	var sh, sm, eh, em int
+	if _, err := fmt.Sscanf(s, "%d:%d-%d:%d", &sh, &sm, &eh, &em); err != nil {
+		return QuietHours{}, fmt.Errorf("invalid quiet hours %q (want HH:MM-HH:MM, e.g. 22:00-07:00)", s)
+	}
+	if sh < 0 || sh > 23 || eh < 0 || eh > 23 || sm < 0 || sm > 59 || em < 0 || em > 59 {
+		return QuietHours{}, fmt.Errorf("invalid quiet hours %q (hours must be 00-23 and minutes 00-59)", s)
+	}
+
+	return QuietHours{start: sh*60 + sm, end: eh*60 + em}, nil
+}
+
+// Contains reports whether t falls inside the quiet hours window.
+func (q QuietHours) Contains(t time.Time) bool {
+	if q.start == q.end {
+		return false
+	}
+
+	minute := t.Hour()*60 + t.Minute()
+	if q.start < q.end {
+		return minute >= q.start && minute < q.end
+	}
+	return minute >= q.start || minute < q.end
+}
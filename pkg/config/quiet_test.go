@@ -0,0 +1,62 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseQuietHours(t *testing.T) {
+	tests := []struct {
+		input   string
+		wantErr bool
+	}{
+		{"", false},
+		{"22:00-07:00", false},
+		{"09:30-17:45", false},
+		{"22-07", true},
+		{"24:00-07:00", true},
+		{"22:60-07:00", true},
+		{"tonight", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			_, err := ParseQuietHours(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseQuietHours(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestQuietHours_Contains(t *testing.T) {
+	at := func(hour, minute int) time.Time {
+		return time.Date(2025, 1, 15, hour, minute, 0, 0, time.Local)
+	}
+
+	overnight, _ := ParseQuietHours("22:00-07:00")
+	daytime, _ := ParseQuietHours("09:00-17:00")
+
+	tests := []struct {
+		name string
+		q    QuietHours
+		t    time.Time
+		want bool
+	}{
+		{"empty window", QuietHours{}, at(3, 0), false},
+		{"overnight late", overnight, at(23, 30), true},
+		{"overnight early", overnight, at(6, 59), true},
+		{"overnight end is exclusive", overnight, at(7, 0), false},
+		{"overnight afternoon", overnight, at(15, 0), false},
+		{"daytime inside", daytime, at(12, 0), true},
+		{"daytime before", daytime, at(8, 59), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.q.Contains(tt.t); got != tt.want {
+				t.Errorf("Contains(%v) = %v, want %v", tt.t.Format("15:04"), got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,212 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// SourceDefault is the Setting source for values not set in the environment.
+const SourceDefault = "default"
+
+// Setting is a single effective configuration value, as reported by Redacted.
+type Setting struct {
+	Name   string // primary environment variable name
+	Value  string // effective value, with secrets masked
+	Source string // environment variable that supplied the value, or SourceDefault
+}
+
+// setting describes how a Config field is loaded so it can be reported.
+type setting struct {
+	env    []string // primary name first, then fallbacks in lookup order
+	secret bool
+	value  func(c *Config) string
+}
+
+// settings lists every environment-backed field in the order LoadFromEnv reads them.
+var settings = []setting{
+	{env: []string{"AGENTCOMMS_PORT", "AGENTCALL_PORT"}, value: func(c *Config) string { return strconv.Itoa(c.Port) }},
+	{env: []string{"AGENTCOMMS_BIND_ADDR", "AGENTCALL_BIND_ADDR"}, value: func(c *Config) string { return c.BindAddr }},
+	{env: []string{"AGENTCOMMS_TRANSPORT", "AGENTCALL_TRANSPORT"}, value: func(c *Config) string { return c.Transport }},
+	{env: []string{"AGENTCOMMS_SERVE_RESTARTS", "AGENTCALL_SERVE_RESTARTS"}, value: func(c *Config) string { return strconv.Itoa(c.ServeRestarts) }},
+	{env: []string{"AGENTCOMMS_SERVE_RESTART_BACKOFF_MS", "AGENTCALL_SERVE_RESTART_BACKOFF_MS"}, value: func(c *Config) string { return strconv.Itoa(c.ServeRestartBackoffMS) }},
+	{env: []string{"AGENTCOMMS_ENABLED_TOOLS", "AGENTCALL_ENABLED_TOOLS"}, value: func(c *Config) string { return strings.Join(c.EnabledTools, ",") }},
+	{env: []string{"AGENTCOMMS_CORS_ORIGINS", "AGENTCALL_CORS_ORIGINS"}, value: func(c *Config) string { return strings.Join(c.CORSOrigins, ",") }},
+
+	{env: []string{"AGENTCOMMS_PHONE_PROVIDER", "AGENTCALL_PHONE_PROVIDER"}, value: func(c *Config) string { return c.PhoneProvider }},
+	{env: []string{"AGENTCOMMS_PHONE_ACCOUNT_SID", "AGENTCALL_PHONE_ACCOUNT_SID"}, value: func(c *Config) string { return c.PhoneAccountSID }},
+	{env: []string{"AGENTCOMMS_PHONE_AUTH_TOKEN", "AGENTCALL_PHONE_AUTH_TOKEN"}, secret: true, value: func(c *Config) string { return c.PhoneAuthToken }},
+	{env: []string{"AGENTCOMMS_PHONE_NUMBER", "AGENTCALL_PHONE_NUMBER"}, value: func(c *Config) string { return c.PhoneNumber }},
+	{env: []string{"AGENTCOMMS_USER_PHONE_NUMBER", "AGENTCALL_USER_PHONE_NUMBER"}, value: func(c *Config) string { return c.UserPhoneNumber }},
+	{env: []string{"AGENTCOMMS_CALLER_ID_NAME", "AGENTCALL_CALLER_ID_NAME"}, value: func(c *Config) string { return c.CallerIDName }},
+	{env: []string{"AGENTCOMMS_TWILIO_REGION", "AGENTCALL_TWILIO_REGION"}, value: func(c *Config) string { return c.TwilioRegion }},
+	{env: []string{"AGENTCOMMS_TWILIO_EDGE", "AGENTCALL_TWILIO_EDGE"}, value: func(c *Config) string { return c.TwilioEdge }},
+	{env: []string{"AGENTCOMMS_TWIML_TEMPLATE", "AGENTCALL_TWIML_TEMPLATE"}, value: func(c *Config) string { return c.TwiMLTemplate }},
+	{env: []string{"AGENTCOMMS_PREFERRED_CODEC", "AGENTCALL_PREFERRED_CODEC"}, value: func(c *Config) string { return c.PreferredCodec }},
+	{env: []string{"AGENTCOMMS_QUIET_HOURS", "AGENTCALL_QUIET_HOURS"}, value: func(c *Config) string { return c.QuietHours }},
+	{env: []string{"AGENTCOMMS_MAX_CALLS_PER_DAY", "AGENTCALL_MAX_CALLS_PER_DAY"}, value: func(c *Config) string { return strconv.Itoa(c.MaxCallsPerDay) }},
+	{env: []string{"AGENTCOMMS_MAX_DAILY_COST_USD", "AGENTCALL_MAX_DAILY_COST_USD"}, value: func(c *Config) string { return strconv.FormatFloat(c.MaxDailyCostUSD, 'g', -1, 64) }},
+	{env: []string{"AGENTCOMMS_CALL_COST_PER_MINUTE_USD", "AGENTCALL_CALL_COST_PER_MINUTE_USD"}, value: func(c *Config) string { return strconv.FormatFloat(c.CallCostPerMinuteUSD, 'g', -1, 64) }},
+	{env: []string{"AGENTCOMMS_MAX_MESSAGE_CHARS", "AGENTCALL_MAX_MESSAGE_CHARS"}, value: func(c *Config) string { return strconv.Itoa(c.MaxMessageChars) }},
+	{env: []string{"AGENTCOMMS_MAX_TTS_CHARS_PER_CALL", "AGENTCALL_MAX_TTS_CHARS_PER_CALL"}, value: func(c *Config) string { return strconv.Itoa(c.MaxTTSCharsPerCall) }},
+	{env: []string{"AGENTCOMMS_MAX_STT_SECONDS_PER_CALL", "AGENTCALL_MAX_STT_SECONDS_PER_CALL"}, value: func(c *Config) string { return strconv.Itoa(c.MaxSTTSecondsPerCall) }},
+	{env: []string{"AGENTCOMMS_DESCRIPTION_REFRESH_MS", "AGENTCALL_DESCRIPTION_REFRESH_MS"}, value: func(c *Config) string { return strconv.Itoa(c.DescriptionRefreshMS) }},
+	{env: []string{"AGENTCOMMS_CONFERENCE_NUMBERS", "AGENTCALL_CONFERENCE_NUMBERS"}, value: func(c *Config) string { return strings.Join(c.ConferenceNumbers, ",") }},
+	{env: []string{"AGENTCOMMS_CALL_STORE_PATH", "AGENTCALL_CALL_STORE_PATH"}, value: func(c *Config) string { return c.CallStorePath }},
+	{env: []string{"AGENTCOMMS_TRANSCRIPT_SINK", "AGENTCALL_TRANSCRIPT_SINK"}, value: func(c *Config) string { return c.TranscriptSink }},
+	{env: []string{"AGENTCOMMS_TRANSCRIPT_ASSISTANT_ROLE", "AGENTCALL_TRANSCRIPT_ASSISTANT_ROLE"}, value: func(c *Config) string { return c.TranscriptAssistantRole }},
+	{env: []string{"AGENTCOMMS_TRANSCRIPT_USER_ROLE", "AGENTCALL_TRANSCRIPT_USER_ROLE"}, value: func(c *Config) string { return c.TranscriptUserRole }},
+	{env: []string{"AGENTCOMMS_CALL_ENDED_WEBHOOK", "AGENTCALL_CALL_ENDED_WEBHOOK"}, value: func(c *Config) string { return c.CallEndedWebhook }},
+	{env: []string{"AGENTCOMMS_CALL_ENDED_WEBHOOK_SECRET", "AGENTCALL_CALL_ENDED_WEBHOOK_SECRET"}, secret: true, value: func(c *Config) string { return c.CallEndedWebhookSecret }},
+	{env: []string{"AGENTCOMMS_TRIGGER_TOKEN", "AGENTCALL_TRIGGER_TOKEN"}, secret: true, value: func(c *Config) string { return c.TriggerToken }},
+	{env: []string{"AGENTCOMMS_SENTIMENT", "AGENTCALL_SENTIMENT"}, value: func(c *Config) string { return c.Sentiment }},
+	{env: []string{"AGENTCOMMS_PRONUNCIATION_DICT", "AGENTCALL_PRONUNCIATION_DICT"}, value: func(c *Config) string { return c.PronunciationDict }},
+
+	{env: []string{"AGENTCOMMS_ENABLE_RECORDING"}, value: func(c *Config) string { return strconv.FormatBool(c.EnableRecording) }},
+	{env: []string{"AGENTCOMMS_RECORDING_CONSENT", "AGENTCALL_RECORDING_CONSENT"}, value: func(c *Config) string { return c.RecordingConsent }},
+	{env: []string{"AGENTCOMMS_RECORDING_CONSENT_VOICE", "AGENTCALL_RECORDING_CONSENT_VOICE"}, value: func(c *Config) string { return c.RecordingConsentVoice }},
+	{env: []string{"AGENTCOMMS_AUDIO_DUMP_DIR", "AGENTCALL_AUDIO_DUMP_DIR"}, value: func(c *Config) string { return c.AudioDumpDir }},
+	{env: []string{"AGENTCOMMS_AUDIO_DIR", "AGENTCALL_AUDIO_DIR"}, value: func(c *Config) string { return c.AudioDir }},
+	{env: []string{"AGENTCOMMS_SMS_FALLBACK_ENABLED"}, value: func(c *Config) string { return strconv.FormatBool(c.SMSFallbackEnabled) }},
+	{env: []string{"AGENTCOMMS_SMS_FALLBACK_MESSAGE"}, value: func(c *Config) string { return c.SMSFallbackMessage }},
+	{env: []string{"AGENTCOMMS_REQUIRE_ACCEPT", "AGENTCALL_REQUIRE_ACCEPT"}, value: func(c *Config) string { return strconv.FormatBool(c.RequireAccept) }},
+	{env: []string{"AGENTCOMMS_REQUIRE_CONFIRMATION", "AGENTCALL_REQUIRE_CONFIRMATION"}, value: func(c *Config) string { return strconv.FormatBool(c.RequireConfirmation) }},
+	{env: []string{"AGENTCOMMS_KEEPALIVE", "AGENTCALL_KEEPALIVE"}, value: func(c *Config) string { return strconv.FormatBool(c.KeepAlive) }},
+	{env: []string{"AGENTCOMMS_REQUIRE_PROVIDERS", "AGENTCALL_REQUIRE_PROVIDERS"}, value: func(c *Config) string { return strconv.FormatBool(c.RequireProviders) }},
+	{env: []string{"AGENTCOMMS_SMS_ENABLED"}, value: func(c *Config) string { return strconv.FormatBool(c.SMSEnabled) }},
+	{env: []string{"AGENTCOMMS_WEBHOOK_ENABLED"}, value: func(c *Config) string { return strconv.FormatBool(c.WebhookEnabled) }},
+	{env: []string{"AGENTCOMMS_WEBHOOK_PORT"}, value: func(c *Config) string { return strconv.Itoa(c.WebhookPort) }},
+
+	{env: []string{"AGENTCOMMS_TTS_PROVIDER", "AGENTCALL_TTS_PROVIDER"}, value: func(c *Config) string { return c.TTSProvider }},
+	{env: []string{"AGENTCOMMS_STT_PROVIDER", "AGENTCALL_STT_PROVIDER"}, value: func(c *Config) string { return c.STTProvider }},
+	{env: []string{"AGENTCOMMS_ELEVENLABS_API_KEY", "AGENTCALL_ELEVENLABS_API_KEY", "ELEVENLABS_API_KEY"}, secret: true, value: func(c *Config) string { return c.ElevenLabsAPIKey }},
+	{env: []string{"AGENTCOMMS_DEEPGRAM_API_KEY", "AGENTCALL_DEEPGRAM_API_KEY", "DEEPGRAM_API_KEY"}, secret: true, value: func(c *Config) string { return c.DeepgramAPIKey }},
+	{env: []string{"AGENTCOMMS_OPENAI_API_KEY", "OPENAI_API_KEY"}, secret: true, value: func(c *Config) string { return c.OpenAIAPIKey }},
+
+	{env: []string{"AGENTCOMMS_TTS_VOICE", "AGENTCALL_TTS_VOICE"}, value: func(c *Config) string { return c.TTSVoice }},
+	{env: []string{"AGENTCOMMS_TTS_MODEL", "AGENTCALL_TTS_MODEL"}, value: func(c *Config) string { return c.TTSModel }},
+	{env: []string{"AGENTCOMMS_TTS_ENABLE_TAGS", "AGENTCALL_TTS_ENABLE_TAGS"}, value: func(c *Config) string { return strconv.FormatBool(c.TTSEnableTags) }},
+	{env: []string{"AGENTCOMMS_TTS_STREAM_RETRIES", "AGENTCALL_TTS_STREAM_RETRIES"}, value: func(c *Config) string { return strconv.Itoa(c.TTSStreamRetries) }},
+	{env: []string{"AGENTCOMMS_SPEAKING_RATE", "AGENTCALL_SPEAKING_RATE"}, value: func(c *Config) string { return strconv.FormatFloat(c.SpeakingRate, 'g', -1, 64) }},
+	{env: []string{"AGENTCOMMS_TTS_CONTINUITY", "AGENTCALL_TTS_CONTINUITY"}, value: func(c *Config) string { return strconv.Itoa(c.TTSContinuityTurns) }},
+	{env: []string{"AGENTCOMMS_TRIM_SILENCE", "AGENTCALL_TRIM_SILENCE"}, value: func(c *Config) string { return strconv.FormatBool(c.TrimSilence) }},
+	{env: []string{"AGENTCOMMS_TRIM_SILENCE_THRESHOLD", "AGENTCALL_TRIM_SILENCE_THRESHOLD"}, value: func(c *Config) string { return strconv.Itoa(c.TrimSilenceThreshold) }},
+	{env: []string{"AGENTCOMMS_TRIM_SILENCE_PAD_MS", "AGENTCALL_TRIM_SILENCE_PAD_MS"}, value: func(c *Config) string { return strconv.Itoa(c.TrimSilencePadMS) }},
+	{env: []string{"AGENTCOMMS_TTS_FRAME_MS", "AGENTCALL_TTS_FRAME_MS"}, value: func(c *Config) string { return strconv.Itoa(c.TTSFrameMS) }},
+	{env: []string{"AGENTCOMMS_TTS_PREFETCH", "AGENTCALL_TTS_PREFETCH"}, value: func(c *Config) string { return strconv.FormatBool(c.TTSPrefetch) }},
+	{env: []string{"AGENTCOMMS_TTS_CACHE", "AGENTCALL_TTS_CACHE"}, value: func(c *Config) string { return strconv.FormatBool(c.TTSCache) }},
+	{env: []string{"AGENTCOMMS_TTS_CACHE_MAX_BYTES", "AGENTCALL_TTS_CACHE_MAX_BYTES"}, value: func(c *Config) string { return strconv.Itoa(c.TTSCacheMaxBytes) }},
+	{env: []string{"AGENTCOMMS_STT_MODEL", "AGENTCALL_STT_MODEL"}, value: func(c *Config) string { return c.STTModel }},
+	{env: []string{"AGENTCOMMS_STT_LANGUAGE", "AGENTCALL_STT_LANGUAGE"}, value: func(c *Config) string { return c.STTLanguage }},
+	{env: []string{"AGENTCOMMS_STT_SILENCE_DURATION_MS", "AGENTCALL_STT_SILENCE_DURATION_MS"}, value: func(c *Config) string { return strconv.Itoa(c.STTSilenceDurationMS) }},
+	{env: []string{"AGENTCOMMS_AGGREGATE_FINALS_MS", "AGENTCALL_AGGREGATE_FINALS_MS"}, value: func(c *Config) string { return strconv.Itoa(c.AggregateFinalsMS) }},
+	{env: []string{"AGENTCOMMS_STT_ADAPTIVE_SILENCE", "AGENTCALL_STT_ADAPTIVE_SILENCE"}, value: func(c *Config) string { return strconv.FormatBool(c.AdaptiveSilence) }},
+	{env: []string{"AGENTCOMMS_STT_ADAPTIVE_SILENCE_MIN_MS", "AGENTCALL_STT_ADAPTIVE_SILENCE_MIN_MS"}, value: func(c *Config) string { return strconv.Itoa(c.AdaptiveSilenceMinMS) }},
+	{env: []string{"AGENTCOMMS_STT_ADAPTIVE_SILENCE_MAX_MS", "AGENTCALL_STT_ADAPTIVE_SILENCE_MAX_MS"}, value: func(c *Config) string { return strconv.Itoa(c.AdaptiveSilenceMaxMS) }},
+	{env: []string{"AGENTCOMMS_MAX_UTTERANCE_MS", "AGENTCALL_MAX_UTTERANCE_MS"}, value: func(c *Config) string { return strconv.Itoa(c.MaxUtteranceMS) }},
+	{env: []string{"AGENTCOMMS_STT_PERSIST_CONNECTION", "AGENTCALL_STT_PERSIST_CONNECTION"}, value: func(c *Config) string { return strconv.FormatBool(c.STTPersistConnection) }},
+	{env: []string{"AGENTCOMMS_STT_CONTEXT_KEYWORDS", "AGENTCALL_STT_CONTEXT_KEYWORDS"}, value: func(c *Config) string { return strconv.FormatBool(c.STTContextKeywords) }},
+	{env: []string{"AGENTCOMMS_STT_CONTEXT_KEYWORD_MAX", "AGENTCALL_STT_CONTEXT_KEYWORD_MAX"}, value: func(c *Config) string { return strconv.Itoa(c.STTContextKeywordMax) }},
+	{env: []string{"AGENTCOMMS_STT_LANGUAGE_DETECTION", "AGENTCALL_STT_LANGUAGE_DETECTION"}, value: func(c *Config) string { return strconv.FormatBool(c.LanguageDetection) }},
+	{env: []string{"AGENTCOMMS_GOODBYE_PHRASES", "AGENTCALL_GOODBYE_PHRASES"}, value: func(c *Config) string { return strings.Join(c.GoodbyePhrases, ",") }},
+	{env: []string{"AGENTCOMMS_YES_PHRASES", "AGENTCALL_YES_PHRASES"}, value: func(c *Config) string { return strings.Join(c.YesPhrases, ",") }},
+	{env: []string{"AGENTCOMMS_NO_PHRASES", "AGENTCALL_NO_PHRASES"}, value: func(c *Config) string { return strings.Join(c.NoPhrases, ",") }},
+	{env: []string{"AGENTCOMMS_CONFIRM_REPROMPTS", "AGENTCALL_CONFIRM_REPROMPTS"}, value: func(c *Config) string { return strconv.Itoa(c.ConfirmReprompts) }},
+
+	{env: []string{"AGENTCOMMS_TUNNEL", "AGENTCALL_TUNNEL"}, value: func(c *Config) string { return c.Tunnel }},
+	{env: []string{"AGENTCOMMS_PUBLIC_URL", "AGENTCALL_PUBLIC_URL"}, value: func(c *Config) string { return c.PublicURL }},
+	{env: []string{"AGENTCOMMS_NGROK_AUTHTOKEN", "AGENTCALL_NGROK_AUTHTOKEN", "NGROK_AUTHTOKEN"}, secret: true, value: func(c *Config) string { return c.NgrokAuthToken }},
+	{env: []string{"AGENTCOMMS_NGROK_DOMAIN", "AGENTCALL_NGROK_DOMAIN"}, value: func(c *Config) string { return c.NgrokDomain }},
+	{env: []string{"AGENTCOMMS_CLOUDFLARED_PATH", "AGENTCALL_CLOUDFLARED_PATH"}, value: func(c *Config) string { return c.CloudflaredPath }},
+	{env: []string{"AGENTCOMMS_CLOUDFLARED_TOKEN", "AGENTCALL_CLOUDFLARED_TOKEN"}, secret: true, value: func(c *Config) string { return c.CloudflaredToken }},
+	{env: []string{"AGENTCOMMS_CLOUDFLARED_HOSTNAME", "AGENTCALL_CLOUDFLARED_HOSTNAME"}, value: func(c *Config) string { return c.CloudflaredHostname }},
+	{env: []string{"AGENTCOMMS_TUNNEL_READY_TIMEOUT_MS", "AGENTCALL_TUNNEL_READY_TIMEOUT_MS"}, value: func(c *Config) string { return strconv.Itoa(c.TunnelReadyTimeoutMS) }},
+	{env: []string{"AGENTCOMMS_TRANSCRIPT_TIMEOUT_MS", "AGENTCALL_TRANSCRIPT_TIMEOUT_MS"}, value: func(c *Config) string { return strconv.Itoa(c.TranscriptTimeoutMS) }},
+	{env: []string{"AGENTCOMMS_POST_SPEECH_DELAY_MS", "AGENTCALL_POST_SPEECH_DELAY_MS"}, value: func(c *Config) string { return strconv.Itoa(c.PostSpeechDelayMS) }},
+	{env: []string{"AGENTCOMMS_ECHO_GUARD_MS", "AGENTCALL_ECHO_GUARD_MS"}, value: func(c *Config) string { return strconv.Itoa(c.EchoGuardMS) }},
+	{env: []string{"AGENTCOMMS_AMD_WAIT_MS", "AGENTCALL_AMD_WAIT_MS"}, value: func(c *Config) string { return strconv.Itoa(c.AMDWaitMS) }},
+	{env: []string{"AGENTCOMMS_STATUS_POLL_MS", "AGENTCALL_STATUS_POLL_MS"}, value: func(c *Config) string { return strconv.Itoa(c.StatusPollMS) }},
+	{env: []string{"AGENTCOMMS_RESUME_WINDOW_MS", "AGENTCALL_RESUME_WINDOW_MS"}, value: func(c *Config) string { return strconv.Itoa(c.ResumeWindowMS) }},
+	{env: []string{"AGENTCOMMS_MAX_CALL_LIFETIME_MS", "AGENTCALL_MAX_CALL_LIFETIME_MS"}, value: func(c *Config) string { return strconv.Itoa(c.MaxCallLifetimeMS) }},
+	{env: []string{"AGENTCOMMS_REDIAL_ATTEMPTS", "AGENTCALL_REDIAL_ATTEMPTS"}, value: func(c *Config) string { return strconv.Itoa(c.RedialAttempts) }},
+	{env: []string{"AGENTCOMMS_REDIAL_DELAY_MS", "AGENTCALL_REDIAL_DELAY_MS"}, value: func(c *Config) string { return strconv.Itoa(c.RedialDelayMS) }},
+	{env: []string{"AGENTCOMMS_WAIT_FOR_GREETING", "AGENTCALL_WAIT_FOR_GREETING"}, value: func(c *Config) string { return strconv.FormatBool(c.WaitForGreeting) }},
+	{env: []string{"AGENTCOMMS_GREETING_WAIT_MS", "AGENTCALL_GREETING_WAIT_MS"}, value: func(c *Config) string { return strconv.Itoa(c.GreetingWaitMS) }},
+	{env: []string{"AGENTCOMMS_GREETING_SILENCE_MS", "AGENTCALL_GREETING_SILENCE_MS"}, value: func(c *Config) string { return strconv.Itoa(c.GreetingSilenceMS) }},
+	{env: []string{"AGENTCOMMS_CONNECT_TONE", "AGENTCALL_CONNECT_TONE"}, value: func(c *Config) string { return strconv.FormatBool(c.ConnectTone) }},
+	{env: []string{"AGENTCOMMS_CONNECT_TONE_HZ", "AGENTCALL_CONNECT_TONE_HZ"}, value: func(c *Config) string { return strconv.Itoa(c.ConnectToneHz) }},
+	{env: []string{"AGENTCOMMS_CONNECT_TONE_MS", "AGENTCALL_CONNECT_TONE_MS"}, value: func(c *Config) string { return strconv.Itoa(c.ConnectToneMS) }},
+
+	{env: []string{"AGENTCOMMS_WHATSAPP_ENABLED"}, value: func(c *Config) string { return strconv.FormatBool(c.WhatsAppEnabled) }},
+	{env: []string{"AGENTCOMMS_WHATSAPP_DB_PATH"}, value: func(c *Config) string { return c.WhatsAppDBPath }},
+	{env: []string{"AGENTCOMMS_DISCORD_ENABLED"}, value: func(c *Config) string { return strconv.FormatBool(c.DiscordEnabled) }},
+	{env: []string{"AGENTCOMMS_DISCORD_TOKEN", "DISCORD_TOKEN"}, secret: true, value: func(c *Config) string { return c.DiscordToken }},
+	{env: []string{"AGENTCOMMS_DISCORD_GUILD_ID"}, value: func(c *Config) string { return c.DiscordGuildID }},
+	{env: []string{"AGENTCOMMS_TELEGRAM_ENABLED"}, value: func(c *Config) string { return strconv.FormatBool(c.TelegramEnabled) }},
+	{env: []string{"AGENTCOMMS_TELEGRAM_TOKEN", "TELEGRAM_BOT_TOKEN"}, secret: true, value: func(c *Config) string { return c.TelegramToken }},
+	{env: []string{"AGENTCOMMS_SLACK_ENABLED"}, value: func(c *Config) string { return strconv.FormatBool(c.SlackEnabled) }},
+	{env: []string{"AGENTCOMMS_SLACK_BOT_TOKEN", "SLACK_BOT_TOKEN"}, secret: true, value: func(c *Config) string { return c.SlackBotToken }},
+	{env: []string{"AGENTCOMMS_SLACK_APP_TOKEN", "SLACK_APP_TOKEN"}, secret: true, value: func(c *Config) string { return c.SlackAppToken }},
+	{env: []string{"AGENTCOMMS_GMAIL_ENABLED"}, value: func(c *Config) string { return strconv.FormatBool(c.GmailEnabled) }},
+	{env: []string{"AGENTCOMMS_GMAIL_CREDENTIALS_FILE", "GMAIL_CREDENTIALS_FILE"}, value: func(c *Config) string { return c.GmailCredentialsFile }},
+	{env: []string{"AGENTCOMMS_GMAIL_TOKEN_FILE", "GMAIL_TOKEN_FILE"}, value: func(c *Config) string { return c.GmailTokenFile }},
+	{env: []string{"AGENTCOMMS_GMAIL_FROM_ADDRESS"}, value: func(c *Config) string { return c.GmailFromAddress }},
+	{env: []string{"AGENTCOMMS_IRC_ENABLED"}, value: func(c *Config) string { return strconv.FormatBool(c.IRCEnabled) }},
+	{env: []string{"AGENTCOMMS_IRC_SERVER", "IRC_SERVER"}, value: func(c *Config) string { return c.IRCServer }},
+	{env: []string{"AGENTCOMMS_IRC_NICK", "IRC_NICK"}, value: func(c *Config) string { return c.IRCNick }},
+	{env: []string{"AGENTCOMMS_IRC_PASSWORD", "IRC_PASSWORD"}, secret: true, value: func(c *Config) string { return c.IRCPassword }},
+	{env: []string{"AGENTCOMMS_IRC_CHANNELS"}, value: func(c *Config) string { return strings.Join(c.IRCChannels, ",") }},
+	{env: []string{"AGENTCOMMS_IRC_USE_TLS"}, value: func(c *Config) string { return strconv.FormatBool(c.IRCUseTLS) }},
+}
+
+// Redacted returns the effective value of every setting with secrets masked.
+// Each setting reports the environment variable it was read from, or
+// SourceDefault when none of its variables are set.
+func (c *Config) Redacted() []Setting {
+	out := make([]Setting, 0, len(settings))
+	for _, s := range settings {
+		value := s.value(c)
+		if s.secret {
+			value = maskSecret(value)
+		}
+		out = append(out, Setting{
+			Name:   s.env[0],
+			Value:  value,
+			Source: envSource(s.env),
+		})
+	}
+	return out
+}
+
+// String returns the redacted configuration, one NAME=value line per setting.
+func (c *Config) String() string {
+	var b strings.Builder
+	for _, s := range c.Redacted() {
+		fmt.Fprintf(&b, "%s=%s (%s)\n", s.Name, s.Value, s.Source)
+	}
+	return b.String()
+}
+
+// envSource returns the first of the given environment variables that is set.
+func envSource(names []string) string {
+	for _, name := range names {
+		if os.Getenv(name) != "" {
+			return name
+		}
+	}
+	return SourceDefault
+}
+
+// maskSecret hides a secret, keeping the last four characters of long values
+// so users can tell which key is in use.
+func maskSecret(s string) string {
+	switch {
+	case s == "":
+		return ""
+	case len(s) < 12:
+		return "****"
+	default:
+		return "****" + s[len(s)-4:]
+	}
+}
@@ -0,0 +1,63 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMaskSecret(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"", ""},
+		{"short", "****"},
+		{"sk-abcdefgh1234", "****1234"},
+	}
+
+	for _, tt := range tests {
+		if got := maskSecret(tt.input); got != tt.want {
+			t.Errorf("maskSecret(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestRedacted(t *testing.T) {
+	t.Setenv("AGENTCOMMS_TTS_VOICE", "")
+	t.Setenv("AGENTCALL_PORT", "4444")
+	t.Setenv("DEEPGRAM_API_KEY", "dg-secret-value-9876")
+
+	cfg := DefaultConfig()
+	cfg.Port = 4444
+	cfg.DeepgramAPIKey = "dg-secret-value-9876"
+
+	got := make(map[string]Setting)
+	for _, s := range cfg.Redacted() {
+		got[s.Name] = s
+	}
+
+	tests := []struct {
+		name   string
+		value  string
+		source string
+	}{
+		{"AGENTCOMMS_PORT", "4444", "AGENTCALL_PORT"},
+		{"AGENTCOMMS_DEEPGRAM_API_KEY", "****9876", "DEEPGRAM_API_KEY"},
+		{"AGENTCOMMS_TTS_VOICE", "Rachel", SourceDefault},
+	}
+
+	for _, tt := range tests {
+		s, ok := got[tt.name]
+		if !ok {
+			t.Errorf("setting %s missing", tt.name)
+			continue
+		}
+		if s.Value != tt.value || s.Source != tt.source {
+			t.Errorf("%s = (%q, %q), want (%q, %q)", tt.name, s.Value, s.Source, tt.value, tt.source)
+		}
+	}
+
+	if strings.Contains(cfg.String(), "dg-secret-value") {
+		t.Error("String() leaked a secret")
+	}
+}
@@ -0,0 +1,126 @@
+package config
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"slices"
+
+	"github.com/plexusone/agentcomms/internal/tenant"
+)
+
+// Tenant is a user of a hosted deployment, identified by the API key its
+// MCP requests carry. Its calls use the base config with the tenant's
+// settings applied.
+type Tenant struct {
+	ID     string `json:"id"`
+	APIKey string `json:"api_key"`
+
+	// UserPhoneNumber is the number the tenant's calls ring (required).
+	UserPhoneNumber string `json:"user_number"`
+
+	// PhoneNumber, CallerIDName, and Voice replace the base settings when
+	// set. PhoneNumber must be a number of the same provider account.
+	PhoneNumber  string `json:"phone_number,omitempty"`
+	CallerIDName string `json:"caller_id_name,omitempty"`
+	Voice        string `json:"voice,omitempty"`
+
+	// ConferenceNumbers may be dialed into the tenant's conferences
+	// besides its own number. The base list is never inherited.
+	ConferenceNumbers []string `json:"conference_numbers,omitempty"`
+}
+
+// apply returns a copy of base with t's settings.
+func (t Tenant) apply(base *Config) *Config {
+	cfg := *base
+	cfg.UserPhoneNumber = t.UserPhoneNumber
+	cfg.ConferenceNumbers = slices.Clone(t.ConferenceNumbers)
+	if t.PhoneNumber != "" {
+		cfg.PhoneNumber = t.PhoneNumber
+	}
+	if t.CallerIDName != "" {
+		cfg.CallerIDName = t.CallerIDName
+	}
+	if t.Voice != "" {
+		cfg.TTSVoice = t.Voice
+	}
+	cfg.Tenants = nil
+	return &cfg
+}
+
+// validateTenants returns an error message for each tenant without an ID,
+// API key, or user number, and for IDs and keys used twice.
+func validateTenants(tenants []Tenant) []string {
+	var errors []string
+	ids := make(map[string]bool)
+	keys := make(map[string]bool)
+	for i, t := range tenants {
+		if t.ID == "" {
+			errors = append(errors, fmt.Sprintf("tenant %d: id is required", i))
+			continue
+		}
+		if t.ID == tenant.DefaultTenantID {
+			errors = append(errors, fmt.Sprintf("tenant ID %q is reserved", t.ID))
+		}
+		if ids[t.ID] {
+			errors = append(errors, fmt.Sprintf("duplicate tenant ID: %s", t.ID))
+		}
+		ids[t.ID] = true
+
+		switch {
+		case t.APIKey == "":
+			errors = append(errors, fmt.Sprintf("tenant %s: api_key is required", t.ID))
+		case keys[t.APIKey]:
+			errors = append(errors, fmt.Sprintf("tenant %s: api_key is used by another tenant", t.ID))
+		}
+		keys[t.APIKey] = true
+
+		if t.UserPhoneNumber == "" {
+			errors = append(errors, fmt.Sprintf("tenant %s: user_number is required", t.ID))
+		}
+	}
+	return errors
+}
+
+// TenantStore resolves API keys to tenants and holds each tenant's merged
+// config.
+type TenantStore struct {
+	tenants []Tenant
+	configs map[string]*Config
+}
+
+// NewTenantStore returns a store for base's tenants, or nil if it has none.
+func NewTenantStore(base *Config) *TenantStore {
+	if len(base.Tenants) == 0 {
+		return nil
+	}
+	s := &TenantStore{
+		tenants: base.Tenants,
+		configs: make(map[string]*Config, len(base.Tenants)),
+	}
+	for _, t := range base.Tenants {
+		s.configs[t.ID] = t.apply(base)
+	}
+	return s
+}
+
+// Authenticate returns the ID of the tenant with the given API key.
+func (s *TenantStore) Authenticate(apiKey string) (string, bool) {
+	if apiKey == "" {
+		return "", false
+	}
+	for _, t := range s.tenants {
+		if subtle.ConstantTimeCompare([]byte(apiKey), []byte(t.APIKey)) == 1 {
+			return t.ID, true
+		}
+	}
+	return "", false
+}
+
+// Config returns the config for the tenant's calls, or nil for an unknown
+// tenant.
+func (s *TenantStore) Config(id string) *Config {
+	if s == nil {
+		return nil
+	}
+	return s.configs[id]
+}
@@ -0,0 +1,86 @@
+package config
+
+import (
+	"slices"
+	"testing"
+)
+
+func testTenants() []Tenant {
+	return []Tenant{
+		{ID: "acme", APIKey: "key-acme", UserPhoneNumber: "+15550001111", PhoneNumber: "+15550004444", Voice: "voice-acme"},
+		{ID: "globex", APIKey: "key-globex", UserPhoneNumber: "+15550002222", ConferenceNumbers: []string{"+15550003333"}},
+	}
+}
+
+func TestTenantStore(t *testing.T) {
+	base := DefaultConfig()
+	base.PhoneNumber = "+15558888888"
+	base.UserPhoneNumber = "+15559999999"
+	base.CallerIDName = "Agent"
+	base.ConferenceNumbers = []string{"+15558888888"}
+	base.Tenants = testTenants()
+
+	s := NewTenantStore(base)
+	if id, ok := s.Authenticate("key-globex"); !ok || id != "globex" {
+		t.Errorf("Authenticate(key-globex) = %q, %v; want globex", id, ok)
+	}
+	for _, key := range []string{"", "key-unknown"} {
+		if _, ok := s.Authenticate(key); ok {
+			t.Errorf("Authenticate(%q) succeeded, want failure", key)
+		}
+	}
+
+	acme := s.Config("acme")
+	if acme.UserPhoneNumber != "+15550001111" || acme.TTSVoice != "voice-acme" || acme.CallerIDName != "Agent" {
+		t.Errorf("Config(acme) = user %q, voice %q, caller ID %q", acme.UserPhoneNumber, acme.TTSVoice, acme.CallerIDName)
+	}
+	if len(acme.ConferenceNumbers) != 0 || acme.Tenants != nil {
+		t.Errorf("Config(acme) inherited conference numbers %v or tenants", acme.ConferenceNumbers)
+	}
+	if acme.PhoneNumber != "+15550004444" {
+		t.Errorf("Config(acme).PhoneNumber = %q, want the tenant's", acme.PhoneNumber)
+	}
+	if got := s.Config("globex").ConferenceNumbers; !slices.Equal(got, []string{"+15550003333"}) {
+		t.Errorf("Config(globex).ConferenceNumbers = %v", got)
+	}
+	if got := s.Config("globex").PhoneNumber; got != "+15558888888" {
+		t.Errorf("Config(globex).PhoneNumber = %q, want the base number", got)
+	}
+	if base.UserPhoneNumber != "+15559999999" {
+		t.Errorf("base UserPhoneNumber changed to %q", base.UserPhoneNumber)
+	}
+	if s.Config("unknown") != nil {
+		t.Error("Config(unknown) != nil")
+	}
+
+	if NewTenantStore(DefaultConfig()) != nil {
+		t.Error("NewTenantStore() without tenants != nil")
+	}
+}
+
+func TestValidateTenants(t *testing.T) {
+	if errs := validateTenants(testTenants()); len(errs) != 0 {
+		t.Errorf("validateTenants() = %v, want none", errs)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(tenants []Tenant)
+	}{
+		{"missing ID", func(ts []Tenant) { ts[0].ID = "" }},
+		{"reserved ID", func(ts []Tenant) { ts[0].ID = "local" }},
+		{"duplicate ID", func(ts []Tenant) { ts[1].ID = ts[0].ID }},
+		{"missing API key", func(ts []Tenant) { ts[0].APIKey = "" }},
+		{"duplicate API key", func(ts []Tenant) { ts[1].APIKey = ts[0].APIKey }},
+		{"missing user number", func(ts []Tenant) { ts[1].UserPhoneNumber = "" }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tenants := testTenants()
+			tt.modify(tenants)
+			if errs := validateTenants(tenants); len(errs) != 1 {
+				t.Errorf("validateTenants() = %v, want one error", errs)
+			}
+		})
+	}
+}
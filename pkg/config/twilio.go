@@ -0,0 +1,29 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// TwilioRegions are the Twilio Regions that can process calls.
+var TwilioRegions = []string{"us1", "ie1", "au1"}
+
+// TwilioEdges are the Twilio edge locations media and API traffic can
+// connect through. "roaming" lets Twilio pick the nearest edge.
+var TwilioEdges = []string{
+	"ashburn", "dublin", "frankfurt", "sao-paulo", "singapore", "sydney", "tokyo", "umatilla", "roaming",
+	"ashburn-ix", "frankfurt-ix", "london-ix", "san-jose-ix", "singapore-ix", "sydney-ix", "tokyo-ix",
+}
+
+// validateTwilioLocation checks a Twilio region and edge. Empty values use
+// Twilio's defaults (us1 via ashburn).
+func validateTwilioLocation(region, edge string) error {
+	if region != "" && !slices.Contains(TwilioRegions, region) {
+		return fmt.Errorf("invalid Twilio region %q (must be one of %s)", region, strings.Join(TwilioRegions, ", "))
+	}
+	if edge != "" && !slices.Contains(TwilioEdges, edge) {
+		return fmt.Errorf("invalid Twilio edge %q (must be one of %s)", edge, strings.Join(TwilioEdges, ", "))
+	}
+	return nil
+}
@@ -2,6 +2,7 @@
 package config
 
 import (
+	"cmp"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -37,8 +38,34 @@ type ServerConfig struct {
 	// Port is the server port (default: 3333).
 	Port int `json:"port,omitempty"`
 
+	// BindAddr is the host or IP address to listen on (default: all
+	// interfaces), e.g. "127.0.0.1" when a tunnel handles exposure.
+	BindAddr string `json:"bind_addr,omitempty"`
+
+	// Transport is the MCP transport, "http" (default) or "stdio". With
+	// stdio, voice needs voice.public_url since no tunnel is started.
+	Transport string `json:"transport,omitempty"`
+
 	// DataDir overrides the default data directory (~/.agentcomms).
 	DataDir string `json:"data_dir,omitempty"`
+
+	// Restarts is how many times in a row the server is restarted after
+	// failing while running (default: 5). Use -1 to exit on the first
+	// failure.
+	Restarts int `json:"restarts,omitempty"`
+
+	// RestartBackoffMS is the wait before the first restart in
+	// milliseconds, doubled after each (default: 1000).
+	RestartBackoffMS int `json:"restart_backoff_ms,omitempty"`
+
+	// EnabledTools limits the MCP tools registered to these names (default:
+	// all tools).
+	EnabledTools []string `json:"enabled_tools,omitempty"`
+
+	// CORSOrigins are the browser origins allowed to call the trigger,
+	// do-not-disturb, and health endpoints, or "*" for any (default: none,
+	// same-origin only). Twilio webhooks never allow other origins.
+	CORSOrigins []string `json:"cors_origins,omitempty"`
 }
 
 // AgentConfig defines an agent and its tmux target.
@@ -67,11 +94,153 @@ type VoiceConfig struct {
 	// STT (speech-to-text) settings.
 	STT STTConfig `json:"stt"`
 
+	// Tunnel selects the webhook tunnel ("ngrok" or "cloudflared", default: "ngrok").
+	Tunnel string `json:"tunnel,omitempty"`
+
+	// PublicURL is a static public https URL for webhooks. When set, no
+	// tunnel is started and tunnel credentials are not required.
+	PublicURL string `json:"public_url,omitempty"`
+
 	// Ngrok settings for webhook tunneling.
 	Ngrok NgrokConfig `json:"ngrok"`
 
+	// Cloudflared settings for webhook tunneling.
+	Cloudflared *CloudflaredConfig `json:"cloudflared,omitempty"`
+
+	// TunnelReadyTimeoutMS is how long the server waits for the tunnel to
+	// come up before it exits with an error (default: 120000, 0 = no limit).
+	TunnelReadyTimeoutMS *int `json:"tunnel_ready_timeout_ms,omitempty"`
+
 	// TranscriptTimeoutMS is the transcript timeout in milliseconds.
 	TranscriptTimeoutMS int `json:"transcript_timeout_ms,omitempty"`
+
+	// ResumeWindowMS keeps an answered call that drops without end_call
+	// this long so resume_call can re-dial it (0 = off).
+	ResumeWindowMS int `json:"resume_window_ms,omitempty"`
+
+	// MaxCallLifetimeMS is how long a call may last before it is hung up
+	// and removed as abandoned (default: 14400000, 0 = no limit).
+	MaxCallLifetimeMS *int `json:"max_call_lifetime_ms,omitempty"`
+
+	// RedialAttempts is how many more times a call that rings out or is
+	// busy is dialed before giving up (0 = off), RedialDelayMS apart
+	// (default: 30000).
+	RedialAttempts int `json:"redial_attempts,omitempty"`
+	RedialDelayMS  int `json:"redial_delay_ms,omitempty"`
+
+	// QuietHours is a daily local-time window during which no calls are
+	// placed, e.g. "22:00-07:00".
+	QuietHours string `json:"quiet_hours,omitempty"`
+
+	// MaxCallsPerDay caps calls placed in any rolling 24 hours (0 =
+	// unlimited).
+	MaxCallsPerDay int `json:"max_calls_per_day,omitempty"`
+
+	// MaxDailyCostUSD caps the estimated telephony cost of calls in any
+	// rolling 24 hours (0 = unlimited).
+	MaxDailyCostUSD float64 `json:"max_daily_cost_usd,omitempty"`
+
+	// CallCostPerMinuteUSD is the telephony rate used to estimate call
+	// costs (default: 0.03).
+	CallCostPerMinuteUSD float64 `json:"call_cost_per_minute_usd,omitempty"`
+
+	// MaxMessageChars rejects longer messages before they are spoken (0 =
+	// no limit).
+	MaxMessageChars int `json:"max_message_chars,omitempty"`
+
+	// MaxTTSCharsPerCall and MaxSTTSecondsPerCall cap the characters
+	// synthesized and the time spent transcribing on one call (0 = no
+	// limit). A call that reaches either is ended before its next turn.
+	MaxTTSCharsPerCall   int `json:"max_tts_chars_per_call,omitempty"`
+	MaxSTTSecondsPerCall int `json:"max_stt_seconds_per_call,omitempty"`
+
+	// DescriptionRefreshMS is how often tool descriptions are rebuilt from
+	// the live budget and quiet hours (0 = only at startup).
+	DescriptionRefreshMS int `json:"description_refresh_ms,omitempty"`
+
+	// ConferenceNumbers may be dialed into conferences besides the user's
+	// own number.
+	ConferenceNumbers []string `json:"conference_numbers,omitempty"`
+
+	// CallStorePath is the JSON file used to persist scheduled calls
+	// (default: ~/.agentcomms/calls.json).
+	CallStorePath string `json:"call_store_path,omitempty"`
+
+	// TranscriptSink is a file path or http(s) URL that receives each
+	// conversation turn as a JSON line while calls are in progress.
+	TranscriptSink string `json:"transcript_sink,omitempty"`
+
+	// TranscriptRoles renames the roles of turns in transcripts sent to the
+	// transcript sink and the call-ended webhook.
+	TranscriptRoles *TranscriptRolesConfig `json:"transcript_roles,omitempty"`
+
+	// CallEndedWebhook is a URL that receives a JSON summary of each call
+	// after end_call. If CallEndedWebhookSecret is set, the payload is
+	// signed with HMAC-SHA256.
+	CallEndedWebhook       string `json:"call_ended_webhook,omitempty"`
+	CallEndedWebhookSecret string `json:"call_ended_webhook_secret,omitempty"`
+
+	// TriggerToken enables POST /trigger-call for placing calls from
+	// external systems such as CI. Requests must send it as a bearer
+	// token.
+	TriggerToken string `json:"trigger_token,omitempty"`
+
+	// Sentiment enables sentiment analysis of user replies: "local" for
+	// the built-in heuristic or an http(s) URL for an external API.
+	Sentiment string `json:"sentiment,omitempty"`
+
+	// PronunciationDict is a JSON file mapping technical terms to how
+	// they should be spoken. The terms are also boosted in speech
+	// recognition.
+	PronunciationDict string `json:"pronunciation_dict,omitempty"`
+
+	// GoodbyePhrases replace the phrases that mark a reply as the user
+	// wanting to end the call. Empty keeps the defaults.
+	GoodbyePhrases []string `json:"goodbye_phrases,omitempty"`
+
+	// YesPhrases and NoPhrases replace the answers confirm_with_user
+	// accepts as yes and no. Empty keeps the defaults.
+	YesPhrases []string `json:"yes_phrases,omitempty"`
+	NoPhrases  []string `json:"no_phrases,omitempty"`
+
+	// ConfirmReprompts is how many times confirm_with_user asks again
+	// after an unclear answer (default: 2).
+	ConfirmReprompts *int `json:"confirm_reprompts,omitempty"`
+
+	// Tenants lets one server place calls for several users. MCP requests
+	// select a tenant with their X-API-Key header.
+	Tenants []Tenant `json:"tenants,omitempty"`
+
+	// Recording records calls the server places. Recorded calls start with
+	// RecordingConsent, "This call is being recorded." by default.
+	Recording             bool   `json:"recording,omitempty"`
+	RecordingConsent      string `json:"recording_consent,omitempty"`
+	RecordingConsentVoice string `json:"recording_consent_voice,omitempty"`
+
+	// AudioDumpDir, if set, receives the user's and the assistant's audio
+	// of each call in separate WAV files, for debugging audio problems.
+	AudioDumpDir string `json:"audio_dump_dir,omitempty"`
+
+	// AudioDir holds pre-rendered audio files that tools may play instead
+	// of synthesized speech.
+	AudioDir string `json:"audio_dir,omitempty"`
+
+	// RequireAccept asks the callee to press 1 before the call connects, so
+	// the assistant does not talk to voicemail or the wrong person.
+	RequireAccept bool `json:"require_accept,omitempty"`
+
+	// RequireConfirmation makes initiate_call return a token instead of
+	// dialing; the call is placed once the agent passes it to
+	// confirm_call. A safety net while iterating on hooks.
+	RequireConfirmation bool `json:"require_confirmation,omitempty"`
+
+	// RequireProviders stops the server if the phone, TTS, or STT provider
+	// cannot be initialized, instead of serving with calls failing.
+	RequireProviders bool `json:"require_providers,omitempty"`
+
+	// KeepAlive streams silence between turns so the carrier or media
+	// stream does not drop a call that is quiet while the agent works.
+	KeepAlive bool `json:"keep_alive,omitempty"`
 }
 
 // PhoneConfig holds phone provider settings.
@@ -90,6 +259,53 @@ type PhoneConfig struct {
 
 	// UserNumber is the recipient phone number (E.164 format).
 	UserNumber string `json:"user_number"`
+
+	// CallerIDName is the caller ID name (CNAM) shown to the user, where
+	// the provider and number support it.
+	CallerIDName string `json:"caller_id_name,omitempty"`
+
+	// AMDWaitMS enables answering machine detection and waits up to this
+	// long for the result before speaking. 0 = off.
+	AMDWaitMS int `json:"amd_wait_ms,omitempty"`
+
+	// StatusPollMS is how often a ringing call's status is checked when
+	// the provider does not push status changes (default: 500).
+	StatusPollMS int `json:"status_poll_ms,omitempty"`
+
+	// WaitForGreeting waits for the callee to finish saying "hello?"
+	// before speaking the first message.
+	WaitForGreeting bool `json:"wait_for_greeting,omitempty"`
+
+	// GreetingWaitMS bounds the wait for the greeting (default: 3000).
+	GreetingWaitMS int `json:"greeting_wait_ms,omitempty"`
+
+	// GreetingSilenceMS is the silence after speech that ends the
+	// greeting (default: 500).
+	GreetingSilenceMS int `json:"greeting_silence_ms,omitempty"`
+
+	// ConnectTone plays a short beep once the call is answered, before
+	// anything is said, of ConnectToneHz (default: 880) for ConnectToneMS
+	// (default: 200).
+	ConnectTone   bool `json:"connect_tone,omitempty"`
+	ConnectToneHz int  `json:"connect_tone_hz,omitempty"`
+	ConnectToneMS int  `json:"connect_tone_ms,omitempty"`
+
+	// Region is the Twilio Region that processes calls, e.g. "ie1"
+	// (default: us1).
+	Region string `json:"region,omitempty"`
+
+	// Edge is the Twilio edge location to connect through, e.g. "dublin"
+	// (default: ashburn). Pick the edge closest to this server.
+	Edge string `json:"edge,omitempty"`
+
+	// PreferredCodec is the call audio codec ("mulaw" or "opus"), used
+	// when the provider's media stream supports it (default: mulaw).
+	PreferredCodec string `json:"preferred_codec,omitempty"`
+
+	// TwiMLTemplate is a file with the TwiML returned when a call is
+	// answered, replacing the default <Connect><Stream>. {stream_url} is
+	// replaced with the media stream URL.
+	TwiMLTemplate string `json:"twiml_template,omitempty"`
 }
 
 // TTSConfig holds text-to-speech settings.
@@ -105,6 +321,50 @@ type TTSConfig struct {
 
 	// Model is the model ID (provider-specific).
 	Model string `json:"model,omitempty"`
+
+	// EnableTags selects a model that supports inline audio tags such as
+	// [excited] when Model is not set. Tags are stripped on models that don't
+	// support them.
+	EnableTags bool `json:"enable_tags,omitempty"`
+
+	// SpeakingRate scales speech speed (1.0 = normal, 0.5-2.0) on providers
+	// that support it.
+	SpeakingRate float64 `json:"speaking_rate,omitempty"`
+
+	// ContinuityTurns is how many previous assistant turns are passed to
+	// the provider as context for natural intonation (0 = off, ElevenLabs only).
+	ContinuityTurns int `json:"continuity_turns,omitempty"`
+
+	// StreamRetries is how many times the rest of a message is
+	// re-synthesized after the TTS stream fails partway (default: 1).
+	StreamRetries *int `json:"stream_retries,omitempty"`
+
+	// TrimSilence drops near-silent audio at the start and end of each
+	// message.
+	TrimSilence bool `json:"trim_silence,omitempty"`
+
+	// TrimSilenceThreshold is the 16-bit amplitude at or below which audio
+	// counts as silent (default: 100).
+	TrimSilenceThreshold int `json:"trim_silence_threshold,omitempty"`
+
+	// TrimSilencePadMS is how much of the trimmed silence is kept at each
+	// end so speech is not clipped (default: 100).
+	TrimSilencePadMS *int `json:"trim_silence_pad_ms,omitempty"`
+
+	// FrameMS re-frames audio into chunks of this many milliseconds, sent
+	// as fast as they play (0 = send audio as it arrives).
+	FrameMS int `json:"frame_ms,omitempty"`
+
+	// Prefetch synthesizes the next message while the user answers when
+	// the agent passes next_message_hint.
+	Prefetch bool `json:"prefetch,omitempty"`
+
+	// Cache keeps synthesized audio in memory so repeated phrases are not
+	// synthesized again.
+	Cache bool `json:"cache,omitempty"`
+
+	// CacheMaxBytes limits the audio held by the cache (default: 8 MiB).
+	CacheMaxBytes int `json:"cache_max_bytes,omitempty"`
 }
 
 // STTConfig holds speech-to-text settings.
@@ -123,6 +383,57 @@ type STTConfig struct {
 
 	// SilenceDurationMS is milliseconds of silence to detect end of speech.
 	SilenceDurationMS int `json:"silence_duration_ms,omitempty"`
+
+	// AggregateFinalsMS keeps listening this long after a final transcript
+	// and joins follow-on speech into the same reply (0 = off).
+	AggregateFinalsMS int `json:"aggregate_finals_ms,omitempty"`
+
+	// AdaptiveSilence learns how long to wait for the caller to go on
+	// from their pauses during the call, within AdaptiveSilenceMinMS and
+	// AdaptiveSilenceMaxMS (defaults: 300 and 2000), starting from
+	// SilenceDurationMS. It replaces AggregateFinalsMS.
+	AdaptiveSilence      bool `json:"adaptive_silence,omitempty"`
+	AdaptiveSilenceMinMS int  `json:"adaptive_silence_min_ms,omitempty"`
+	AdaptiveSilenceMaxMS int  `json:"adaptive_silence_max_ms,omitempty"`
+
+	// PostSpeechDelayMS drops caller audio for this long after our speech
+	// finishes playing, so the tail of it is not transcribed. 0 = off.
+	PostSpeechDelayMS int `json:"post_speech_delay_ms,omitempty"`
+
+	// EchoGuardMS discards utterances that start while our speech is
+	// playing or within this long after it ends. 0 = off.
+	EchoGuardMS int `json:"echo_guard_ms,omitempty"`
+
+	// PersistConnection keeps one streaming connection open for the whole
+	// call instead of reconnecting every turn.
+	PersistConnection bool `json:"persist_connection,omitempty"`
+
+	// MaxUtteranceMS finalizes a reply after this much speech, counted
+	// from the user's first word (0 = no limit).
+	MaxUtteranceMS int `json:"max_utterance_ms,omitempty"`
+
+	// ContextKeywords boosts terms from recent assistant turns, so replies
+	// that repeat them are transcribed correctly.
+	ContextKeywords bool `json:"context_keywords,omitempty"`
+
+	// ContextKeywordMax caps the terms boosted from the conversation
+	// (default: 20).
+	ContextKeywordMax int `json:"context_keyword_max,omitempty"`
+
+	// LanguageDetection flags replies that seem to be in a different
+	// language than Language, so the agent can switch with set_language.
+	LanguageDetection bool `json:"language_detection,omitempty"`
+}
+
+// TranscriptRolesConfig names the roles of exported transcript turns, for
+// systems that expect a different vocabulary. Empty fields keep the
+// default.
+type TranscriptRolesConfig struct {
+	// Assistant is the role of the assistant's turns (default: "assistant").
+	Assistant string `json:"assistant,omitempty"`
+
+	// User is the role of the user's turns (default: "user").
+	User string `json:"user,omitempty"`
 }
 
 // NgrokConfig holds ngrok tunnel settings.
@@ -134,6 +445,18 @@ type NgrokConfig struct {
 	Domain string `json:"domain,omitempty"`
 }
 
+// CloudflaredConfig holds cloudflared tunnel settings.
+type CloudflaredConfig struct {
+	// Path is the cloudflared binary (default: "cloudflared" on PATH).
+	Path string `json:"path,omitempty"`
+
+	// Token is an optional named-tunnel token. If empty, a quick tunnel is used.
+	Token string `json:"token,omitempty"`
+
+	// Hostname is the public hostname of the named tunnel (required with token).
+	Hostname string `json:"hostname,omitempty"`
+}
+
 // ChatConfig holds chat provider configuration.
 type ChatConfig struct {
 	// Discord configuration (optional).
@@ -311,6 +634,21 @@ func (c *UnifiedConfig) Validate() error {
 		}
 	}
 
+	if v := c.Server.RestartBackoffMS; v != 0 {
+		errors = append(errors, validateMillis([]msSetting{{restartBackoffMS, v}}, fromFile)...)
+	}
+	if err := validateBindAddr(c.Server.BindAddr); err != nil {
+		errors = append(errors, "server: "+err.Error())
+	}
+	for _, origin := range c.Server.CORSOrigins {
+		if err := validateCORSOrigin(origin); err != nil {
+			errors = append(errors, "server: "+err.Error())
+		}
+	}
+	if t := c.Server.Transport; t != "" && t != TransportHTTP && t != TransportStdio {
+		errors = append(errors, fmt.Sprintf("server.transport must be %q or %q", TransportHTTP, TransportStdio))
+	}
+
 	// Validate voice config
 	if c.Voice != nil {
 		if c.Voice.Phone.AccountSID == "" {
@@ -331,10 +669,143 @@ func (c *UnifiedConfig) Validate() error {
 		if c.Voice.STT.APIKey == "" {
 			errors = append(errors, "voice.stt.api_key is required")
 		}
-		if c.Voice.Ngrok.AuthToken == "" {
-			errors = append(errors, "voice.ngrok.auth_token is required")
+		switch {
+		case c.Voice.PublicURL != "":
+			if err := validatePublicURL(c.Voice.PublicURL); err != nil {
+				errors = append(errors, "voice.public_url: "+err.Error())
+			}
+		case c.Server.Transport == TransportStdio:
+			errors = append(errors, "voice.public_url is required with server.transport \"stdio\"")
+		case c.Voice.Tunnel == "" || c.Voice.Tunnel == TunnelNgrok:
+			if c.Voice.Ngrok.AuthToken == "" {
+				errors = append(errors, "voice.ngrok.auth_token is required")
+			}
+		case c.Voice.Tunnel == TunnelCloudflared:
+			if cf := c.Voice.Cloudflared; cf != nil && cf.Token != "" && cf.Hostname == "" {
+				errors = append(errors, "voice.cloudflared.hostname is required with a token")
+			}
+		default:
+			errors = append(errors, fmt.Sprintf("invalid tunnel %q", c.Voice.Tunnel))
 		}
 
+		if _, err := ParseQuietHours(c.Voice.QuietHours); err != nil {
+			errors = append(errors, "voice.quiet_hours: "+err.Error())
+		}
+		if c.Voice.MaxCallsPerDay < 0 {
+			errors = append(errors, "voice.max_calls_per_day must be 0 or more")
+		}
+		if c.Voice.MaxDailyCostUSD < 0 {
+			errors = append(errors, "voice.max_daily_cost_usd must be 0 or more")
+		}
+		if c.Voice.CallCostPerMinuteUSD < 0 {
+			errors = append(errors, "voice.call_cost_per_minute_usd must be more than 0")
+		}
+		if c.Voice.MaxMessageChars < 0 {
+			errors = append(errors, "voice.max_message_chars must be 0 or more")
+		}
+		if c.Voice.MaxTTSCharsPerCall < 0 {
+			errors = append(errors, "voice.max_tts_chars_per_call must be 0 or more")
+		}
+		if c.Voice.MaxSTTSecondsPerCall < 0 {
+			errors = append(errors, "voice.max_stt_seconds_per_call must be 0 or more")
+		}
+		if r := c.Voice.TTS.StreamRetries; r != nil && *r < 0 {
+			errors = append(errors, "voice.tts.stream_retries must be 0 or more")
+		}
+		if r := c.Voice.RedialAttempts; r < 0 || r > MaxRedialAttempts {
+			errors = append(errors, fmt.Sprintf("voice.redial_attempts must be between 0 and %d", MaxRedialAttempts))
+		}
+		if r := c.Voice.ConfirmReprompts; r != nil && (*r < 0 || *r > MaxConfirmReprompts) {
+			errors = append(errors, fmt.Sprintf("voice.confirm_reprompts must be between 0 and %d", MaxConfirmReprompts))
+		}
+		if err := validateTwilioLocation(c.Voice.Phone.Region, c.Voice.Phone.Edge); err != nil {
+			errors = append(errors, "voice.phone: "+err.Error())
+		}
+		for _, msg := range validateTenants(c.Voice.Tenants) {
+			errors = append(errors, "voice.tenants: "+msg)
+		}
+		if len(c.Voice.Tenants) > 0 && c.Server.Transport == TransportStdio {
+			// Tenants are identified by a request header, which stdio lacks
+			errors = append(errors, "voice.tenants require server.transport \"http\"")
+		}
+		if v := c.Voice.TTS.TrimSilenceThreshold; v < 0 || v > MaxTrimSilenceThreshold {
+			errors = append(errors, fmt.Sprintf("voice.tts.trim_silence_threshold must be between 0 and %d", MaxTrimSilenceThreshold))
+		}
+		if c.Voice.TTS.CacheMaxBytes < 0 {
+			errors = append(errors, "voice.tts.cache_max_bytes must be 0 or more")
+		}
+		if v := c.Voice.STT.ContextKeywordMax; v < 0 || v > MaxSTTContextKeywordMax {
+			errors = append(errors, fmt.Sprintf("voice.stt.context_keyword_max must be between 1 and %d", MaxSTTContextKeywordMax))
+		}
+		if codec := c.Voice.Phone.PreferredCodec; codec != "" && codec != CodecMulaw && codec != CodecOpus {
+			errors = append(errors, fmt.Sprintf("voice.phone.preferred_codec must be %q or %q", CodecMulaw, CodecOpus))
+		}
+
+		// Zero means unset and keeps the default
+		var millis []msSetting
+		if v := c.Voice.TranscriptTimeoutMS; v != 0 {
+			millis = append(millis, msSetting{transcriptTimeoutMS, v})
+		}
+		if v := c.Voice.STT.SilenceDurationMS; v != 0 {
+			millis = append(millis, msSetting{silenceDurationMS, v})
+		}
+		minSilence, maxSilence := DefaultAdaptiveSilenceMinMS, DefaultAdaptiveSilenceMaxMS
+		if v := c.Voice.STT.AdaptiveSilenceMinMS; v != 0 {
+			millis = append(millis, msSetting{adaptiveSilenceMinMS, v})
+			minSilence = v
+		}
+		if v := c.Voice.STT.AdaptiveSilenceMaxMS; v != 0 {
+			millis = append(millis, msSetting{adaptiveSilenceMaxMS, v})
+			maxSilence = v
+		}
+		if minSilence > maxSilence {
+			errors = append(errors, "voice.stt.adaptive_silence_min_ms must not exceed voice.stt.adaptive_silence_max_ms")
+		}
+		if v := c.Voice.Phone.StatusPollMS; v != 0 {
+			millis = append(millis, msSetting{statusPollMS, v})
+		}
+		if v := c.Voice.Phone.GreetingWaitMS; v != 0 {
+			millis = append(millis, msSetting{greetingWaitMS, v})
+		}
+		if v := c.Voice.Phone.GreetingSilenceMS; v != 0 {
+			millis = append(millis, msSetting{greetingSilenceMS, v})
+		}
+		if v := c.Voice.Phone.ConnectToneMS; v != 0 {
+			millis = append(millis, msSetting{connectToneMS, v})
+		}
+		if r := c.Voice.TranscriptRoles; r != nil {
+			assistant, user := cmp.Or(r.Assistant, "assistant"), cmp.Or(r.User, "user")
+			if assistant == user {
+				errors = append(errors, fmt.Sprintf("voice.transcript_roles: assistant and user are both %q", user))
+			}
+		}
+		if v := c.Voice.Phone.ConnectToneHz; v != 0 && (v < MinConnectToneHz || v > MaxConnectToneHz) {
+			errors = append(errors, fmt.Sprintf("voice.phone.connect_tone_hz must be between %d and %d", MinConnectToneHz, MaxConnectToneHz))
+		}
+		millis = append(millis,
+			msSetting{aggregateFinalsMS, c.Voice.STT.AggregateFinalsMS},
+			msSetting{maxUtteranceMS, c.Voice.STT.MaxUtteranceMS},
+			msSetting{postSpeechDelayMS, c.Voice.STT.PostSpeechDelayMS},
+			msSetting{echoGuardMS, c.Voice.STT.EchoGuardMS},
+			msSetting{ttsFrameMS, c.Voice.TTS.FrameMS},
+			msSetting{amdWaitMS, c.Voice.Phone.AMDWaitMS},
+			msSetting{resumeWindowMS, c.Voice.ResumeWindowMS},
+			msSetting{descriptionRefreshMS, c.Voice.DescriptionRefreshMS},
+		)
+		if v := c.Voice.MaxCallLifetimeMS; v != nil {
+			millis = append(millis, msSetting{maxCallLifetimeMS, *v})
+		}
+		if v := c.Voice.RedialDelayMS; v != 0 {
+			millis = append(millis, msSetting{redialDelayMS, v})
+		}
+		if v := c.Voice.TTS.TrimSilencePadMS; v != nil {
+			millis = append(millis, msSetting{trimSilencePadMS, *v})
+		}
+		if v := c.Voice.TunnelReadyTimeoutMS; v != nil {
+			millis = append(millis, msSetting{tunnelReadyTimeoutMS, *v})
+		}
+		errors = append(errors, validateMillis(millis, fromFile)...)
+
 		// Validate provider names
 		validProviders := map[string]bool{"elevenlabs": true, "deepgram": true, "openai": true}
 		if c.Voice.TTS.Provider != "" && !validProviders[c.Voice.TTS.Provider] {
@@ -390,6 +861,21 @@ func (c *UnifiedConfig) Validate() error {
 func (c *UnifiedConfig) ToLegacyConfig() *Config {
 	cfg := DefaultConfig()
 	cfg.Port = c.Server.Port
+	cfg.BindAddr = c.Server.BindAddr
+	if c.Server.Transport != "" {
+		cfg.Transport = c.Server.Transport
+	}
+	switch {
+	case c.Server.Restarts < 0:
+		cfg.ServeRestarts = 0
+	case c.Server.Restarts > 0:
+		cfg.ServeRestarts = c.Server.Restarts
+	}
+	if c.Server.RestartBackoffMS != 0 {
+		cfg.ServeRestartBackoffMS = c.Server.RestartBackoffMS
+	}
+	cfg.EnabledTools = c.Server.EnabledTools
+	cfg.CORSOrigins = c.Server.CORSOrigins
 
 	if c.Voice != nil {
 		cfg.PhoneProvider = c.Voice.Phone.Provider
@@ -400,6 +886,31 @@ func (c *UnifiedConfig) ToLegacyConfig() *Config {
 		cfg.PhoneAuthToken = c.Voice.Phone.AuthToken
 		cfg.PhoneNumber = c.Voice.Phone.Number
 		cfg.UserPhoneNumber = c.Voice.Phone.UserNumber
+		cfg.CallerIDName = c.Voice.Phone.CallerIDName
+		cfg.AMDWaitMS = c.Voice.Phone.AMDWaitMS
+		if c.Voice.Phone.StatusPollMS != 0 {
+			cfg.StatusPollMS = c.Voice.Phone.StatusPollMS
+		}
+		cfg.WaitForGreeting = c.Voice.Phone.WaitForGreeting
+		if c.Voice.Phone.GreetingWaitMS != 0 {
+			cfg.GreetingWaitMS = c.Voice.Phone.GreetingWaitMS
+		}
+		if c.Voice.Phone.GreetingSilenceMS != 0 {
+			cfg.GreetingSilenceMS = c.Voice.Phone.GreetingSilenceMS
+		}
+		cfg.ConnectTone = c.Voice.Phone.ConnectTone
+		if c.Voice.Phone.ConnectToneHz != 0 {
+			cfg.ConnectToneHz = c.Voice.Phone.ConnectToneHz
+		}
+		if c.Voice.Phone.ConnectToneMS != 0 {
+			cfg.ConnectToneMS = c.Voice.Phone.ConnectToneMS
+		}
+		cfg.TwilioRegion = c.Voice.Phone.Region
+		cfg.TwilioEdge = c.Voice.Phone.Edge
+		cfg.TwiMLTemplate = c.Voice.Phone.TwiMLTemplate
+		if c.Voice.Phone.PreferredCodec != "" {
+			cfg.PreferredCodec = c.Voice.Phone.PreferredCodec
+		}
 
 		cfg.TTSProvider = c.Voice.TTS.Provider
 		if cfg.TTSProvider == "" {
@@ -407,6 +918,30 @@ func (c *UnifiedConfig) ToLegacyConfig() *Config {
 		}
 		cfg.TTSVoice = c.Voice.TTS.Voice
 		cfg.TTSModel = c.Voice.TTS.Model
+		cfg.TTSEnableTags = c.Voice.TTS.EnableTags
+		cfg.TTSContinuityTurns = c.Voice.TTS.ContinuityTurns
+		if c.Voice.TTS.StreamRetries != nil {
+			cfg.TTSStreamRetries = *c.Voice.TTS.StreamRetries
+		}
+		if c.Voice.TTS.SpeakingRate != 0 {
+			cfg.SpeakingRate = c.Voice.TTS.SpeakingRate
+		}
+		cfg.TrimSilence = c.Voice.TTS.TrimSilence
+		cfg.TTSFrameMS = c.Voice.TTS.FrameMS
+		cfg.TTSPrefetch = c.Voice.TTS.Prefetch
+		cfg.TTSCache = c.Voice.TTS.Cache
+		if c.Voice.TTS.CacheMaxBytes != 0 {
+			cfg.TTSCacheMaxBytes = c.Voice.TTS.CacheMaxBytes
+		}
+		if c.Voice.TTS.TrimSilenceThreshold != 0 {
+			cfg.TrimSilenceThreshold = c.Voice.TTS.TrimSilenceThreshold
+		}
+		if c.Voice.TTS.TrimSilencePadMS != nil {
+			cfg.TrimSilencePadMS = *c.Voice.TTS.TrimSilencePadMS
+		}
+		if cfg.TTSEnableTags && cfg.TTSModel == "" && cfg.TTSProvider == ProviderElevenLabs {
+			cfg.TTSModel = ElevenLabsTagModel
+		}
 
 		cfg.STTProvider = c.Voice.STT.Provider
 		if cfg.STTProvider == "" {
@@ -414,11 +949,102 @@ func (c *UnifiedConfig) ToLegacyConfig() *Config {
 		}
 		cfg.STTModel = c.Voice.STT.Model
 		cfg.STTLanguage = c.Voice.STT.Language
-		cfg.STTSilenceDurationMS = c.Voice.STT.SilenceDurationMS
+		if c.Voice.STT.SilenceDurationMS != 0 {
+			cfg.STTSilenceDurationMS = c.Voice.STT.SilenceDurationMS
+		}
+		cfg.STTPersistConnection = c.Voice.STT.PersistConnection
+		cfg.AggregateFinalsMS = c.Voice.STT.AggregateFinalsMS
+		cfg.AdaptiveSilence = c.Voice.STT.AdaptiveSilence
+		if c.Voice.STT.AdaptiveSilenceMinMS != 0 {
+			cfg.AdaptiveSilenceMinMS = c.Voice.STT.AdaptiveSilenceMinMS
+		}
+		if c.Voice.STT.AdaptiveSilenceMaxMS != 0 {
+			cfg.AdaptiveSilenceMaxMS = c.Voice.STT.AdaptiveSilenceMaxMS
+		}
+		cfg.PostSpeechDelayMS = c.Voice.STT.PostSpeechDelayMS
+		cfg.EchoGuardMS = c.Voice.STT.EchoGuardMS
+		cfg.MaxUtteranceMS = c.Voice.STT.MaxUtteranceMS
+		cfg.STTContextKeywords = c.Voice.STT.ContextKeywords
+		if c.Voice.STT.ContextKeywordMax != 0 {
+			cfg.STTContextKeywordMax = c.Voice.STT.ContextKeywordMax
+		}
+		cfg.LanguageDetection = c.Voice.STT.LanguageDetection
 
+		if c.Voice.Tunnel != "" {
+			cfg.Tunnel = c.Voice.Tunnel
+		}
+		cfg.PublicURL = strings.TrimSuffix(c.Voice.PublicURL, "/")
 		cfg.NgrokAuthToken = c.Voice.Ngrok.AuthToken
 		cfg.NgrokDomain = c.Voice.Ngrok.Domain
-		cfg.TranscriptTimeoutMS = c.Voice.TranscriptTimeoutMS
+		if cf := c.Voice.Cloudflared; cf != nil {
+			cfg.CloudflaredPath = cf.Path
+			cfg.CloudflaredToken = cf.Token
+			cfg.CloudflaredHostname = cf.Hostname
+		}
+		if c.Voice.TunnelReadyTimeoutMS != nil {
+			cfg.TunnelReadyTimeoutMS = *c.Voice.TunnelReadyTimeoutMS
+		}
+		if c.Voice.TranscriptTimeoutMS != 0 {
+			cfg.TranscriptTimeoutMS = c.Voice.TranscriptTimeoutMS
+		}
+		cfg.ResumeWindowMS = c.Voice.ResumeWindowMS
+		if c.Voice.MaxCallLifetimeMS != nil {
+			cfg.MaxCallLifetimeMS = *c.Voice.MaxCallLifetimeMS
+		}
+		cfg.RedialAttempts = c.Voice.RedialAttempts
+		if c.Voice.RedialDelayMS != 0 {
+			cfg.RedialDelayMS = c.Voice.RedialDelayMS
+		}
+		cfg.QuietHours = c.Voice.QuietHours
+		cfg.MaxCallsPerDay = c.Voice.MaxCallsPerDay
+		cfg.MaxDailyCostUSD = c.Voice.MaxDailyCostUSD
+		if c.Voice.CallCostPerMinuteUSD != 0 {
+			cfg.CallCostPerMinuteUSD = c.Voice.CallCostPerMinuteUSD
+		}
+		cfg.MaxMessageChars = c.Voice.MaxMessageChars
+		cfg.MaxTTSCharsPerCall = c.Voice.MaxTTSCharsPerCall
+		cfg.MaxSTTSecondsPerCall = c.Voice.MaxSTTSecondsPerCall
+		cfg.DescriptionRefreshMS = c.Voice.DescriptionRefreshMS
+		cfg.ConferenceNumbers = c.Voice.ConferenceNumbers
+		cfg.CallStorePath = c.Voice.CallStorePath
+		cfg.TranscriptSink = c.Voice.TranscriptSink
+		if r := c.Voice.TranscriptRoles; r != nil {
+			if r.Assistant != "" {
+				cfg.TranscriptAssistantRole = r.Assistant
+			}
+			if r.User != "" {
+				cfg.TranscriptUserRole = r.User
+			}
+		}
+		cfg.CallEndedWebhook = c.Voice.CallEndedWebhook
+		cfg.CallEndedWebhookSecret = c.Voice.CallEndedWebhookSecret
+		cfg.TriggerToken = c.Voice.TriggerToken
+		cfg.Sentiment = c.Voice.Sentiment
+		cfg.PronunciationDict = c.Voice.PronunciationDict
+		if len(c.Voice.GoodbyePhrases) > 0 {
+			cfg.GoodbyePhrases = c.Voice.GoodbyePhrases
+		}
+		if len(c.Voice.YesPhrases) > 0 {
+			cfg.YesPhrases = c.Voice.YesPhrases
+		}
+		if len(c.Voice.NoPhrases) > 0 {
+			cfg.NoPhrases = c.Voice.NoPhrases
+		}
+		if c.Voice.ConfirmReprompts != nil {
+			cfg.ConfirmReprompts = *c.Voice.ConfirmReprompts
+		}
+		cfg.EnableRecording = c.Voice.Recording
+		if c.Voice.RecordingConsent != "" {
+			cfg.RecordingConsent = c.Voice.RecordingConsent
+		}
+		cfg.RecordingConsentVoice = c.Voice.RecordingConsentVoice
+		cfg.AudioDumpDir = c.Voice.AudioDumpDir
+		cfg.AudioDir = c.Voice.AudioDir
+		cfg.RequireAccept = c.Voice.RequireAccept
+		cfg.RequireConfirmation = c.Voice.RequireConfirmation
+		cfg.RequireProviders = c.Voice.RequireProviders
+		cfg.Tenants = c.Voice.Tenants
+		cfg.KeepAlive = c.Voice.KeepAlive
 
 		// Set API keys based on provider
 		switch cfg.TTSProvider {
@@ -237,6 +237,137 @@ func TestUnifiedConfigValidate(t *testing.T) {
 			wantError: true,
 			errMsg:    "invalid TTS provider",
 		},
+		{
+			name: "millisecond setting out of range names the file field",
+			config: &UnifiedConfig{
+				Voice: &VoiceConfig{
+					Phone: PhoneConfig{
+						AccountSID: "sid",
+						AuthToken:  "token",
+						Number:     "+1234",
+						UserNumber: "+5678",
+					},
+					TTS: TTSConfig{APIKey: "key"},
+					STT: STTConfig{
+						APIKey:      "key",
+						EchoGuardMS: 20000,
+					},
+					Ngrok: NgrokConfig{
+						AuthToken: "token",
+					},
+				},
+			},
+			wantError: true,
+			errMsg:    "invalid voice.stt.echo_guard_ms 20000",
+		},
+		{
+			name:      "restart backoff out of range",
+			config:    &UnifiedConfig{Server: ServerConfig{RestartBackoffMS: 50}},
+			wantError: true,
+			errMsg:    "invalid server.restart_backoff_ms 50",
+		},
+		{
+			name:      "unknown transport",
+			config:    &UnifiedConfig{Server: ServerConfig{Transport: "sse"}},
+			wantError: true,
+			errMsg:    "server.transport must be",
+		},
+		{
+			name: "stdio transport needs a public URL for voice",
+			config: &UnifiedConfig{
+				Server: ServerConfig{Transport: TransportStdio},
+				Voice: &VoiceConfig{
+					Phone: PhoneConfig{
+						AccountSID: "sid",
+						AuthToken:  "token",
+						Number:     "+1234",
+						UserNumber: "+5678",
+					},
+					TTS:   TTSConfig{APIKey: "key"},
+					STT:   STTConfig{APIKey: "key"},
+					Ngrok: NgrokConfig{AuthToken: "token"},
+				},
+			},
+			wantError: true,
+			errMsg:    "voice.public_url is required",
+		},
+		{
+			name: "tenants over stdio",
+			config: &UnifiedConfig{
+				Server: ServerConfig{Transport: TransportStdio},
+				Voice: &VoiceConfig{
+					Phone: PhoneConfig{
+						AccountSID: "sid",
+						AuthToken:  "token",
+						Number:     "+1234",
+						UserNumber: "+5678",
+					},
+					TTS:       TTSConfig{APIKey: "key"},
+					STT:       STTConfig{APIKey: "key"},
+					PublicURL: "https://calls.example.com",
+					Tenants:   testTenants(),
+				},
+			},
+			wantError: true,
+			errMsg:    "voice.tenants require server.transport",
+		},
+		{
+			name: "adaptive silence minimum above the default maximum",
+			config: &UnifiedConfig{
+				Voice: &VoiceConfig{
+					Phone: PhoneConfig{
+						AccountSID: "sid",
+						AuthToken:  "token",
+						Number:     "+1234",
+						UserNumber: "+5678",
+					},
+					TTS:   TTSConfig{APIKey: "key"},
+					STT:   STTConfig{APIKey: "key", AdaptiveSilence: true, AdaptiveSilenceMinMS: 2500},
+					Ngrok: NgrokConfig{AuthToken: "token"},
+				},
+			},
+			wantError: true,
+			errMsg:    "voice.stt.adaptive_silence_min_ms must not exceed",
+		},
+		{
+			name: "connect tone above the phone band",
+			config: &UnifiedConfig{
+				Voice: &VoiceConfig{
+					Phone: PhoneConfig{
+						AccountSID:    "sid",
+						AuthToken:     "token",
+						Number:        "+1234",
+						UserNumber:    "+5678",
+						ConnectTone:   true,
+						ConnectToneHz: 5000,
+					},
+					TTS:   TTSConfig{APIKey: "key"},
+					STT:   STTConfig{APIKey: "key"},
+					Ngrok: NgrokConfig{AuthToken: "token"},
+				},
+			},
+			wantError: true,
+			errMsg:    "voice.phone.connect_tone_hz must be between",
+		},
+		{
+			name: "transcript roles renamed to the same name",
+			config: &UnifiedConfig{
+				Voice: &VoiceConfig{
+					Phone: PhoneConfig{
+						AccountSID: "sid",
+						AuthToken:  "token",
+						Number:     "+1234",
+						UserNumber: "+5678",
+					},
+					TTS:             TTSConfig{APIKey: "key"},
+					STT:             STTConfig{APIKey: "key"},
+					Ngrok:           NgrokConfig{AuthToken: "token"},
+					TranscriptRoles: &TranscriptRolesConfig{Assistant: "user"},
+				},
+			},
+			wantError: true,
+			errMsg:    "voice.transcript_roles",
+		},
 	}
 
 	for _, tt := range tests {
@@ -258,6 +389,8 @@ func TestUnifiedConfigValidate(t *testing.T) {
 }
 
 func TestUnifiedConfigToLegacyConfig(t *testing.T) {
+	streamRetries := 0
+	reprompts := 0
 	cfg := &UnifiedConfig{
 		Server: ServerConfig{Port: 4444},
 		Voice: &VoiceConfig{
@@ -267,22 +400,36 @@ func TestUnifiedConfigToLegacyConfig(t *testing.T) {
 				AuthToken:  "token456",
 				Number:     "+15551234567",
 				UserNumber: "+15559876543",
+				AMDWaitMS:  5000,
 			},
 			TTS: TTSConfig{
-				Provider: "elevenlabs",
-				APIKey:   "eleven_key",
-				Voice:    "Rachel",
-				Model:    "eleven_turbo_v2_5",
+				Provider:      "elevenlabs",
+				APIKey:        "eleven_key",
+				StreamRetries: &streamRetries,
+				Voice:         "Rachel",
+				Model:         "eleven_turbo_v2_5",
 			},
 			STT: STTConfig{
-				Provider: "deepgram",
-				APIKey:   "dg_key",
-				Model:    "nova-2",
-				Language: "en-US",
+				Provider:          "deepgram",
+				APIKey:            "dg_key",
+				Model:             "nova-2",
+				Language:          "en-US",
+				AggregateFinalsMS: 1500,
+				PostSpeechDelayMS: 250,
+				EchoGuardMS:       400,
+				MaxUtteranceMS:    30000,
+
+				AdaptiveSilence:      true,
+				AdaptiveSilenceMaxMS: 1200,
+				LanguageDetection:    true,
 			},
 			Ngrok: NgrokConfig{
 				AuthToken: "ngrok_token",
 			},
+			GoodbyePhrases:   []string{"ciao", "see ya"},
+			YesPhrases:       []string{"si"},
+			ConfirmReprompts: &reprompts,
+			Recording:        true,
 		},
 		Chat: &ChatConfig{
 			Discord: &DiscordConfig{
@@ -323,6 +470,42 @@ func TestUnifiedConfigToLegacyConfig(t *testing.T) {
 	if legacy.DeepgramAPIKey != "dg_key" {
 		t.Errorf("DeepgramAPIKey = %q, want dg_key", legacy.DeepgramAPIKey)
 	}
+	if legacy.AggregateFinalsMS != 1500 {
+		t.Errorf("AggregateFinalsMS = %d, want 1500", legacy.AggregateFinalsMS)
+	}
+	if !legacy.AdaptiveSilence || legacy.AdaptiveSilenceMinMS != DefaultAdaptiveSilenceMinMS || legacy.AdaptiveSilenceMaxMS != 1200 {
+		t.Errorf("AdaptiveSilence = %v, min %d, max %d; want enabled, default, 1200", legacy.AdaptiveSilence, legacy.AdaptiveSilenceMinMS, legacy.AdaptiveSilenceMaxMS)
+	}
+	if !legacy.LanguageDetection {
+		t.Error("LanguageDetection = false, want true")
+	}
+	if legacy.PostSpeechDelayMS != 250 {
+		t.Errorf("PostSpeechDelayMS = %d, want 250", legacy.PostSpeechDelayMS)
+	}
+	if legacy.EchoGuardMS != 400 {
+		t.Errorf("EchoGuardMS = %d, want 400", legacy.EchoGuardMS)
+	}
+	if legacy.MaxUtteranceMS != 30000 {
+		t.Errorf("MaxUtteranceMS = %d, want 30000", legacy.MaxUtteranceMS)
+	}
+	if legacy.TTSStreamRetries != 0 {
+		t.Errorf("TTSStreamRetries = %d, want an explicit 0", legacy.TTSStreamRetries)
+	}
+	if legacy.AMDWaitMS != 5000 {
+		t.Errorf("AMDWaitMS = %d, want 5000", legacy.AMDWaitMS)
+	}
+	if len(legacy.GoodbyePhrases) != 2 || legacy.GoodbyePhrases[0] != "ciao" {
+		t.Errorf("GoodbyePhrases = %q, want [ciao see ya]", legacy.GoodbyePhrases)
+	}
+	if len(legacy.YesPhrases) != 1 || len(legacy.NoPhrases) == 0 {
+		t.Errorf("YesPhrases, NoPhrases = %q, %q; want [si] and the default no phrases", legacy.YesPhrases, legacy.NoPhrases)
+	}
+	if legacy.ConfirmReprompts != 0 {
+		t.Errorf("ConfirmReprompts = %d, want an explicit 0", legacy.ConfirmReprompts)
+	}
+	if !legacy.EnableRecording || legacy.RecordingConsent != DefaultRecordingConsent {
+		t.Errorf("EnableRecording, RecordingConsent = %v, %q; want enabled with the default announcement", legacy.EnableRecording, legacy.RecordingConsent)
+	}
 
 	// Check Discord
 	if !legacy.DiscordEnabled {
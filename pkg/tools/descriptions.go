@@ -0,0 +1,53 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/plexusone/agentcomms/pkg/voice"
+)
+
+// initiateCallDescription is the initiate_call description, followed by the
+// call constraints in effect when the tools were last registered, so the
+// agent can weigh cost, budget, and quiet hours before calling.
+func initiateCallDescription(manager *voice.Manager) string {
+	const base = "Call the user on the phone to discuss something. Use this when you need to report task completion, request input, discuss decisions, or escalate blockers. The call will ring the user's phone, and when they answer, your message will be spoken. Then you'll receive their spoken response. If calls require confirmation, nothing is dialed: the result has status 'confirmation_required' and a confirmation_token for confirm_call."
+	if manager == nil {
+		return base
+	}
+	c := manager.CallConstraints()
+
+	var b strings.Builder
+	b.WriteString(base)
+	fmt.Fprintf(&b, " Calls cost about $%g per started minute.", c.CostPerMinuteUSD)
+	b.WriteString(messageLimit(manager))
+	if c.QuietHours != "" {
+		fmt.Fprintf(&b, " No calls are placed during quiet hours (%s, server local time).", c.QuietHours)
+	}
+	if c.InQuietHours {
+		b.WriteString(" Quiet hours are in effect now, so calls fail with quiet_hours.")
+	}
+	if c.DoNotDisturb {
+		b.WriteString(" Do-not-disturb is on, so calls fail with do_not_disturb.")
+	}
+	if c.Budget.MaxCalls > 0 {
+		fmt.Fprintf(&b, " %d of %d calls left in the daily budget.", c.Budget.CallsRemaining, c.Budget.MaxCalls)
+	}
+	if c.Budget.MaxCostUSD > 0 {
+		fmt.Fprintf(&b, " $%.2f of the $%.2f daily cost budget left (about %d minutes of calls).",
+			c.Budget.CostRemainingUSD, c.Budget.MaxCostUSD, int(c.Budget.TimeRemaining.Minutes()))
+	}
+	return b.String()
+}
+
+// messageLimit is a description sentence stating the maximum message
+// length, or "" when there is none.
+func messageLimit(manager *voice.Manager) string {
+	if manager == nil {
+		return ""
+	}
+	if n := manager.CallConstraints().MaxMessageChars; n > 0 {
+		return fmt.Sprintf(" Messages longer than %d characters are rejected with message_too_long.", n)
+	}
+	return ""
+}
@@ -0,0 +1,43 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/plexusone/agentcomms/pkg/config"
+	"github.com/plexusone/agentcomms/pkg/voice"
+)
+
+func TestInitiateCallDescription(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.MaxMessageChars = 500
+	cfg.QuietHours = "22:00-07:00"
+	cfg.MaxCallsPerDay = 10
+	cfg.MaxDailyCostUSD = 1.5
+	m, err := voice.New(cfg)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	got := initiateCallDescription(m)
+	for _, want := range []string{
+		"$0.03 per started minute",
+		"longer than 500 characters",
+		"quiet hours (22:00-07:00",
+		"10 of 10 calls left",
+		"$1.50 of the $1.50 daily cost budget left (about 50 minutes",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("description = %q, missing %q", got, want)
+		}
+	}
+
+	m.SetDoNotDisturb(true)
+	if got := initiateCallDescription(m); !strings.Contains(got, "do_not_disturb") {
+		t.Errorf("description = %q, want do-not-disturb noted", got)
+	}
+
+	if got := initiateCallDescription(nil); strings.Contains(got, "per started minute") {
+		t.Errorf("description without a manager = %q, want no constraints", got)
+	}
+}
@@ -0,0 +1,133 @@
+package tools
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/plexusone/agentcomms/pkg/voice"
+)
+
+// Error codes returned in structured tool errors.
+const (
+	ErrorCodeNotInitialized       = "not_initialized"
+	ErrorCodeCallNotFound         = "call_not_found"
+	ErrorCodeDialFailed           = "dial_failed"
+	ErrorCodeNotAnswered          = "not_answered"
+	ErrorCodeSMSSent              = "not_answered_sms_sent"
+	ErrorCodeDeclined             = "declined"
+	ErrorCodeVoicemail            = "voicemail"
+	ErrorCodeSpeechFailed         = "speech_failed"
+	ErrorCodeQuietHours           = "quiet_hours"
+	ErrorCodeDoNotDisturb         = "do_not_disturb"
+	ErrorCodeInvalidSchedule      = "invalid_schedule"
+	ErrorCodeScheduleNotFound     = "schedule_not_found"
+	ErrorCodeInvalidVolume        = "invalid_volume"
+	ErrorCodeInvalidVoice         = "invalid_voice"
+	ErrorCodeInvalidAudio         = "invalid_audio"
+	ErrorCodeInvalidConference    = "invalid_conference"
+	ErrorCodeNumberNotAllowed     = "number_not_allowed"
+	ErrorCodeBudgetExceeded       = "budget_exceeded"
+	ErrorCodeNothingToRepeat      = "nothing_to_repeat"
+	ErrorCodeConfirmationNotFound = "confirmation_not_found"
+	ErrorCodeUnauthorized         = "unauthorized"
+	ErrorCodeInvalidHistoryQuery  = "invalid_history_query"
+	ErrorCodeNoClearAnswer        = "no_clear_answer"
+	ErrorCodeMessageTooLong       = "message_too_long"
+	ErrorCodeInvalidMetadata      = "invalid_metadata"
+	ErrorCodeListeningMuted       = "listening_muted"
+	ErrorCodeInvalidLanguage      = "invalid_language"
+	ErrorCodeCallDropped          = "call_dropped"
+	ErrorCodeCallActive           = "call_active"
+	ErrorCodeUsageLimit           = "usage_limit"
+	ErrorCodeInternal             = "internal"
+)
+
+// ErrorOutput is the structured error returned by tools so the agent can
+// decide whether to retry, re-dial, or switch to text.
+type ErrorOutput struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// errorCode maps an error to its tool error code.
+func errorCode(err error) string {
+	switch {
+	case errors.Is(err, voice.ErrSMSFallbackSent):
+		return ErrorCodeSMSSent
+	case errors.Is(err, voice.ErrNotAnswered):
+		return ErrorCodeNotAnswered
+	case errors.Is(err, voice.ErrDeclined):
+		return ErrorCodeDeclined
+	case errors.Is(err, voice.ErrVoicemail):
+		return ErrorCodeVoicemail
+	case errors.Is(err, voice.ErrCallNotFound):
+		return ErrorCodeCallNotFound
+	case errors.Is(err, voice.ErrNotInitialized):
+		return ErrorCodeNotInitialized
+	case errors.Is(err, voice.ErrDialFailed):
+		return ErrorCodeDialFailed
+	case errors.Is(err, voice.ErrUsageLimit):
+		// Before speech_failed, which wraps a message refused at the limit
+		return ErrorCodeUsageLimit
+	case errors.Is(err, voice.ErrSpeechFailed):
+		return ErrorCodeSpeechFailed
+	case errors.Is(err, voice.ErrQuietHours):
+		return ErrorCodeQuietHours
+	case errors.Is(err, voice.ErrDoNotDisturb):
+		return ErrorCodeDoNotDisturb
+	case errors.Is(err, voice.ErrInvalidSchedule):
+		return ErrorCodeInvalidSchedule
+	case errors.Is(err, voice.ErrScheduleNotFound):
+		return ErrorCodeScheduleNotFound
+	case errors.Is(err, voice.ErrInvalidVolume):
+		return ErrorCodeInvalidVolume
+	case errors.Is(err, voice.ErrInvalidVoice):
+		return ErrorCodeInvalidVoice
+	case errors.Is(err, voice.ErrInvalidAudio):
+		return ErrorCodeInvalidAudio
+	case errors.Is(err, voice.ErrInvalidConference):
+		return ErrorCodeInvalidConference
+	case errors.Is(err, voice.ErrNumberNotAllowed):
+		return ErrorCodeNumberNotAllowed
+	case errors.Is(err, voice.ErrBudgetExceeded):
+		return ErrorCodeBudgetExceeded
+	case errors.Is(err, voice.ErrNothingToRepeat):
+		return ErrorCodeNothingToRepeat
+	case errors.Is(err, voice.ErrConfirmationNotFound):
+		return ErrorCodeConfirmationNotFound
+	case errors.Is(err, voice.ErrUnauthorized):
+		return ErrorCodeUnauthorized
+	case errors.Is(err, voice.ErrInvalidHistoryQuery):
+		return ErrorCodeInvalidHistoryQuery
+	case errors.Is(err, voice.ErrNoClearAnswer):
+		return ErrorCodeNoClearAnswer
+	case errors.Is(err, voice.ErrMessageTooLong):
+		return ErrorCodeMessageTooLong
+	case errors.Is(err, voice.ErrInvalidMetadata):
+		return ErrorCodeInvalidMetadata
+	case errors.Is(err, voice.ErrListeningMuted):
+		return ErrorCodeListeningMuted
+	case errors.Is(err, voice.ErrInvalidLanguage):
+		return ErrorCodeInvalidLanguage
+	case errors.Is(err, voice.ErrCallDropped):
+		return ErrorCodeCallDropped
+	case errors.Is(err, voice.ErrCallActive):
+		return ErrorCodeCallActive
+	default:
+		return ErrorCodeInternal
+	}
+}
+
+// errorResult builds an error tool result carrying an ErrorOutput as JSON.
+func errorResult(err error) *mcp.CallToolResult {
+	data, _ := json.Marshal(ErrorOutput{
+		Code:    errorCode(err),
+		Message: err.Error(),
+	})
+	return &mcp.CallToolResult{
+		IsError: true,
+		Content: []mcp.Content{&mcp.TextContent{Text: string(data)}},
+	}
+}
@@ -0,0 +1,85 @@
+package tools
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/plexusone/agentcomms/pkg/voice"
+)
+
+func TestErrorCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"call not found", fmt.Errorf("failed to continue call: %w: call-1", voice.ErrCallNotFound), ErrorCodeCallNotFound},
+		{"not answered", voice.ErrNotAnswered, ErrorCodeNotAnswered},
+		{"sms sent", fmt.Errorf("%w, %w", voice.ErrNotAnswered, voice.ErrSMSFallbackSent), ErrorCodeSMSSent},
+		{"declined", voice.ErrDeclined, ErrorCodeDeclined},
+		{"voicemail", voice.ErrVoicemail, ErrorCodeVoicemail},
+		{"not initialized", voice.ErrNotInitialized, ErrorCodeNotInitialized},
+		{"dial failed", fmt.Errorf("%w: boom", voice.ErrDialFailed), ErrorCodeDialFailed},
+		{"speech failed", fmt.Errorf("%w: tts", voice.ErrSpeechFailed), ErrorCodeSpeechFailed},
+		{"invalid volume", fmt.Errorf("%w, got 2", voice.ErrInvalidVolume), ErrorCodeInvalidVolume},
+		{"invalid voice", fmt.Errorf("%w: nope", voice.ErrInvalidVoice), ErrorCodeInvalidVoice},
+		{"invalid audio", fmt.Errorf("failed to load audio: %w: too long", voice.ErrInvalidAudio), ErrorCodeInvalidAudio},
+		{"invalid conference", fmt.Errorf("%w: no participants", voice.ErrInvalidConference), ErrorCodeInvalidConference},
+		{"number not allowed", fmt.Errorf("%w: +15550000000", voice.ErrNumberNotAllowed), ErrorCodeNumberNotAllowed},
+		{"budget exceeded", fmt.Errorf("%w: 10 calls in the last 24 hours (limit 10)", voice.ErrBudgetExceeded), ErrorCodeBudgetExceeded},
+		{"nothing to repeat", fmt.Errorf("%w on call call-1", voice.ErrNothingToRepeat), ErrorCodeNothingToRepeat},
+		{"confirmation not found", fmt.Errorf("failed to confirm call: %w: abc", voice.ErrConfirmationNotFound), ErrorCodeConfirmationNotFound},
+		{"unauthorized", voice.ErrUnauthorized, ErrorCodeUnauthorized},
+		{"do not disturb", fmt.Errorf("failed to initiate call: %w", voice.ErrDoNotDisturb), ErrorCodeDoNotDisturb},
+		{"invalid history query", fmt.Errorf("failed to get call history: %w", voice.ErrInvalidHistoryQuery), ErrorCodeInvalidHistoryQuery},
+		{"no clear answer", fmt.Errorf("failed to confirm with user: %w after 2 re-prompts", voice.ErrNoClearAnswer), ErrorCodeNoClearAnswer},
+		{"message too long", fmt.Errorf("failed to initiate call: %w: 600 characters (limit 500)", voice.ErrMessageTooLong), ErrorCodeMessageTooLong},
+		{"invalid metadata", fmt.Errorf("failed to initiate call: %w: empty key", voice.ErrInvalidMetadata), ErrorCodeInvalidMetadata},
+		{"listening muted", fmt.Errorf("failed to continue call: %w", voice.ErrListeningMuted), ErrorCodeListeningMuted},
+		{"invalid language", fmt.Errorf("failed to set language: %w: \"spanish\"", voice.ErrInvalidLanguage), ErrorCodeInvalidLanguage},
+		{"call dropped", fmt.Errorf("failed to continue call: %w: call-1", voice.ErrCallDropped), ErrorCodeCallDropped},
+		{"call active", fmt.Errorf("failed to resume call: %w: call-1", voice.ErrCallActive), ErrorCodeCallActive},
+		{"usage limit", fmt.Errorf("failed to continue call: %w: 5000 of 5000 TTS characters used", voice.ErrUsageLimit), ErrorCodeUsageLimit},
+		{"message over usage limit", fmt.Errorf("%w: %w: 40 more TTS characters would exceed 4990 of 5000 used", voice.ErrSpeechFailed, voice.ErrUsageLimit), ErrorCodeUsageLimit},
+		{"other", errors.New("boom"), ErrorCodeInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errorCode(tt.err); got != tt.want {
+				t.Errorf("errorCode() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestErrorResult(t *testing.T) {
+	err := fmt.Errorf("failed to end call: %w: call-9", voice.ErrCallNotFound)
+	result := errorResult(err)
+
+	if !result.IsError {
+		t.Error("expected IsError to be set")
+	}
+	if len(result.Content) != 1 {
+		t.Fatalf("expected 1 content item, got %d", len(result.Content))
+	}
+	text, ok := result.Content[0].(*mcp.TextContent)
+	if !ok {
+		t.Fatalf("expected text content, got %T", result.Content[0])
+	}
+
+	var out ErrorOutput
+	if err := json.Unmarshal([]byte(text.Text), &out); err != nil {
+		t.Fatalf("failed to decode error output: %v", err)
+	}
+	if out.Code != ErrorCodeCallNotFound {
+		t.Errorf("Code = %q, want %q", out.Code, ErrorCodeCallNotFound)
+	}
+	if out.Message != err.Error() {
+		t.Errorf("Message = %q, want %q", out.Message, err.Error())
+	}
+}
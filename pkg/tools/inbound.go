@@ -162,8 +162,12 @@ type SendAgentMessageOutput struct {
 
 // RegisterInboundTools registers inbound message MCP tools with the runtime.
 func RegisterInboundTools(rt *mcpkit.Runtime, manager *InboundManager) {
+	registerInboundTools(&registry{rt: rt}, manager)
+}
+
+func registerInboundTools(r *registry, manager *InboundManager) {
 	// check_messages - Check for new messages from humans
-	mcpkit.AddTool(rt, &mcp.Tool{
+	addTool(r, &mcp.Tool{
 		Name:        "check_messages",
 		Description: "Check for new messages sent to this agent from humans via chat (Discord, Telegram, WhatsApp). Use this periodically during long tasks to see if the user has sent any instructions or feedback. Returns only human messages (not agent responses).",
 		InputSchema: map[string]any{
@@ -252,7 +256,7 @@ func RegisterInboundTools(rt *mcpkit.Runtime, manager *InboundManager) {
 	})
 
 	// get_agent_events - Get all events (messages, interrupts, etc.)
-	mcpkit.AddTool(rt, &mcp.Tool{
+	addTool(r, &mcp.Tool{
 		Name:        "get_agent_events",
 		Description: "Get recent events for an agent including all message types, interrupts, and status changes. Use this for a complete view of agent activity. For just human messages, use check_messages instead.",
 		InputSchema: map[string]any{
@@ -308,7 +312,7 @@ func RegisterInboundTools(rt *mcpkit.Runtime, manager *InboundManager) {
 	})
 
 	// daemon_status - Check if the daemon is running
-	mcpkit.AddTool(rt, &mcp.Tool{
+	addTool(r, &mcp.Tool{
 		Name:        "daemon_status",
 		Description: "Check if the agentcomms daemon is running and get its status. The daemon handles inbound messages from chat platforms (Discord, Telegram, WhatsApp) and routes them to agents.",
 		InputSchema: map[string]any{
@@ -337,7 +341,7 @@ func RegisterInboundTools(rt *mcpkit.Runtime, manager *InboundManager) {
 	})
 
 	// list_agents - List all available agents and their status
-	mcpkit.AddTool(rt, &mcp.Tool{
+	addTool(r, &mcp.Tool{
 		Name:        "list_agents",
 		Description: "List all available agents registered with the AgentComms daemon and their status. Use this to discover which agents are available for communication and whether they are online or offline.",
 		InputSchema: map[string]any{
@@ -381,7 +385,7 @@ func RegisterInboundTools(rt *mcpkit.Runtime, manager *InboundManager) {
 	})
 
 	// send_agent_message - Send a message to another agent
-	mcpkit.AddTool(rt, &mcp.Tool{
+	addTool(r, &mcp.Tool{
 		Name:        "send_agent_message",
 		Description: "Send a message to another agent in the AgentComms system. Use this for agent-to-agent communication, for example to delegate tasks, request help, or coordinate work with other agents.",
 		InputSchema: map[string]any{
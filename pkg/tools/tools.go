@@ -3,7 +3,10 @@ package tools
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
+	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	mcpkit "github.com/plexusone/mcpkit/runtime"
@@ -14,46 +17,414 @@ import (
 
 // InitiateCallInput is the input for the initiate_call tool.
 type InitiateCallInput struct {
-	Message string `json:"message"`
+	Message        string            `json:"message"`
+	Volume         *float64          `json:"volume,omitempty"`
+	IdempotencyKey string            `json:"idempotency_key,omitempty"`
+	Async          bool              `json:"async,omitempty"`
+	Metadata       map[string]string `json:"metadata,omitempty"`
 }
 
 // InitiateCallOutput is the output of the initiate_call tool.
 type InitiateCallOutput struct {
 	CallID   string `json:"call_id"`
+	Status   string `json:"status,omitempty"` // "ringing" for async calls, "confirmation_required" for held calls
 	Response string `json:"response"`
+	NoSpeech bool   `json:"no_speech,omitempty"` // nothing was heard before the listen timeout
+
+	// UserWantsToEnd hints that the response sounds like a goodbye, so the
+	// agent should consider calling end_call.
+	UserWantsToEnd bool `json:"user_wants_to_end,omitempty"`
+
+	// AnsweredBy is the answering machine detection result ("human" or
+	// "unknown") when detection is enabled. Machines and faxes fail the
+	// call instead.
+	AnsweredBy string `json:"answered_by,omitempty"`
+
+	// Sentiment is the detected tone of the response ("positive",
+	// "neutral", "negative", "frustrated") when analysis is enabled.
+	Sentiment string `json:"sentiment,omitempty"`
+
+	// DetectedLanguage hints that the response seems to be in this
+	// language ("es", "fr", ...) rather than the call's STT language, when
+	// language detection is enabled; consider calling set_language.
+	DetectedLanguage string `json:"detected_language,omitempty"`
+
+	// FirstWordLatencyMS is from dialing to the first audio of the message
+	// reaching the call, and TimeToAnswerMS the part of it spent ringing.
+	// Both are set once the call was answered.
+	FirstWordLatencyMS int64 `json:"first_word_latency_ms,omitempty"`
+	TimeToAnswerMS     int64 `json:"time_to_answer_ms,omitempty"`
+
+	// Outcome is how the call turned out: "answered", "no_speech",
+	// "voicemail", "not_answered", "busy", "declined", or "failed". It is
+	// also set when the tool fails, and empty until a call has been tried.
+	Outcome string `json:"outcome,omitempty"`
+
+	// Attempts is how many times the number was dialed for the outcome,
+	// more than 1 when a call that rang out or was busy was redialed.
+	Attempts int `json:"attempts,omitempty"`
+
+	// ConfirmationToken is set instead of placing the call when calls
+	// require confirmation; pass it to confirm_call to dial.
+	ConfirmationToken string `json:"confirmation_token,omitempty"`
+}
+
+// ConfirmCallInput is the input for the confirm_call tool.
+type ConfirmCallInput struct {
+	ConfirmationToken string `json:"confirmation_token"`
 }
 
 // ContinueCallInput is the input for the continue_call tool.
 type ContinueCallInput struct {
-	CallID  string `json:"call_id"`
-	Message string `json:"message"`
+	CallID          string   `json:"call_id"`
+	Message         string   `json:"message"`
+	Volume          *float64 `json:"volume,omitempty"`
+	NextMessageHint string   `json:"next_message_hint,omitempty"`
+	Audio           string   `json:"audio,omitempty"`
 }
 
 // ContinueCallOutput is the output of the continue_call tool.
 type ContinueCallOutput struct {
-	Response string `json:"response"`
+	Response         string `json:"response"`
+	NoSpeech         bool   `json:"no_speech,omitempty"`         // nothing was heard before the listen timeout
+	UserWantsToEnd   bool   `json:"user_wants_to_end,omitempty"` // the response sounds like a goodbye
+	Sentiment        string `json:"sentiment,omitempty"`         // detected tone of the response, when enabled
+	DetectedLanguage string `json:"detected_language,omitempty"` // the response seems to be in another language, when enabled
+
+	// The call so far, so the agent can keep it short
+	DurationSeconds  float64 `json:"duration_seconds,omitempty"`
+	EstimatedCostUSD float64 `json:"estimated_cost_usd,omitempty"`
+}
+
+// ResumeCallInput is the input for the resume_call tool.
+type ResumeCallInput struct {
+	CallID  string   `json:"call_id"`
+	Message string   `json:"message"`
+	Volume  *float64 `json:"volume,omitempty"`
+}
+
+// ResumeCallOutput is the output of the resume_call tool.
+type ResumeCallOutput struct {
+	CallID           string `json:"call_id"`      // the new call, which replaces the dropped one
+	ResumedFrom      string `json:"resumed_from"` // the dropped call
+	Response         string `json:"response"`
+	NoSpeech         bool   `json:"no_speech,omitempty"`         // nothing was heard before the listen timeout
+	UserWantsToEnd   bool   `json:"user_wants_to_end,omitempty"` // the response sounds like a goodbye
+	Sentiment        string `json:"sentiment,omitempty"`         // detected tone of the response, when enabled
+	DetectedLanguage string `json:"detected_language,omitempty"` // the response seems to be in another language, when enabled
+	Outcome          string `json:"outcome,omitempty"`           // as for initiate_call
+}
+
+// RepeatLastInput is the input for the repeat_last tool.
+type RepeatLastInput struct {
+	CallID string   `json:"call_id"`
+	Slower bool     `json:"slower,omitempty"`
+	Volume *float64 `json:"volume,omitempty"`
+}
+
+// RepeatLastOutput is the output of the repeat_last tool.
+type RepeatLastOutput struct {
+	Message        string `json:"message"` // the repeated message
+	Response       string `json:"response"`
+	NoSpeech       bool   `json:"no_speech,omitempty"`         // nothing was heard before the listen timeout
+	UserWantsToEnd bool   `json:"user_wants_to_end,omitempty"` // the response sounds like a goodbye
+}
+
+// ConfirmWithUserInput is the input for the confirm_with_user tool.
+type ConfirmWithUserInput struct {
+	CallID   string   `json:"call_id"`
+	Question string   `json:"question"`
+	Volume   *float64 `json:"volume,omitempty"`
+}
+
+// ConfirmWithUserOutput is the output of the confirm_with_user tool.
+type ConfirmWithUserOutput struct {
+	Confirmed bool   `json:"confirmed"` // the user said yes
+	Response  string `json:"response"`  // the user's answer
 }
 
 // SpeakToUserInput is the input for the speak_to_user tool.
 type SpeakToUserInput struct {
-	CallID  string `json:"call_id"`
-	Message string `json:"message"`
+	CallID  string   `json:"call_id"`
+	Message string   `json:"message"`
+	Volume  *float64 `json:"volume,omitempty"`
+	Audio   string   `json:"audio,omitempty"`
 }
 
 // SpeakToUserOutput is the output of the speak_to_user tool.
 type SpeakToUserOutput struct {
 	Success bool `json:"success"`
+
+	// The call so far, as for continue_call
+	DurationSeconds  float64 `json:"duration_seconds,omitempty"`
+	EstimatedCostUSD float64 `json:"estimated_cost_usd,omitempty"`
+}
+
+// SpeakAndWaitDigitsInput is the input for the speak_and_wait_digits tool.
+type SpeakAndWaitDigitsInput struct {
+	CallID    string   `json:"call_id"`
+	Message   string   `json:"message"`
+	MaxDigits int      `json:"max_digits,omitempty"`
+	Volume    *float64 `json:"volume,omitempty"`
+}
+
+// SpeakAndWaitDigitsOutput is the output of the speak_and_wait_digits tool.
+type SpeakAndWaitDigitsOutput struct {
+	Type             string `json:"type"` // "speech" or "dtmf"
+	Value            string `json:"value"`
+	NoSpeech         bool   `json:"no_speech,omitempty"`         // nothing was heard or pressed before the listen timeout
+	UserWantsToEnd   bool   `json:"user_wants_to_end,omitempty"` // the spoken reply sounds like a goodbye
+	Sentiment        string `json:"sentiment,omitempty"`         // detected tone of the spoken reply, when enabled
+	DetectedLanguage string `json:"detected_language,omitempty"` // the spoken reply seems to be in another language, when enabled
+}
+
+// SetVoiceInput is the input for the set_voice tool.
+type SetVoiceInput struct {
+	CallID  string `json:"call_id"`
+	VoiceID string `json:"voice_id"`
+}
+
+// SetVoiceOutput is the output of the set_voice tool.
+type SetVoiceOutput struct {
+	VoiceID   string `json:"voice_id"`
+	VoiceName string `json:"voice_name,omitempty"`
+}
+
+// ListVoicesInput is the input for the list_voices tool.
+type ListVoicesInput struct {
+	Language string `json:"language,omitempty"`
+}
+
+// ListVoicesOutput is the output of the list_voices tool.
+type ListVoicesOutput struct {
+	Voices []VoiceOutput `json:"voices"`
+}
+
+// VoiceOutput is a voice offered by the TTS provider.
+type VoiceOutput struct {
+	ID       string `json:"id"`
+	Name     string `json:"name,omitempty"`
+	Language string `json:"language,omitempty"`
+	Gender   string `json:"gender,omitempty"`
+}
+
+// SetLanguageInput is the input for the set_language tool.
+type SetLanguageInput struct {
+	CallID   string `json:"call_id"`
+	Language string `json:"language"`
+}
+
+// SetLanguageOutput is the output of the set_language tool.
+type SetLanguageOutput struct {
+	Language string `json:"language"`
+}
+
+// ListeningMuteInput is the input for the mute_listening and
+// unmute_listening tools.
+type ListeningMuteInput struct {
+	CallID string `json:"call_id"`
+}
+
+// ListeningMuteOutput is the output of the mute_listening and
+// unmute_listening tools.
+type ListeningMuteOutput struct {
+	Muted bool `json:"muted"`
+}
+
+// StartConferenceInput is the input for the start_conference tool.
+type StartConferenceInput struct {
+	Numbers []string `json:"numbers"`
+	Message string   `json:"message,omitempty"`
+	Volume  *float64 `json:"volume,omitempty"`
+}
+
+// ConferenceParticipant describes a participant in a StartConferenceOutput.
+type ConferenceParticipant struct {
+	Number string `json:"number"`
+	Status string `json:"status"`
+}
+
+// StartConferenceOutput is the output of the start_conference tool.
+type StartConferenceOutput struct {
+	CallID       string                  `json:"call_id"` // the assistant's call; use with continue_call and end_call
+	Conference   string                  `json:"conference"`
+	Participants []ConferenceParticipant `json:"participants"`
+}
+
+// GetCallStatusInput is the input for the get_call_status tool.
+type GetCallStatusInput struct {
+	CallID     string `json:"call_id,omitempty"`
+	ScheduleID string `json:"schedule_id,omitempty"`
+}
+
+// GetCallStatusOutput is the output of the get_call_status tool.
+type GetCallStatusOutput struct {
+	CallID          string  `json:"call_id,omitempty"` // set when looked up by schedule_id
+	Status          string  `json:"status"`            // "scheduled", "ringing", "answered", "ended", ...
+	DurationSeconds float64 `json:"duration_seconds"`
+	Speaking        bool    `json:"speaking"`
+	Listening       bool    `json:"listening"`
+	ListeningMuted  bool    `json:"listening_muted,omitempty"`
+
+	// Metrics so far; omitted for scheduled calls that were not placed yet
+	Metrics *CallMetricsOutput `json:"metrics,omitempty"`
+
+	// For async calls: Pending until the opening message was spoken and
+	// answered, then the user's Response (or NoSpeech).
+	Pending  bool   `json:"pending,omitempty"`
+	Response string `json:"response,omitempty"`
+	NoSpeech bool   `json:"no_speech,omitempty"`
+}
+
+// IsUserOnCallInput is the input for the is_user_on_call tool.
+type IsUserOnCallInput struct {
+	Number string `json:"number,omitempty"`
+}
+
+// IsUserOnCallOutput is the output of the is_user_on_call tool.
+type IsUserOnCallOutput struct {
+	OnCall bool   `json:"on_call"`
+	CallID string `json:"call_id,omitempty"`
+}
+
+// GetBudgetInput is the input for the get_budget tool.
+type GetBudgetInput struct {
+	CallID string `json:"call_id,omitempty"`
+}
+
+// GetBudgetOutput is the output of the get_budget tool. Remaining values
+// are omitted for limits that are not set.
+type GetBudgetOutput struct {
+	CallsRemaining   *int     `json:"calls_remaining,omitempty"`
+	CostRemainingUSD *float64 `json:"cost_remaining_usd,omitempty"`
+	SecondsRemaining *float64 `json:"seconds_remaining,omitempty"`
+
+	// Set when call_id is given
+	CallCostUSD          float64  `json:"call_cost_usd,omitempty"`
+	CallSecondsRemaining *float64 `json:"call_seconds_remaining,omitempty"`
 }
 
 // EndCallInput is the input for the end_call tool.
 type EndCallInput struct {
-	CallID  string `json:"call_id"`
-	Message string `json:"message,omitempty"`
+	CallID  string   `json:"call_id"`
+	Message string   `json:"message,omitempty"`
+	Volume  *float64 `json:"volume,omitempty"`
 }
 
 // EndCallOutput is the output of the end_call tool.
 type EndCallOutput struct {
+	DurationSeconds float64            `json:"duration_seconds"`
+	Metrics         *CallMetricsOutput `json:"metrics,omitempty"`
+}
+
+// CallMetricsOutput reports call latency and audio volume in get_call_status
+// and end_call output.
+type CallMetricsOutput struct {
+	TimeToAnswerMS     int64 `json:"time_to_answer_ms"`
+	FirstWordLatencyMS int64 `json:"first_word_latency_ms"` // dial to first audio sent
+	RoundTrips         int   `json:"round_trips"`
+	AvgRoundTripMS     int64 `json:"avg_round_trip_ms"` // end of our speech to first user transcript
+	MaxRoundTripMS     int64 `json:"max_round_trip_ms"`
+	AudioBytesIn       int64 `json:"audio_bytes_in"`
+	AudioBytesOut      int64 `json:"audio_bytes_out"`
+	STTSetups          int   `json:"stt_setups"`
+	AvgSTTSetupMS      int64 `json:"avg_stt_setup_ms"` // opening a transcription stream
+	MaxSTTSetupMS      int64 `json:"max_stt_setup_ms"`
+	PrefetchHits       int   `json:"prefetch_hits,omitempty"`     // messages played from next_message_hint audio
+	PrefetchSavedMS    int64 `json:"prefetch_saved_ms,omitempty"` // TTS latency saved by them in total
+	TTSCacheHits       int   `json:"tts_cache_hits,omitempty"`    // messages played from cached audio
+	TTSCacheMisses     int   `json:"tts_cache_misses,omitempty"`  // messages synthesized with the cache on
+
+	// Usage that per-character and per-second providers bill for
+	TTSChars   int     `json:"tts_chars"`
+	STTSeconds float64 `json:"stt_seconds"` // time spent listening to the user
+}
+
+// callMetricsOutput converts voice metrics to tool output.
+func callMetricsOutput(m voice.CallMetrics) *CallMetricsOutput {
+	return &CallMetricsOutput{
+		TimeToAnswerMS:     m.TimeToAnswer.Milliseconds(),
+		FirstWordLatencyMS: m.FirstWordLatency.Milliseconds(),
+		RoundTrips:         m.RoundTrips,
+		AvgRoundTripMS:     m.AvgRoundTrip.Milliseconds(),
+		MaxRoundTripMS:     m.MaxRoundTrip.Milliseconds(),
+		AudioBytesIn:       m.AudioBytesIn,
+		AudioBytesOut:      m.AudioBytesOut,
+		STTSetups:          m.STTSetups,
+		AvgSTTSetupMS:      m.AvgSTTSetup.Milliseconds(),
+		MaxSTTSetupMS:      m.MaxSTTSetup.Milliseconds(),
+		PrefetchHits:       m.PrefetchHits,
+		PrefetchSavedMS:    m.PrefetchSaved.Milliseconds(),
+		TTSCacheHits:       m.TTSCacheHits,
+		TTSCacheMisses:     m.TTSCacheMisses,
+		TTSChars:           m.TTSChars,
+		STTSeconds:         m.STTTime.Seconds(),
+	}
+}
+
+// ScheduleCallInput is the input for the schedule_call tool.
+type ScheduleCallInput struct {
+	At      string `json:"at"`
+	Message string `json:"message"`
+}
+
+// ScheduleCallOutput is the output of the schedule_call tool.
+type ScheduleCallOutput struct {
+	ScheduleID string `json:"schedule_id"`
+	At         string `json:"at"`
+}
+
+// CancelScheduledCallInput is the input for the cancel_scheduled_call tool.
+type CancelScheduledCallInput struct {
+	ScheduleID string `json:"schedule_id"`
+}
+
+// CancelScheduledCallOutput is the output of the cancel_scheduled_call tool.
+type CancelScheduledCallOutput struct {
+	Success bool `json:"success"`
+}
+
+// GetCallbacksInput is the input for the get_callbacks tool.
+type GetCallbacksInput struct{}
+
+// GetCallbacksOutput is the output of the get_callbacks tool.
+type GetCallbacksOutput struct {
+	Callbacks []CallbackOutput `json:"callbacks"`
+}
+
+// CallbackOutput is a missed call the user called back.
+type CallbackOutput struct {
+	CallID       string `json:"call_id"`
+	Message      string `json:"message"`
+	MissedAt     string `json:"missed_at"`
+	CalledBackAt string `json:"called_back_at"`
+}
+
+// GetCallHistoryInput is the input for the get_call_history tool.
+type GetCallHistoryInput struct {
+	Since  string `json:"since,omitempty"`
+	Until  string `json:"until,omitempty"`
+	Offset int    `json:"offset,omitempty"`
+	Limit  int    `json:"limit,omitempty"`
+}
+
+// GetCallHistoryOutput is the output of the get_call_history tool.
+type GetCallHistoryOutput struct {
+	Calls      []CallRecordOutput `json:"calls"`
+	NextOffset *int               `json:"next_offset,omitempty"` // set when more calls follow
+}
+
+// CallRecordOutput is a completed call in the call history.
+type CallRecordOutput struct {
+	CallID          string  `json:"call_id"`
+	To              string  `json:"to"` // redacted to the last four digits
+	StartedAt       string  `json:"started_at"`
+	EndedAt         string  `json:"ended_at"`
 	DurationSeconds float64 `json:"duration_seconds"`
+	Turns           int     `json:"turns"`
+	Summary         string  `json:"summary"`
+
+	RecordingConsent bool `json:"recording_consent,omitempty"` // the call was recorded and announced it
 }
 
 // SendMessageInput is the input for the send_message tool.
@@ -89,12 +460,128 @@ type GetMessagesOutput struct {
 	Messages []chat.MessageInfo `json:"messages"`
 }
 
+// audioProperty is the input schema for pre-rendered audio played instead
+// of speaking the message.
+var audioProperty = map[string]any{
+	"type":        "string",
+	"description": "Optional pre-rendered WAV file to play instead of speaking the message: an http(s) URL, or a file in the configured audio directory. The message should say what the audio says; it is recorded in the transcript. Use this for recorded announcements, or when speech synthesis is failing.",
+}
+
+// volumeProperty is the input schema for the optional volume of spoken messages.
+var nextMessageHintProperty = map[string]any{
+	"type":        "string",
+	"description": "Optional message you expect to send next on this call. It is synthesized while the user answers so it plays without delay if your next message is exactly this text; otherwise it is discarded.",
+}
+
+var volumeProperty = map[string]any{
+	"type":        "number",
+	"description": "Optional speech volume from 0.0 to 1.0 (default: 1.0). Use a lower volume for sensitive content such as one-time codes.",
+	"minimum":     0,
+	"maximum":     1,
+}
+
+// speakOptions converts the optional volume from a tool input to speak options.
+func speakOptions(volume *float64) ([]voice.SpeakOption, error) {
+	if volume == nil {
+		return nil, nil
+	}
+	if *volume < 0 || *volume > 1 {
+		return nil, fmt.Errorf("%w, got %g", voice.ErrInvalidVolume, *volume)
+	}
+	return []voice.SpeakOption{voice.WithVolume(*volume)}, nil
+}
+
+// withAudio adds the pre-rendered audio at source to opts, if given.
+func withAudio(ctx context.Context, manager *voice.Manager, opts []voice.SpeakOption, source string) ([]voice.SpeakOption, error) {
+	if source == "" {
+		return opts, nil
+	}
+	audio, err := manager.LoadAudio(ctx, source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load audio: %w", err)
+	}
+	return append(opts, voice.WithAudio(audio)), nil
+}
+
+// progressReporter returns a function that sends each interim transcript to
+// the client as a progress notification, or nil if the client did not ask
+// for progress.
+func progressReporter(ctx context.Context, req *mcp.CallToolRequest) func(string) {
+	if req == nil || req.Session == nil || req.Params == nil {
+		return nil
+	}
+	token := req.Params.GetProgressToken()
+	if token == nil {
+		return nil
+	}
+
+	var n float64
+	return func(transcript string) {
+		n++
+		// Best effort; the final transcript is still returned
+		_ = req.Session.NotifyProgress(ctx, &mcp.ProgressNotificationParams{
+			ProgressToken: token,
+			Progress:      n,
+			Message:       transcript,
+		})
+	}
+}
+
+// placeCall places a call for initiate_call or confirm_call.
+func placeCall(ctx context.Context, manager *voice.Manager, call voice.PendingCall) (*mcp.CallToolResult, InitiateCallOutput, error) {
+	ctx = voice.WithCallMetadata(ctx, call.Metadata)
+	if call.Async {
+		state, err := manager.InitiateCallAsyncOnce(ctx, call.IdempotencyKey, call.Message, call.Options...)
+		if err != nil {
+			return errorResult(fmt.Errorf("failed to initiate call: %w", err)), InitiateCallOutput{}, nil
+		}
+		return nil, InitiateCallOutput{CallID: state.ID, Status: "ringing"}, nil
+	}
+
+	state, response, err := manager.InitiateCallOnce(ctx, call.IdempotencyKey, call.Message, call.Options...)
+	outcome := string(voice.OutcomeOf(err))
+	if errors.Is(err, voice.ErrNoSpeech) {
+		// The call is connected; return its ID so the agent can re-prompt
+		metrics := state.Metrics()
+		return nil, InitiateCallOutput{
+			CallID:             state.ID,
+			NoSpeech:           true,
+			FirstWordLatencyMS: metrics.FirstWordLatency.Milliseconds(),
+			TimeToAnswerMS:     metrics.TimeToAnswer.Milliseconds(),
+			Outcome:            outcome,
+			Attempts:           state.Attempts(),
+		}, nil
+	}
+	if err != nil {
+		// Calls that did not connect are gone, so there is no call ID
+		return errorResult(fmt.Errorf("failed to initiate call: %w", err)), InitiateCallOutput{Outcome: outcome, Attempts: voice.AttemptsOf(err)}, nil
+	}
+
+	metrics := state.Metrics()
+	return nil, InitiateCallOutput{
+		CallID:             state.ID,
+		Response:           response,
+		AnsweredBy:         string(state.AnsweredBy()),
+		UserWantsToEnd:     manager.WantsToEnd(response),
+		Sentiment:          string(manager.LastSentiment(state.ID)),
+		DetectedLanguage:   manager.LanguageMismatch(state.ID),
+		FirstWordLatencyMS: metrics.FirstWordLatency.Milliseconds(),
+		TimeToAnswerMS:     metrics.TimeToAnswer.Milliseconds(),
+		Outcome:            outcome,
+		Attempts:           state.Attempts(),
+	}, nil
+}
+
 // RegisterVoiceTools registers voice-related MCP tools with the runtime.
 func RegisterVoiceTools(rt *mcpkit.Runtime, manager *voice.Manager) {
+	registerVoiceTools(&registry{rt: rt}, manager)
+}
+
+func registerVoiceTools(r *registry, manager *voice.Manager) {
 	// initiate_call - Start a new call to the user
-	mcpkit.AddTool(rt, &mcp.Tool{
+	addTool(r, &mcp.Tool{
 		Name:        "initiate_call",
-		Description: "Call the user on the phone to discuss something. Use this when you need to report task completion, request input, discuss decisions, or escalate blockers. The call will ring the user's phone, and when they answer, your message will be spoken. Then you'll receive their spoken response.",
+		Description: initiateCallDescription(manager),
 		InputSchema: map[string]any{
 			"type": "object",
 			"properties": map[string]any{
@@ -102,25 +589,73 @@ func RegisterVoiceTools(rt *mcpkit.Runtime, manager *voice.Manager) {
 					"type":        "string",
 					"description": "The message to speak to the user when they answer. Should be conversational and clear.",
 				},
+				"volume": volumeProperty,
+				"idempotency_key": map[string]any{
+					"type":        "string",
+					"description": "Optional unique key for this call. Retrying with the same key within 10 minutes returns the original call instead of dialing again.",
+				},
+				"async": map[string]any{
+					"type":        "boolean",
+					"description": "Return immediately with the call_id while the phone is ringing instead of waiting for the answer and reply. The message is spoken when the user answers; poll get_call_status for their response. continue_call waits until that first exchange is done.",
+				},
+				"metadata": map[string]any{
+					"type":                 "object",
+					"additionalProperties": map[string]any{"type": "string"},
+					"description":          fmt.Sprintf("Optional context for the call, such as {\"repo\": \"org/app\", \"task\": \"JIRA-123\"}. Not spoken; it is logged and included in transcripts and the call-ended webhook so the call can be matched to your work. Up to %d entries.", voice.MaxCallMetadataEntries),
+				},
 			},
 			"required": []string{"message"},
 		},
 	}, func(ctx context.Context, req *mcp.CallToolRequest, in InitiateCallInput) (*mcp.CallToolResult, InitiateCallOutput, error) {
-		state, response, err := manager.InitiateCall(ctx, in.Message)
+		opts, err := speakOptions(in.Volume)
 		if err != nil {
-			return nil, InitiateCallOutput{}, fmt.Errorf("failed to initiate call: %w", err)
+			return errorResult(err), InitiateCallOutput{}, nil
 		}
 
-		return nil, InitiateCallOutput{
-			CallID:   state.ID,
-			Response: response,
-		}, nil
+		if err := voice.ValidateCallMetadata(in.Metadata); err != nil {
+			return errorResult(err), InitiateCallOutput{}, nil
+		}
+
+		call := voice.PendingCall{
+			Message:        in.Message,
+			IdempotencyKey: in.IdempotencyKey,
+			Async:          in.Async,
+			Metadata:       in.Metadata,
+			Options:        opts,
+		}
+		if manager.ConfirmationRequired() {
+			token := manager.HoldCall(call)
+			return nil, InitiateCallOutput{Status: "confirmation_required", ConfirmationToken: token}, nil
+		}
+		return placeCall(ctx, manager, call)
+	})
+
+	// confirm_call - Place a call held for confirmation
+	addTool(r, &mcp.Tool{
+		Name:        "confirm_call",
+		Description: "Place a call that initiate_call held for confirmation (status 'confirmation_required'). Only confirm if you still intend to call the user now. The result is the same as initiate_call's.",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"confirmation_token": map[string]any{
+					"type":        "string",
+					"description": "The confirmation_token returned by initiate_call. Tokens expire after 10 minutes and can be used once.",
+				},
+			},
+			"required": []string{"confirmation_token"},
+		},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, in ConfirmCallInput) (*mcp.CallToolResult, InitiateCallOutput, error) {
+		call, err := manager.ConfirmCall(in.ConfirmationToken)
+		if err != nil {
+			return errorResult(fmt.Errorf("failed to confirm call: %w", err)), InitiateCallOutput{}, nil
+		}
+		return placeCall(ctx, manager, call)
 	})
 
 	// continue_call - Continue an existing call with another message
-	mcpkit.AddTool(rt, &mcp.Tool{
+	addTool(r, &mcp.Tool{
 		Name:        "continue_call",
-		Description: "Continue an active phone call by speaking another message and listening for the user's response. Use this for multi-turn conversations within the same call.",
+		Description: "Continue an active phone call by speaking another message and listening for the user's response. Use this for multi-turn conversations within the same call." + messageLimit(manager),
 		InputSchema: map[string]any{
 			"type": "object",
 			"properties": map[string]any{
@@ -132,24 +667,218 @@ func RegisterVoiceTools(rt *mcpkit.Runtime, manager *voice.Manager) {
 					"type":        "string",
 					"description": "The message to speak to the user.",
 				},
+				"volume":            volumeProperty,
+				"next_message_hint": nextMessageHintProperty,
+				"audio":             audioProperty,
 			},
 			"required": []string{"call_id", "message"},
 		},
 	}, func(ctx context.Context, req *mcp.CallToolRequest, in ContinueCallInput) (*mcp.CallToolResult, ContinueCallOutput, error) {
-		response, err := manager.ContinueCall(ctx, in.CallID, in.Message)
+		opts, err := speakOptions(in.Volume)
+		if err != nil {
+			return errorResult(err), ContinueCallOutput{}, nil
+		}
+		if in.NextMessageHint != "" {
+			opts = append(opts, voice.WithNextMessageHint(in.NextMessageHint))
+		}
+		if opts, err = withAudio(ctx, manager, opts, in.Audio); err != nil {
+			return errorResult(err), ContinueCallOutput{}, nil
+		}
+
+		response, err := manager.ContinueCall(ctx, in.CallID, in.Message, opts...)
+		duration, cost := manager.CallElapsed(in.CallID)
+		if errors.Is(err, voice.ErrNoSpeech) {
+			return nil, ContinueCallOutput{NoSpeech: true, DurationSeconds: duration.Seconds(), EstimatedCostUSD: cost}, nil
+		}
 		if err != nil {
-			return nil, ContinueCallOutput{}, fmt.Errorf("failed to continue call: %w", err)
+			return errorResult(fmt.Errorf("failed to continue call: %w", err)), ContinueCallOutput{}, nil
 		}
 
 		return nil, ContinueCallOutput{
-			Response: response,
+			Response:         response,
+			UserWantsToEnd:   manager.WantsToEnd(response),
+			Sentiment:        string(manager.LastSentiment(in.CallID)),
+			DetectedLanguage: manager.LanguageMismatch(in.CallID),
+			DurationSeconds:  duration.Seconds(),
+			EstimatedCostUSD: cost,
 		}, nil
 	})
 
+	// continue_call_streaming - Like continue_call, with interim transcripts
+	// sent as progress notifications
+	addTool(r, &mcp.Tool{
+		Name:        "continue_call_streaming",
+		Description: "Like continue_call, but while the user is speaking, interim transcripts are sent as progress notifications (when the request includes a progress token) so you can start reasoning about a long answer early. The result is the same final transcript continue_call returns." + messageLimit(manager),
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"call_id": map[string]any{
+					"type":        "string",
+					"description": "The ID of the active call (returned from initiate_call).",
+				},
+				"message": map[string]any{
+					"type":        "string",
+					"description": "The message to speak to the user.",
+				},
+				"volume":            volumeProperty,
+				"next_message_hint": nextMessageHintProperty,
+				"audio":             audioProperty,
+			},
+			"required": []string{"call_id", "message"},
+		},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, in ContinueCallInput) (*mcp.CallToolResult, ContinueCallOutput, error) {
+		opts, err := speakOptions(in.Volume)
+		if err != nil {
+			return errorResult(err), ContinueCallOutput{}, nil
+		}
+		if in.NextMessageHint != "" {
+			opts = append(opts, voice.WithNextMessageHint(in.NextMessageHint))
+		}
+		if opts, err = withAudio(ctx, manager, opts, in.Audio); err != nil {
+			return errorResult(err), ContinueCallOutput{}, nil
+		}
+
+		response, err := manager.ContinueCallStreaming(ctx, in.CallID, in.Message, progressReporter(ctx, req), opts...)
+		duration, cost := manager.CallElapsed(in.CallID)
+		if errors.Is(err, voice.ErrNoSpeech) {
+			return nil, ContinueCallOutput{NoSpeech: true, DurationSeconds: duration.Seconds(), EstimatedCostUSD: cost}, nil
+		}
+		if err != nil {
+			return errorResult(fmt.Errorf("failed to continue call: %w", err)), ContinueCallOutput{}, nil
+		}
+
+		return nil, ContinueCallOutput{
+			Response:         response,
+			UserWantsToEnd:   manager.WantsToEnd(response),
+			Sentiment:        string(manager.LastSentiment(in.CallID)),
+			DetectedLanguage: manager.LanguageMismatch(in.CallID),
+			DurationSeconds:  duration.Seconds(),
+			EstimatedCostUSD: cost,
+		}, nil
+	})
+
+	// resume_call - Re-dial a dropped call and carry on the conversation
+	addTool(r, &mcp.Tool{
+		Name:        "resume_call",
+		Description: "Call the user back after a call dropped (continue_call fails with call_dropped) and carry on the same conversation. Speaks the message and listens for the user's response like continue_call. Returns a new call_id to use from then on. Only works shortly after the drop, and only when resuming is enabled." + messageLimit(manager),
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"call_id": map[string]any{
+					"type":        "string",
+					"description": "The ID of the dropped call.",
+				},
+				"message": map[string]any{
+					"type":        "string",
+					"description": "The message to speak when the user answers, e.g. \"Sorry, we got cut off. As I was saying...\"",
+				},
+				"volume": volumeProperty,
+			},
+			"required": []string{"call_id", "message"},
+		},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, in ResumeCallInput) (*mcp.CallToolResult, ResumeCallOutput, error) {
+		opts, err := speakOptions(in.Volume)
+		if err != nil {
+			return errorResult(err), ResumeCallOutput{}, nil
+		}
+
+		state, response, err := manager.ResumeCall(ctx, in.CallID, in.Message, opts...)
+		outcome := string(voice.OutcomeOf(err))
+		if errors.Is(err, voice.ErrNoSpeech) {
+			return nil, ResumeCallOutput{CallID: state.ID, ResumedFrom: in.CallID, NoSpeech: true, Outcome: outcome}, nil
+		}
+		if err != nil {
+			return errorResult(fmt.Errorf("failed to resume call: %w", err)), ResumeCallOutput{Outcome: outcome}, nil
+		}
+
+		return nil, ResumeCallOutput{
+			CallID:           state.ID,
+			ResumedFrom:      in.CallID,
+			Response:         response,
+			UserWantsToEnd:   manager.WantsToEnd(response),
+			Sentiment:        string(manager.LastSentiment(state.ID)),
+			DetectedLanguage: manager.LanguageMismatch(state.ID),
+			Outcome:          outcome,
+		}, nil
+	})
+
+	// repeat_last - Say the last message again
+	addTool(r, &mcp.Tool{
+		Name:        "repeat_last",
+		Description: "Repeat your last message on the call word for word and listen for the user's response. Use this when the user didn't catch it (\"what?\", \"say that again\") instead of rephrasing.",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"call_id": map[string]any{
+					"type":        "string",
+					"description": "The ID of the active call.",
+				},
+				"slower": map[string]any{
+					"type":        "boolean",
+					"description": "Speak the message more slowly than usual (ElevenLabs and OpenAI voices only).",
+				},
+				"volume": volumeProperty,
+			},
+			"required": []string{"call_id"},
+		},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, in RepeatLastInput) (*mcp.CallToolResult, RepeatLastOutput, error) {
+		opts, err := speakOptions(in.Volume)
+		if err != nil {
+			return errorResult(err), RepeatLastOutput{}, nil
+		}
+
+		message, response, err := manager.RepeatLast(ctx, in.CallID, in.Slower, opts...)
+		if errors.Is(err, voice.ErrNoSpeech) {
+			return nil, RepeatLastOutput{Message: message, NoSpeech: true}, nil
+		}
+		if err != nil {
+			return errorResult(fmt.Errorf("failed to repeat message: %w", err)), RepeatLastOutput{}, nil
+		}
+
+		return nil, RepeatLastOutput{
+			Message:        message,
+			Response:       response,
+			UserWantsToEnd: manager.WantsToEnd(response),
+		}, nil
+	})
+
+	// confirm_with_user - Ask a yes/no question until the answer is clear
+	addTool(r, &mcp.Tool{
+		Name:        "confirm_with_user",
+		Description: "Ask the user a yes/no question on an active call and get back a boolean. Unclear answers are asked again a few times. Use this before high-stakes or irreversible actions instead of interpreting a free-form continue_call reply. Fails with no_clear_answer if the user never gives a clear yes or no." + messageLimit(manager),
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"call_id": map[string]any{
+					"type":        "string",
+					"description": "The ID of the active call.",
+				},
+				"question": map[string]any{
+					"type":        "string",
+					"description": "A question the user can answer with yes or no, e.g. \"Should I deploy to production now?\"",
+				},
+				"volume": volumeProperty,
+			},
+			"required": []string{"call_id", "question"},
+		},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, in ConfirmWithUserInput) (*mcp.CallToolResult, ConfirmWithUserOutput, error) {
+		opts, err := speakOptions(in.Volume)
+		if err != nil {
+			return errorResult(err), ConfirmWithUserOutput{}, nil
+		}
+
+		confirmed, response, err := manager.ConfirmWithUser(ctx, in.CallID, in.Question, opts...)
+		if err != nil {
+			return errorResult(fmt.Errorf("failed to confirm with user: %w", err)), ConfirmWithUserOutput{}, nil
+		}
+
+		return nil, ConfirmWithUserOutput{Confirmed: confirmed, Response: response}, nil
+	})
+
 	// speak_to_user - Speak without waiting for response
-	mcpkit.AddTool(rt, &mcp.Tool{
+	addTool(r, &mcp.Tool{
 		Name:        "speak_to_user",
-		Description: "Speak a message to the user without waiting for a response. Use this for acknowledgments before performing time-consuming operations, or for status updates during a call.",
+		Description: "Speak a message to the user without waiting for a response. Use this for acknowledgments before performing time-consuming operations, or for status updates during a call." + messageLimit(manager),
 		InputSchema: map[string]any{
 			"type": "object",
 			"properties": map[string]any{
@@ -161,20 +890,356 @@ func RegisterVoiceTools(rt *mcpkit.Runtime, manager *voice.Manager) {
 					"type":        "string",
 					"description": "The message to speak to the user.",
 				},
+				"volume": volumeProperty,
+				"audio":  audioProperty,
 			},
 			"required": []string{"call_id", "message"},
 		},
 	}, func(ctx context.Context, req *mcp.CallToolRequest, in SpeakToUserInput) (*mcp.CallToolResult, SpeakToUserOutput, error) {
-		err := manager.SpeakToUser(ctx, in.CallID, in.Message)
+		opts, err := speakOptions(in.Volume)
+		if err != nil {
+			return errorResult(err), SpeakToUserOutput{Success: false}, nil
+		}
+		if opts, err = withAudio(ctx, manager, opts, in.Audio); err != nil {
+			return errorResult(err), SpeakToUserOutput{Success: false}, nil
+		}
+
+		err = manager.SpeakToUser(ctx, in.CallID, in.Message, opts...)
 		if err != nil {
-			return nil, SpeakToUserOutput{Success: false}, fmt.Errorf("failed to speak: %w", err)
+			return errorResult(fmt.Errorf("failed to speak: %w", err)), SpeakToUserOutput{Success: false}, nil
 		}
 
-		return nil, SpeakToUserOutput{Success: true}, nil
+		duration, cost := manager.CallElapsed(in.CallID)
+		return nil, SpeakToUserOutput{Success: true, DurationSeconds: duration.Seconds(), EstimatedCostUSD: cost}, nil
+	})
+
+	// speak_and_wait_digits - Speak, then accept either speech or key presses
+	addTool(r, &mcp.Tool{
+		Name:        "speak_and_wait_digits",
+		Description: "Speak a prompt on an active call and wait for the user to either answer by voice or press keys on their phone, whichever comes first. Use this for prompts like \"say yes or press 1\". The result's type is 'speech' (value is the transcript) or 'dtmf' (value is the keys pressed). If both arrive together, key presses win." + messageLimit(manager),
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"call_id": map[string]any{
+					"type":        "string",
+					"description": "The ID of the active call.",
+				},
+				"message": map[string]any{
+					"type":        "string",
+					"description": "The prompt to speak to the user.",
+				},
+				"max_digits": map[string]any{
+					"type":        "integer",
+					"description": "Maximum number of keys to collect (default: 1). Entry also ends when the user presses # or pauses.",
+					"default":     1,
+				},
+				"volume": volumeProperty,
+			},
+			"required": []string{"call_id", "message"},
+		},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, in SpeakAndWaitDigitsInput) (*mcp.CallToolResult, SpeakAndWaitDigitsOutput, error) {
+		opts, err := speakOptions(in.Volume)
+		if err != nil {
+			return errorResult(err), SpeakAndWaitDigitsOutput{}, nil
+		}
+
+		input, err := manager.SpeakAndWaitDigits(ctx, in.CallID, in.Message, in.MaxDigits, opts...)
+		if errors.Is(err, voice.ErrNoSpeech) {
+			return nil, SpeakAndWaitDigitsOutput{NoSpeech: true}, nil
+		}
+		if err != nil {
+			return errorResult(fmt.Errorf("failed to collect input: %w", err)), SpeakAndWaitDigitsOutput{}, nil
+		}
+
+		out := SpeakAndWaitDigitsOutput{
+			Type:  string(input.Type),
+			Value: input.Value,
+		}
+		// Key presses have no tone; the call's last sentiment would belong
+		// to an earlier reply
+		if input.Type == voice.InputSpeech {
+			out.UserWantsToEnd = manager.WantsToEnd(input.Value)
+			out.Sentiment = string(manager.LastSentiment(in.CallID))
+			out.DetectedLanguage = manager.LanguageMismatch(in.CallID)
+		}
+		return nil, out, nil
+	})
+
+	// set_voice - Change the TTS voice for the rest of a call
+	addTool(r, &mcp.Tool{
+		Name:        "set_voice",
+		Description: "Change the voice used to speak on an active call. Later messages use the new voice without re-dialing. Use this for role-play, or to switch to a clearer voice if the user has trouble understanding.",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"call_id": map[string]any{
+					"type":        "string",
+					"description": "The ID of the active call.",
+				},
+				"voice_id": map[string]any{
+					"type":        "string",
+					"description": "The TTS provider's voice ID to switch to.",
+				},
+			},
+			"required": []string{"call_id", "voice_id"},
+		},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, in SetVoiceInput) (*mcp.CallToolResult, SetVoiceOutput, error) {
+		v, err := manager.SetCallVoice(ctx, in.CallID, in.VoiceID)
+		if err != nil {
+			return errorResult(fmt.Errorf("failed to set voice: %w", err)), SetVoiceOutput{}, nil
+		}
+
+		return nil, SetVoiceOutput{
+			VoiceID:   v.ID,
+			VoiceName: v.Name,
+		}, nil
+	})
+
+	// list_voices - Voices the TTS provider offers
+	addTool(r, &mcp.Tool{
+		Name:        "list_voices",
+		Description: "List the voices the TTS provider offers, with their IDs, names, and languages. Use it to pick a voice ID for set_voice, for example one that speaks the user's language, or to help the user choose a voice for the configuration. Pass language to only list voices for it.",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"language": map[string]any{
+					"type":        "string",
+					"description": "Only list voices for this language, e.g. \"es\" or \"pt-BR\". A base code also matches its regional variants.",
+				},
+			},
+		},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, in ListVoicesInput) (*mcp.CallToolResult, ListVoicesOutput, error) {
+		voices, err := manager.ListVoices(ctx)
+		if err != nil {
+			return errorResult(fmt.Errorf("failed to list voices: %w", err)), ListVoicesOutput{}, nil
+		}
+
+		out := ListVoicesOutput{Voices: []VoiceOutput{}}
+		for _, v := range voices {
+			if in.Language != "" && !voice.VoiceSpeaks(v, in.Language) {
+				continue
+			}
+			out.Voices = append(out.Voices, VoiceOutput{
+				ID:       v.ID,
+				Name:     v.Name,
+				Language: v.Language,
+				Gender:   v.Gender,
+			})
+		}
+		return nil, out, nil
+	})
+
+	// set_language - Change the STT language for the rest of a call
+	addTool(r, &mcp.Tool{
+		Name:        "set_language",
+		Description: "Change the language the user's replies are transcribed in on an active call, e.g. when they answer in Spanish on an English call. Later replies are transcribed in the new language without re-dialing. When language detection is enabled, replies in another language come back with detected_language set. This does not change the voice; call set_voice too if the current voice can't speak the language.",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"call_id": map[string]any{
+					"type":        "string",
+					"description": "The ID of the active call.",
+				},
+				"language": map[string]any{
+					"type":        "string",
+					"description": "BCP-47 language code to transcribe in, e.g. \"es\" or \"pt-BR\".",
+				},
+			},
+			"required": []string{"call_id", "language"},
+		},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, in SetLanguageInput) (*mcp.CallToolResult, SetLanguageOutput, error) {
+		language, err := manager.SetCallLanguage(ctx, in.CallID, in.Language)
+		if err != nil {
+			return errorResult(fmt.Errorf("failed to set language: %w", err)), SetLanguageOutput{}, nil
+		}
+		return nil, SetLanguageOutput{Language: language}, nil
+	})
+
+	// mute_listening, unmute_listening - Pause transcription on a call
+	muteSchema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"call_id": map[string]any{
+				"type":        "string",
+				"description": "The ID of the active call.",
+			},
+		},
+		"required": []string{"call_id"},
+	}
+	setMuted := func(ctx context.Context, callID string, muted bool) (*mcp.CallToolResult, ListeningMuteOutput, error) {
+		muted, err := manager.SetListeningMuted(ctx, callID, muted)
+		if err != nil {
+			return errorResult(fmt.Errorf("failed to set listening mute: %w", err)), ListeningMuteOutput{}, nil
+		}
+		return nil, ListeningMuteOutput{Muted: muted}, nil
+	}
+	addTool(r, &mcp.Tool{
+		Name:        "mute_listening",
+		Description: "Stop transcribing the user on an active call without hanging up, e.g. while they talk to someone else. The call stays connected and speak_to_user still works, but continue_call and the other tools that wait for a reply fail with listening_muted until unmute_listening is called. No speech-to-text is used while muted.",
+		InputSchema: muteSchema,
+	}, func(ctx context.Context, req *mcp.CallToolRequest, in ListeningMuteInput) (*mcp.CallToolResult, ListeningMuteOutput, error) {
+		return setMuted(ctx, in.CallID, true)
+	})
+	addTool(r, &mcp.Tool{
+		Name:        "unmute_listening",
+		Description: "Resume transcribing the user on a call muted with mute_listening, so continue_call can wait for replies again.",
+		InputSchema: muteSchema,
+	}, func(ctx context.Context, req *mcp.CallToolRequest, in ListeningMuteInput) (*mcp.CallToolResult, ListeningMuteOutput, error) {
+		return setMuted(ctx, in.CallID, false)
+	})
+
+	// start_conference - Dial several people into a call with the assistant
+	addTool(r, &mcp.Tool{
+		Name:        "start_conference",
+		Description: "Start a conference call: dial one or more people into a shared call that you take part in, e.g. for a pairing session. Returns a call_id for your own leg; use continue_call and speak_to_user with it to talk to everyone, and end_call to end the conference for everyone. Only the user's number and configured conference numbers can be dialed." + messageLimit(manager),
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"numbers": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Phone numbers to dial in E.164 format, e.g. +15551234567.",
+				},
+				"message": map[string]any{
+					"type":        "string",
+					"description": "Optional message to speak once you are connected, e.g. an introduction while people join.",
+				},
+				"volume": volumeProperty,
+			},
+			"required": []string{"numbers"},
+		},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, in StartConferenceInput) (*mcp.CallToolResult, StartConferenceOutput, error) {
+		opts, err := speakOptions(in.Volume)
+		if err != nil {
+			return errorResult(err), StartConferenceOutput{}, nil
+		}
+
+		conf, err := manager.StartConference(ctx, in.Numbers, in.Message, opts...)
+		if err != nil && conf == nil {
+			return errorResult(fmt.Errorf("failed to start conference: %w", err)), StartConferenceOutput{}, nil
+		}
+		if err != nil {
+			// The conference is up; only the opening message failed
+			return errorResult(fmt.Errorf("conference %s started but the message failed (call_id %s): %w", conf.Name, conf.Call.ID, err)), StartConferenceOutput{}, nil
+		}
+
+		out := StartConferenceOutput{
+			CallID:     conf.Call.ID,
+			Conference: conf.Name,
+		}
+		for _, p := range conf.Participants() {
+			out.Participants = append(out.Participants, ConferenceParticipant{
+				Number: p.Number,
+				Status: string(p.Status),
+			})
+		}
+		return nil, out, nil
+	})
+
+	// get_call_status - Read-only status check
+	addTool(r, &mcp.Tool{
+		Name:        "get_call_status",
+		Description: "Check the status of a call without affecting it: ringing, answered, ended, etc., how long it has lasted, whether the assistant is currently speaking or listening, and latency metrics so far. Use this to confirm a call is still live before continue_call, e.g. if the user may have hung up. Pass schedule_id instead of call_id to find the call placed by schedule_call; its call_id is returned for continue_call or end_call.",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"call_id": map[string]any{
+					"type":        "string",
+					"description": "The ID of the call.",
+				},
+				"schedule_id": map[string]any{
+					"type":        "string",
+					"description": "The schedule ID returned from schedule_call, instead of call_id. Status is \"scheduled\" until the call is placed.",
+				},
+			},
+		},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, in GetCallStatusInput) (*mcp.CallToolResult, GetCallStatusOutput, error) {
+		callID := in.CallID
+		if callID == "" && in.ScheduleID != "" {
+			var err error
+			callID, err = manager.ScheduledCallID(in.ScheduleID)
+			if err != nil {
+				return errorResult(fmt.Errorf("failed to get call status: %w", err)), GetCallStatusOutput{}, nil
+			}
+			if callID == "" {
+				return nil, GetCallStatusOutput{Status: "scheduled"}, nil
+			}
+		}
+
+		info, err := manager.CallStatus(ctx, callID)
+		if err != nil {
+			return errorResult(fmt.Errorf("failed to get call status: %w", err)), GetCallStatusOutput{}, nil
+		}
+
+		return nil, GetCallStatusOutput{
+			CallID:          info.CallID,
+			Status:          string(info.Status),
+			DurationSeconds: info.Duration.Seconds(),
+			Speaking:        info.Speaking,
+			Listening:       info.Listening,
+			ListeningMuted:  info.Muted,
+			Metrics:         callMetricsOutput(info.Metrics),
+			Pending:         info.Pending,
+			Response:        info.Response,
+			NoSpeech:        info.NoSpeech,
+		}, nil
+	})
+
+	// is_user_on_call - Avoid dialing someone already on a call
+	addTool(r, &mcp.Tool{
+		Name:        "is_user_on_call",
+		Description: "Check whether the user is already on a call with you, ringing or in progress, before calling with initiate_call. If so, the call's call_id is returned: continue that call with continue_call instead of dialing again.",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"number": map[string]any{
+					"type":        "string",
+					"description": "Number to check in E.164 format. Defaults to the user's number.",
+				},
+			},
+		},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, in IsUserOnCallInput) (*mcp.CallToolResult, IsUserOnCallOutput, error) {
+		callID, onCall := manager.IsNumberActive(ctx, in.Number)
+		return nil, IsUserOnCallOutput{OnCall: onCall, CallID: callID}, nil
+	})
+
+	// get_budget - Room left in the daily call budget
+	addTool(r, &mcp.Tool{
+		Name:        "get_budget",
+		Description: "Check how much of the daily call budget is left: calls, estimated cost in USD, and the call time that cost pays for. Pass call_id to also get the call's cost so far and how many seconds it can go on before the cost budget runs out, so you can keep the conversation short when little is left. Values are omitted for limits that are not configured.",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"call_id": map[string]any{
+					"type":        "string",
+					"description": "The ID of the current call, if any.",
+				},
+			},
+		},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, in GetBudgetInput) (*mcp.CallToolResult, GetBudgetOutput, error) {
+		b, err := manager.Budget(ctx, in.CallID)
+		if err != nil {
+			return errorResult(fmt.Errorf("failed to get budget: %w", err)), GetBudgetOutput{}, nil
+		}
+
+		out := GetBudgetOutput{CallCostUSD: b.CallCostUSD}
+		if b.MaxCalls > 0 {
+			out.CallsRemaining = &b.CallsRemaining
+		}
+		if b.MaxCostUSD > 0 {
+			seconds := b.TimeRemaining.Seconds()
+			out.CostRemainingUSD = &b.CostRemainingUSD
+			out.SecondsRemaining = &seconds
+			if in.CallID != "" {
+				callSeconds := b.CallTimeRemaining.Seconds()
+				out.CallSecondsRemaining = &callSeconds
+			}
+		}
+		return nil, out, nil
 	})
 
 	// end_call - End the call with an optional final message
-	mcpkit.AddTool(rt, &mcp.Tool{
+	addTool(r, &mcp.Tool{
 		Name:        "end_call",
 		Description: "End an active phone call. Optionally speak a final message before hanging up. The message will be spoken and then the call will be terminated.",
 		InputSchema: map[string]any{
@@ -188,25 +1253,190 @@ func RegisterVoiceTools(rt *mcpkit.Runtime, manager *voice.Manager) {
 					"type":        "string",
 					"description": "Optional final message to speak before ending the call.",
 				},
+				"volume": volumeProperty,
 			},
 			"required": []string{"call_id"},
 		},
 	}, func(ctx context.Context, req *mcp.CallToolRequest, in EndCallInput) (*mcp.CallToolResult, EndCallOutput, error) {
-		duration, err := manager.EndCall(ctx, in.CallID, in.Message)
+		opts, err := speakOptions(in.Volume)
 		if err != nil {
-			return nil, EndCallOutput{}, fmt.Errorf("failed to end call: %w", err)
+			return errorResult(err), EndCallOutput{}, nil
+		}
+
+		metrics, err := manager.EndCall(ctx, in.CallID, in.Message, opts...)
+		if err != nil {
+			return errorResult(fmt.Errorf("failed to end call: %w", err)), EndCallOutput{}, nil
 		}
 
 		return nil, EndCallOutput{
-			DurationSeconds: duration.Seconds(),
+			DurationSeconds: metrics.Duration.Seconds(),
+			Metrics:         callMetricsOutput(metrics),
+		}, nil
+	})
+
+	// schedule_call - Place a call at a future time
+	addTool(r, &mcp.Tool{
+		Name:        "schedule_call",
+		Description: "Schedule a phone call to the user at a future time, e.g. to deliver results at an agreed time. The message is spoken when the user answers and the call stays connected; pass the schedule_id to get_call_status to find the call_id, read the reply, and continue or end the call. Scheduled calls survive a server restart. Times during the user's quiet hours are rejected." + messageLimit(manager),
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"at": map[string]any{
+					"type":        "string",
+					"description": "When to call, as an RFC 3339 timestamp with time zone (e.g. 2025-01-15T15:00:00-08:00).",
+				},
+				"message": map[string]any{
+					"type":        "string",
+					"description": "The message to speak to the user when they answer.",
+				},
+			},
+			"required": []string{"at", "message"},
+		},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, in ScheduleCallInput) (*mcp.CallToolResult, ScheduleCallOutput, error) {
+		at, err := time.Parse(time.RFC3339, in.At)
+		if err != nil {
+			return errorResult(fmt.Errorf("%w: at must be an RFC 3339 timestamp: %v", voice.ErrInvalidSchedule, err)), ScheduleCallOutput{}, nil
+		}
+
+		scheduled, err := manager.ScheduleCall(ctx, at, in.Message)
+		if err != nil {
+			return errorResult(fmt.Errorf("failed to schedule call: %w", err)), ScheduleCallOutput{}, nil
+		}
+
+		return nil, ScheduleCallOutput{
+			ScheduleID: scheduled.ID,
+			At:         scheduled.At.Format(time.RFC3339),
 		}, nil
 	})
+
+	// cancel_scheduled_call - Cancel a pending scheduled call
+	addTool(r, &mcp.Tool{
+		Name:        "cancel_scheduled_call",
+		Description: "Cancel a call previously scheduled with schedule_call, if it has not been placed yet.",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"schedule_id": map[string]any{
+					"type":        "string",
+					"description": "The schedule ID returned from schedule_call.",
+				},
+			},
+			"required": []string{"schedule_id"},
+		},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, in CancelScheduledCallInput) (*mcp.CallToolResult, CancelScheduledCallOutput, error) {
+		if err := manager.CancelScheduledCall(ctx, in.ScheduleID); err != nil {
+			return errorResult(fmt.Errorf("failed to cancel scheduled call: %w", err)), CancelScheduledCallOutput{Success: false}, nil
+		}
+
+		return nil, CancelScheduledCallOutput{Success: true}, nil
+	})
+
+	// get_callbacks - Missed calls the user has called back
+	addTool(r, &mcp.Tool{
+		Name:        "get_callbacks",
+		Description: "List calls the user did not answer but has since called back, with the message each call was meant to deliver. Each callback is returned once. Use initiate_call to resume the conversation.",
+		InputSchema: map[string]any{
+			"type":       "object",
+			"properties": map[string]any{},
+		},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, in GetCallbacksInput) (*mcp.CallToolResult, GetCallbacksOutput, error) {
+		out := GetCallbacksOutput{Callbacks: []CallbackOutput{}}
+		for _, c := range manager.TakeCallbacks(ctx) {
+			out.Callbacks = append(out.Callbacks, CallbackOutput{
+				CallID:       c.CallID,
+				Message:      c.Message,
+				MissedAt:     c.At.Format(time.RFC3339),
+				CalledBackAt: c.CalledBackAt.Format(time.RFC3339),
+			})
+		}
+		return nil, out, nil
+	})
+
+	// get_call_history - Completed calls from the persisted call log
+	addTool(r, &mcp.Tool{
+		Name:        "get_call_history",
+		Description: "List completed calls with the user, newest first, with when each took place, how long it lasted, and the start of the conversation. Use this to recall earlier calls, e.g. what was discussed yesterday. Filter by when calls ended with since and until, and page through results with offset and the returned next_offset.",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"since": map[string]any{
+					"type":        "string",
+					"description": "Only calls that ended at or after this RFC 3339 timestamp.",
+				},
+				"until": map[string]any{
+					"type":        "string",
+					"description": "Only calls that ended before this RFC 3339 timestamp.",
+				},
+				"offset": map[string]any{
+					"type":        "integer",
+					"description": "Number of calls to skip, from next_offset of the previous page (default: 0).",
+					"minimum":     0,
+				},
+				"limit": map[string]any{
+					"type":        "integer",
+					"description": "Maximum number of calls to return (default: 10).",
+					"default":     10,
+					"minimum":     1,
+					"maximum":     maxCallHistoryLimit,
+				},
+			},
+		},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, in GetCallHistoryInput) (*mcp.CallToolResult, GetCallHistoryOutput, error) {
+		var since, until time.Time
+		var err error
+		if in.Since != "" {
+			if since, err = time.Parse(time.RFC3339, in.Since); err != nil {
+				return errorResult(fmt.Errorf("%w: since must be an RFC 3339 timestamp: %v", voice.ErrInvalidHistoryQuery, err)), GetCallHistoryOutput{}, nil
+			}
+		}
+		if in.Until != "" {
+			if until, err = time.Parse(time.RFC3339, in.Until); err != nil {
+				return errorResult(fmt.Errorf("%w: until must be an RFC 3339 timestamp: %v", voice.ErrInvalidHistoryQuery, err)), GetCallHistoryOutput{}, nil
+			}
+		}
+		limit := in.Limit
+		if limit <= 0 {
+			limit = 10
+		}
+		limit = min(limit, maxCallHistoryLimit)
+
+		records, more, err := manager.CallHistory(ctx, since, until, in.Offset, limit)
+		if err != nil {
+			return errorResult(fmt.Errorf("failed to get call history: %w", err)), GetCallHistoryOutput{}, nil
+		}
+
+		out := GetCallHistoryOutput{Calls: []CallRecordOutput{}}
+		for _, rec := range records {
+			out.Calls = append(out.Calls, CallRecordOutput{
+				CallID:           rec.CallID,
+				To:               rec.To,
+				StartedAt:        rec.StartedAt.Format(time.RFC3339),
+				EndedAt:          rec.EndedAt.Format(time.RFC3339),
+				DurationSeconds:  rec.DurationSeconds,
+				Turns:            rec.Turns,
+				Summary:          rec.Summary,
+				RecordingConsent: rec.RecordingConsent,
+			})
+		}
+		if more {
+			next := in.Offset + len(records)
+			out.NextOffset = &next
+		}
+		return nil, out, nil
+	})
 }
 
+// maxCallHistoryLimit caps the calls returned by one get_call_history page.
+const maxCallHistoryLimit = 50
+
 // RegisterChatTools registers chat-related MCP tools with the runtime.
 func RegisterChatTools(rt *mcpkit.Runtime, manager *chat.Manager) {
+	registerChatTools(&registry{rt: rt}, manager)
+}
+
+func registerChatTools(r *registry, manager *chat.Manager) {
 	// send_message - Send a message to a chat channel
-	mcpkit.AddTool(rt, &mcp.Tool{
+	addTool(r, &mcp.Tool{
 		Name:        "send_message",
 		Description: "Send a message to the user via a chat channel (Discord, Telegram, or WhatsApp). Use this for asynchronous communication when the user is not on a phone call.",
 		InputSchema: map[string]any{
@@ -240,14 +1470,14 @@ func RegisterChatTools(rt *mcpkit.Runtime, manager *chat.Manager) {
 			err = manager.SendMessage(ctx, in.Provider, in.ChatID, in.Message)
 		}
 		if err != nil {
-			return nil, SendMessageOutput{Success: false}, fmt.Errorf("failed to send message: %w", err)
+			return errorResult(fmt.Errorf("failed to send message: %w", err)), SendMessageOutput{Success: false}, nil
 		}
 
 		return nil, SendMessageOutput{Success: true}, nil
 	})
 
 	// list_channels - List available chat channels
-	mcpkit.AddTool(rt, &mcp.Tool{
+	addTool(r, &mcp.Tool{
 		Name:        "list_channels",
 		Description: "List all available chat channels and their connection status. Returns which messaging platforms are connected and ready to use.",
 		InputSchema: map[string]any{
@@ -260,7 +1490,7 @@ func RegisterChatTools(rt *mcpkit.Runtime, manager *chat.Manager) {
 	})
 
 	// get_messages - Get recent messages from a chat
-	mcpkit.AddTool(rt, &mcp.Tool{
+	addTool(r, &mcp.Tool{
 		Name:        "get_messages",
 		Description: "Get recent messages from a chat conversation. Use this to see what the user has said in a chat channel.",
 		InputSchema: map[string]any{
@@ -291,7 +1521,7 @@ func RegisterChatTools(rt *mcpkit.Runtime, manager *chat.Manager) {
 
 		messages, err := manager.GetMessages(in.Provider, in.ChatID, limit)
 		if err != nil {
-			return nil, GetMessagesOutput{}, fmt.Errorf("failed to get messages: %w", err)
+			return errorResult(fmt.Errorf("failed to get messages: %w", err)), GetMessagesOutput{}, nil
 		}
 
 		return nil, GetMessagesOutput{Messages: messages}, nil
@@ -300,15 +1530,115 @@ func RegisterChatTools(rt *mcpkit.Runtime, manager *chat.Manager) {
 
 // RegisterTools registers all MCP tools (voice + chat + inbound) with the runtime.
 // This is a convenience function that calls RegisterVoiceTools, RegisterChatTools, and RegisterInboundTools.
-func RegisterTools(rt *mcpkit.Runtime, voiceManager *voice.Manager, chatManager *chat.Manager) {
+// If enabled is not empty, only the named tools are registered; an unknown
+// name is an error and nothing is registered.
+func RegisterTools(rt *mcpkit.Runtime, voiceManager *voice.Manager, chatManager *chat.Manager, enabled []string) error {
+	r, err := newRegistry(rt, voiceManager, enabled)
+	if err != nil {
+		return err
+	}
+
 	if voiceManager != nil {
-		RegisterVoiceTools(rt, voiceManager)
+		registerVoiceTools(r, voiceManager)
 	}
 	if chatManager != nil {
-		RegisterChatTools(rt, chatManager)
+		registerChatTools(r, chatManager)
 	}
 
 	// Always register inbound tools - they check daemon status dynamically
 	inboundManager := NewInboundManager(InboundConfig{})
-	RegisterInboundTools(rt, inboundManager)
+	registerInboundTools(r, inboundManager)
+	return nil
+}
+
+// RefreshToolDescriptions registers the voice tools again so descriptions
+// that include live call constraints, such as the remaining budget and
+// whether it is quiet hours, are current. rt, voiceManager, and enabled must
+// be those given to RegisterTools.
+func RefreshToolDescriptions(rt *mcpkit.Runtime, voiceManager *voice.Manager, enabled []string) error {
+	r, err := newRegistry(rt, voiceManager, enabled)
+	if err != nil {
+		return err
+	}
+	registerVoiceTools(r, voiceManager)
+	return nil
+}
+
+// newRegistry returns a registry for rt that only adds the enabled tools
+// (all tools if enabled is empty) and authenticates tenants if voiceManager
+// has any.
+func newRegistry(rt *mcpkit.Runtime, voiceManager *voice.Manager, enabled []string) (*registry, error) {
+	r := &registry{rt: rt}
+	if len(enabled) > 0 {
+		known := ToolNames()
+		r.enabled = make(map[string]bool, len(enabled))
+		for _, name := range enabled {
+			if !slices.Contains(known, name) {
+				return nil, fmt.Errorf("unknown tool %q in enabled tools", name)
+			}
+			r.enabled[name] = true
+		}
+	}
+	if voiceManager != nil && voiceManager.MultiTenant() {
+		r.authenticate = voiceManager.Authenticate
+	}
+	return r, nil
+}
+
+// ToolNames returns the names of all tools RegisterTools can register, in
+// registration order.
+func ToolNames() []string {
+	r := &registry{}
+	registerVoiceTools(r, nil)
+	registerChatTools(r, nil)
+	registerInboundTools(r, nil)
+	return r.names
+}
+
+// registry adds tools to a runtime, skipping those not enabled. With a nil
+// runtime it only records tool names; handlers are not run, so managers may
+// be nil then.
+type registry struct {
+	rt      *mcpkit.Runtime
+	enabled map[string]bool // nil enables all tools
+	names   []string
+
+	// authenticate, if set, resolves the APIKeyHeader of each request to
+	// its tenant before the handler runs.
+	authenticate func(ctx context.Context, apiKey string) (context.Context, error)
+}
+
+// APIKeyHeader carries a tenant's API key on MCP requests when tenants are
+// configured.
+const APIKeyHeader = "X-API-Key"
+
+// requestAPIKey returns the API key sent with req, if any.
+func requestAPIKey(req *mcp.CallToolRequest) string {
+	if req == nil || req.Extra == nil {
+		return ""
+	}
+	return req.Extra.Header.Get(APIKeyHeader)
+}
+
+// addTool registers a tool with r's runtime if the tool is enabled.
+func addTool[In, Out any](r *registry, t *mcp.Tool, h func(context.Context, *mcp.CallToolRequest, In) (*mcp.CallToolResult, Out, error)) {
+	if r.rt == nil {
+		r.names = append(r.names, t.Name)
+		return
+	}
+	if r.enabled != nil && !r.enabled[t.Name] {
+		return
+	}
+	if authenticate := r.authenticate; authenticate != nil {
+		next := h
+		h = func(ctx context.Context, req *mcp.CallToolRequest, in In) (*mcp.CallToolResult, Out, error) {
+			ctx, err := authenticate(ctx, requestAPIKey(req))
+			if err != nil {
+				var zero Out
+				return errorResult(err), zero, nil
+			}
+			return next(ctx, req, in)
+		}
+	}
+	mcpkit.AddTool(r.rt, t, h)
 }
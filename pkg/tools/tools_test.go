@@ -0,0 +1,30 @@
+package tools
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestToolNames(t *testing.T) {
+	names := ToolNames()
+	for _, want := range []string{"initiate_call", "end_call", "send_message", "check_messages"} {
+		if !slices.Contains(names, want) {
+			t.Errorf("ToolNames() = %v, missing %q", names, want)
+		}
+	}
+	seen := make(map[string]bool)
+	for _, name := range names {
+		if seen[name] {
+			t.Errorf("ToolNames() lists %q twice", name)
+		}
+		seen[name] = true
+	}
+}
+
+func TestRegisterTools_UnknownTool(t *testing.T) {
+	err := RegisterTools(nil, nil, nil, []string{"initiate_call", "intiate_call"})
+	if err == nil || !strings.Contains(err.Error(), `"intiate_call"`) {
+		t.Errorf("RegisterTools() error = %v, want the unknown tool named", err)
+	}
+}
@@ -0,0 +1,49 @@
+package voice
+
+import (
+	"context"
+	"time"
+)
+
+// AcceptDigit is the key the callee presses to accept a call when
+// RequireAccept is enabled.
+const AcceptDigit = "1"
+
+// acceptTimeout is how long InitiateCall waits for the callee to accept
+// after answering. It covers the spoken prompt plus the gather timeout.
+const acceptTimeout = 20 * time.Second
+
+// NotifyAccepted records whether the callee of the call with the given
+// provider call ID (e.g., a Twilio CallSid) pressed AcceptDigit. It never
+// blocks; unknown calls are ignored.
+func (m *Manager) NotifyAccepted(providerCallID string, accepted bool) {
+	m.callsMu.RLock()
+	defer m.callsMu.RUnlock()
+
+	for _, state := range m.calls {
+		if state.Call == nil || state.Call.ID() != providerCallID {
+			continue
+		}
+		select {
+		case state.acceptCh <- accepted:
+		default:
+		}
+		return
+	}
+}
+
+// waitForAccept waits up to timeout for the callee to accept the call. A
+// wrong digit, no digit, or a timeout all count as declined.
+func waitForAccept(ctx context.Context, state *CallState, timeout time.Duration) bool {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case accepted := <-state.acceptCh:
+		return accepted
+	case <-timer.C:
+		return false
+	case <-ctx.Done():
+		return false
+	}
+}
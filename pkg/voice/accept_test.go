@@ -0,0 +1,39 @@
+package voice
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestWaitForAccept(t *testing.T) {
+	tests := []struct {
+		name     string
+		accepted *bool
+		want     bool
+	}{
+		{"accepted", func() *bool { b := true; return &b }(), true},
+		{"wrong digit", func() *bool { b := false; return &b }(), false},
+		{"no answer", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestManager(t)
+			state := &CallState{
+				ID:       "call-1",
+				Call:     &fakeCall{id: "CA123"},
+				acceptCh: make(chan bool, 1),
+			}
+			m.calls[state.ID] = state
+
+			if tt.accepted != nil {
+				m.NotifyAccepted("CA123", *tt.accepted)
+			}
+
+			if got := waitForAccept(context.Background(), state, 20*time.Millisecond); got != tt.want {
+				t.Errorf("waitForAccept() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
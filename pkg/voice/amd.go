@@ -0,0 +1,133 @@
+package voice
+
+import (
+	"context"
+	"time"
+)
+
+// AnsweredBy is the answering machine detection (AMD) result for a call.
+type AnsweredBy string
+
+// AMD results.
+const (
+	AnsweredByHuman   AnsweredBy = "human"
+	AnsweredByMachine AnsweredBy = "machine"
+	AnsweredByFax     AnsweredBy = "fax"
+	AnsweredByUnknown AnsweredBy = "unknown"
+)
+
+// answeredByMachineStart is Twilio's early machine result, sent as soon as
+// a machine is detected and while its greeting is still playing. It is
+// reported as AnsweredByMachine; speech waits for the end-of-greeting result.
+const answeredByMachineStart AnsweredBy = "machine_start"
+
+// amdBufferSize fits machine_start and the end-of-greeting result arriving
+// before anyone waits for them.
+const amdBufferSize = 2
+
+// machineGreetingTimeout bounds the wait for a voicemail greeting to end
+// after a machine is detected.
+const machineGreetingTimeout = 30 * time.Second
+
+// AnsweredByFromTwilio maps a Twilio AnsweredBy callback value to an AMD
+// result. It returns false for empty or unrecognized values.
+func AnsweredByFromTwilio(answeredBy string) (AnsweredBy, bool) {
+	switch answeredBy {
+	case "human":
+		return AnsweredByHuman, true
+	case "machine_start":
+		return answeredByMachineStart, true
+	case "machine_end_beep", "machine_end_silence", "machine_end_other":
+		return AnsweredByMachine, true
+	case "fax":
+		return AnsweredByFax, true
+	case "unknown":
+		return AnsweredByUnknown, true
+	default:
+		return "", false
+	}
+}
+
+// AnsweredBy returns the AMD result for the call, or "" if none has arrived.
+func (cs *CallState) AnsweredBy() AnsweredBy {
+	if answeredBy := cs.rawAnsweredBy(); answeredBy != answeredByMachineStart {
+		return answeredBy
+	}
+	return AnsweredByMachine
+}
+
+// rawAnsweredBy returns the latest AMD result, including machine_start.
+func (cs *CallState) rawAnsweredBy() AnsweredBy {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+	return cs.answeredBy
+}
+
+// NotifyAnsweredBy records the AMD result for the call with the given
+// provider call ID (e.g., a Twilio CallSid). It never blocks; unknown calls
+// are ignored.
+func (m *Manager) NotifyAnsweredBy(providerCallID string, answeredBy AnsweredBy) {
+	m.callsMu.RLock()
+	defer m.callsMu.RUnlock()
+
+	for _, state := range m.calls {
+		if state.Call == nil || state.Call.ID() != providerCallID {
+			continue
+		}
+		state.mu.Lock()
+		// A late machine_start must not hide the end-of-greeting result
+		if answeredBy != answeredByMachineStart || state.answeredBy == "" {
+			state.answeredBy = answeredBy
+		}
+		state.mu.Unlock()
+
+		select {
+		case state.amdCh <- answeredBy:
+		default:
+		}
+		return
+	}
+}
+
+// waitForAnsweredBy waits up to timeout for the AMD result so the first
+// message is not spoken over a voicemail greeting. When a machine is
+// detected it also waits, up to machineGreetingTimeout, for the greeting to
+// end. It returns AnsweredByUnknown if no result arrives in time.
+func waitForAnsweredBy(ctx context.Context, state *CallState, timeout time.Duration) AnsweredBy {
+	answeredBy := state.rawAnsweredBy()
+	if answeredBy == "" {
+		answeredBy = awaitAMD(ctx, state.amdCh, timeout, "")
+	}
+
+	switch answeredBy {
+	case "":
+		return AnsweredByUnknown
+	case answeredByMachineStart:
+		if end := awaitAMD(ctx, state.amdCh, machineGreetingTimeout, answeredByMachineStart); end != "" {
+			return end
+		}
+		return AnsweredByMachine
+	default:
+		return answeredBy
+	}
+}
+
+// awaitAMD returns the next result on ch other than skip, or "" if none
+// arrives within timeout.
+func awaitAMD(ctx context.Context, ch <-chan AnsweredBy, timeout time.Duration, skip AnsweredBy) AnsweredBy {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for {
+		select {
+		case answeredBy := <-ch:
+			if answeredBy != skip {
+				return answeredBy
+			}
+		case <-timer.C:
+			return ""
+		case <-ctx.Done():
+			return ""
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package voice
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/plexusone/omnivoice"
+)
+
+func TestAnsweredByFromTwilio(t *testing.T) {
+	tests := []struct {
+		input string
+		want  AnsweredBy
+		ok    bool
+	}{
+		{"human", AnsweredByHuman, true},
+		{"machine_start", answeredByMachineStart, true},
+		{"machine_end_beep", AnsweredByMachine, true},
+		{"fax", AnsweredByFax, true},
+		{"unknown", AnsweredByUnknown, true},
+		{"", "", false},
+		{"robot", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, ok := AnsweredByFromTwilio(tt.input)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("AnsweredByFromTwilio(%q) = (%q, %v), want (%q, %v)", tt.input, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestWaitForAnsweredBy(t *testing.T) {
+	m := newTestManager(t)
+	state := &CallState{
+		ID:    "call-1",
+		Call:  &fakeCall{id: "CA123"},
+		amdCh: make(chan AnsweredBy, 1),
+	}
+	m.calls[state.ID] = state
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		m.NotifyAnsweredBy("CA123", AnsweredByMachine)
+	}()
+
+	if got := waitForAnsweredBy(context.Background(), state, time.Second); got != AnsweredByMachine {
+		t.Errorf("waitForAnsweredBy() = %q, want %q", got, AnsweredByMachine)
+	}
+	if got := state.AnsweredBy(); got != AnsweredByMachine {
+		t.Errorf("AnsweredBy() = %q, want %q", got, AnsweredByMachine)
+	}
+}
+
+func TestWaitForAnsweredBy_WaitsForGreetingEnd(t *testing.T) {
+	m := newTestManager(t)
+	state := &CallState{
+		ID:    "call-1",
+		Call:  &fakeCall{id: "CA123"},
+		amdCh: make(chan AnsweredBy, amdBufferSize),
+	}
+	m.calls[state.ID] = state
+
+	m.NotifyAnsweredBy("CA123", answeredByMachineStart)
+	if got := state.AnsweredBy(); got != AnsweredByMachine {
+		t.Errorf("AnsweredBy() during greeting = %q, want %q", got, AnsweredByMachine)
+	}
+
+	ended := make(chan struct{})
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		close(ended)
+		m.NotifyAnsweredBy("CA123", AnsweredByMachine)
+	}()
+
+	if got := waitForAnsweredBy(context.Background(), state, time.Second); got != AnsweredByMachine {
+		t.Errorf("waitForAnsweredBy() = %q, want %q", got, AnsweredByMachine)
+	}
+	select {
+	case <-ended:
+	default:
+		t.Error("waitForAnsweredBy() returned before the greeting ended")
+	}
+}
+
+func TestLeaveVoicemail(t *testing.T) {
+	m := newTestManager(t)
+	m.ttsProvider = &fakeTTS{streams: [][]omnivoice.StreamChunk{{{Audio: []byte("msg"), IsFinal: true}}}}
+	conn := &fakeConn{}
+	state := &CallState{ID: "call-1", Call: hangupCall{&fakeCall{id: "CA123", transport: conn}}}
+	m.calls[state.ID] = state
+
+	if err := m.leaveVoicemail(context.Background(), state, "The build is done."); !errors.Is(err, ErrVoicemail) {
+		t.Fatalf("leaveVoicemail() error = %v, want ErrVoicemail", err)
+	}
+	if conn.audio.String() != "msg" {
+		t.Errorf("audio = %q, want the message", conn.audio.String())
+	}
+	if m.GetCall(state.ID) != nil {
+		t.Error("call still registered after voicemail")
+	}
+	if missed, ok := m.missed.take(time.Now()); !ok || missed.CallID != state.ID {
+		t.Errorf("missed call = %+v, %v; want the voicemail call", missed, ok)
+	}
+}
+
+func TestWaitForAnsweredBy_Timeout(t *testing.T) {
+	state := &CallState{amdCh: make(chan AnsweredBy, 1)}
+
+	if got := waitForAnsweredBy(context.Background(), state, 10*time.Millisecond); got != AnsweredByUnknown {
+		t.Errorf("waitForAnsweredBy() = %q, want %q", got, AnsweredByUnknown)
+	}
+}
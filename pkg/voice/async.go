@@ -0,0 +1,176 @@
+package voice
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// failedCallTTL is how long the reason an async call did not connect is
+// kept for CallStatus and ContinueCall.
+const failedCallTTL = 10 * time.Minute
+
+// openingTurn is the first turn of a call placed with InitiateCallAsync,
+// which runs in the background. done is closed once the fields are set.
+type openingTurn struct {
+	done     chan struct{}
+	response string
+	err      error
+}
+
+// failedCall is why an async call did not connect.
+type failedCall struct {
+	err error
+	at  time.Time
+}
+
+// failedCalls remembers async calls that were removed before the agent
+// could learn why.
+type failedCalls struct {
+	mu      sync.Mutex
+	entries map[string]failedCall
+}
+
+// record stores the error for callID. Expired entries are pruned.
+func (f *failedCalls) record(callID string, err error, now time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for id, c := range f.entries {
+		if now.Sub(c.at) > failedCallTTL {
+			delete(f.entries, id)
+		}
+	}
+	if f.entries == nil {
+		f.entries = make(map[string]failedCall)
+	}
+	f.entries[callID] = failedCall{err: err, at: now}
+}
+
+// lookup returns the error recorded for callID, or nil.
+func (f *failedCalls) lookup(callID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.entries[callID].err
+}
+
+// InitiateCallAsync places a call to the user and returns as soon as it is
+// ringing. Once the user answers, message is spoken and their reply is
+// collected in the background; CallStatus reports it, and ContinueCall
+// waits for it before taking the next turn. If the call does not connect,
+// it is removed and CallStatus and ContinueCall return the reason.
+func (m *Manager) InitiateCallAsync(ctx context.Context, message string, opts ...SpeakOption) (*CallState, error) {
+	if err := m.checkMessage(message); err != nil {
+		return nil, err
+	}
+	state, err := m.dial(ctx, m.configFor(ctx).UserPhoneNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	turn := &openingTurn{done: make(chan struct{})}
+	state.mu.Lock()
+	state.opening = turn
+	state.mu.Unlock()
+
+	// The call outlives the request that placed it
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		defer close(turn.done)
+
+		if err := m.connect(ctx, state, message); err != nil {
+			m.failed.record(state.ID, err, time.Now())
+			turn.err = err
+			return
+		}
+
+		turn.response, turn.err = m.speakAndListen(ctx, state, message, nil, opts...)
+		if turn.err != nil {
+			turn.err = speechError(turn.err)
+		}
+	}()
+
+	return state, nil
+}
+
+// InitiateCallAsyncOnce is InitiateCallAsync with an idempotency key (see
+// InitiateCallOnce).
+func (m *Manager) InitiateCallAsyncOnce(ctx context.Context, key, message string, opts ...SpeakOption) (*CallState, error) {
+	state, _, err := m.once(ctx, key, func() (*CallState, string, error) {
+		state, err := m.InitiateCallAsync(ctx, message, opts...)
+		return state, "", err
+	})
+	return state, err
+}
+
+// readyCall returns the active call with the given ID, first waiting for
+// the opening turn of an async call to finish. A call that used up its
+// TTS or STT allowance is ended instead (see checkUsage).
+func (m *Manager) readyCall(ctx context.Context, callID string) (*CallState, error) {
+	state := m.tenantCall(ctx, callID)
+	if state == nil {
+		return nil, m.callNotFound(callID)
+	}
+	if err := state.awaitOpening(ctx); err != nil {
+		return nil, err
+	}
+	if m.getCall(callID) == nil {
+		return nil, m.callNotFound(callID)
+	}
+	if state.dropped() {
+		return nil, fmt.Errorf("%w: %s", ErrCallDropped, callID)
+	}
+	if err := m.checkUsage(ctx, state); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+// awaitOpening waits for the background opening turn of an async call, if
+// any, so turns never overlap.
+func (cs *CallState) awaitOpening(ctx context.Context) error {
+	cs.mu.RLock()
+	turn := cs.opening
+	cs.mu.RUnlock()
+	if turn == nil {
+		return nil
+	}
+
+	select {
+	case <-turn.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// openingResult reports the state of an async call's opening turn.
+func (cs *CallState) openingResult() (pending bool, response string, err error) {
+	cs.mu.RLock()
+	turn := cs.opening
+	cs.mu.RUnlock()
+	if turn == nil {
+		return false, "", nil
+	}
+
+	select {
+	case <-turn.done:
+		return false, turn.response, turn.err
+	default:
+		return true, "", nil
+	}
+}
+
+// callNotFound returns why callID is not an active call: the recorded
+// reason for an async call that did not connect, ErrNotInitialized if the
+// providers failed to start, or ErrCallNotFound.
+func (m *Manager) callNotFound(callID string) error {
+	if err := m.failed.lookup(callID); err != nil {
+		return fmt.Errorf("call %s did not connect: %w", callID, err)
+	}
+	if m.InitError() != nil {
+		return m.notInitialized()
+	}
+	return fmt.Errorf("%w: %s", ErrCallNotFound, callID)
+}
@@ -0,0 +1,83 @@
+package voice
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/plexusone/omnivoice"
+)
+
+// dialCallSystem returns call for every MakeCall.
+type dialCallSystem struct {
+	omnivoice.CallSystem
+
+	call omnivoice.Call
+}
+
+func (d *dialCallSystem) MakeCall(context.Context, string, ...omnivoice.CallOption) (omnivoice.Call, error) {
+	return d.call, nil
+}
+
+// hangupCall is a fakeCall that can be hung up.
+type hangupCall struct{ *fakeCall }
+
+func (hangupCall) Hangup(context.Context) error { return nil }
+
+func TestInitiateCallAsync_NotAnswered(t *testing.T) {
+	m := newTestManager(t)
+	call := &fakeCall{id: "CA-1", status: omnivoice.StatusRinging}
+	m.callSystem = &dialCallSystem{call: hangupCall{call}}
+
+	state, err := m.InitiateCallAsync(context.Background(), "The build is done.")
+	if err != nil {
+		t.Fatalf("InitiateCallAsync() error = %v", err)
+	}
+
+	info, err := m.CallStatus(context.Background(), state.ID)
+	if err != nil {
+		t.Fatalf("CallStatus() error = %v", err)
+	}
+	if !info.Pending || info.Status != omnivoice.StatusRinging {
+		t.Errorf("CallStatus() = %+v, want pending and ringing", info)
+	}
+
+	m.NotifyStatus("CA-1", omnivoice.StatusNoAnswer)
+	if err := state.awaitOpening(context.Background()); err != nil {
+		t.Fatalf("awaitOpening() error = %v", err)
+	}
+
+	// The call is gone, but the reason is still reported
+	if _, err := m.CallStatus(context.Background(), state.ID); !errors.Is(err, ErrNotAnswered) {
+		t.Errorf("CallStatus() error = %v, want ErrNotAnswered", err)
+	}
+	if _, err := m.ContinueCall(context.Background(), state.ID, "Hello?"); !errors.Is(err, ErrNotAnswered) {
+		t.Errorf("ContinueCall() error = %v, want ErrNotAnswered", err)
+	}
+	if _, ok := m.missed.take(time.Now()); !ok {
+		t.Error("unanswered async call was not recorded as missed")
+	}
+}
+
+func TestInitiateCallAsync_EndedWhileRinging(t *testing.T) {
+	m := newTestManager(t)
+	call := &fakeCall{id: "CA-1", status: omnivoice.StatusRinging}
+	m.callSystem = &dialCallSystem{call: hangupCall{call}}
+
+	state, err := m.InitiateCallAsync(context.Background(), "The build is done.")
+	if err != nil {
+		t.Fatalf("InitiateCallAsync() error = %v", err)
+	}
+
+	m.removeCall(state.ID)
+	call.setStatus(omnivoice.StatusEnded)
+	if err := state.awaitOpening(context.Background()); err != nil {
+		t.Fatalf("awaitOpening() error = %v", err)
+	}
+
+	// Hanging up on purpose is not a missed call
+	if _, ok := m.missed.take(time.Now()); ok {
+		t.Error("call ended by the agent was recorded as missed")
+	}
+}
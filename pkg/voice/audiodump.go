@@ -0,0 +1,170 @@
+package voice
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// audioDump holds the files a call's audio is copied to when
+// Config.AudioDumpDir is set: what the user said and what the assistant
+// said, each in its own file.
+type audioDump struct {
+	user, assistant *dumpFile
+}
+
+// openAudioDump creates the dump files for a call, named after its ID. It
+// returns nil if dumping is off or the files cannot be created, which is
+// logged rather than failing the call.
+func (m *Manager) openAudioDump(callID string) *audioDump {
+	dir := m.config.AudioDumpDir
+	if dir == "" {
+		return nil
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		slog.Warn("failed to create audio dump directory", "call_id", callID, "dir", dir, "error", err)
+		return nil
+	}
+
+	// Mu-law is decoded to PCM WAV so the files play anywhere; other
+	// codecs are written as received
+	ext := m.codec.name
+	if m.codec == codecMulaw {
+		ext = "wav"
+	}
+	user, err := createDumpFile(filepath.Join(dir, fmt.Sprintf("%s-user.%s", callID, ext)), m.codec)
+	if err != nil {
+		slog.Warn("failed to create audio dump", "call_id", callID, "error", err)
+		return nil
+	}
+	assistant, err := createDumpFile(filepath.Join(dir, fmt.Sprintf("%s-assistant.%s", callID, ext)), m.codec)
+	if err != nil {
+		user.close()
+		slog.Warn("failed to create audio dump", "call_id", callID, "error", err)
+		return nil
+	}
+	slog.Debug("dumping call audio", "call_id", callID, "user", user.f.Name(), "assistant", assistant.f.Name())
+	return &audioDump{user: user, assistant: assistant}
+}
+
+// teeAssistantAudio returns a writer that copies audio written to w into
+// the call's assistant dump, or w if the call has none.
+func (cs *CallState) teeAssistantAudio(w io.Writer) io.Writer {
+	if cs.audioDump == nil {
+		return w
+	}
+	return io.MultiWriter(w, cs.audioDump.assistant)
+}
+
+// teeUserAudio returns a reader that copies audio read from r into the
+// call's user dump, or r if the call has none.
+func (cs *CallState) teeUserAudio(r io.Reader) io.Reader {
+	if cs.audioDump == nil {
+		return r
+	}
+	return io.TeeReader(r, cs.audioDump.user)
+}
+
+// closeAudioDump finishes the call's dump files, if any.
+func (cs *CallState) closeAudioDump() {
+	if cs.audioDump == nil {
+		return
+	}
+	cs.audioDump.user.close()
+	cs.audioDump.assistant.close()
+}
+
+// dumpFile is one side of a call's audio dump. Writes never fail, so a
+// full disk cannot break the call; the first error is logged and the rest
+// of the audio is dropped.
+type dumpFile struct {
+	mu     sync.Mutex
+	f      *os.File
+	wav    bool // mu-law decoded to 16-bit PCM
+	size   int  // bytes written after the header
+	buf    []byte
+	failed bool
+}
+
+// createDumpFile creates the file at path. Mu-law audio gets a WAV header,
+// whose sizes are filled in by close.
+func createDumpFile(path string, c codec) (*dumpFile, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return nil, err
+	}
+	d := &dumpFile{f: f, wav: c == codecMulaw}
+	if d.wav {
+		if _, err := f.Write(wavHeader(c.sampleRate, 0)); err != nil {
+			_ = f.Close()
+			return nil, err
+		}
+	}
+	return d, nil
+}
+
+// Write appends audio to the file. It always reports success.
+func (d *dumpFile) Write(p []byte) (int, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.failed || d.f == nil {
+		return len(p), nil
+	}
+
+	data := p
+	if d.wav {
+		d.buf = d.buf[:0]
+		for _, b := range p {
+			d.buf = binary.LittleEndian.AppendUint16(d.buf, uint16(int16(ulawToLinear(b))))
+		}
+		data = d.buf
+	}
+	n, err := d.f.Write(data)
+	d.size += n
+	if err != nil {
+		d.failed = true
+		slog.Warn("failed to write audio dump; dropping the rest", "file", d.f.Name(), "error", err)
+	}
+	return len(p), nil
+}
+
+// close fills in the WAV header sizes and closes the file. Later writes
+// are dropped.
+func (d *dumpFile) close() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.f == nil {
+		return
+	}
+	if d.wav {
+		if _, err := d.f.WriteAt(wavHeader(codecMulaw.sampleRate, d.size), 0); err != nil {
+			slog.Warn("failed to finish audio dump", "file", d.f.Name(), "error", err)
+		}
+	}
+	_ = d.f.Close()
+	d.f = nil
+}
+
+// wavHeader returns the header of a mono 16-bit PCM WAV file with
+// dataSize bytes of samples.
+func wavHeader(sampleRate, dataSize int) []byte {
+	const bytesPerSample = 2
+	h := make([]byte, 0, 44)
+	h = append(h, "RIFF"...)
+	h = binary.LittleEndian.AppendUint32(h, uint32(36+dataSize))
+	h = append(h, "WAVEfmt "...)
+	h = binary.LittleEndian.AppendUint32(h, 16) // fmt chunk size
+	h = binary.LittleEndian.AppendUint16(h, 1)  // PCM
+	h = binary.LittleEndian.AppendUint16(h, 1)  // mono
+	h = binary.LittleEndian.AppendUint32(h, uint32(sampleRate))
+	h = binary.LittleEndian.AppendUint32(h, uint32(sampleRate*bytesPerSample))
+	h = binary.LittleEndian.AppendUint16(h, bytesPerSample)
+	h = binary.LittleEndian.AppendUint16(h, 8*bytesPerSample)
+	h = append(h, "data"...)
+	h = binary.LittleEndian.AppendUint32(h, uint32(dataSize))
+	return h
+}
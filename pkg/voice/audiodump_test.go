@@ -0,0 +1,104 @@
+package voice
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/plexusone/omnivoice"
+)
+
+func TestAudioDump_SpeakAndListen(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "dumps")
+	m := newTestManager(t)
+	m.config.AudioDumpDir = dir
+	m.ttsProvider = &fakeTTS{streams: [][]omnivoice.StreamChunk{
+		{{Audio: []byte{0x00, 0x80, ulawSilence}, IsFinal: true}},
+	}}
+	conn := &fakeConn{}
+	state := m.addCall(context.Background(), &fakeCall{transport: conn}, "+15551234567", time.Now())
+
+	if err := m.speak(context.Background(), state, "Hello."); err != nil {
+		t.Fatalf("speak() error = %v", err)
+	}
+	if _, err := io.ReadAll(state.teeUserAudio(bytes.NewReader([]byte{ulawSilence, 0x00}))); err != nil {
+		t.Fatalf("read user audio: %v", err)
+	}
+	m.removeCall(state.ID)
+
+	assistant := readWAV(t, filepath.Join(dir, state.ID+"-assistant.wav"))
+	if want := []int16{-32124, 32124, 0}; !slices.Equal(assistant, want) {
+		t.Errorf("assistant samples = %v, want %v", assistant, want)
+	}
+	if got := conn.audio.Bytes(); !bytes.Equal(got, []byte{0x00, 0x80, ulawSilence}) {
+		t.Errorf("call audio = %x, want mu-law passed through", got)
+	}
+	user := readWAV(t, filepath.Join(dir, state.ID+"-user.wav"))
+	if want := []int16{0, -32124}; !slices.Equal(user, want) {
+		t.Errorf("user samples = %v, want %v", user, want)
+	}
+}
+
+func TestAudioDump_Disabled(t *testing.T) {
+	m := newTestManager(t)
+	if d := m.openAudioDump("call-1"); d != nil {
+		t.Error("audio dumped without AudioDumpDir")
+	}
+
+	state := &CallState{ID: "call-1"}
+	var buf bytes.Buffer
+	if w := state.teeAssistantAudio(&buf); w != &buf {
+		t.Error("assistant audio teed without a dump")
+	}
+	state.closeAudioDump()
+}
+
+func TestDumpFile_WritesAfterCloseDropped(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "call-1-user.wav")
+	d, err := createDumpFile(path, codecMulaw)
+	if err != nil {
+		t.Fatalf("createDumpFile() error = %v", err)
+	}
+	_, _ = d.Write([]byte{ulawSilence})
+	d.close()
+	if n, err := d.Write([]byte{0x00}); n != 1 || err != nil {
+		t.Errorf("Write() after close = %d, %v; want success", n, err)
+	}
+	d.close()
+
+	if got := readWAV(t, path); len(got) != 1 {
+		t.Errorf("samples = %v, want the one written before close", got)
+	}
+}
+
+// readWAV checks the header of the WAV file at path and returns its
+// samples.
+func readWAV(t *testing.T, path string) []int16 {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read %s: %v", path, err)
+	}
+	if len(data) < 44 || string(data[:4]) != "RIFF" || string(data[8:16]) != "WAVEfmt " {
+		t.Fatalf("%s: not a WAV file", path)
+	}
+	if rate := binary.LittleEndian.Uint32(data[24:]); rate != 8000 {
+		t.Errorf("%s: sample rate = %d, want 8000", path, rate)
+	}
+	size := int(binary.LittleEndian.Uint32(data[40:]))
+	if size != len(data)-44 || int(binary.LittleEndian.Uint32(data[4:])) != 36+size {
+		t.Fatalf("%s: header sizes do not match %d data bytes", path, len(data)-44)
+	}
+
+	samples := make([]int16, size/2)
+	for i := range samples {
+		samples[i] = int16(binary.LittleEndian.Uint16(data[44+2*i:]))
+	}
+	return samples
+}
@@ -0,0 +1,271 @@
+package voice
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	// maxAudioDuration is the longest pre-rendered audio that may be
+	// played as one message.
+	maxAudioDuration = 2 * time.Minute
+
+	// maxAudioBytes bounds how much of an audio file is read: two minutes
+	// of 48 kHz 16-bit stereo, with room for the header.
+	maxAudioBytes = 24 << 20
+
+	// audioFetchTimeout bounds downloading audio from a URL.
+	audioFetchTimeout = 15 * time.Second
+)
+
+// WAV format codes for the encodings LoadAudio accepts.
+const (
+	wavFormatPCM        = 1
+	wavFormatMulaw      = 7
+	wavFormatExtensible = 0xFFFE
+)
+
+// Audio is a pre-rendered message to play with WithAudio, converted to
+// the call's 8 kHz mu-law.
+type Audio struct {
+	ulaw []byte
+}
+
+// Duration returns how long the audio plays.
+func (a *Audio) Duration() time.Duration {
+	return codecMulaw.playbackDuration(len(a.ulaw))
+}
+
+// WithAudio plays a instead of synthesizing the message, which is still
+// recorded as the assistant's turn. Nothing goes through the TTS provider,
+// so canned audio still plays while it is down.
+func WithAudio(a *Audio) SpeakOption {
+	return func(o *speakOptions) {
+		o.audio = a
+	}
+}
+
+// LoadAudio reads a WAV file to play with WithAudio from source, an
+// http(s) URL or a path under Config.AudioDir. 8 kHz mono mu-law is played
+// as is; 16-bit PCM at a multiple of 8 kHz is mixed down and resampled.
+// Other formats, empty audio, and audio longer than two minutes fail with
+// ErrInvalidAudio, as does any audio when calls use a compressed codec.
+func (m *Manager) LoadAudio(ctx context.Context, source string) (*Audio, error) {
+	if !m.codec.sampled {
+		return nil, fmt.Errorf("%w: pre-rendered audio is not supported with the %s codec", ErrInvalidAudio, m.codec.name)
+	}
+
+	data, err := m.readAudioSource(ctx, source)
+	if err != nil {
+		return nil, err
+	}
+	ulaw, err := decodeWAV(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrInvalidAudio, source, err)
+	}
+
+	a := &Audio{ulaw: ulaw}
+	if d := a.Duration(); d > maxAudioDuration {
+		return nil, fmt.Errorf("%w: %s: %v long (limit %v)", ErrInvalidAudio, source, d.Round(time.Second), maxAudioDuration)
+	}
+	return a, nil
+}
+
+// readAudioSource returns the contents of source, downloading URLs and
+// reading paths only from inside Config.AudioDir.
+func (m *Manager) readAudioSource(ctx context.Context, source string) ([]byte, error) {
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		return fetchAudio(ctx, source)
+	}
+
+	dir := m.config.AudioDir
+	if dir == "" {
+		return nil, fmt.Errorf("%w: audio files cannot be played without an audio directory (set AGENTCOMMS_AUDIO_DIR)", ErrInvalidAudio)
+	}
+	name := source
+	if filepath.IsAbs(name) {
+		rel, err := filepath.Rel(dir, name)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s is not in the audio directory", ErrInvalidAudio, source)
+		}
+		name = rel
+	}
+
+	// Opening in the directory also rejects paths that escape it through
+	// ".." or symlinks
+	f, err := os.OpenInRoot(dir, name)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to open %s: %w", ErrInvalidAudio, source, err)
+	}
+	defer f.Close()
+	return readAudio(f, source)
+}
+
+// fetchAudio downloads audio from url.
+func fetchAudio(ctx context.Context, url string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, audioFetchTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidAudio, err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download audio: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: %s returned status %d", ErrInvalidAudio, url, resp.StatusCode)
+	}
+	return readAudio(resp.Body, url)
+}
+
+// readAudio reads r up to maxAudioBytes.
+func readAudio(r io.Reader, source string) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxAudioBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audio %s: %w", source, err)
+	}
+	if len(data) > maxAudioBytes {
+		return nil, fmt.Errorf("%w: %s is larger than %d MB", ErrInvalidAudio, source, maxAudioBytes>>20)
+	}
+	return data, nil
+}
+
+// wavFormat is a WAV file's fmt chunk.
+type wavFormat struct {
+	format     int
+	channels   int
+	sampleRate int
+	bits       int
+}
+
+// decodeWAV returns the audio in a WAV file as 8 kHz mono mu-law.
+func decodeWAV(data []byte) ([]byte, error) {
+	if len(data) < 12 || string(data[0:4]) != "RIFF" || string(data[8:12]) != "WAVE" {
+		return nil, fmt.Errorf("not a WAV file")
+	}
+
+	var format *wavFormat
+	var samples []byte
+	for rest := data[12:]; len(rest) >= 8 && samples == nil; {
+		id := string(rest[0:4])
+		size := int(binary.LittleEndian.Uint32(rest[4:8]))
+		rest = rest[8:]
+		if size > len(rest) {
+			// Streamed files may leave the data size unset
+			if id != "data" {
+				return nil, fmt.Errorf("truncated %q chunk", id)
+			}
+			size = len(rest)
+		}
+		chunk := rest[:size]
+		rest = rest[min(size+size%2, len(rest)):]
+
+		switch id {
+		case "fmt ":
+			if len(chunk) < 16 {
+				return nil, fmt.Errorf("truncated fmt chunk")
+			}
+			format = &wavFormat{
+				format:     int(binary.LittleEndian.Uint16(chunk[0:2])),
+				channels:   int(binary.LittleEndian.Uint16(chunk[2:4])),
+				sampleRate: int(binary.LittleEndian.Uint32(chunk[4:8])),
+				bits:       int(binary.LittleEndian.Uint16(chunk[14:16])),
+			}
+			// The real format of an extensible file leads its subformat GUID
+			if format.format == wavFormatExtensible && len(chunk) >= 26 {
+				format.format = int(binary.LittleEndian.Uint16(chunk[24:26]))
+			}
+		case "data":
+			if format == nil {
+				return nil, fmt.Errorf("data before fmt chunk")
+			}
+			samples = chunk
+		}
+	}
+	if format == nil || samples == nil {
+		return nil, fmt.Errorf("missing fmt or data chunk")
+	}
+	return format.toULaw(samples)
+}
+
+// toULaw converts samples in f to 8 kHz mono mu-law.
+func (f *wavFormat) toULaw(samples []byte) ([]byte, error) {
+	var width int
+	switch {
+	case f.format == wavFormatMulaw && f.bits == 8:
+		width = 1
+	case f.format == wavFormatPCM && f.bits == 16:
+		width = 2
+	case f.format == wavFormatMulaw || f.format == wavFormatPCM:
+		return nil, fmt.Errorf("unsupported %d-bit samples (need 8-bit mu-law or 16-bit PCM)", f.bits)
+	default:
+		return nil, fmt.Errorf("unsupported WAV format %d (need mu-law or PCM)", f.format)
+	}
+	if f.channels < 1 || f.channels > 2 {
+		return nil, fmt.Errorf("unsupported %d channels (need mono or stereo)", f.channels)
+	}
+	if f.sampleRate <= 0 || f.sampleRate%codecMulaw.sampleRate != 0 {
+		return nil, fmt.Errorf("unsupported sample rate %d Hz (need a multiple of %d Hz)", f.sampleRate, codecMulaw.sampleRate)
+	}
+
+	frame := width * f.channels
+	frames := len(samples) / frame
+	if frames == 0 {
+		return nil, fmt.Errorf("no audio")
+	}
+	if width == 1 && f.channels == 1 && f.sampleRate == codecMulaw.sampleRate {
+		return samples[:frames], nil
+	}
+
+	sample := func(i int) int {
+		if width == 1 {
+			return ulawToLinear(samples[i])
+		}
+		return int(int16(binary.LittleEndian.Uint16(samples[i:])))
+	}
+
+	// Mix down, and average each run of frames into one 8 kHz sample
+	step := f.sampleRate / codecMulaw.sampleRate
+	out := make([]byte, frames/step)
+	for i := range out {
+		sum := 0
+		for j := range step {
+			for c := range f.channels {
+				sum += sample((i*step+j)*frame + c*width)
+			}
+		}
+		out[i] = linearToULaw(sum / (step * f.channels))
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("no audio")
+	}
+	return out, nil
+}
+
+// playAudio writes audio to w in chunks of size, stopping between chunks
+// if ctx is cancelled. It returns the number of bytes written.
+func playAudio(ctx context.Context, w io.Writer, audio []byte, size int) (int, error) {
+	written := 0
+	for chunk := range slices.Chunk(audio, size) {
+		if err := ctx.Err(); err != nil {
+			return written, err
+		}
+		n, err := w.Write(chunk)
+		written += n
+		if err != nil {
+			return written, fmt.Errorf("failed to write audio: %w", err)
+		}
+	}
+	return written, nil
+}
@@ -0,0 +1,188 @@
+package voice
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// testWAV returns a WAV file holding data in the given format.
+func testWAV(format, channels, sampleRate, bits int, data []byte) []byte {
+	var b bytes.Buffer
+	b.WriteString("RIFF")
+	_ = binary.Write(&b, binary.LittleEndian, uint32(36+len(data)))
+	b.WriteString("WAVEfmt ")
+	blockAlign := channels * bits / 8
+	for _, v := range []any{
+		uint32(16), uint16(format), uint16(channels), uint32(sampleRate),
+		uint32(sampleRate * blockAlign), uint16(blockAlign), uint16(bits),
+	} {
+		_ = binary.Write(&b, binary.LittleEndian, v)
+	}
+	b.WriteString("data")
+	_ = binary.Write(&b, binary.LittleEndian, uint32(len(data)))
+	b.Write(data)
+	return b.Bytes()
+}
+
+// pcm16 encodes samples as 16-bit little-endian PCM.
+func pcm16(samples ...int16) []byte {
+	var b bytes.Buffer
+	_ = binary.Write(&b, binary.LittleEndian, samples)
+	return b.Bytes()
+}
+
+func TestDecodeWAV(t *testing.T) {
+	ulaw := []byte{0x01, 0x7f, 0xff, 0x80}
+	got, err := decodeWAV(testWAV(wavFormatMulaw, 1, 8000, 8, ulaw))
+	if err != nil || !bytes.Equal(got, ulaw) {
+		t.Errorf("mu-law: got %x, %v; want %x played as is", got, err, ulaw)
+	}
+
+	// 16 kHz stereo: each pair of frames is averaged into one sample
+	pcm := pcm16(1000, 3000, 1000, 3000, -2000, -2000, -2000, -2000)
+	got, err = decodeWAV(testWAV(wavFormatPCM, 2, 16000, 16, pcm))
+	want := []byte{linearToULaw(2000), linearToULaw(-2000)}
+	if err != nil || !bytes.Equal(got, want) {
+		t.Errorf("PCM: got %x, %v; want %x", got, err, want)
+	}
+}
+
+func TestDecodeWAV_Rejects(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"not WAV", []byte("ID3\x04 this is an mp3")},
+		{"8-bit PCM", testWAV(wavFormatPCM, 1, 8000, 8, make([]byte, 8))},
+		{"float", testWAV(3, 1, 8000, 32, make([]byte, 32))},
+		{"11 kHz", testWAV(wavFormatPCM, 1, 11025, 16, make([]byte, 16))},
+		{"surround", testWAV(wavFormatPCM, 6, 8000, 16, make([]byte, 24))},
+		{"empty", testWAV(wavFormatMulaw, 1, 8000, 8, nil)},
+		{"no data", testWAV(wavFormatMulaw, 1, 8000, 8, nil)[:36]},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, err := decodeWAV(tt.data); err == nil {
+				t.Errorf("decodeWAV() = %d bytes, want an error", len(got))
+			}
+		})
+	}
+}
+
+func TestLoadAudio_File(t *testing.T) {
+	m := newTestManager(t)
+	m.config.AudioDir = t.TempDir()
+	wav := testWAV(wavFormatMulaw, 1, 8000, 8, bytes.Repeat([]byte{0xff}, 8000))
+	if err := os.WriteFile(filepath.Join(m.config.AudioDir, "closed.wav"), wav, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, source := range []string{"closed.wav", filepath.Join(m.config.AudioDir, "closed.wav")} {
+		a, err := m.LoadAudio(context.Background(), source)
+		if err != nil {
+			t.Fatalf("LoadAudio(%q) error = %v", source, err)
+		}
+		if a.Duration().Seconds() != 1 {
+			t.Errorf("Duration() = %v, want 1s", a.Duration())
+		}
+	}
+}
+
+func TestLoadAudio_Rejects(t *testing.T) {
+	outside := filepath.Join(t.TempDir(), "secret.wav")
+	if err := os.WriteFile(outside, testWAV(wavFormatMulaw, 1, 8000, 8, []byte{0xff}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	long := testWAV(wavFormatMulaw, 1, 8000, 8, make([]byte, 8000*int(maxAudioDuration.Seconds()+1)))
+	if err := os.WriteFile(filepath.Join(dir, "long.wav"), long, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(outside, filepath.Join(dir, "link.wav")); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		dir    string
+		source string
+	}{
+		{"no audio dir", "", "long.wav"},
+		{"absolute outside", dir, outside},
+		{"relative outside", dir, filepath.Join("..", filepath.Base(filepath.Dir(outside)), "secret.wav")},
+		{"symlink outside", dir, "link.wav"},
+		{"missing", dir, "missing.wav"},
+		{"too long", dir, "long.wav"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestManager(t)
+			m.config.AudioDir = tt.dir
+			if _, err := m.LoadAudio(context.Background(), tt.source); !errors.Is(err, ErrInvalidAudio) {
+				t.Errorf("LoadAudio(%q) error = %v, want ErrInvalidAudio", tt.source, err)
+			}
+		})
+	}
+}
+
+func TestLoadAudio_URL(t *testing.T) {
+	wav := testWAV(wavFormatPCM, 1, 8000, 16, pcm16(0, 100, -100, 0))
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/closed.wav" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write(wav)
+	}))
+	defer srv.Close()
+	m := newTestManager(t)
+
+	a, err := m.LoadAudio(context.Background(), srv.URL+"/closed.wav")
+	if err != nil {
+		t.Fatalf("LoadAudio() error = %v", err)
+	}
+	if len(a.ulaw) != 4 {
+		t.Errorf("loaded %d samples, want 4", len(a.ulaw))
+	}
+	if _, err := m.LoadAudio(context.Background(), srv.URL+"/missing.wav"); !errors.Is(err, ErrInvalidAudio) {
+		t.Errorf("LoadAudio(missing) error = %v, want ErrInvalidAudio", err)
+	}
+}
+
+func TestLoadAudio_RejectsCompressedCodec(t *testing.T) {
+	m := newTestManager(t)
+	m.codec = codecOpus
+
+	if _, err := m.LoadAudio(context.Background(), "https://example.com/closed.wav"); !errors.Is(err, ErrInvalidAudio) {
+		t.Errorf("LoadAudio() error = %v, want ErrInvalidAudio", err)
+	}
+}
+
+func TestSpeak_WithAudio(t *testing.T) {
+	m := newTestManager(t)
+	tts := &fakeTTS{}
+	m.ttsProvider = tts
+	conn := &fakeConn{}
+	state := &CallState{ID: "call-1", Call: &fakeCall{transport: conn}}
+	audio := &Audio{ulaw: bytes.Repeat([]byte{0x7f}, 2000)}
+
+	if err := m.speak(context.Background(), state, "We are closed today.", WithAudio(audio)); err != nil {
+		t.Fatalf("speak() error = %v", err)
+	}
+	if !bytes.Equal(conn.audio.Bytes(), audio.ulaw) {
+		t.Errorf("played %d bytes, want the %d bytes of audio", conn.audio.Len(), len(audio.ulaw))
+	}
+	if len(tts.texts) != 0 {
+		t.Errorf("synthesized %q, want no TTS", tts.texts)
+	}
+	if len(state.Conversation) != 1 || state.Conversation[0].Content != "We are closed today." {
+		t.Errorf("conversation = %+v, want the message recorded", state.Conversation)
+	}
+}
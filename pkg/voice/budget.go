@@ -0,0 +1,227 @@
+package voice
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"slices"
+	"sync"
+	"time"
+)
+
+// budgetWindow is the rolling window for the daily call budget.
+const budgetWindow = 24 * time.Hour
+
+// CallUsage records a placed call for the daily call budget. CostUSD is
+// filled in when the call ends.
+type CallUsage struct {
+	CallID  string    `json:"call_id,omitempty"`
+	At      time.Time `json:"at"`
+	CostUSD float64   `json:"cost_usd,omitempty"`
+}
+
+// callBudget tracks calls placed in the last budgetWindow.
+type callBudget struct {
+	mu      sync.Mutex
+	entries []*CallUsage
+}
+
+// reserveCall counts a call about to be dialed against the daily budget,
+// or returns ErrBudgetExceeded if the budget is used up. Calls still in
+// progress count toward the call limit but not the cost limit. The caller
+// must pass the result to assignCall or releaseCall.
+func (m *Manager) reserveCall(now time.Time) (*CallUsage, error) {
+	maxCalls, maxCost := m.config.MaxCallsPerDay, m.config.MaxDailyCostUSD
+	if maxCalls <= 0 && maxCost <= 0 {
+		return nil, nil
+	}
+
+	b := &m.budget
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.entries = slices.DeleteFunc(b.entries, func(u *CallUsage) bool {
+		return now.Sub(u.At) >= budgetWindow
+	})
+
+	if maxCalls > 0 && len(b.entries) >= maxCalls {
+		return nil, fmt.Errorf("%w: %d calls in the last 24 hours (limit %d)", ErrBudgetExceeded, len(b.entries), maxCalls)
+	}
+	if maxCost > 0 {
+		var spent float64
+		for _, u := range b.entries {
+			spent += u.CostUSD
+		}
+		if spent >= maxCost {
+			return nil, fmt.Errorf("%w: $%.2f spent in the last 24 hours (limit $%.2f)", ErrBudgetExceeded, spent, maxCost)
+		}
+	}
+
+	u := &CallUsage{At: now}
+	b.entries = append(b.entries, u)
+	m.saveUsage()
+	return u, nil
+}
+
+// assignCall links a reservation to the call that was placed for it.
+func (m *Manager) assignCall(u *CallUsage, callID string) {
+	if u == nil {
+		return
+	}
+
+	m.budget.mu.Lock()
+	defer m.budget.mu.Unlock()
+	u.CallID = callID
+	m.saveUsage()
+}
+
+// releaseCall returns a reservation whose call could not be dialed.
+func (m *Manager) releaseCall(u *CallUsage) {
+	if u == nil {
+		return
+	}
+
+	b := &m.budget
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.entries = slices.DeleteFunc(b.entries, func(e *CallUsage) bool { return e == u })
+	m.saveUsage()
+}
+
+// recordCallCost sets the estimated cost of an ended call. Calls that were
+// never answered cost nothing.
+func (m *Manager) recordCallCost(state *CallState, endedAt time.Time) {
+	state.mu.RLock()
+	answeredAt := state.metrics.answeredAt
+	state.mu.RUnlock()
+	if answeredAt.IsZero() {
+		return
+	}
+
+	b := &m.budget
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, u := range b.entries {
+		if u.CallID == state.ID {
+			u.CostUSD = estimatedCost(endedAt.Sub(answeredAt), m.config.CallCostPerMinuteUSD)
+			m.saveUsage()
+			return
+		}
+	}
+}
+
+// Budget is the room left in the daily call budget. A limit of 0 is
+// unlimited, and the values derived from it are then zero.
+type Budget struct {
+	MaxCalls       int
+	CallsRemaining int
+
+	MaxCostUSD       float64
+	CostRemainingUSD float64
+	// TimeRemaining is the call time the remaining cost pays for.
+	TimeRemaining time.Duration
+
+	// For the call the budget was requested for: its estimated cost so
+	// far, which is included in CostRemainingUSD, and how much longer it
+	// can last before the cost budget is used up. The budget is only
+	// checked when placing calls, so the call is not cut off then.
+	CallCostUSD       float64
+	CallTimeRemaining time.Duration
+}
+
+// Budget returns the room left in the daily call budget, and for the given
+// call if callID is set, so the agent can keep calls short when little is
+// left.
+func (m *Manager) Budget(ctx context.Context, callID string) (Budget, error) {
+	var answeredAt time.Time
+	if callID != "" {
+		state := m.tenantCall(ctx, callID)
+		if state == nil {
+			return Budget{}, m.callNotFound(callID)
+		}
+		state.mu.RLock()
+		answeredAt = state.metrics.answeredAt
+		state.mu.RUnlock()
+	}
+
+	return m.budgetAt(time.Now(), callID, answeredAt), nil
+}
+
+// budgetAt computes the budget at now for the call answered at answeredAt
+// (zero if it was not answered or no call was given).
+func (m *Manager) budgetAt(now time.Time, callID string, answeredAt time.Time) Budget {
+	out := Budget{MaxCalls: m.config.MaxCallsPerDay, MaxCostUSD: m.config.MaxDailyCostUSD}
+
+	b := &m.budget
+	b.mu.Lock()
+	calls := 0
+	var spent float64
+	for _, u := range b.entries {
+		if now.Sub(u.At) >= budgetWindow {
+			continue
+		}
+		calls++
+		if u.CallID != callID {
+			spent += u.CostUSD
+		}
+	}
+	b.mu.Unlock()
+
+	if out.MaxCalls > 0 {
+		out.CallsRemaining = max(out.MaxCalls-calls, 0)
+	}
+
+	var elapsed time.Duration
+	if !answeredAt.IsZero() {
+		elapsed = now.Sub(answeredAt)
+		out.CallCostUSD = estimatedCost(elapsed, m.config.CallCostPerMinuteUSD)
+	}
+	if out.MaxCostUSD > 0 {
+		available := max(out.MaxCostUSD-spent, 0)
+		out.CostRemainingUSD = max(available-out.CallCostUSD, 0)
+		out.TimeRemaining = costTime(out.CostRemainingUSD, m.config.CallCostPerMinuteUSD)
+		if callID != "" {
+			// Calls are billed per started minute
+			out.CallTimeRemaining = max(costTime(available, m.config.CallCostPerMinuteUSD)-elapsed, 0)
+		}
+	}
+	return out
+}
+
+// costTime returns the call time, in whole billed minutes at perMinute
+// USD, that usd pays for.
+func costTime(usd, perMinute float64) time.Duration {
+	return time.Duration(math.Floor(usd/perMinute+1e-9)) * time.Minute
+}
+
+// loadUsage restores the budget saved by a previous run.
+func (m *Manager) loadUsage(usage []CallUsage) {
+	b := &m.budget
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.entries = b.entries[:0]
+	for _, u := range usage {
+		b.entries = append(b.entries, &u)
+	}
+}
+
+// saveUsage persists the budget, if a store is set. Failures are logged;
+// the in-memory budget still applies. The caller must hold m.budget.mu.
+func (m *Manager) saveUsage() {
+	m.schedulesMu.Lock()
+	store := m.store
+	m.schedulesMu.Unlock()
+	if store == nil {
+		return
+	}
+
+	usage := make([]CallUsage, len(m.budget.entries))
+	for i, u := range m.budget.entries {
+		usage[i] = *u
+	}
+	if err := store.SaveUsage(usage); err != nil {
+		slog.Warn("failed to save call budget", "error", err)
+	}
+}
@@ -0,0 +1,138 @@
+package voice
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestReserveCall_MaxCalls(t *testing.T) {
+	m := newTestManager(t)
+	m.config.MaxCallsPerDay = 2
+	now := time.Now()
+
+	for i := range 2 {
+		if _, err := m.reserveCall(now); err != nil {
+			t.Fatalf("reserveCall() #%d error = %v", i+1, err)
+		}
+	}
+	if _, err := m.reserveCall(now); !errors.Is(err, ErrBudgetExceeded) {
+		t.Fatalf("reserveCall() over limit error = %v, want ErrBudgetExceeded", err)
+	}
+
+	// The window is rolling, so the budget frees up 24 hours later
+	if _, err := m.reserveCall(now.Add(budgetWindow)); err != nil {
+		t.Errorf("reserveCall() after window error = %v", err)
+	}
+}
+
+func TestReserveCall_ReleaseOnDialFailure(t *testing.T) {
+	m := newTestManager(t)
+	m.config.MaxCallsPerDay = 1
+	now := time.Now()
+
+	u, err := m.reserveCall(now)
+	if err != nil {
+		t.Fatalf("reserveCall() error = %v", err)
+	}
+	m.releaseCall(u)
+
+	if _, err := m.reserveCall(now); err != nil {
+		t.Errorf("reserveCall() after release error = %v", err)
+	}
+}
+
+func TestReserveCall_MaxCost(t *testing.T) {
+	m := newTestManager(t)
+	m.config.MaxDailyCostUSD = 0.05
+	now := time.Now()
+
+	// A 90-second call costs two started minutes
+	u, err := m.reserveCall(now)
+	if err != nil {
+		t.Fatalf("reserveCall() error = %v", err)
+	}
+	state := &CallState{ID: "call-1"}
+	state.markAnswered(now)
+	m.assignCall(u, state.ID)
+	m.recordCallCost(state, now.Add(90*time.Second))
+
+	if _, err := m.reserveCall(now); !errors.Is(err, ErrBudgetExceeded) {
+		t.Fatalf("reserveCall() over cost error = %v, want ErrBudgetExceeded", err)
+	}
+}
+
+func TestReserveCall_Unlimited(t *testing.T) {
+	m := newTestManager(t)
+	for range 100 {
+		if _, err := m.reserveCall(time.Now()); err != nil {
+			t.Fatalf("reserveCall() error = %v", err)
+		}
+	}
+}
+
+func TestReserveCall_SurvivesRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "calls.json")
+
+	m := newTestManager(t)
+	m.config.MaxCallsPerDay = 1
+	if err := m.SetStore(NewFileCallStore(path)); err != nil {
+		t.Fatalf("SetStore() error = %v", err)
+	}
+	u, err := m.reserveCall(time.Now())
+	if err != nil {
+		t.Fatalf("reserveCall() error = %v", err)
+	}
+	m.assignCall(u, "call-1")
+
+	restarted := newTestManager(t)
+	restarted.config.MaxCallsPerDay = 1
+	if err := restarted.SetStore(NewFileCallStore(path)); err != nil {
+		t.Fatalf("SetStore() error = %v", err)
+	}
+	if _, err := restarted.reserveCall(time.Now()); !errors.Is(err, ErrBudgetExceeded) {
+		t.Errorf("reserveCall() after restart error = %v, want ErrBudgetExceeded", err)
+	}
+}
+
+func TestBudgetAt(t *testing.T) {
+	m := newTestManager(t)
+	m.config.MaxCallsPerDay = 5
+	m.config.MaxDailyCostUSD = 0.30
+	now := time.Now()
+
+	// An ended 90-second call cost $0.06
+	ended, _ := m.reserveCall(now.Add(-time.Hour))
+	endedState := &CallState{ID: "call-1"}
+	endedState.markAnswered(now.Add(-time.Hour))
+	m.assignCall(ended, endedState.ID)
+	m.recordCallCost(endedState, now.Add(-time.Hour+90*time.Second))
+
+	// The current call was answered 70 seconds ago: two started minutes
+	current, _ := m.reserveCall(now.Add(-80 * time.Second))
+	m.assignCall(current, "call-2")
+
+	b := m.budgetAt(now, "call-2", now.Add(-70*time.Second))
+	if b.CallsRemaining != 3 {
+		t.Errorf("CallsRemaining = %d, want 3", b.CallsRemaining)
+	}
+	if b.CallCostUSD != 0.06 {
+		t.Errorf("CallCostUSD = %g, want 0.06", b.CallCostUSD)
+	}
+	if diff := b.CostRemainingUSD - 0.18; diff > 1e-9 || diff < -1e-9 {
+		t.Errorf("CostRemainingUSD = %g, want 0.18", b.CostRemainingUSD)
+	}
+	if b.TimeRemaining != 6*time.Minute {
+		t.Errorf("TimeRemaining = %v, want 6m", b.TimeRemaining)
+	}
+	// $0.24 pays for 8 minutes of this call, 70 seconds of which are used
+	if b.CallTimeRemaining != 8*time.Minute-70*time.Second {
+		t.Errorf("CallTimeRemaining = %v, want 6m50s", b.CallTimeRemaining)
+	}
+
+	m.config.MaxCallsPerDay, m.config.MaxDailyCostUSD = 0, 0
+	if b := m.budgetAt(now, "", time.Time{}); b != (Budget{}) {
+		t.Errorf("unlimited budgetAt() = %+v, want zero", b)
+	}
+}
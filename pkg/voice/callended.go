@@ -0,0 +1,123 @@
+package voice
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"time"
+)
+
+const (
+	// callEndedTimeout bounds the call-ended webhook post.
+	callEndedTimeout = 10 * time.Second
+
+	// SignatureHeader carries the hex HMAC-SHA256 of the call-ended payload,
+	// prefixed with "sha256=", when a webhook secret is configured.
+	SignatureHeader = "X-Agentcomms-Signature"
+)
+
+// CallEndedEvent is posted to the call-ended webhook after EndCall.
+type CallEndedEvent struct {
+	CallID           string            `json:"call_id"`
+	DurationSeconds  float64           `json:"duration_seconds"`
+	Turns            int               `json:"turns"`
+	EstimatedCostUSD float64           `json:"estimated_cost_usd"`
+	Transcript       []TranscriptEntry `json:"transcript"`
+	EndedAt          time.Time         `json:"ended_at"`
+	Metadata         map[string]string `json:"metadata,omitempty"`
+}
+
+// newCallEndedEvent summarizes a call for the call-ended webhook, with its
+// cost estimated at perMinute USD and its turns' roles renamed by roles.
+func newCallEndedEvent(state *CallState, endedAt time.Time, perMinute float64, roles transcriptRoles) CallEndedEvent {
+	state.mu.RLock()
+	defer state.mu.RUnlock()
+
+	duration := endedAt.Sub(state.StartTime)
+	transcript := make([]TranscriptEntry, len(state.Conversation))
+	for i, turn := range state.Conversation {
+		transcript[i] = TranscriptEntry{
+			CallID:    state.ID,
+			Role:      roles.name(turn.Role),
+			Content:   turn.Content,
+			Timestamp: turn.Timestamp,
+			Sentiment: turn.Sentiment,
+		}
+	}
+
+	return CallEndedEvent{
+		CallID:           state.ID,
+		DurationSeconds:  duration.Seconds(),
+		Turns:            len(transcript),
+		EstimatedCostUSD: estimatedCost(duration, perMinute),
+		Transcript:       transcript,
+		EndedAt:          endedAt,
+		Metadata:         state.metadata,
+	}
+}
+
+// notifyCallEnded posts the call summary to the call-ended webhook, if
+// configured. The post runs in the background so it never delays hangup;
+// Close waits for it.
+func (m *Manager) notifyCallEnded(state *CallState) {
+	if m.config.CallEndedWebhook == "" {
+		return
+	}
+
+	event := newCallEndedEvent(state, time.Now(), m.config.CallCostPerMinuteUSD, m.transcriptRoles())
+	m.hooks.Add(1)
+	go func() {
+		defer m.hooks.Done()
+		if err := postCallEnded(m.config.CallEndedWebhook, m.config.CallEndedWebhookSecret, event); err != nil {
+			slog.Warn("call-ended webhook failed", "call_id", event.CallID, "metadata", event.Metadata, "error", err)
+		}
+	}()
+}
+
+// postCallEnded posts event to url as JSON, signed with secret if set.
+func postCallEnded(url, secret string, event CallEndedEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if secret != "" {
+		req.Header.Set(SignatureHeader, "sha256="+signPayload(secret, body))
+	}
+
+	client := &http.Client{Timeout: callEndedTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("call-ended webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+// estimatedCost is the rough telephony cost of a call lasting d at
+// perMinute USD. Calls are billed per started minute.
+func estimatedCost(d time.Duration, perMinute float64) float64 {
+	return math.Ceil(d.Minutes()) * perMinute
+}
+
+// signPayload returns the hex HMAC-SHA256 of body keyed with secret.
+func signPayload(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,74 @@
+package voice
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestNewCallEndedEvent(t *testing.T) {
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	state := &CallState{ID: "call-1", StartTime: start}
+	state.AddTurn("assistant", "Build finished.")
+	state.AddTurn("user", "Great, thanks.")
+
+	event := newCallEndedEvent(state, start.Add(90*time.Second), 0.05, transcriptRoles{})
+
+	if event.CallID != "call-1" || event.Turns != 2 || len(event.Transcript) != 2 {
+		t.Errorf("event = %+v, want call-1 with 2 turns", event)
+	}
+	if event.DurationSeconds != 90 {
+		t.Errorf("DurationSeconds = %g, want 90", event.DurationSeconds)
+	}
+	// 90s is billed as two started minutes
+	if want := 2 * 0.05; event.EstimatedCostUSD != want {
+		t.Errorf("EstimatedCostUSD = %g, want %g", event.EstimatedCostUSD, want)
+	}
+	if event.Transcript[1].Role != "user" || event.Transcript[1].Content != "Great, thanks." {
+		t.Errorf("Transcript[1] = %+v", event.Transcript[1])
+	}
+}
+
+func TestPostCallEnded_Signed(t *testing.T) {
+	var body []byte
+	var signature string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		signature = r.Header.Get(SignatureHeader)
+	}))
+	defer srv.Close()
+
+	event := CallEndedEvent{CallID: "call-1", Turns: 3}
+	if err := postCallEnded(srv.URL, "s3cret", event); err != nil {
+		t.Fatalf("postCallEnded() error = %v", err)
+	}
+
+	if want := "sha256=" + signPayload("s3cret", body); signature != want {
+		t.Errorf("signature = %q, want %q", signature, want)
+	}
+
+	var got CallEndedEvent
+	if err := json.Unmarshal(body, &got); err != nil {
+		t.Fatalf("payload is not JSON: %v", err)
+	}
+	if got.CallID != "call-1" || got.Turns != 3 {
+		t.Errorf("payload = %+v", got)
+	}
+}
+
+func TestPostCallEnded_Unsigned(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(SignatureHeader) != "" {
+			t.Error("payload signed without a secret")
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	if err := postCallEnded(srv.URL, "", CallEndedEvent{CallID: "call-1"}); err == nil {
+		t.Error("postCallEnded() expected error for 500 response")
+	}
+}
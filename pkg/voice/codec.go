@@ -0,0 +1,67 @@
+package voice
+
+import (
+	"slices"
+	"time"
+
+	"github.com/plexusone/agentcomms/pkg/config"
+)
+
+// codec is an audio encoding for call media, with the names TTS and STT
+// providers use for it.
+type codec struct {
+	name        string // as in config.PreferredCodec
+	ttsFormat   string // omnivoice.SynthesisConfig.OutputFormat
+	sttEncoding string // omnivoice.TranscriptionConfig.Encoding
+	sampleRate  int
+
+	// bytesPerSecond is the audio rate used to estimate playback time.
+	// For variable bitrate codecs it is the typical rate.
+	bytesPerSecond int
+
+	// sampled is set for codecs with one byte per sample, whose audio can
+	// be scaled and padded with silence byte by byte. Compressed codecs
+	// skip volume scaling and keep-alive silence.
+	sampled bool
+}
+
+var (
+	codecMulaw = codec{
+		name:           config.CodecMulaw,
+		ttsFormat:      "ulaw",
+		sttEncoding:    "mulaw",
+		sampleRate:     8000,
+		bytesPerSecond: ulawBytesPerSecond,
+		sampled:        true,
+	}
+	codecOpus = codec{
+		name:           config.CodecOpus,
+		ttsFormat:      "opus",
+		sttEncoding:    "opus",
+		sampleRate:     48000,
+		bytesPerSecond: 4000, // ~32 kbps for speech
+	}
+)
+
+// transportCodecs lists the codecs each phone provider's media stream can
+// carry. Twilio Media Streams only carry 8 kHz mu-law; Telnyx streams can
+// be opened with Opus.
+var transportCodecs = map[string][]codec{
+	"twilio": {codecMulaw},
+	"telnyx": {codecMulaw, codecOpus},
+}
+
+// negotiateCodec returns the preferred codec if the phone provider's media
+// stream supports it, and mu-law otherwise.
+func negotiateCodec(provider, preferred string) codec {
+	i := slices.IndexFunc(transportCodecs[provider], func(c codec) bool { return c.name == preferred })
+	if i < 0 {
+		return codecMulaw
+	}
+	return transportCodecs[provider][i]
+}
+
+// playbackDuration returns how long n bytes of audio take to play.
+func (c codec) playbackDuration(n int) time.Duration {
+	return time.Duration(n) * time.Second / time.Duration(c.bytesPerSecond)
+}
@@ -0,0 +1,47 @@
+package voice
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNegotiateCodec(t *testing.T) {
+	tests := []struct {
+		provider  string
+		preferred string
+		want      codec
+	}{
+		{"twilio", "mulaw", codecMulaw},
+		{"twilio", "opus", codecMulaw},
+		{"telnyx", "opus", codecOpus},
+		{"telnyx", "mulaw", codecMulaw},
+		{"unknown", "opus", codecMulaw},
+		{"telnyx", "", codecMulaw},
+	}
+
+	for _, tt := range tests {
+		if got := negotiateCodec(tt.provider, tt.preferred); got != tt.want {
+			t.Errorf("negotiateCodec(%q, %q) = %s, want %s", tt.provider, tt.preferred, got.name, tt.want.name)
+		}
+	}
+}
+
+func TestCodecPlaybackDuration(t *testing.T) {
+	if got := codecMulaw.playbackDuration(8000); got != time.Second {
+		t.Errorf("mulaw playbackDuration(8000) = %v, want 1s", got)
+	}
+	if got := codecOpus.playbackDuration(4000); got != time.Second {
+		t.Errorf("opus playbackDuration(4000) = %v, want 1s", got)
+	}
+}
+
+func TestSynthesisConfig_FollowsCodec(t *testing.T) {
+	m := newTestManager(t)
+	m.codec = codecOpus
+
+	synth := m.synthesisConfig(&CallState{}, "")
+	stt := m.transcriptionConfig(nil)
+	if synth.OutputFormat != "opus" || synth.SampleRate != 48000 || stt.Encoding != "opus" || stt.SampleRate != 48000 {
+		t.Errorf("synthesis %s/%d, transcription %s/%d, want opus at 48 kHz", synth.OutputFormat, synth.SampleRate, stt.Encoding, stt.SampleRate)
+	}
+}
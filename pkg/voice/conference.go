@@ -0,0 +1,302 @@
+package voice
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/plexusone/omnivoice"
+
+	"github.com/plexusone/agentcomms/pkg/config"
+)
+
+// maxConferenceParticipants limits how many people one conference dials.
+const maxConferenceParticipants = 5
+
+// ConferenceLeg is the part a call leg plays in a conference. The voice
+// webhook uses it to choose the leg's TwiML.
+type ConferenceLeg int
+
+// Conference legs.
+const (
+	// NotConference is a leg that is not part of a conference.
+	NotConference ConferenceLeg = iota
+
+	// ConferenceAssistant is the assistant's leg, dialed to the configured
+	// Twilio number. It connects to the media stream like any other call.
+	ConferenceAssistant
+
+	// ConferenceBridge is the far end of the assistant's leg, answered on
+	// the Twilio number. It joins the conference, so the assistant hears
+	// and is heard by everyone.
+	ConferenceBridge
+
+	// ConferenceParticipant is a dialed participant; it joins the conference.
+	ConferenceParticipant
+)
+
+// Participant is a person dialed into a conference.
+type Participant struct {
+	Number  string
+	CallSID string // provider call ID; empty if dialing failed
+	Status  omnivoice.CallStatus
+}
+
+// ConferenceState tracks a multi-party call. The assistant takes part
+// through Call, an ordinary call: speak with ContinueCall or SpeakToUser
+// and hang up with EndCall, which ends the conference for everyone.
+type ConferenceState struct {
+	Name      string // Twilio conference name
+	Call      *CallState
+	StartTime time.Time
+
+	mu           sync.RWMutex
+	participants []Participant
+	bridgeSID    string // provider call ID of the bridge leg, once claimed
+}
+
+// Participants returns a snapshot of the conference's participants.
+func (c *ConferenceState) Participants() []Participant {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return slices.Clone(c.participants)
+}
+
+// StartConference dials the assistant and the given numbers into a new
+// conference and speaks message to everyone once the assistant is
+// connected. Numbers must be the user's or listed in ConferenceNumbers.
+// Participants that cannot be dialed are reported with StatusFailed; the
+// conference fails only if nobody could be dialed.
+func (m *Manager) StartConference(ctx context.Context, numbers []string, message string, opts ...SpeakOption) (*ConferenceState, error) {
+	cs := m.currentCallSystem()
+	if cs == nil || m.InitError() != nil {
+		return nil, m.notInitialized()
+	}
+	if m.DoNotDisturb() {
+		return nil, ErrDoNotDisturb
+	}
+	if m.quietHours.Contains(time.Now()) {
+		return nil, ErrQuietHours
+	}
+	if err := m.checkMessage(message); err != nil {
+		return nil, err
+	}
+	if len(numbers) == 0 || len(numbers) > maxConferenceParticipants {
+		return nil, fmt.Errorf("%w: need 1 to %d participants, got %d", ErrInvalidConference, maxConferenceParticipants, len(numbers))
+	}
+	for _, n := range numbers {
+		if !m.conferenceNumberAllowed(m.configFor(ctx), n) {
+			return nil, fmt.Errorf("%w: %s", ErrNumberNotAllowed, n)
+		}
+	}
+
+	// The conference counts as one call against the daily budget
+	usage, err := m.reserveCall(time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	var callOpts []omnivoice.CallOption
+	if m.config.EnableRecording {
+		callOpts = append(callOpts, omnivoice.WithRecording())
+	}
+
+	// Connect the assistant first so it is in the conference before anyone answers
+	conf, err := m.connectConferenceAssistant(ctx, cs, usage, callOpts)
+	if err != nil {
+		return nil, err
+	}
+	state := conf.Call
+
+	if m.config.KeepAlive {
+		m.startKeepAlive(state)
+	}
+
+	dialed := 0
+	for _, n := range numbers {
+		p := Participant{Number: n, Status: omnivoice.StatusRinging}
+		c, err := cs.MakeCall(ctx, n, append(slices.Clone(callOpts), omnivoice.WithAnswerURL(m.conferenceAnswerURL(conf.Name)))...)
+		if err == nil && c == nil {
+			err = errNoCall
+		}
+		if err != nil {
+			slog.Warn("failed to dial conference participant", "conference", conf.Name, "number", redactNumber(n), "error", err)
+			p.Status = omnivoice.StatusFailed
+		} else {
+			p.CallSID = c.ID()
+			dialed++
+		}
+
+		conf.mu.Lock()
+		conf.participants = append(conf.participants, p)
+		conf.mu.Unlock()
+	}
+	if dialed == 0 {
+		_ = state.Call.Hangup(ctx)
+		m.removeCall(state.ID)
+		return nil, fmt.Errorf("%w: no participants could be dialed", ErrDialFailed)
+	}
+
+	if message != "" {
+		if err := m.speak(ctx, state, message, opts...); err != nil {
+			return conf, fmt.Errorf("%w: %w", ErrSpeechFailed, err)
+		}
+	}
+
+	return conf, nil
+}
+
+// connectConferenceAssistant dials the assistant's leg of a new conference
+// to the configured number and waits for it to be answered. The leg rings
+// our own number, and the inbound leg that answers it is the conference's
+// bridge. Starts are serialized until then, so that inbound leg can only
+// belong to this conference. The leg is dialed through cs, and usage is the
+// conference's budget reservation.
+func (m *Manager) connectConferenceAssistant(ctx context.Context, cs omnivoice.CallSystem, usage *CallUsage, callOpts []omnivoice.CallOption) (*ConferenceState, error) {
+	m.conferenceStartMu.Lock()
+	defer m.conferenceStartMu.Unlock()
+
+	dialedAt := time.Now()
+	call, err := cs.MakeCall(ctx, m.config.PhoneNumber, append(slices.Clone(callOpts), omnivoice.WithAnswerURL(m.answerURL()))...)
+	if err == nil && call == nil {
+		err = errNoCall
+	}
+	if err != nil {
+		m.releaseCall(usage)
+		return nil, fmt.Errorf("%w: %w", ErrDialFailed, err)
+	}
+	state := m.addCall(ctx, call, m.config.PhoneNumber, dialedAt)
+	m.assignCall(usage, state.ID)
+
+	conf := &ConferenceState{
+		Name:      "agentcomms-" + state.ID,
+		Call:      state,
+		StartTime: time.Now(),
+	}
+	m.conferencesMu.Lock()
+	m.conferences[state.ID] = conf
+	m.pendingBridge = conf
+	m.conferencesMu.Unlock()
+
+	answered := m.waitForAnswer(ctx, call, state.statusCh, 30*time.Second)
+
+	m.conferencesMu.Lock()
+	if m.pendingBridge == conf {
+		m.pendingBridge = nil
+	}
+	m.conferencesMu.Unlock()
+
+	if !answered {
+		_ = call.Hangup(ctx)
+		m.removeCall(state.ID)
+		return nil, fmt.Errorf("%w: the assistant's conference leg did not connect", ErrDialFailed)
+	}
+	state.markAnswered(time.Now())
+	return conf, nil
+}
+
+// ConferenceLeg reports whether a call leg belongs to a conference, and if
+// so, the conference name and the leg's part in it. from and direction are
+// the Twilio From and Direction webhook parameters, and conference is the
+// conference query parameter of a participant's answer URL.
+func (m *Manager) ConferenceLeg(providerCallID, from, direction, conference string) (string, ConferenceLeg) {
+	if name, leg := m.knownConferenceLeg(providerCallID, direction, conference); leg != NotConference {
+		return name, leg
+	}
+
+	// The assistant's leg rings our own number; the inbound leg that
+	// answers it is claimed as the bridge of the conference being started
+	if providerCallID == "" || direction != "inbound" || from != m.config.PhoneNumber {
+		return "", NotConference
+	}
+	m.conferencesMu.Lock()
+	defer m.conferencesMu.Unlock()
+
+	conf := m.pendingBridge
+	if conf == nil {
+		return "", NotConference
+	}
+	m.pendingBridge = nil
+	conf.mu.Lock()
+	conf.bridgeSID = providerCallID
+	conf.mu.Unlock()
+	return conf.Name, ConferenceBridge
+}
+
+// knownConferenceLeg matches a call leg to a conference by its provider call
+// ID, or a participant leg by the conference it was dialed into.
+func (m *Manager) knownConferenceLeg(providerCallID, direction, conference string) (string, ConferenceLeg) {
+	m.conferencesMu.RLock()
+	defer m.conferencesMu.RUnlock()
+
+	for _, conf := range m.conferences {
+		if providerCallID != "" && conf.Call.Call.ID() == providerCallID {
+			return conf.Name, ConferenceAssistant
+		}
+
+		conf.mu.RLock()
+		bridgeSID := conf.bridgeSID
+		isParticipant := false
+		for _, p := range conf.participants {
+			if p.CallSID != "" && p.CallSID == providerCallID {
+				isParticipant = true
+				break
+			}
+		}
+		conf.mu.RUnlock()
+
+		switch {
+		case providerCallID != "" && providerCallID == bridgeSID:
+			return conf.Name, ConferenceBridge
+		case isParticipant:
+			return conf.Name, ConferenceParticipant
+		case conference == conf.Name && strings.HasPrefix(direction, "outbound"):
+			// The answer URL can arrive before the participant's call SID
+			// is recorded
+			return conf.Name, ConferenceParticipant
+		}
+	}
+	return "", NotConference
+}
+
+// notifyParticipantStatus records a status change for a conference participant.
+func (m *Manager) notifyParticipantStatus(providerCallID string, status omnivoice.CallStatus) {
+	m.conferencesMu.RLock()
+	defer m.conferencesMu.RUnlock()
+
+	for _, conf := range m.conferences {
+		conf.mu.Lock()
+		for i := range conf.participants {
+			if conf.participants[i].CallSID == providerCallID {
+				conf.participants[i].Status = status
+				conf.mu.Unlock()
+				return
+			}
+		}
+		conf.mu.Unlock()
+	}
+}
+
+// removeConference forgets the conference whose assistant call is callID, if any.
+func (m *Manager) removeConference(callID string) {
+	m.conferencesMu.Lock()
+	defer m.conferencesMu.Unlock()
+	if conf := m.conferences[callID]; conf != nil && conf == m.pendingBridge {
+		m.pendingBridge = nil
+	}
+	delete(m.conferences, callID)
+}
+
+// conferenceNumberAllowed reports whether a conference placed with cfg may
+// dial number. Dialing is limited to known numbers so a confused agent
+// cannot run up charges calling arbitrary destinations.
+func (m *Manager) conferenceNumberAllowed(cfg *config.Config, number string) bool {
+	if number == "" {
+		return false
+	}
+	return number == cfg.UserPhoneNumber || slices.Contains(cfg.ConferenceNumbers, number)
+}
@@ -0,0 +1,105 @@
+package voice
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/plexusone/omnivoice"
+)
+
+func TestConferenceLeg(t *testing.T) {
+	m := newTestManager(t)
+	m.config.PhoneNumber = "+15550001111"
+	state := &CallState{ID: "call-1", Call: &fakeCall{id: "CA-assistant"}}
+	conf := &ConferenceState{
+		Name: "agentcomms-call-1",
+		Call: state,
+		participants: []Participant{
+			{Number: "+15552223333", CallSID: "CA-alice", Status: omnivoice.StatusRinging},
+			{Number: "+15554445555", Status: omnivoice.StatusFailed},
+		},
+	}
+	m.conferences[state.ID] = conf
+	m.pendingBridge = conf
+
+	// Cases run in order: the bridge is claimed once, then matched by SID
+	tests := []struct {
+		name               string
+		callSID, from, dir string
+		conference         string
+		wantName           string
+		wantLeg            ConferenceLeg
+	}{
+		{"assistant", "CA-assistant", m.config.PhoneNumber, "outbound-api", "", conf.Name, ConferenceAssistant},
+		{"participant", "CA-alice", m.config.PhoneNumber, "outbound-api", "", conf.Name, ConferenceParticipant},
+		{"participant by answer URL", "CA-bob", m.config.PhoneNumber, "outbound-api", conf.Name, conf.Name, ConferenceParticipant},
+		{"unknown conference", "CA-eve", m.config.PhoneNumber, "outbound-api", "agentcomms-nope", "", NotConference},
+		{"unrelated", "CA-user", "+15559998888", "inbound", "", "", NotConference},
+		{"bridge", "CA-bridge", m.config.PhoneNumber, "inbound", "", conf.Name, ConferenceBridge},
+		{"bridge again", "CA-bridge", m.config.PhoneNumber, "inbound", "", conf.Name, ConferenceBridge},
+		{"second inbound is not a bridge", "CA-other", m.config.PhoneNumber, "inbound", "", "", NotConference},
+		{"failed dial has no call SID", "", "", "outbound-api", "", "", NotConference},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name, leg := m.ConferenceLeg(tt.callSID, tt.from, tt.dir, tt.conference)
+			if name != tt.wantName || leg != tt.wantLeg {
+				t.Errorf("ConferenceLeg() = (%q, %v), want (%q, %v)", name, leg, tt.wantName, tt.wantLeg)
+			}
+		})
+	}
+}
+
+func TestConference_ParticipantStatus(t *testing.T) {
+	m := newTestManager(t)
+	state := &CallState{ID: "call-1", Call: &fakeCall{id: "CA-assistant"}}
+	conf := &ConferenceState{
+		Call:         state,
+		participants: []Participant{{Number: "+15552223333", CallSID: "CA-alice", Status: omnivoice.StatusRinging}},
+	}
+	m.conferences[state.ID] = conf
+
+	m.NotifyStatus("CA-alice", omnivoice.StatusAnswered)
+	if got := conf.Participants()[0].Status; got != omnivoice.StatusAnswered {
+		t.Errorf("participant status = %q, want %q", got, omnivoice.StatusAnswered)
+	}
+
+	// Ending the assistant's call ends the conference
+	m.calls[state.ID] = state
+	m.removeCall(state.ID)
+	if _, leg := m.ConferenceLeg("CA-alice", "", "outbound-api", ""); leg != NotConference {
+		t.Error("conference still active after its assistant call was removed")
+	}
+}
+
+func TestStartConference_Validation(t *testing.T) {
+	m := newTestManager(t)
+	m.callSystem = &fakeCallSystem{}
+	m.config.UserPhoneNumber = "+15551234567"
+	m.config.ConferenceNumbers = []string{"+15552223333"}
+
+	tests := []struct {
+		name    string
+		numbers []string
+		wantErr error
+	}{
+		{"no participants", nil, ErrInvalidConference},
+		{"too many", []string{"1", "2", "3", "4", "5", "6"}, ErrInvalidConference},
+		{"unlisted number", []string{"+15551234567", "+19005550000"}, ErrNumberNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := m.StartConference(context.Background(), tt.numbers, ""); !errors.Is(err, tt.wantErr) {
+				t.Errorf("StartConference() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// fakeCallSystem is a call system that must not be dialed.
+type fakeCallSystem struct {
+	omnivoice.CallSystem
+}
@@ -0,0 +1,86 @@
+package voice
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// confirmationWindow is how long a held call waits for ConfirmCall.
+const confirmationWindow = 10 * time.Minute
+
+// PendingCall is a call request held until it is confirmed (see
+// RequireConfirmation).
+type PendingCall struct {
+	Message        string
+	IdempotencyKey string
+	Async          bool
+	Metadata       map[string]string // see WithCallMetadata
+	Options        []SpeakOption
+
+	heldAt time.Time
+}
+
+// pendingCalls holds call requests awaiting confirmation, by token.
+type pendingCalls struct {
+	mu      sync.Mutex
+	entries map[string]PendingCall
+}
+
+// hold stores c under a new token and returns the token. Expired requests
+// are pruned.
+func (p *pendingCalls) hold(c PendingCall, now time.Time) string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.prune(now)
+	if p.entries == nil {
+		p.entries = make(map[string]PendingCall)
+	}
+	token := NewUUID()
+	c.heldAt = now
+	p.entries[token] = c
+	return token
+}
+
+// take removes and returns the request held under token.
+func (p *pendingCalls) take(token string, now time.Time) (PendingCall, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.prune(now)
+	c, ok := p.entries[token]
+	delete(p.entries, token)
+	return c, ok
+}
+
+// prune drops requests older than confirmationWindow. p.mu must be held.
+func (p *pendingCalls) prune(now time.Time) {
+	for token, c := range p.entries {
+		if now.Sub(c.heldAt) > confirmationWindow {
+			delete(p.entries, token)
+		}
+	}
+}
+
+// ConfirmationRequired reports whether calls must be confirmed before they
+// are placed, a safety net against agents or hooks calling by accident.
+func (m *Manager) ConfirmationRequired() bool {
+	return m.config.RequireConfirmation
+}
+
+// HoldCall stores a call request until ConfirmCall is called with the
+// returned token. Unconfirmed requests expire after 10 minutes.
+func (m *Manager) HoldCall(c PendingCall) string {
+	return m.pending.hold(c, time.Now())
+}
+
+// ConfirmCall removes and returns the call request held under token so the
+// caller can place it. Each token can be confirmed once.
+func (m *Manager) ConfirmCall(token string) (PendingCall, error) {
+	c, ok := m.pending.take(token, time.Now())
+	if !ok {
+		return PendingCall{}, fmt.Errorf("%w: %s", ErrConfirmationNotFound, token)
+	}
+	return c, nil
+}
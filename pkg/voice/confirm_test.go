@@ -0,0 +1,41 @@
+package voice
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestConfirmCall(t *testing.T) {
+	m := newTestManager(t)
+
+	token := m.HoldCall(PendingCall{Message: "Build is done", Async: true})
+	c, err := m.ConfirmCall(token)
+	if err != nil {
+		t.Fatalf("ConfirmCall() error = %v", err)
+	}
+	if c.Message != "Build is done" || !c.Async {
+		t.Errorf("ConfirmCall() = %+v, want the held call", c)
+	}
+
+	// Each token is confirmed once
+	if _, err := m.ConfirmCall(token); !errors.Is(err, ErrConfirmationNotFound) {
+		t.Errorf("second ConfirmCall() error = %v, want ErrConfirmationNotFound", err)
+	}
+	if _, err := m.ConfirmCall("unknown"); !errors.Is(err, ErrConfirmationNotFound) {
+		t.Errorf("ConfirmCall(unknown) error = %v, want ErrConfirmationNotFound", err)
+	}
+}
+
+func TestPendingCalls_Expire(t *testing.T) {
+	var p pendingCalls
+	now := time.Now()
+	token := p.hold(PendingCall{Message: "hi"}, now)
+
+	if _, ok := p.take(token, now.Add(confirmationWindow+time.Second)); ok {
+		t.Error("take() found a call held longer than the confirmation window")
+	}
+	if len(p.entries) != 0 {
+		t.Errorf("%d entries left, want expired ones pruned", len(p.entries))
+	}
+}
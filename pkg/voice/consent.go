@@ -0,0 +1,51 @@
+package voice
+
+import (
+	"context"
+	"html"
+	"log/slog"
+)
+
+// announceRecording speaks Config.RecordingConsent on a recorded call
+// before anything else is said. With RequireAccept the callee already heard
+// it ahead of the accept prompt (see recordingConsentTwiML), so the call is
+// only marked.
+func (m *Manager) announceRecording(ctx context.Context, state *CallState) error {
+	if !m.config.EnableRecording {
+		return nil
+	}
+	if !m.config.RequireAccept {
+		if err := m.speak(ctx, state, m.config.RecordingConsent, withVoice(m.config.RecordingConsentVoice)); err != nil {
+			return err
+		}
+	}
+	state.markRecordingConsent()
+	return nil
+}
+
+// recordingConsentTwiML returns a Say verb announcing the recording, for
+// TwiML that plays before the media stream connects, or "" when calls are
+// not recorded. Twilio speaks it in its own voice.
+func (m *Manager) recordingConsentTwiML() string {
+	if !m.config.EnableRecording {
+		return ""
+	}
+	return "\n    <Say>" + html.EscapeString(m.config.RecordingConsent) + "</Say>"
+}
+
+// markRecordingConsent records that the callee heard the recording
+// announcement.
+func (cs *CallState) markRecordingConsent() {
+	cs.mu.Lock()
+	cs.recordingConsent = true
+	cs.mu.Unlock()
+	slog.Info("recording consent played", "call_id", cs.ID)
+}
+
+// RecordingConsentPlayed reports whether the callee heard the recording
+// announcement.
+func (cs *CallState) RecordingConsentPlayed() bool {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+	return cs.recordingConsent
+}
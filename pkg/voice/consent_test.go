@@ -0,0 +1,141 @@
+package voice
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/plexusone/agentcomms/pkg/config"
+	"github.com/plexusone/omnivoice"
+)
+
+func TestAnnounceRecording(t *testing.T) {
+	tests := []struct {
+		name          string
+		recording     bool
+		requireAccept bool
+		wantSpoken    bool
+		wantConsent   bool
+	}{
+		{"recorded", true, false, true, true},
+		{"heard before the accept prompt", true, true, false, true},
+		{"not recorded", false, false, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestManager(t)
+			m.config.EnableRecording = tt.recording
+			m.config.RequireAccept = tt.requireAccept
+			m.config.RecordingConsent = config.DefaultRecordingConsent
+			m.config.RecordingConsentVoice = "consent-voice"
+			tts := &fakeTTS{}
+			m.ttsProvider = tts
+			state := &CallState{ID: "call-1", Call: &fakeCall{transport: &fakeConn{}}}
+
+			if err := m.announceRecording(context.Background(), state); err != nil {
+				t.Fatalf("announceRecording() error = %v", err)
+			}
+
+			spoken := len(tts.texts) > 0
+			if spoken != tt.wantSpoken {
+				t.Fatalf("spoken = %v, want %v", spoken, tt.wantSpoken)
+			}
+			if spoken {
+				if tts.texts[0] != config.DefaultRecordingConsent {
+					t.Errorf("spoke %q, want the consent announcement", tts.texts[0])
+				}
+				if tts.configs[0].VoiceID != "consent-voice" {
+					t.Errorf("VoiceID = %q, want the consent voice", tts.configs[0].VoiceID)
+				}
+			}
+			if got := state.RecordingConsentPlayed(); got != tt.wantConsent {
+				t.Errorf("RecordingConsentPlayed() = %v, want %v", got, tt.wantConsent)
+			}
+		})
+	}
+}
+
+func TestLeaveVoicemail_AnnouncesRecordingFirst(t *testing.T) {
+	m := newTestManager(t)
+	m.config.EnableRecording = true
+	m.config.RecordingConsent = "This call is recorded."
+	m.ttsProvider = &fakeTTS{streams: [][]omnivoice.StreamChunk{
+		{{Audio: []byte("consent "), IsFinal: true}},
+		{{Audio: []byte("msg"), IsFinal: true}},
+	}}
+	conn := &fakeConn{}
+	state := &CallState{ID: "call-1", Call: hangupCall{&fakeCall{id: "CA123", transport: conn}}}
+	m.calls[state.ID] = state
+
+	if err := m.leaveVoicemail(context.Background(), state, "The build is done."); !errors.Is(err, ErrVoicemail) {
+		t.Fatalf("leaveVoicemail() error = %v, want ErrVoicemail", err)
+	}
+	if got := conn.audio.String(); got != "consent msg" {
+		t.Errorf("audio = %q, want the announcement before the message", got)
+	}
+}
+
+func TestRecordCall_RecordingConsent(t *testing.T) {
+	store := NewFileCallStore(filepath.Join(t.TempDir(), "calls.json"))
+	m := newTestManager(t)
+	if err := m.SetStore(store); err != nil {
+		t.Fatalf("SetStore() error = %v", err)
+	}
+
+	start := time.Now()
+	state := &CallState{ID: "call-1", StartTime: start, to: "+15551234567"}
+	state.markAnswered(start)
+	state.markRecordingConsent()
+	m.recordCall(state, start.Add(time.Minute))
+
+	records, err := store.List(CallQuery{})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(records) != 1 || !records[0].RecordingConsent {
+		t.Errorf("List() = %+v, want the consent recorded", records)
+	}
+}
+
+func TestVoiceHandler_RecordingConsent(t *testing.T) {
+	m := newTestManager(t)
+	m.config.EnableRecording = true
+	m.config.RecordingConsent = "Calls are recorded & kept."
+
+	tests := []struct {
+		name          string
+		requireAccept bool
+		conference    bool
+		before        string // the announcement must come before this
+	}{
+		{"accept prompt", true, false, "<Gather"},
+		{"conference participant", false, true, "<Dial>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(m.VoiceHandler(func() string { return "https://example.com" }, tt.requireAccept))
+			defer srv.Close()
+			m.publicURL = srv.URL
+
+			target := srv.URL + VoicePath
+			if tt.conference {
+				state := &CallState{ID: "call-1", Call: &fakeCall{id: "CA-assistant"}}
+				m.conferences[state.ID] = &ConferenceState{Name: "agentcomms-call-1", Call: state}
+				target += "?conference=agentcomms-call-1"
+			}
+			twiml := postVoice(t, target, url.Values{"CallSid": {"CA123"}, "Direction": {"outbound-api"}})
+
+			say := strings.Index(twiml, "<Say>Calls are recorded &amp; kept.</Say>")
+			if say < 0 || say > strings.Index(twiml, tt.before) {
+				t.Errorf("TwiML = %s, want the announcement before %s", twiml, tt.before)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package voice
+
+import (
+	"fmt"
+	"time"
+	"unicode/utf8"
+)
+
+// CallConstraints are the limits on calls placed now, for agents deciding
+// whether and how to call.
+type CallConstraints struct {
+	CostPerMinuteUSD float64
+	MaxMessageChars  int // 0 = no limit
+
+	QuietHours   string // daily window with no calls, e.g. "22:00-07:00"
+	InQuietHours bool   // calls are refused until quiet hours end
+	DoNotDisturb bool   // calls are refused until do-not-disturb is off
+
+	// Budget is what is left of the daily call budget.
+	Budget Budget
+}
+
+// CallConstraints returns the current limits on placing calls.
+func (m *Manager) CallConstraints() CallConstraints {
+	now := time.Now()
+	return CallConstraints{
+		CostPerMinuteUSD: m.config.CallCostPerMinuteUSD,
+		MaxMessageChars:  m.config.MaxMessageChars,
+		QuietHours:       m.config.QuietHours,
+		InQuietHours:     m.quietHours.Contains(now),
+		DoNotDisturb:     m.DoNotDisturb(),
+		Budget:           m.budgetAt(now, "", time.Time{}),
+	}
+}
+
+// checkMessage rejects a message longer than MaxMessageChars.
+func (m *Manager) checkMessage(message string) error {
+	limit := m.config.MaxMessageChars
+	if n := utf8.RuneCountInString(message); limit > 0 && n > limit {
+		return fmt.Errorf("%w: %d characters (limit %d)", ErrMessageTooLong, n, limit)
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+package voice
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestCheckMessage(t *testing.T) {
+	m := newTestManager(t)
+	long := strings.Repeat("é", 11)
+
+	if err := m.checkMessage(long); err != nil {
+		t.Errorf("checkMessage() with no limit error = %v", err)
+	}
+
+	m.config.MaxMessageChars = 10
+	if err := m.checkMessage(strings.Repeat("é", 10)); err != nil {
+		t.Errorf("checkMessage() at the limit error = %v", err)
+	}
+	if err := m.checkMessage(long); !errors.Is(err, ErrMessageTooLong) {
+		t.Errorf("checkMessage() over the limit error = %v, want ErrMessageTooLong", err)
+	}
+	if _, _, err := m.InitiateCall(context.Background(), long); !errors.Is(err, ErrMessageTooLong) {
+		t.Errorf("InitiateCall() error = %v, want ErrMessageTooLong before dialing", err)
+	}
+}
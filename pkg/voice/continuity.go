@@ -0,0 +1,49 @@
+package voice
+
+import (
+	"strings"
+
+	"github.com/plexusone/agentcomms/pkg/config"
+)
+
+// previousTextExtension is the ElevenLabs synthesis parameter carrying text
+// spoken before the current request, used for prosody continuity.
+const previousTextExtension = "previous_text"
+
+// continuityProviders lists TTS providers that accept previous text.
+var continuityProviders = map[string]bool{
+	config.ProviderElevenLabs: true,
+}
+
+// previousAssistantText returns up to the last n assistant turns of the
+// conversation, oldest first, joined into one string.
+func (cs *CallState) previousAssistantText(n int) string {
+	if n <= 0 {
+		return ""
+	}
+
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+
+	var turns []string
+	for i := len(cs.Conversation) - 1; i >= 0 && len(turns) < n; i-- {
+		if turn := cs.Conversation[i]; turn.Role == "assistant" {
+			turns = append(turns, turn.Content)
+		}
+	}
+
+	// Collected newest first
+	for i, j := 0, len(turns)-1; i < j; i, j = i+1, j-1 {
+		turns[i], turns[j] = turns[j], turns[i]
+	}
+	return strings.Join(turns, " ")
+}
+
+// synthesisExtensions returns provider-specific synthesis parameters for
+// speaking after previous, or nil if there are none.
+func (m *Manager) synthesisExtensions(previous string) map[string]any {
+	if previous == "" || !continuityProviders[m.config.TTSProvider] {
+		return nil
+	}
+	return map[string]any{previousTextExtension: previous}
+}
@@ -0,0 +1,65 @@
+package voice
+
+import (
+	"testing"
+
+	"github.com/plexusone/agentcomms/pkg/config"
+)
+
+func TestPreviousAssistantText(t *testing.T) {
+	state := &CallState{}
+	state.AddTurn("assistant", "I finished the refactor.")
+	state.AddTurn("user", "Nice.")
+	state.AddTurn("assistant", "Want me to open a PR?")
+	state.AddTurn("user", "Yes.")
+
+	tests := []struct {
+		n    int
+		want string
+	}{
+		{0, ""},
+		{1, "Want me to open a PR?"},
+		{2, "I finished the refactor. Want me to open a PR?"},
+		{5, "I finished the refactor. Want me to open a PR?"},
+	}
+
+	for _, tt := range tests {
+		if got := state.previousAssistantText(tt.n); got != tt.want {
+			t.Errorf("previousAssistantText(%d) = %q, want %q", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestSynthesisExtensions(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider string
+		previous string
+		want     bool
+	}{
+		{"elevenlabs with context", config.ProviderElevenLabs, "Hello.", true},
+		{"elevenlabs first turn", config.ProviderElevenLabs, "", false},
+		{"unsupported provider", config.ProviderDeepgram, "Hello.", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestManager(t)
+			m.config.TTSProvider = tt.provider
+
+			ext := m.synthesisExtensions(tt.previous)
+			if !tt.want {
+				if ext != nil {
+					t.Errorf("synthesisExtensions(%q) = %v, want nil", tt.previous, ext)
+				}
+				return
+			}
+			if ext == nil {
+				t.Fatalf("synthesisExtensions(%q) = nil", tt.previous)
+			}
+			if got := ext[previousTextExtension]; got != tt.previous {
+				t.Errorf("%s = %v, want %q", previousTextExtension, got, tt.previous)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package voice
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// corsMaxAge is how long, in seconds, browsers may cache a preflight
+// response.
+const corsMaxAge = "600"
+
+// AllowOrigins lets pages from origins, such as a dashboard, call h from
+// the browser. "*" allows any origin. Other origins get no CORS headers,
+// so browsers keep them same-origin, as they do every origin when origins
+// is empty. Requests still need their usual bearer token. Twilio webhooks
+// must not be wrapped.
+func AllowOrigins(h http.Handler, origins []string) http.Handler {
+	if len(origins) == 0 {
+		return h
+	}
+	allowed := make([]string, len(origins))
+	for i, origin := range origins {
+		allowed[i] = strings.ToLower(strings.TrimSuffix(origin, "/"))
+	}
+	anyOrigin := slices.Contains(allowed, "*")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Origin")
+		origin := r.Header.Get("Origin")
+		if origin == "" || (!anyOrigin && !slices.Contains(allowed, strings.ToLower(origin))) {
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			w.Header().Set("Access-Control-Allow-Methods", "GET, POST")
+			w.Header().Set("Access-Control-Allow-Headers", "Authorization, Content-Type")
+			w.Header().Set("Access-Control-Max-Age", corsMaxAge)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,77 @@
+package voice
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAllowOrigins(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	h := AllowOrigins(ok, []string{"https://Dashboard.example.com/"})
+
+	tests := []struct {
+		name       string
+		method     string
+		origin     string
+		preflight  bool
+		wantCode   int
+		wantOrigin string
+	}{
+		{"same origin", http.MethodPost, "", false, http.StatusOK, ""},
+		{"allowed", http.MethodPost, "https://dashboard.example.com", false, http.StatusOK, "https://dashboard.example.com"},
+		{"allowed preflight", http.MethodOptions, "https://dashboard.example.com", true, http.StatusNoContent, "https://dashboard.example.com"},
+		{"other origin", http.MethodPost, "https://evil.example.com", false, http.StatusOK, ""},
+		{"other origin preflight", http.MethodOptions, "https://evil.example.com", true, http.StatusOK, ""},
+		{"other scheme", http.MethodPost, "http://dashboard.example.com", false, http.StatusOK, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, TriggerCallPath, nil)
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			if tt.preflight {
+				req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantCode {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantCode)
+			}
+			if got := rec.Header().Get("Access-Control-Allow-Origin"); got != tt.wantOrigin {
+				t.Errorf("Access-Control-Allow-Origin = %q, want %q", got, tt.wantOrigin)
+			}
+			if tt.preflight && tt.wantOrigin != "" && rec.Header().Get("Access-Control-Allow-Headers") == "" {
+				t.Error("preflight response does not allow the Authorization header")
+			}
+		})
+	}
+}
+
+func TestAllowOrigins_Any(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	req := httptest.NewRequest(http.MethodGet, DNDPath, nil)
+	req.Header.Set("Origin", "http://localhost:5173")
+	rec := httptest.NewRecorder()
+
+	AllowOrigins(ok, []string{"*"}).ServeHTTP(rec, req)
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "http://localhost:5173" {
+		t.Errorf("Access-Control-Allow-Origin = %q, want the request's origin", got)
+	}
+}
+
+func TestAllowOrigins_NoneConfigured(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	req := httptest.NewRequest(http.MethodGet, DNDPath, nil)
+	req.Header.Set("Origin", "https://dashboard.example.com")
+	rec := httptest.NewRecorder()
+
+	AllowOrigins(ok, nil).ServeHTTP(rec, req)
+	if len(rec.Header()) != 0 {
+		t.Errorf("headers = %v, want none without allowed origins", rec.Header())
+	}
+}
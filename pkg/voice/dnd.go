@@ -0,0 +1,85 @@
+package voice
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+)
+
+// DNDPath is where DNDHandler is served.
+const DNDPath = "/dnd"
+
+// dndRequest is the optional JSON body of a POST to DNDPath.
+type dndRequest struct {
+	Enabled *bool `json:"enabled"`
+}
+
+// dndResponse reports the do-not-disturb state.
+type dndResponse struct {
+	DND bool `json:"dnd"`
+}
+
+// DoNotDisturb reports whether do-not-disturb is on, in which case no calls
+// are placed.
+func (m *Manager) DoNotDisturb() bool {
+	return m.dnd.Load()
+}
+
+// SetDoNotDisturb turns do-not-disturb on or off. While it is on, placing a
+// call, including a scheduled call coming due, fails with
+// ErrDoNotDisturb. Calls in progress are not affected.
+func (m *Manager) SetDoNotDisturb(on bool) {
+	if m.dnd.Swap(on) != on {
+		slog.Info("do not disturb changed", "dnd", on)
+	}
+}
+
+// ToggleDoNotDisturb flips do-not-disturb and returns the new state.
+func (m *Manager) ToggleDoNotDisturb() bool {
+	for {
+		on := m.dnd.Load()
+		if m.dnd.CompareAndSwap(on, !on) {
+			slog.Info("do not disturb changed", "dnd", !on)
+			return !on
+		}
+	}
+}
+
+// DNDHandler serves DNDPath for requests with a bearer token matching
+// token. GET reports the do-not-disturb state; POST sets it from a JSON
+// body {"enabled": true|false}, or toggles it if the body is empty. Both
+// respond with {"dnd": ...}. An empty token rejects every request.
+func (m *Manager) DNDHandler(token string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodPost {
+			w.Header().Set("Allow", "GET, POST")
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if !bearerTokenValid(r, token) {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		if r.Method == http.MethodPost {
+			r.Body = http.MaxBytesReader(w, r.Body, 1<<10)
+			var req dndRequest
+			err := json.NewDecoder(r.Body).Decode(&req)
+			switch {
+			case errors.Is(err, io.EOF):
+				m.ToggleDoNotDisturb()
+			case err != nil || req.Enabled == nil:
+				http.Error(w, `Body must be empty or {"enabled": true|false}`, http.StatusBadRequest)
+				return
+			default:
+				m.SetDoNotDisturb(*req.Enabled)
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(dndResponse{DND: m.DoNotDisturb()})
+	})
+}
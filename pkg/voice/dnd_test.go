@@ -0,0 +1,69 @@
+package voice
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDNDHandler(t *testing.T) {
+	m := newTestManager(t)
+	handler := m.DNDHandler("secret")
+
+	tests := []struct {
+		name       string
+		method     string
+		auth       string
+		body       string
+		wantStatus int
+		wantDND    bool
+	}{
+		{"no token", http.MethodPost, "", "", http.StatusUnauthorized, false},
+		{"toggle on", http.MethodPost, "Bearer secret", "", http.StatusOK, true},
+		{"get", http.MethodGet, "Bearer secret", "", http.StatusOK, true},
+		{"set on again", http.MethodPost, "Bearer secret", `{"enabled":true}`, http.StatusOK, true},
+		{"bad body", http.MethodPost, "Bearer secret", `{"on":false}`, http.StatusBadRequest, true},
+		{"set off", http.MethodPost, "Bearer secret", `{"enabled":false}`, http.StatusOK, false},
+		{"wrong method", http.MethodDelete, "Bearer secret", "", http.StatusMethodNotAllowed, false},
+	}
+
+	// Cases run in order, each starting from the state the last one left
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, DNDPath, strings.NewReader(tt.body))
+		if tt.auth != "" {
+			req.Header.Set("Authorization", tt.auth)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		if rec.Code != tt.wantStatus {
+			t.Fatalf("%s: status = %d, want %d: %s", tt.name, rec.Code, tt.wantStatus, rec.Body)
+		}
+		if got := m.DoNotDisturb(); got != tt.wantDND {
+			t.Errorf("%s: DoNotDisturb() = %v, want %v", tt.name, got, tt.wantDND)
+		}
+		if tt.wantStatus == http.StatusOK && !strings.Contains(rec.Body.String(), `"dnd":`) {
+			t.Errorf("%s: body = %s, want the DND state", tt.name, rec.Body)
+		}
+	}
+}
+
+func TestDoNotDisturb_RejectsCalls(t *testing.T) {
+	m := newTestManager(t)
+	m.callSystem = &dialCallSystem{}
+	m.SetDoNotDisturb(true)
+
+	if _, _, err := m.InitiateCall(context.Background(), "hello"); !errors.Is(err, ErrDoNotDisturb) {
+		t.Errorf("InitiateCall() error = %v, want ErrDoNotDisturb", err)
+	}
+	if _, err := m.StartConference(context.Background(), []string{m.config.UserPhoneNumber}, "hello"); !errors.Is(err, ErrDoNotDisturb) {
+		t.Errorf("StartConference() error = %v, want ErrDoNotDisturb", err)
+	}
+
+	if m.ToggleDoNotDisturb() {
+		t.Error("ToggleDoNotDisturb() = true, want it turned off")
+	}
+}
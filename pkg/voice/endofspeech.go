@@ -0,0 +1,47 @@
+package voice
+
+import "time"
+
+// Adaptive end-of-speech tuning (Config.AdaptiveSilence). Each reply
+// ended by silence contributes the longest pause the caller went on
+// speaking after, or zero if they spoke without one, to a moving average.
+// The silence that ends a reply is a margin above that average, so callers
+// who pause to think are not cut off and quick ones are not kept waiting.
+const (
+	pauseAverageWeight = 0.3 // weight of the newest reply in the average
+	pauseMargin        = 2   // end-of-speech silence as a multiple of the average
+)
+
+// endOfSpeechSilence returns how long to keep listening after a final
+// transcript for the caller to go on. Without AdaptiveSilence it is
+// AggregateFinalsMS, where 0 ends the reply at the first final.
+func (m *Manager) endOfSpeechSilence(state *CallState) time.Duration {
+	if !m.config.AdaptiveSilence {
+		return time.Duration(m.config.AggregateFinalsMS) * time.Millisecond
+	}
+
+	state.mu.RLock()
+	avg, observed := state.pauseAverage, state.pausesObserved
+	state.mu.RUnlock()
+
+	silence := time.Duration(m.config.STTSilenceDurationMS) * time.Millisecond
+	if observed {
+		silence = avg * pauseMargin
+	}
+	minSilence := time.Duration(m.config.AdaptiveSilenceMinMS) * time.Millisecond
+	maxSilence := time.Duration(m.config.AdaptiveSilenceMaxMS) * time.Millisecond
+	return min(max(silence, minSilence), maxSilence)
+}
+
+// observePause adds the longest pause of a reply ended by silence to the
+// call's average. The first reply starts the average at the configured
+// STTSilenceDurationMS.
+func (m *Manager) observePause(state *CallState, pause time.Duration) {
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	if !state.pausesObserved {
+		state.pauseAverage = time.Duration(m.config.STTSilenceDurationMS) * time.Millisecond / pauseMargin
+		state.pausesObserved = true
+	}
+	state.pauseAverage = time.Duration(pauseAverageWeight*float64(pause) + (1-pauseAverageWeight)*float64(state.pauseAverage))
+}
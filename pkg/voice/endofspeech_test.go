@@ -0,0 +1,76 @@
+package voice
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/plexusone/omnivoice"
+)
+
+func TestEndOfSpeechSilence(t *testing.T) {
+	m := newTestManager(t)
+	m.config.AggregateFinalsMS = 250
+	state := &CallState{ID: "call-1"}
+
+	if got := m.endOfSpeechSilence(state); got != 250*time.Millisecond {
+		t.Errorf("without AdaptiveSilence = %v, want AggregateFinalsMS", got)
+	}
+
+	m.config.AdaptiveSilence = true
+	if got := m.endOfSpeechSilence(state); got != 800*time.Millisecond {
+		t.Errorf("before any reply = %v, want STTSilenceDurationMS", got)
+	}
+
+	// Replies without pauses shorten the wait down to the minimum
+	for range 20 {
+		m.observePause(state, 0)
+	}
+	if got := m.endOfSpeechSilence(state); got != 300*time.Millisecond {
+		t.Errorf("after quick replies = %v, want AdaptiveSilenceMinMS", got)
+	}
+
+	// Long pauses lengthen it up to the maximum
+	m.observePause(state, 900*time.Millisecond)
+	if got := m.endOfSpeechSilence(state); got <= 300*time.Millisecond || got >= 900*time.Millisecond {
+		t.Errorf("after one long pause = %v, want between the minimum and twice the pause", got)
+	}
+	for range 20 {
+		m.observePause(state, 1500*time.Millisecond)
+	}
+	if got := m.endOfSpeechSilence(state); got != 2000*time.Millisecond {
+		t.Errorf("after slow replies = %v, want AdaptiveSilenceMaxMS", got)
+	}
+}
+
+func TestAwaitTranscript_AdaptiveSilence(t *testing.T) {
+	m := newTestManager(t)
+	m.config.AdaptiveSilence = true
+	m.config.STTSilenceDurationMS = 200
+	m.config.AdaptiveSilenceMinMS = 100
+	state := &CallState{ID: "call-1"}
+
+	events := make(chan omnivoice.StreamEvent)
+	go func() {
+		events <- omnivoice.StreamEvent{Transcript: "Let me think.", IsFinal: true}
+		time.Sleep(150 * time.Millisecond)
+		events <- omnivoice.StreamEvent{Transcript: "Ship it.", IsFinal: true}
+	}()
+
+	start := time.Now()
+	got, err := m.awaitTranscript(context.Background(), state, events, nil)
+	if err != nil {
+		t.Fatalf("awaitTranscript() error = %v", err)
+	}
+	if want := "Let me think. Ship it."; got != want {
+		t.Errorf("transcript = %q, want %q", got, want)
+	}
+	if elapsed := time.Since(start); elapsed < 350*time.Millisecond {
+		t.Errorf("returned after %v, want to wait STTSilenceDurationMS after the last final", elapsed)
+	}
+
+	// The 150ms pause moves the average up from half the starting 200ms
+	if got := m.endOfSpeechSilence(state); got <= 200*time.Millisecond {
+		t.Errorf("silence after a long pause = %v, want more than 200ms", got)
+	}
+}
@@ -0,0 +1,129 @@
+package voice
+
+import "errors"
+
+// Sentinel errors returned by the Manager. Use errors.Is to test for them.
+var (
+	// ErrNotInitialized is returned when a call is attempted before Initialize succeeds.
+	ErrNotInitialized = errors.New("call manager not initialized")
+
+	// ErrCallNotFound is returned when a call ID does not refer to an active call.
+	ErrCallNotFound = errors.New("call not found")
+
+	// ErrDialFailed is returned when the phone provider could not place the call.
+	ErrDialFailed = errors.New("failed to make call")
+
+	// ErrNotAnswered is returned when the user did not answer the call.
+	ErrNotAnswered = errors.New("call not answered")
+
+	// ErrBusy is returned, wrapped with ErrNotAnswered, when the line was busy.
+	ErrBusy = errors.New("line busy")
+
+	// ErrSpeechFailed is returned when speaking to or listening to the user failed
+	// (TTS, STT, or audio transport errors) on an otherwise connected call.
+	ErrSpeechFailed = errors.New("speech failed")
+
+	// ErrDeclined is returned when the callee did not press the accept digit
+	// on a call that requires acceptance.
+	ErrDeclined = errors.New("call declined")
+
+	// ErrVoicemail is returned when answering machine detection found a
+	// machine. The message was left as a voicemail and the call hung up.
+	ErrVoicemail = errors.New("call reached voicemail; message left")
+
+	// ErrNoSpeech is returned when listening ended without hearing anything
+	// intelligible. The call is still active, so the caller can re-prompt.
+	ErrNoSpeech = errors.New("no speech detected")
+
+	// ErrMessageTooLong is returned when a message to speak is longer
+	// than MaxMessageChars.
+	ErrMessageTooLong = errors.New("message too long")
+
+	// ErrInvalidMetadata is returned when call metadata exceeds the size
+	// limits (see ValidateCallMetadata).
+	ErrInvalidMetadata = errors.New("invalid call metadata")
+
+	// ErrNoClearAnswer is returned when a yes/no question got no clear
+	// answer after every re-prompt. The call is still active.
+	ErrNoClearAnswer = errors.New("no clear yes or no answer")
+
+	// ErrQuietHours is returned when a call would be placed during quiet hours.
+	ErrQuietHours = errors.New("quiet hours are in effect")
+
+	// ErrDoNotDisturb is returned when a call would be placed while
+	// do-not-disturb is on.
+	ErrDoNotDisturb = errors.New("do not disturb is on; calls are paused until it is turned off")
+
+	// ErrInvalidSchedule is returned when a scheduled call time is not usable.
+	ErrInvalidSchedule = errors.New("invalid schedule")
+
+	// ErrScheduleNotFound is returned when a schedule ID does not refer to a pending scheduled call.
+	ErrScheduleNotFound = errors.New("scheduled call not found")
+
+	// ErrInvalidVolume is returned when a requested speech volume is outside 0.0-1.0.
+	ErrInvalidVolume = errors.New("volume must be between 0.0 and 1.0")
+
+	// ErrInvalidVoice is returned when a TTS voice ID is not known to the provider.
+	ErrInvalidVoice = errors.New("unknown voice")
+
+	// ErrInvalidAudio is returned when pre-rendered audio cannot be
+	// played: it is not allowed, not a supported WAV file, or too long.
+	ErrInvalidAudio = errors.New("invalid audio")
+
+	// ErrInvalidConference is returned when a conference request has no
+	// participants or too many.
+	ErrInvalidConference = errors.New("invalid conference")
+
+	// ErrNumberNotAllowed is returned when a number to dial is not the
+	// user's or in the configured conference allowlist.
+	ErrNumberNotAllowed = errors.New("number not allowed")
+
+	// ErrNothingToRepeat is returned by RepeatLast when the assistant has
+	// not spoken on the call yet.
+	ErrNothingToRepeat = errors.New("nothing to repeat")
+
+	// ErrConfirmationNotFound is returned when a confirmation token does not
+	// refer to a held call, because it was already used or has expired.
+	ErrConfirmationNotFound = errors.New("confirmation not found")
+
+	// ErrUnauthorized is returned when a request's API key matches no
+	// tenant.
+	ErrUnauthorized = errors.New("unknown or missing API key")
+
+	// ErrInvalidHistoryQuery is returned when a call history query has an
+	// empty time range or a bad page.
+	ErrInvalidHistoryQuery = errors.New("invalid call history query")
+
+	// ErrBudgetExceeded is returned when the daily call or cost budget is
+	// used up.
+	ErrBudgetExceeded = errors.New("daily call budget exceeded")
+
+	// ErrListeningMuted is returned when a reply is requested on a call
+	// whose listening is muted (see Manager.SetListeningMuted). Nothing was
+	// spoken and the call is still active.
+	ErrListeningMuted = errors.New("listening is muted on this call")
+
+	// ErrInvalidLanguage is returned when an STT language is not a BCP-47
+	// language code.
+	ErrInvalidLanguage = errors.New("invalid language")
+
+	// ErrCallDropped is returned when a call dropped without being ended
+	// and is being kept for Manager.ResumeCall (see Config.ResumeWindowMS).
+	ErrCallDropped = errors.New("call dropped; it can be resumed")
+
+	// ErrCallActive is returned when resuming a call that has not dropped.
+	ErrCallActive = errors.New("call is still connected")
+
+	// ErrUsageLimit is returned when a call was ended instead of taking
+	// another turn because it used up Config.MaxTTSCharsPerCall or
+	// Config.MaxSTTSecondsPerCall, or a message would have gone over the
+	// TTS cap.
+	ErrUsageLimit = errors.New("call reached its usage limit")
+
+	// ErrSMSFallbackSent is returned alongside ErrNotAnswered when an SMS was sent instead.
+	ErrSMSFallbackSent = errors.New("sent SMS instead")
+)
+
+// errNoCall is wrapped in ErrDialFailed when the provider reports success
+// but returns no call.
+var errNoCall = errors.New("provider returned no call")
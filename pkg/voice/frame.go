@@ -0,0 +1,90 @@
+package voice
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// frameLead is how far ahead of playback a frameWriter keeps the media
+// stream, so a late wakeup never leaves it without audio.
+const frameLead = 60 * time.Millisecond
+
+// frameWriter re-frames mu-law audio into fixed-size frames and writes them
+// no faster than they play, frameLead ahead. TTS providers send audio in
+// bursts of any size, which media streams buffer unevenly; an even stream
+// of frames plays more smoothly. Audio short of a full frame is held until
+// more arrives or flush pads it out with silence.
+type frameWriter struct {
+	ctx      context.Context
+	w        io.Writer
+	size     int
+	duration time.Duration // playback time of one frame
+	held     []byte
+	start    time.Time // when the first frame started playing
+	frames   int       // frames written
+}
+
+// newFrameWriter returns a writer that sends frames of size bytes, each
+// lasting duration, to w. Waits between frames end when ctx is done.
+func newFrameWriter(ctx context.Context, w io.Writer, size int, duration time.Duration) *frameWriter {
+	return &frameWriter{ctx: ctx, w: w, size: size, duration: duration}
+}
+
+func (f *frameWriter) Write(p []byte) (int, error) {
+	f.held = append(f.held, p...)
+	for len(f.held) >= f.size {
+		if err := f.writeFrame(f.held[:f.size]); err != nil {
+			return 0, err
+		}
+		f.held = f.held[f.size:]
+	}
+	return len(p), nil
+}
+
+// writeFrame waits until frame is due and writes it. A stream that fell
+// behind, such as after a TTS retry, is paced from now on rather than
+// sent in a burst to catch up.
+func (f *frameWriter) writeFrame(frame []byte) error {
+	now := time.Now()
+	if f.start.IsZero() || now.Sub(f.playAt(f.frames)) > 0 {
+		f.start = now.Add(-time.Duration(f.frames) * f.duration)
+	}
+
+	if wait := time.Until(f.playAt(f.frames).Add(-frameLead)); wait > 0 {
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-f.ctx.Done():
+			timer.Stop()
+			return f.ctx.Err()
+		}
+	}
+
+	if _, err := f.w.Write(frame); err != nil {
+		return err
+	}
+	f.frames++
+	return nil
+}
+
+// playAt returns when frame n starts playing.
+func (f *frameWriter) playAt(n int) time.Time {
+	return f.start.Add(time.Duration(n) * f.duration)
+}
+
+// flush pads held audio to a full frame with silence, writes it, and
+// returns how many bytes of silence were added. The audio is best effort
+// at this point, so write errors are ignored.
+func (f *frameWriter) flush() int {
+	if len(f.held) == 0 {
+		return 0
+	}
+	pad := f.size - len(f.held)
+	for range pad {
+		f.held = append(f.held, ulawSilence)
+	}
+	_ = f.writeFrame(f.held)
+	f.held = nil
+	return pad
+}
@@ -0,0 +1,109 @@
+package voice
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/plexusone/omnivoice"
+)
+
+// frameRecorder records the size of each write.
+type frameRecorder struct {
+	sizes []int
+	total int
+}
+
+func (r *frameRecorder) Write(p []byte) (int, error) {
+	r.sizes = append(r.sizes, len(p))
+	r.total += len(p)
+	return len(p), nil
+}
+
+func TestFrameWriter(t *testing.T) {
+	rec := &frameRecorder{}
+	f := newFrameWriter(context.Background(), rec, 160, time.Millisecond)
+
+	// Bursty chunks of any size come out as whole frames
+	for _, n := range []int{100, 500, 37, 1000} {
+		if got, err := f.Write(make([]byte, n)); err != nil || got != n {
+			t.Fatalf("Write(%d bytes) = %d, %v", n, got, err)
+		}
+	}
+	if pad := f.flush(); pad != 11*160-1637 {
+		t.Errorf("flush() padded %d bytes, want %d", pad, 11*160-1637)
+	}
+
+	if len(rec.sizes) != 11 {
+		t.Fatalf("wrote %d frames, want 11", len(rec.sizes))
+	}
+	for i, n := range rec.sizes {
+		if n != 160 {
+			t.Errorf("frame %d is %d bytes, want 160", i, n)
+		}
+	}
+	if f.flush() != 0 || len(rec.sizes) != 11 {
+		t.Error("second flush wrote again")
+	}
+}
+
+func TestFrameWriter_Paced(t *testing.T) {
+	rec := &frameRecorder{}
+	f := newFrameWriter(context.Background(), rec, 160, 20*time.Millisecond)
+
+	// 10 frames play for 200ms; all but the lead is waited out
+	start := time.Now()
+	if _, err := f.Write(make([]byte, 1600)); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	want := 9*20*time.Millisecond - frameLead
+	if elapsed := time.Since(start); elapsed < want || elapsed > want+150*time.Millisecond {
+		t.Errorf("wrote 10 frames in %v, want about %v", elapsed, want)
+	}
+}
+
+func TestFrameWriter_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	f := newFrameWriter(ctx, &frameRecorder{}, 160, time.Second)
+
+	if _, err := f.Write(make([]byte, 1600)); !errors.Is(err, context.Canceled) {
+		t.Errorf("Write() error = %v, want context.Canceled", err)
+	}
+}
+
+func TestSpeak_TTSFrames(t *testing.T) {
+	m := newTestManager(t)
+	m.config.TTSFrameMS = 5
+	m.ttsProvider = &fakeTTS{streams: [][]omnivoice.StreamChunk{
+		{{Audio: make([]byte, 250)}, {Audio: make([]byte, 30), IsFinal: true}},
+	}}
+	rec := &frameRecorder{}
+	state := &CallState{ID: "call-1", Call: &fakeCall{transport: &recordingConn{w: rec}}}
+
+	if err := m.speak(context.Background(), state, "Hello."); err != nil {
+		t.Fatalf("speak() error = %v", err)
+	}
+	// 280 bytes make seven 40-byte frames at 5ms
+	if len(rec.sizes) != 7 {
+		t.Fatalf("wrote %v, want 7 frames", rec.sizes)
+	}
+	for i, n := range rec.sizes {
+		if n != 40 {
+			t.Errorf("frame %d is %d bytes, want 40", i, n)
+		}
+	}
+}
+
+// recordingConn is a transport that sends the call's audio to w.
+type recordingConn struct {
+	omnivoice.Transport
+
+	w *frameRecorder
+}
+
+func (c *recordingConn) AudioIn() io.WriteCloser {
+	return nopWriteCloser{c.w}
+}
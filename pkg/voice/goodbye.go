@@ -0,0 +1,49 @@
+package voice
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// goodbyeTailWords is how many words may follow a goodbye phrase, as in
+// "okay bye, thanks", for it to still count.
+const goodbyeTailWords = 2
+
+// WantsToEnd reports whether a user reply sounds like the user wants to end
+// the call, using the configured goodbye phrases. It is only a hint for the
+// agent, which decides whether to call end_call.
+func (m *Manager) WantsToEnd(transcript string) bool {
+	return isGoodbye(transcript, m.config.GoodbyePhrases)
+}
+
+// isGoodbye reports whether one of phrases appears as whole words at or
+// near the end of transcript. Matching near the end avoids flagging replies
+// like "say goodbye to the old API and use the new one".
+func isGoodbye(transcript string, phrases []string) bool {
+	words := normalizeWords(transcript)
+	for _, phrase := range phrases {
+		pw := normalizeWords(phrase)
+		if len(pw) == 0 || len(pw) > len(words) {
+			continue
+		}
+
+		earliest := max(len(words)-len(pw)-goodbyeTailWords, 0)
+		for start := len(words) - len(pw); start >= earliest; start-- {
+			if slices.Equal(words[start:start+len(pw)], pw) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// normalizeWords lowercases s and splits it into words, dropping
+// punctuation other than apostrophes. Typographic apostrophes, which some
+// STT providers emit, are treated as plain ones.
+func normalizeWords(s string) []string {
+	s = strings.ReplaceAll(strings.ToLower(s), "’", "'")
+	return strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '\''
+	})
+}
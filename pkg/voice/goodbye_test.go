@@ -0,0 +1,45 @@
+package voice
+
+import (
+	"testing"
+
+	"github.com/plexusone/agentcomms/pkg/config"
+)
+
+func TestIsGoodbye(t *testing.T) {
+	phrases := config.DefaultGoodbyePhrases()
+
+	tests := []struct {
+		transcript string
+		want       bool
+	}{
+		{"Okay, bye.", true},
+		{"Okay bye, thanks!", true},
+		{"Great, talk to you later.", true},
+		{"That’s all for now.", true},
+		{"Goodbye", true},
+		{"Say goodbye to the old API and use the new one.", false},
+		{"Can you add a bypass flag?", false},
+		{"Yes, go ahead and deploy it.", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.transcript, func(t *testing.T) {
+			if got := isGoodbye(tt.transcript, phrases); got != tt.want {
+				t.Errorf("isGoodbye(%q) = %v, want %v", tt.transcript, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsGoodbye_CustomPhrases(t *testing.T) {
+	phrases := []string{"ciao"}
+
+	if !isGoodbye("ok ciao", phrases) {
+		t.Error("expected custom phrase to match")
+	}
+	if isGoodbye("ok bye", phrases) {
+		t.Error("expected default phrase not to match when overridden")
+	}
+}
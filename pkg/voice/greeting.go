@@ -0,0 +1,85 @@
+package voice
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/plexusone/omnivoice"
+)
+
+// waitForGreeting waits, up to GreetingWaitMS, for the callee to finish
+// the greeting most people answer with, so the first message does not talk
+// over their "hello?". The greeting ends once GreetingSilenceMS passes
+// without new speech; a callee who says nothing is waited for in full.
+// Transcription problems are logged and the message is spoken anyway.
+func (m *Manager) waitForGreeting(ctx context.Context, state *CallState) {
+	transport := state.Call.Transport()
+	if transport == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(m.config.GreetingWaitMS)*time.Millisecond)
+	defer cancel()
+
+	var events <-chan omnivoice.StreamEvent
+	if m.config.STTPersistConnection {
+		session, err := m.sttSession(state, transport)
+		if err != nil {
+			slog.Warn("failed to listen for greeting", "call_id", state.ID, "error", err)
+			return
+		}
+		session.startTurn(time.Now())
+		defer session.endTurn()
+		events = session.events
+	} else {
+		writer, ev, err := m.transcribeStream(ctx, state)
+		if err != nil {
+			slog.Warn("failed to listen for greeting", "call_id", state.ID, "error", err)
+			return
+		}
+		defer func() { _ = writer.Close() }()
+		go pumpAudio(ctx, audioInReader{r: state.teeUserAudio(transport.AudioOut()), state: state}, writer, time.Now())
+		events = ev
+	}
+
+	start := time.Now()
+	heard := awaitGreeting(ctx, events, time.Duration(m.config.GreetingSilenceMS)*time.Millisecond)
+	slog.Debug("waited for greeting", "call_id", state.ID, "heard", heard, "waited", time.Since(start))
+}
+
+// awaitGreeting consumes transcription events until silence has passed
+// after the last speech, ctx is done, or the stream ends, and reports
+// whether any speech was heard. Before the first speech only ctx ends the
+// wait.
+func awaitGreeting(ctx context.Context, events <-chan omnivoice.StreamEvent, silence time.Duration) bool {
+	var quiet *time.Timer
+	var quietC <-chan time.Time
+	defer func() {
+		if quiet != nil {
+			quiet.Stop()
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return quiet != nil
+		case <-quietC:
+			return true
+		case event, ok := <-events:
+			if !ok || event.Error != nil {
+				return quiet != nil
+			}
+			if event.Transcript == "" {
+				continue
+			}
+			if quiet == nil {
+				quiet = time.NewTimer(silence)
+				quietC = quiet.C
+			} else {
+				quiet.Reset(silence)
+			}
+		}
+	}
+}
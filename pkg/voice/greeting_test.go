@@ -0,0 +1,49 @@
+package voice
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/plexusone/omnivoice"
+)
+
+func TestAwaitGreeting(t *testing.T) {
+	tests := []struct {
+		name      string
+		events    []omnivoice.StreamEvent
+		close     bool
+		wantHeard bool
+		wantWait  bool // waits for the whole timeout
+	}{
+		{"greeting then silence", []omnivoice.StreamEvent{{Transcript: "hel"}, {Transcript: "hello?", IsFinal: true}}, false, true, false},
+		{"no greeting", nil, false, false, true},
+		{"stream closed", nil, true, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events := make(chan omnivoice.StreamEvent, len(tt.events))
+			for _, e := range tt.events {
+				events <- e
+			}
+			if tt.close {
+				close(events)
+			}
+
+			timeout := 500 * time.Millisecond
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+
+			start := time.Now()
+			heard := awaitGreeting(ctx, events, 20*time.Millisecond)
+			waited := time.Since(start)
+			if heard != tt.wantHeard {
+				t.Errorf("awaitGreeting() = %v, want %v", heard, tt.wantHeard)
+			}
+			if got := waited >= timeout; got != tt.wantWait {
+				t.Errorf("waited %v of the %v timeout, want whole wait %v", waited, timeout, tt.wantWait)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package voice
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+)
+
+// historySummaryLen bounds the conversation excerpt kept for each call in
+// the call history, in runes.
+const historySummaryLen = 500
+
+// CallRecord is a completed call in the call history. The number is
+// redacted so the history never holds full phone numbers.
+type CallRecord struct {
+	CallID          string    `json:"call_id"`
+	Tenant          string    `json:"tenant,omitempty"` // tenant the call was placed for
+	To              string    `json:"to"`
+	StartedAt       time.Time `json:"started_at"`
+	EndedAt         time.Time `json:"ended_at"`
+	DurationSeconds float64   `json:"duration_seconds"`
+	Turns           int       `json:"turns"`
+	Summary         string    `json:"summary"` // the conversation, truncated
+
+	// RecordingConsent is set when the call was recorded and the callee
+	// heard the recording announcement.
+	RecordingConsent bool `json:"recording_consent,omitempty"`
+}
+
+// CallQuery selects calls from the call history: those placed for Tenant
+// ("" for the base config) that ended in [Since, Until). A zero time leaves
+// that end of the range open.
+type CallQuery struct {
+	Since  time.Time
+	Until  time.Time
+	Tenant string
+}
+
+// filter returns the records matching q, newest first.
+func (q CallQuery) filter(records []CallRecord) []CallRecord {
+	var out []CallRecord
+	for _, r := range records {
+		if r.Tenant != q.Tenant {
+			continue
+		}
+		if !q.Since.IsZero() && r.EndedAt.Before(q.Since) {
+			continue
+		}
+		if !q.Until.IsZero() && !r.EndedAt.Before(q.Until) {
+			continue
+		}
+		out = append(out, r)
+	}
+	slices.SortStableFunc(out, func(a, b CallRecord) int {
+		return b.EndedAt.Compare(a.EndedAt)
+	})
+	return out
+}
+
+// CallHistory returns a page of ctx's tenant's completed calls that ended
+// in [since, until), newest first, skipping the first offset. It also
+// reports whether more calls follow the page. Without a store the history
+// is empty.
+func (m *Manager) CallHistory(ctx context.Context, since, until time.Time, offset, limit int) ([]CallRecord, bool, error) {
+	if offset < 0 || limit <= 0 {
+		return nil, false, ErrInvalidHistoryQuery
+	}
+	if !since.IsZero() && !until.IsZero() && !since.Before(until) {
+		return nil, false, ErrInvalidHistoryQuery
+	}
+
+	m.schedulesMu.Lock()
+	store := m.store
+	m.schedulesMu.Unlock()
+	if store == nil {
+		return nil, false, nil
+	}
+
+	records, err := store.List(CallQuery{Since: since, Until: until, Tenant: tenantID(ctx)})
+	if err != nil {
+		return nil, false, err
+	}
+	if offset >= len(records) {
+		return nil, false, nil
+	}
+	end := min(offset+limit, len(records))
+	return records[offset:end], end < len(records), nil
+}
+
+// recordCall adds an ended call to the call history. Calls that were never
+// answered are not kept.
+func (m *Manager) recordCall(state *CallState, endedAt time.Time) {
+	m.schedulesMu.Lock()
+	store := m.store
+	m.schedulesMu.Unlock()
+	if store == nil {
+		return
+	}
+
+	state.mu.RLock()
+	if state.metrics.answeredAt.IsZero() {
+		state.mu.RUnlock()
+		return
+	}
+	record := CallRecord{
+		CallID:           state.ID,
+		Tenant:           state.tenant,
+		To:               redactNumber(state.to),
+		StartedAt:        state.StartTime,
+		EndedAt:          endedAt,
+		DurationSeconds:  endedAt.Sub(state.StartTime).Seconds(),
+		Turns:            len(state.Conversation),
+		Summary:          summarizeConversation(state.Conversation),
+		RecordingConsent: state.recordingConsent,
+	}
+	state.mu.RUnlock()
+
+	if err := store.SaveCall(record); err != nil {
+		slog.Warn("failed to save call history", "call_id", record.CallID, "error", err)
+	}
+}
+
+// summarizeConversation returns the conversation as "role: content" lines,
+// truncated to historySummaryLen runes.
+func summarizeConversation(turns []ConversationTurn) string {
+	var b strings.Builder
+	for i, turn := range turns {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(turn.Role + ": " + turn.Content)
+	}
+
+	summary := []rune(b.String())
+	if len(summary) <= historySummaryLen {
+		return string(summary)
+	}
+	return string(summary[:historySummaryLen-1]) + "…"
+}
+
+// redactNumber masks all but the last four characters of a phone number.
+// Numbers are redacted wherever they are logged or kept.
+func redactNumber(n string) string {
+	if len(n) <= 4 {
+		return strings.Repeat("*", len(n))
+	}
+	return strings.Repeat("*", len(n)-4) + n[len(n)-4:]
+}
@@ -0,0 +1,102 @@
+package voice
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/plexusone/agentcomms/internal/tenant"
+)
+
+func TestCallHistory(t *testing.T) {
+	store := NewFileCallStore(filepath.Join(t.TempDir(), "calls.json"))
+	base := time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC)
+	for i, id := range []string{"call-1", "call-2", "call-3"} {
+		_ = store.SaveCall(CallRecord{CallID: id, EndedAt: base.Add(time.Duration(i) * time.Hour)})
+	}
+	_ = store.SaveCall(CallRecord{CallID: "call-acme", Tenant: "acme", EndedAt: base})
+
+	m := newTestManager(t)
+	if err := m.SetStore(store); err != nil {
+		t.Fatalf("SetStore() error = %v", err)
+	}
+
+	ids := func(records []CallRecord) string {
+		var out []string
+		for _, r := range records {
+			out = append(out, r.CallID)
+		}
+		return strings.Join(out, ",")
+	}
+
+	tests := []struct {
+		name          string
+		ctx           context.Context
+		since, until  time.Time
+		offset, limit int
+		want          string
+		wantMore      bool
+	}{
+		{"newest first", context.Background(), time.Time{}, time.Time{}, 0, 10, "call-3,call-2,call-1", false},
+		{"first page", context.Background(), time.Time{}, time.Time{}, 0, 2, "call-3,call-2", true},
+		{"last page", context.Background(), time.Time{}, time.Time{}, 2, 2, "call-1", false},
+		{"time range", context.Background(), base.Add(time.Hour), base.Add(2 * time.Hour), 0, 10, "call-2", false},
+		{"tenant", tenant.WithTenantID(context.Background(), "acme"), time.Time{}, time.Time{}, 0, 10, "call-acme", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records, more, err := m.CallHistory(tt.ctx, tt.since, tt.until, tt.offset, tt.limit)
+			if err != nil {
+				t.Fatalf("CallHistory() error = %v", err)
+			}
+			if got := ids(records); got != tt.want || more != tt.wantMore {
+				t.Errorf("CallHistory() = %s, %v; want %s, %v", got, more, tt.want, tt.wantMore)
+			}
+		})
+	}
+
+	if _, _, err := m.CallHistory(context.Background(), base, base, 0, 10); !errors.Is(err, ErrInvalidHistoryQuery) {
+		t.Errorf("empty range error = %v, want ErrInvalidHistoryQuery", err)
+	}
+}
+
+func TestRecordCall(t *testing.T) {
+	store := NewFileCallStore(filepath.Join(t.TempDir(), "calls.json"))
+	m := newTestManager(t)
+	if err := m.SetStore(store); err != nil {
+		t.Fatalf("SetStore() error = %v", err)
+	}
+
+	start := time.Now()
+	missed := &CallState{ID: "call-missed", StartTime: start, to: "+15551234567"}
+	m.recordCall(missed, start.Add(time.Minute))
+
+	answered := &CallState{ID: "call-1", StartTime: start, to: "+15551234567"}
+	answered.markAnswered(start)
+	answered.Conversation = []ConversationTurn{
+		{Role: "assistant", Content: "The build is done."},
+		{Role: "user", Content: strings.Repeat("ok ", 200)},
+	}
+	m.recordCall(answered, start.Add(time.Minute))
+
+	records, err := store.List(CallQuery{})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(records) != 1 || records[0].CallID != "call-1" {
+		t.Fatalf("List() = %+v, want only the answered call", records)
+	}
+	r := records[0]
+	if r.To != "********4567" {
+		t.Errorf("To = %q, want the number redacted", r.To)
+	}
+	if r.Turns != 2 || r.DurationSeconds != 60 {
+		t.Errorf("Turns, DurationSeconds = %d, %g; want 2, 60", r.Turns, r.DurationSeconds)
+	}
+	if n := len([]rune(r.Summary)); n != historySummaryLen || !strings.HasPrefix(r.Summary, "assistant: The build is done.\nuser: ok") {
+		t.Errorf("Summary = %q (%d runes), want the truncated conversation", r.Summary, n)
+	}
+}
@@ -0,0 +1,97 @@
+package voice
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// idempotencyWindow is how long an idempotency key is remembered after the
+// call it placed was started.
+const idempotencyWindow = 10 * time.Minute
+
+// dialResult is the outcome of an InitiateCall made with an idempotency
+// key. done is closed once the fields are set.
+type dialResult struct {
+	at       time.Time
+	done     chan struct{}
+	state    *CallState
+	response string
+	err      error
+}
+
+// dialKeys tracks recent idempotency keys.
+type dialKeys struct {
+	mu      sync.Mutex
+	entries map[string]*dialResult
+}
+
+// claim returns the result for key and whether the caller is the first to
+// use it and must place the call. Expired keys are pruned.
+func (d *dialKeys) claim(key string, now time.Time) (*dialResult, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for k, r := range d.entries {
+		if now.Sub(r.at) > idempotencyWindow {
+			delete(d.entries, k)
+		}
+	}
+
+	if r, ok := d.entries[key]; ok {
+		return r, false
+	}
+	if d.entries == nil {
+		d.entries = make(map[string]*dialResult)
+	}
+	r := &dialResult{at: now, done: make(chan struct{})}
+	d.entries[key] = r
+	return r, true
+}
+
+// forget removes key so the next use dials again.
+func (d *dialKeys) forget(key string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.entries, key)
+}
+
+// InitiateCallOnce is InitiateCall with an idempotency key. A retry with
+// the same key within idempotencyWindow does not dial again; it waits for
+// the original attempt and returns its call state and response. Attempts
+// that failed without placing a call are forgotten, so they can be retried.
+// An empty key always dials.
+func (m *Manager) InitiateCallOnce(ctx context.Context, key, message string, opts ...SpeakOption) (*CallState, string, error) {
+	return m.once(ctx, key, func() (*CallState, string, error) {
+		return m.InitiateCall(ctx, message, opts...)
+	})
+}
+
+// once runs initiate at most once per idempotency key (see
+// InitiateCallOnce).
+func (m *Manager) once(ctx context.Context, key string, initiate func() (*CallState, string, error)) (*CallState, string, error) {
+	if key == "" {
+		return initiate()
+	}
+	// Tenants choose keys independently
+	if id := tenantID(ctx); id != "" {
+		key = id + "/" + key
+	}
+
+	r, first := m.dials.claim(key, time.Now())
+	if !first {
+		select {
+		case <-r.done:
+			return r.state, r.response, r.err
+		case <-ctx.Done():
+			return nil, "", ctx.Err()
+		}
+	}
+
+	r.state, r.response, r.err = initiate()
+	if r.state == nil {
+		m.dials.forget(key)
+	}
+	close(r.done)
+	return r.state, r.response, r.err
+}
@@ -0,0 +1,66 @@
+package voice
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDialKeys_Claim(t *testing.T) {
+	var d dialKeys
+	now := time.Now()
+
+	first, ok := d.claim("k1", now)
+	if !ok {
+		t.Fatal("first claim of k1 was not first")
+	}
+	if again, ok := d.claim("k1", now.Add(time.Minute)); ok || again != first {
+		t.Error("retry of k1 within the window should return the original attempt")
+	}
+	if _, ok := d.claim("k2", now); !ok {
+		t.Error("claim of a different key was not first")
+	}
+	if _, ok := d.claim("k1", now.Add(idempotencyWindow+time.Second)); !ok {
+		t.Error("claim of an expired key was not first")
+	}
+
+	d.forget("k2")
+	if _, ok := d.claim("k2", now); !ok {
+		t.Error("claim of a forgotten key was not first")
+	}
+}
+
+func TestInitiateCallOnce_RetryAfterFailure(t *testing.T) {
+	m := newTestManager(t)
+
+	// Not initialized, so every attempt fails before dialing
+	for range 2 {
+		if _, _, err := m.InitiateCallOnce(context.Background(), "k1", "hello"); !errors.Is(err, ErrNotInitialized) {
+			t.Fatalf("InitiateCallOnce() error = %v, want ErrNotInitialized", err)
+		}
+	}
+	if _, ok := m.dials.claim("k1", time.Now()); !ok {
+		t.Error("failed attempt was remembered")
+	}
+}
+
+func TestInitiateCallOnce_Duplicate(t *testing.T) {
+	m := newTestManager(t)
+	state := &CallState{ID: "call-1"}
+
+	r, _ := m.dials.claim("k1", time.Now())
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		r.state, r.response = state, "sounds good"
+		close(r.done)
+	}()
+
+	got, response, err := m.InitiateCallOnce(context.Background(), "k1", "hello")
+	if err != nil {
+		t.Fatalf("InitiateCallOnce() error = %v", err)
+	}
+	if got != state || response != "sounds good" {
+		t.Errorf("InitiateCallOnce() = (%v, %q), want the original call", got, response)
+	}
+}
@@ -0,0 +1,42 @@
+package voice
+
+import (
+	"crypto/rand"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// IDGenerator returns a new, unique call ID.
+type IDGenerator func() string
+
+// NewUUID returns a random (version 4) UUID. It is the default call ID: it
+// is unique across restarts and instances and reveals nothing about how
+// many calls were placed.
+func NewUUID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])  // never fails
+	b[6] = b[6]&0x0f | 0x40 // version 4
+	b[8] = b[8]&0x3f | 0x80 // RFC 4122 variant
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// SequentialIDs returns a generator of readable "call-N-unixsec" IDs,
+// numbered from 1. They are only unique within one process; use them where
+// readability matters more, such as tests.
+func SequentialIDs() IDGenerator {
+	var mu sync.Mutex
+	n := 0
+	return func() string {
+		mu.Lock()
+		defer mu.Unlock()
+		n++
+		return fmt.Sprintf("call-%d-%d", n, time.Now().Unix())
+	}
+}
+
+// SetIDGenerator replaces the call ID generator (default NewUUID). Call it
+// before placing calls.
+func (m *Manager) SetIDGenerator(gen IDGenerator) {
+	m.newCallID = gen
+}
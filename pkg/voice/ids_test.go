@@ -0,0 +1,61 @@
+package voice
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+)
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+func TestNewUUID(t *testing.T) {
+	seen := make(map[string]bool)
+	for range 100 {
+		id := NewUUID()
+		if !uuidPattern.MatchString(id) {
+			t.Fatalf("NewUUID() = %q, want a version 4 UUID", id)
+		}
+		if seen[id] {
+			t.Fatalf("NewUUID() returned %q twice", id)
+		}
+		seen[id] = true
+	}
+}
+
+func TestSequentialIDs(t *testing.T) {
+	gen := SequentialIDs()
+	pattern := regexp.MustCompile(`^call-(\d+)-\d+$`)
+
+	for _, want := range []string{"1", "2"} {
+		id := gen()
+		m := pattern.FindStringSubmatch(id)
+		if m == nil || m[1] != want {
+			t.Errorf("SequentialIDs()() = %q, want call-%s-<unixsec>", id, want)
+		}
+	}
+}
+
+func TestSetIDGenerator(t *testing.T) {
+	m := newTestManager(t)
+
+	// Default IDs are opaque UUIDs
+	if state := m.addCall(context.Background(), &fakeCall{id: "CA-1"}, "+15550001111", time.Now()); !uuidPattern.MatchString(state.ID) {
+		t.Errorf("default call ID = %q, want a UUID", state.ID)
+	}
+
+	m.SetIDGenerator(func() string { return "call-fixed" })
+	state := m.addCall(context.Background(), &fakeCall{id: "CA-2"}, "+15550001111", time.Now())
+	if state.ID != "call-fixed" {
+		t.Errorf("call ID = %q, want %q", state.ID, "call-fixed")
+	}
+	if _, err := m.CallStatus(context.Background(), "call-fixed"); err != nil {
+		t.Errorf("CallStatus() error = %v", err)
+	}
+
+	m.removeCall(state.ID)
+	if _, err := m.CallStatus(context.Background(), "call-fixed"); !errors.Is(err, ErrCallNotFound) {
+		t.Errorf("CallStatus() after removal error = %v, want ErrCallNotFound", err)
+	}
+}
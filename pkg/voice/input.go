@@ -0,0 +1,223 @@
+package voice
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/plexusone/omnivoice-core/transport"
+)
+
+// InputType identifies how the user responded to a prompt.
+type InputType string
+
+// Input types.
+const (
+	InputSpeech InputType = "speech"
+	InputDTMF   InputType = "dtmf"
+)
+
+// Input is the user's response to SpeakAndWaitDigits.
+type Input struct {
+	Type  InputType
+	Value string // transcript for speech, key presses for DTMF
+}
+
+// digitsBufferSize is the number of pushed key presses buffered per call.
+const digitsBufferSize = 32
+
+// dtmfGracePeriod is how long a finished transcript waits for a key press
+// that arrived at nearly the same time. DTMF wins ties because it is exact.
+const dtmfGracePeriod = 300 * time.Millisecond
+
+// dtmfInterDigitTimeout ends digit collection when the user stops pressing keys.
+const dtmfInterDigitTimeout = 3 * time.Second
+
+// speechResult is the outcome of a listen for use in a select.
+type speechResult struct {
+	text string
+	err  error
+}
+
+// deferTurnKey is the context key marking listens whose caller records the
+// user's turn itself.
+type deferTurnKey struct{}
+
+// withDeferredTurn returns a context whose listens return the transcript
+// without recording it as a user turn, for callers that may discard it.
+func withDeferredTurn(ctx context.Context) context.Context {
+	return context.WithValue(ctx, deferTurnKey{}, true)
+}
+
+// turnDeferred reports whether ctx was returned by withDeferredTurn.
+func turnDeferred(ctx context.Context) bool {
+	deferred, _ := ctx.Value(deferTurnKey{}).(bool)
+	return deferred
+}
+
+// NotifyDigits delivers DTMF key presses for the call with the given provider
+// call ID (e.g., a Twilio CallSid). Key presses on Twilio media streams are
+// read by the transport and delivered here. It never blocks; digits are
+// dropped if the call is unknown or its buffer is full.
+func (m *Manager) NotifyDigits(providerCallID, digits string) {
+	m.callsMu.RLock()
+	defer m.callsMu.RUnlock()
+
+	for _, state := range m.calls {
+		if state.Call == nil || state.Call.ID() != providerCallID {
+			continue
+		}
+		for _, d := range digits {
+			select {
+			case state.digitsCh <- string(d):
+			default:
+			}
+		}
+		return
+	}
+}
+
+// onStreamDTMF delivers a key press the Twilio transport read from a call's
+// media stream.
+func (m *Manager) onStreamDTMF(conn transport.Connection, digit string) {
+	if c, ok := conn.(interface{ CallSID() string }); ok {
+		m.NotifyDigits(c.CallSID(), digit)
+	}
+}
+
+// SpeakAndWaitDigits speaks a message and returns the user's reply, either
+// speech or up to maxDigits DTMF key presses, whichever comes first. Digit
+// entry ends early on '#' or after a pause. If both arrive together, DTMF
+// is preferred.
+func (m *Manager) SpeakAndWaitDigits(ctx context.Context, callID, message string, maxDigits int, opts ...SpeakOption) (Input, error) {
+	if err := m.checkMessage(message); err != nil {
+		return Input{}, err
+	}
+	state, err := m.readyCall(ctx, callID)
+	if err != nil {
+		return Input{}, err
+	}
+
+	if state.ListeningMuted() {
+		return Input{}, ErrListeningMuted
+	}
+
+	// Keys pressed before this prompt don't answer it
+	drainDigits(state.digitsCh)
+
+	if err := m.speak(ctx, state, message, opts...); err != nil {
+		return Input{}, fmt.Errorf("%w: %w", ErrSpeechFailed, err)
+	}
+
+	// Stop listening as soon as the race is decided
+	listenCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Only the winning input is recorded as the user's turn, so speech
+	// that loses to a key press is not
+	speech := make(chan speechResult, 1)
+	go func() {
+		text, err := m.listen(withDeferredTurn(listenCtx), state, nil)
+		speech <- speechResult{text: text, err: err}
+	}()
+
+	input, err := raceInput(ctx, speech, state.digitsCh, maxDigits)
+	if err != nil {
+		return Input{}, speechError(err)
+	}
+	switch input.Type {
+	case InputSpeech:
+		m.recordUserTurn(ctx, state, input.Value)
+	case InputDTMF:
+		state.AddTurn("user", input.Value)
+	}
+
+	return input, nil
+}
+
+// drainDigits discards buffered key presses.
+func drainDigits(ch chan string) {
+	for {
+		select {
+		case <-ch:
+		default:
+			return
+		}
+	}
+}
+
+// raceInput waits for whichever of speech or DTMF arrives first.
+func raceInput(ctx context.Context, speech <-chan speechResult, digits <-chan string, maxDigits int) (Input, error) {
+	select {
+	case <-ctx.Done():
+		return Input{}, ctx.Err()
+	case d, ok := <-digits:
+		if ok {
+			return collectDigits(ctx, d, digits, maxDigits), nil
+		}
+		// No DTMF source; speech is the only option
+		res := <-speech
+		return speechInput(res)
+	case res := <-speech:
+		timer := time.NewTimer(dtmfGracePeriod)
+		defer timer.Stop()
+
+		select {
+		case d, ok := <-digits:
+			if ok {
+				return collectDigits(ctx, d, digits, maxDigits), nil
+			}
+		case <-timer.C:
+		case <-ctx.Done():
+		}
+		return speechInput(res)
+	}
+}
+
+// speechInput converts a listen result to an Input.
+func speechInput(res speechResult) (Input, error) {
+	if res.err != nil {
+		return Input{}, res.err
+	}
+	return Input{Type: InputSpeech, Value: res.text}, nil
+}
+
+// collectDigits gathers key presses starting with first until maxDigits are
+// collected, '#' is pressed, the user pauses, or the context ends.
+func collectDigits(ctx context.Context, first string, digits <-chan string, maxDigits int) Input {
+	if maxDigits <= 0 {
+		maxDigits = 1
+	}
+
+	var b strings.Builder
+	add := func(d string) bool {
+		if d == "#" {
+			return true
+		}
+		b.WriteString(d)
+		return b.Len() >= maxDigits
+	}
+
+	done := add(first)
+	timer := time.NewTimer(dtmfInterDigitTimeout)
+	defer timer.Stop()
+
+	for !done {
+		select {
+		case d, ok := <-digits:
+			if !ok {
+				done = true
+				continue
+			}
+			done = add(d)
+			timer.Reset(dtmfInterDigitTimeout)
+		case <-timer.C:
+			done = true
+		case <-ctx.Done():
+			done = true
+		}
+	}
+
+	return Input{Type: InputDTMF, Value: b.String()}
+}
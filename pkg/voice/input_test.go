@@ -0,0 +1,206 @@
+package voice
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/plexusone/omnivoice"
+	"github.com/plexusone/omnivoice-core/transport"
+)
+
+// digitsChan returns a channel pre-loaded with the given key presses.
+func digitsChan(digits ...string) chan string {
+	ch := make(chan string, len(digits))
+	for _, d := range digits {
+		ch <- d
+	}
+	return ch
+}
+
+func TestRaceInput(t *testing.T) {
+	tests := []struct {
+		name      string
+		speech    *speechResult
+		digits    []string
+		maxDigits int
+		want      Input
+	}{
+		{
+			name:   "speech only",
+			speech: &speechResult{text: "yes"},
+			want:   Input{Type: InputSpeech, Value: "yes"},
+		},
+		{
+			name:      "digits only",
+			digits:    []string{"1"},
+			maxDigits: 1,
+			want:      Input{Type: InputDTMF, Value: "1"},
+		},
+		{
+			name:      "both prefers dtmf",
+			speech:    &speechResult{text: "yes"},
+			digits:    []string{"1"},
+			maxDigits: 1,
+			want:      Input{Type: InputDTMF, Value: "1"},
+		},
+		{
+			name:      "stops at max digits",
+			digits:    []string{"1", "2", "3"},
+			maxDigits: 2,
+			want:      Input{Type: InputDTMF, Value: "12"},
+		},
+		{
+			name:      "stops at pound",
+			digits:    []string{"4", "2", "#", "9"},
+			maxDigits: 4,
+			want:      Input{Type: InputDTMF, Value: "42"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			speech := make(chan speechResult, 1)
+			if tt.speech != nil {
+				speech <- *tt.speech
+			}
+
+			got, err := raceInput(context.Background(), speech, digitsChan(tt.digits...), tt.maxDigits)
+			if err != nil {
+				t.Fatalf("raceInput() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("raceInput() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRaceInput_SpeechError(t *testing.T) {
+	wantErr := errors.New("stt failed")
+	speech := make(chan speechResult, 1)
+	speech <- speechResult{err: wantErr}
+
+	if _, err := raceInput(context.Background(), speech, nil, 1); !errors.Is(err, wantErr) {
+		t.Errorf("raceInput() error = %v, want %v", err, wantErr)
+	}
+}
+
+func TestNotifyDigits(t *testing.T) {
+	m := newTestManager(t)
+	state := &CallState{
+		ID:       "call-1",
+		Call:     &fakeCall{id: "CA123"},
+		digitsCh: make(chan string, digitsBufferSize),
+	}
+	m.calls[state.ID] = state
+
+	m.NotifyDigits("CA123", "12")
+	m.NotifyDigits("CA999", "3") // unknown call is ignored
+
+	got := collectDigits(context.Background(), <-state.digitsCh, state.digitsCh, 2)
+	if got.Value != "12" {
+		t.Errorf("digits = %q, want %q", got.Value, "12")
+	}
+	if len(state.digitsCh) != 0 {
+		t.Errorf("unexpected buffered digits: %d", len(state.digitsCh))
+	}
+}
+
+// streamConn is a media stream connection that reports its call.
+type streamConn struct {
+	transport.Connection
+
+	callSID string
+}
+
+func (c *streamConn) CallSID() string { return c.callSID }
+
+func TestOnStreamDTMF(t *testing.T) {
+	m := newTestManager(t)
+	state := &CallState{
+		ID:       "call-1",
+		Call:     &fakeCall{id: "CA123"},
+		digitsCh: make(chan string, digitsBufferSize),
+	}
+	m.calls[state.ID] = state
+
+	m.onStreamDTMF(&streamConn{callSID: "CA123"}, "7")
+
+	if got := <-state.digitsCh; got != "7" {
+		t.Errorf("digit = %q, want %q", got, "7")
+	}
+}
+
+// duplexConn is a call transport that takes our audio and plays the
+// caller's from out.
+type duplexConn struct {
+	*fakeConn
+
+	out io.Reader
+}
+
+func (c duplexConn) AudioOut() io.Reader { return c.out }
+
+func TestSpeakAndWaitDigits_RecordsOnlyWinner(t *testing.T) {
+	m := newTestManager(t)
+	m.config.EchoGuardMS = 0
+	m.ttsProvider = &fakeTTS{streams: [][]omnivoice.StreamChunk{
+		{{Audio: []byte{ulawSilence}, IsFinal: true}},
+	}}
+	stt := &fakeSTT{}
+	m.sttProvider = stt
+	conn := duplexConn{fakeConn: &fakeConn{}, out: strings.NewReader("")}
+	state := m.addCall(context.Background(), &fakeCall{id: "CA123", transport: conn}, "+15551234567", time.Now())
+
+	// The caller says something, then presses a key within the grace window
+	go func() {
+		for {
+			stt.mu.Lock()
+			if len(stt.streams) > 0 {
+				events := stt.streams[0]
+				stt.mu.Unlock()
+				events <- omnivoice.StreamEvent{Transcript: "yes", IsFinal: true}
+				close(events)
+				time.Sleep(50 * time.Millisecond)
+				m.NotifyDigits("CA123", "1")
+				return
+			}
+			stt.mu.Unlock()
+			time.Sleep(time.Millisecond)
+		}
+	}()
+
+	got, err := m.SpeakAndWaitDigits(context.Background(), state.ID, "Press 1 or say yes.", 1)
+	if err != nil {
+		t.Fatalf("SpeakAndWaitDigits() error = %v", err)
+	}
+	if want := (Input{Type: InputDTMF, Value: "1"}); got != want {
+		t.Errorf("SpeakAndWaitDigits() = %+v, want %+v", got, want)
+	}
+
+	var users []string
+	for _, turn := range state.Conversation {
+		if turn.Role == "user" {
+			users = append(users, turn.Content)
+		}
+	}
+	if len(users) != 1 || users[0] != "1" {
+		t.Errorf("user turns = %q, want only the key press", users)
+	}
+}
+
+func TestDrainDigits(t *testing.T) {
+	ch := make(chan string, digitsBufferSize)
+	ch <- "1"
+	ch <- "2"
+
+	drainDigits(ch)
+
+	if len(ch) != 0 {
+		t.Errorf("buffered digits after drain = %d, want 0", len(ch))
+	}
+}
@@ -0,0 +1,67 @@
+package voice
+
+import (
+	"bytes"
+	"context"
+	"time"
+)
+
+const (
+	// keepAliveFrame is how often silence is sent, matching the 20ms media
+	// frames Twilio uses.
+	keepAliveFrame = 20 * time.Millisecond
+
+	// ulawSilence is a mu-law sample of zero amplitude.
+	ulawSilence = 0xFF
+)
+
+// silenceFrame is one keepAliveFrame of mu-law silence.
+var silenceFrame = bytes.Repeat([]byte{ulawSilence}, ulawBytesPerSecond*int(keepAliveFrame/time.Millisecond)/1000)
+
+// startKeepAlive streams silence to the call whenever nothing is being
+// spoken, so the media stream is not dropped while the agent is busy. It
+// runs until stopKeepAlive is called.
+func (m *Manager) startKeepAlive(state *CallState) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	state.mu.Lock()
+	state.keepAliveCancel = cancel
+	state.mu.Unlock()
+
+	go keepAlive(ctx, state, keepAliveFrame)
+}
+
+// keepAlive sends a silence frame every interval until ctx is done. Frames
+// are skipped while speak holds the call's audio lock, so real speech
+// always takes over immediately.
+func keepAlive(ctx context.Context, state *CallState, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !state.audioMu.TryLock() {
+				continue
+			}
+			if transport := state.Call.Transport(); transport != nil {
+				_, _ = transport.AudioIn().Write(silenceFrame)
+			}
+			state.audioMu.Unlock()
+		}
+	}
+}
+
+// stopKeepAlive stops the call's silence stream, if any.
+func (cs *CallState) stopKeepAlive() {
+	cs.mu.Lock()
+	stop := cs.keepAliveCancel
+	cs.keepAliveCancel = nil
+	cs.mu.Unlock()
+
+	if stop != nil {
+		stop()
+	}
+}
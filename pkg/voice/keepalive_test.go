@@ -0,0 +1,56 @@
+package voice
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// runKeepAlive runs keepAlive for d and waits for it to stop.
+func runKeepAlive(state *CallState, d time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), d)
+	defer cancel()
+
+	done := make(chan struct{})
+	go func() {
+		keepAlive(ctx, state, time.Millisecond)
+		close(done)
+	}()
+	<-done
+}
+
+func TestKeepAlive_SendsSilence(t *testing.T) {
+	conn := &fakeConn{}
+	state := &CallState{Call: &fakeCall{transport: conn}}
+
+	runKeepAlive(state, 30*time.Millisecond)
+
+	audio := conn.audio.Bytes()
+	if len(audio) == 0 || len(audio)%len(silenceFrame) != 0 {
+		t.Fatalf("sent %d bytes, want whole silence frames", len(audio))
+	}
+	for _, b := range audio {
+		if b != ulawSilence {
+			t.Fatalf("sent sample 0x%02x, want silence", b)
+		}
+	}
+}
+
+func TestKeepAlive_PausesWhileSpeaking(t *testing.T) {
+	conn := &fakeConn{}
+	state := &CallState{Call: &fakeCall{transport: conn}}
+
+	state.audioMu.Lock()
+	runKeepAlive(state, 30*time.Millisecond)
+	state.audioMu.Unlock()
+
+	if n := conn.audio.Len(); n != 0 {
+		t.Errorf("sent %d bytes while speaking, want 0", n)
+	}
+}
+
+func TestSilenceFrame(t *testing.T) {
+	if got, want := len(silenceFrame), 160; got != want {
+		t.Errorf("len(silenceFrame) = %d, want %d (20ms at 8 kHz)", got, want)
+	}
+}
@@ -0,0 +1,134 @@
+package voice
+
+import (
+	"strings"
+	"unicode"
+)
+
+// contextKeywordTurns is how many recent assistant turns are searched for
+// STT context keywords.
+const contextKeywordTurns = 3
+
+// contextKeywordMinLen is the shortest plain word boosted from the
+// conversation; shorter ones are too common to help recognition.
+const contextKeywordMinLen = 5
+
+// commonWords are words too common in speech to be worth boosting.
+var commonWords = map[string]bool{
+	"about": true, "actually": true, "after": true, "again": true,
+	"already": true, "another": true, "anything": true, "around": true,
+	"because": true, "before": true, "believe": true, "better": true,
+	"called": true, "change": true, "changes": true, "check": true,
+	"could": true, "every": true, "everything": true, "finished": true,
+	"first": true, "going": true, "great": true, "little": true,
+	"maybe": true, "minute": true, "minutes": true, "ok": true, "other": true,
+	"people": true, "please": true, "pretty": true, "really": true,
+	"right": true, "second": true, "seconds": true, "should": true,
+	"something": true, "sound": true, "sounds": true, "start": true,
+	"still": true, "thanks": true, "their": true, "there": true,
+	"these": true, "thing": true, "things": true, "think": true,
+	"those": true, "through": true, "today": true, "wanted": true,
+	"where": true, "whether": true, "which": true, "working": true,
+	"would": true,
+}
+
+// keywords returns the terms to boost in speech recognition for the call's
+// next reply: the pronunciation dictionary's terms and, with
+// STTContextKeywords, terms from recent assistant turns.
+func (m *Manager) keywords(state *CallState) []string {
+	keywords := m.pronunciation.sttKeywords()
+	if !m.config.STTContextKeywords || state == nil {
+		return keywords
+	}
+	recent := state.contextKeywords(contextKeywordTurns, m.config.STTContextKeywordMax)
+	if len(recent) == 0 {
+		return keywords
+	}
+
+	seen := make(map[string]bool, len(keywords))
+	for _, k := range keywords {
+		seen[strings.ToLower(k)] = true
+	}
+	out := append([]string(nil), keywords...)
+	for _, k := range recent {
+		if !seen[strings.ToLower(k)] {
+			out = append(out, k)
+		}
+	}
+	return out
+}
+
+// contextKeywords returns up to max distinctive terms from the last turns
+// assistant turns, newest first, so the recognizer is primed for words the
+// user is likely to repeat. Technical-looking terms (acronyms, mixed case,
+// digits, hyphens) come before other long words.
+func (cs *CallState) contextKeywords(turns, max int) []string {
+	cs.mu.RLock()
+	var texts []string
+	for i := len(cs.Conversation) - 1; i >= 0 && len(texts) < turns; i-- {
+		if turn := cs.Conversation[i]; turn.Role == "assistant" {
+			texts = append(texts, turn.Content)
+		}
+	}
+	cs.mu.RUnlock()
+
+	var technical, plain []string
+	seen := make(map[string]bool)
+	for _, text := range texts {
+		for _, word := range strings.FieldsFunc(stripAudioTags(text), isKeywordSeparator) {
+			word = strings.Trim(word, "-_.")
+			key := strings.ToLower(word)
+			if word == "" || seen[key] || commonWords[key] {
+				continue
+			}
+			switch {
+			case isTechnicalTerm(word):
+				technical = append(technical, word)
+			case len(word) >= contextKeywordMinLen:
+				plain = append(plain, word)
+			default:
+				continue
+			}
+			seen[key] = true
+		}
+	}
+
+	out := append(technical, plain...)
+	if len(out) > max {
+		out = out[:max]
+	}
+	return out
+}
+
+// isKeywordSeparator splits text into words, keeping terms such as
+// "gRPC-web", "snake_case", and "v1.2" whole.
+func isKeywordSeparator(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-' && r != '_' && r != '.'
+}
+
+// isTechnicalTerm reports whether word looks like a name or code rather
+// than an everyday word: an acronym, mixed case, or letters with digits or
+// joiners.
+func isTechnicalTerm(word string) bool {
+	var letters, upper, digits int
+	joined := false
+	for i, r := range word {
+		switch {
+		case unicode.IsUpper(r):
+			letters++
+			if i > 0 {
+				upper++
+			}
+		case unicode.IsLetter(r):
+			letters++
+		case unicode.IsDigit(r):
+			digits++
+		default:
+			joined = true
+		}
+	}
+	if letters == 0 {
+		return false
+	}
+	return upper > 0 || digits > 0 || joined
+}
@@ -0,0 +1,45 @@
+package voice
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestContextKeywords(t *testing.T) {
+	state := &CallState{Conversation: []ConversationTurn{
+		{Role: "assistant", Content: "Earlier I mentioned Kubernetes."},
+		{Role: "user", Content: "Tell me about Postgres."},
+		{Role: "assistant", Content: "OK, the JWT expired. Should I rotate the refresh token via the gRPC-web proxy?"},
+	}}
+
+	got := state.contextKeywords(3, 10)
+	want := []string{"JWT", "gRPC-web", "expired", "rotate", "refresh", "token", "proxy", "Earlier", "mentioned", "Kubernetes"}
+	if !slices.Equal(got, want) {
+		t.Errorf("contextKeywords() = %q, want %q", got, want)
+	}
+
+	if got := state.contextKeywords(3, 2); !slices.Equal(got, []string{"JWT", "gRPC-web"}) {
+		t.Errorf("contextKeywords() capped = %q, want the technical terms first", got)
+	}
+	if got := state.contextKeywords(1, 10); slices.Contains(got, "Kubernetes") {
+		t.Errorf("contextKeywords() = %q, want only the last turn", got)
+	}
+}
+
+func TestKeywords(t *testing.T) {
+	m := newTestManager(t)
+	m.pronunciation = newPronunciation(map[string]string{"JWT": "jot"})
+	state := &CallState{Conversation: []ConversationTurn{
+		{Role: "assistant", Content: "The JWT expired on the staging server."},
+	}}
+
+	if got := m.keywords(state); !slices.Equal(got, []string{"JWT"}) {
+		t.Errorf("keywords() without context keywords = %q, want the dictionary only", got)
+	}
+
+	m.config.STTContextKeywords = true
+	m.config.STTContextKeywordMax = 10
+	if got, want := m.keywords(state), []string{"JWT", "expired", "staging", "server"}; !slices.Equal(got, want) {
+		t.Errorf("keywords() = %q, want %q", got, want)
+	}
+}
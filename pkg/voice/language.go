@@ -0,0 +1,163 @@
+package voice
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// minLanguageHits is how many of a language's common words a reply needs
+// before it is taken to be in that language, so a borrowed word or a name
+// ("gracias", "Oui Bistro") is not enough.
+const minLanguageHits = 2
+
+// languageTagPattern matches the shape of a BCP-47 language tag, such as
+// "es" or "pt-BR". Whether the STT provider supports it is up to the
+// provider.
+var languageTagPattern = regexp.MustCompile(`^[A-Za-z]{2,3}(-[A-Za-z0-9]{2,8})*$`)
+
+// languageWords are common words by base language code, for telling which
+// language a reply is in. A word appears in at most one list, so words
+// shared between languages ("no", "la", "non") count for none of them.
+var languageWords = map[string][]string{
+	"en": {
+		"the", "and", "is", "are", "you", "what", "this", "that", "with", "have",
+		"it's", "i'm", "don't", "can't", "would", "could", "there", "yes", "okay",
+	},
+	"es": {
+		"el", "los", "las", "y", "estoy", "pero", "sí", "usted", "qué", "cómo",
+		"muy", "gracias", "hola", "bueno", "también", "ahora", "quiero", "tengo",
+		"eso", "esto", "hay", "puedo",
+	},
+	"pt": {
+		"não", "você", "sim", "obrigado", "obrigada", "muito", "estou", "isso",
+		"isto", "agora", "também", "eu", "uma", "com", "mas", "ele", "ela",
+		"tenho", "quero", "olá", "bom",
+	},
+	"fr": {
+		"les", "est", "et", "je", "vous", "oui", "merci", "pas", "c'est", "avec",
+		"mais", "très", "bonjour", "suis", "une", "des", "du", "qui", "nous", "j'ai",
+	},
+	"de": {
+		"der", "das", "und", "ist", "ich", "nicht", "ja", "nein", "danke", "wir",
+		"mit", "auch", "aber", "bitte", "habe", "sehr", "ein", "eine", "sie",
+	},
+	"it": {
+		"gli", "che", "è", "sono", "sì", "grazie", "ciao", "anche", "perché",
+		"questo", "molto", "io", "della", "bene", "voglio", "ho",
+	},
+}
+
+// sttLanguage returns the language the call's replies are transcribed in:
+// its override (see Manager.SetCallLanguage) or the configured one.
+func (m *Manager) sttLanguage(state *CallState) string {
+	if state == nil {
+		return m.config.STTLanguage
+	}
+	state.mu.RLock()
+	defer state.mu.RUnlock()
+	if state.language != "" {
+		return state.language
+	}
+	return m.config.STTLanguage
+}
+
+// SetCallLanguage switches the language replies are transcribed in for
+// the rest of a call, e.g. when the user answers in Spanish on a call
+// configured for English. The next reply is transcribed in the new
+// language; a persistent transcription stream is reopened for it. The TTS
+// voice is unchanged, so switch it too with SetCallVoice if needed.
+func (m *Manager) SetCallLanguage(ctx context.Context, callID, language string) (string, error) {
+	state := m.tenantCall(ctx, callID)
+	if state == nil {
+		return "", m.callNotFound(callID)
+	}
+	if !languageTagPattern.MatchString(language) {
+		return "", fmt.Errorf("%w: %q (use a BCP-47 code such as \"es\" or \"pt-BR\")", ErrInvalidLanguage, language)
+	}
+
+	state.mu.Lock()
+	state.language = language
+	state.languageMismatch = ""
+	listening := state.listening
+	state.mu.Unlock()
+
+	// A reply already being listened for is finished in the old language;
+	// sttSession reopens the stream for the next one
+	if !listening {
+		state.closeSTTSession()
+	}
+	return language, nil
+}
+
+// LanguageMismatch returns the base language code ("es", "fr", ...) the
+// latest user reply on a call seems to be in when it is not the call's STT
+// language, or "" if it matches, detection is off (see
+// Config.LanguageDetection), or the call is unknown. It is only a hint for
+// the agent, which decides whether to call set_language.
+func (m *Manager) LanguageMismatch(callID string) string {
+	state := m.getCall(callID)
+	if state == nil {
+		return ""
+	}
+
+	state.mu.RLock()
+	defer state.mu.RUnlock()
+	return state.languageMismatch
+}
+
+// checkLanguage records whether a user reply seems to be in a different
+// language than the call's STT language (see Manager.LanguageMismatch).
+// STT providers fit speech in another language to the configured one, so
+// a reply that comes back as mostly another language's common words is a
+// strong sign the user switched.
+func (m *Manager) checkLanguage(state *CallState, text string) {
+	if !m.config.LanguageDetection {
+		return
+	}
+
+	mismatch := ""
+	want := baseLanguage(m.sttLanguage(state))
+	if got := detectLanguage(text); got != "" && want != "" && got != want {
+		mismatch = got
+	}
+
+	state.mu.Lock()
+	state.languageMismatch = mismatch
+	state.mu.Unlock()
+}
+
+// detectLanguage returns the base code of the language whose common words
+// text uses most, or "" if no language has at least minLanguageHits of
+// them or two languages tie.
+func detectLanguage(text string) string {
+	words := normalizeWords(text)
+	best, bestHits, tied := "", 0, false
+	for lang, common := range languageWords {
+		hits := 0
+		for _, w := range words {
+			if slices.Contains(common, w) {
+				hits++
+			}
+		}
+		switch {
+		case hits > bestHits:
+			best, bestHits, tied = lang, hits, false
+		case hits == bestHits && hits > 0:
+			tied = true
+		}
+	}
+	if bestHits < minLanguageHits || tied {
+		return ""
+	}
+	return best
+}
+
+// baseLanguage returns the lowercased language subtag of a BCP-47 tag, so
+// "en-US" gives "en".
+func baseLanguage(tag string) string {
+	base, _, _ := strings.Cut(tag, "-")
+	return strings.ToLower(base)
+}
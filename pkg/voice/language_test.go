@@ -0,0 +1,131 @@
+package voice
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDetectLanguage(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"Yes, that is what I meant.", "en"},
+		{"No, gracias. Estoy muy ocupado ahora.", "es"},
+		{"Oui, je suis là. Merci!", "fr"},
+		{"Ja, das ist gut. Danke.", "de"},
+		{"Sì, grazie, va bene.", "it"},
+		{"Não, obrigado. Estou bem.", "pt"},
+		{"Gracias", ""}, // one borrowed word is not enough
+		{"Deploy it to prod.", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := detectLanguage(tt.text); got != tt.want {
+			t.Errorf("detectLanguage(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestLanguageWords_Disjoint(t *testing.T) {
+	seen := map[string]string{}
+	for lang, words := range languageWords {
+		for _, w := range words {
+			if other, ok := seen[w]; ok {
+				t.Errorf("%q is listed for both %s and %s", w, other, lang)
+			}
+			seen[w] = lang
+		}
+	}
+}
+
+func TestLanguageMismatch(t *testing.T) {
+	m := newTestManager(t)
+	m.config.LanguageDetection = true
+	state := m.addCall(context.Background(), &fakeCall{}, "+15551234567", time.Now())
+
+	m.checkLanguage(state, "Lo siento, no hablo inglés. ¿Qué quiere usted?")
+	if got := m.LanguageMismatch(state.ID); got != "es" {
+		t.Fatalf("LanguageMismatch() = %q, want es", got)
+	}
+
+	// Switching clears the hint, and Spanish replies then match
+	if _, err := m.SetCallLanguage(context.Background(), state.ID, "es-MX"); err != nil {
+		t.Fatalf("SetCallLanguage() error = %v", err)
+	}
+	if got := m.LanguageMismatch(state.ID); got != "" {
+		t.Errorf("LanguageMismatch() after switching = %q, want none", got)
+	}
+	m.checkLanguage(state, "Sí, ahora estoy bien, gracias.")
+	if got := m.LanguageMismatch(state.ID); got != "" {
+		t.Errorf("LanguageMismatch() for a Spanish reply = %q, want none", got)
+	}
+
+	m.checkLanguage(state, "Yes, and this is what I have.")
+	if got := m.LanguageMismatch(state.ID); got != "en" {
+		t.Errorf("LanguageMismatch() for an English reply = %q, want en", got)
+	}
+}
+
+func TestLanguageMismatch_Off(t *testing.T) {
+	m := newTestManager(t)
+	state := m.addCall(context.Background(), &fakeCall{}, "+15551234567", time.Now())
+
+	m.checkLanguage(state, "Lo siento, no hablo inglés. ¿Qué quiere usted?")
+	if got := m.LanguageMismatch(state.ID); got != "" {
+		t.Errorf("LanguageMismatch() with detection off = %q, want none", got)
+	}
+}
+
+func TestSetCallLanguage_ReopensPersistentStream(t *testing.T) {
+	m := newTestManager(t)
+	stt := &fakeSTT{}
+	m.sttProvider = stt
+	transport := &audioSource{r: strings.NewReader("")}
+	state := m.addCall(context.Background(), &fakeCall{transport: transport}, "+15551234567", time.Now())
+
+	first, err := m.sttSession(state, transport)
+	if err != nil {
+		t.Fatalf("sttSession() error = %v", err)
+	}
+
+	// Switching mid-reply keeps the stream until the reply is done
+	state.listening = true
+	if _, err := m.SetCallLanguage(context.Background(), state.ID, "fr"); err != nil {
+		t.Fatalf("SetCallLanguage() error = %v", err)
+	}
+	if state.stt != first {
+		t.Fatal("stream closed while listening")
+	}
+	state.listening = false
+
+	second, err := m.sttSession(state, transport)
+	if err != nil {
+		t.Fatalf("sttSession() error = %v", err)
+	}
+	if second == first || len(stt.configs) != 2 {
+		t.Fatalf("opened %d streams, want a reconnect for the new language", len(stt.configs))
+	}
+	if stt.configs[0].Language != "en-US" || stt.configs[1].Language != "fr" {
+		t.Errorf("languages = %q, %q; want en-US, then fr", stt.configs[0].Language, stt.configs[1].Language)
+	}
+	state.closeSTTSession()
+}
+
+func TestSetCallLanguage_Invalid(t *testing.T) {
+	m := newTestManager(t)
+	state := m.addCall(context.Background(), &fakeCall{}, "+15551234567", time.Now())
+
+	for _, lang := range []string{"", "spanish", "es_ES", "e"} {
+		if _, err := m.SetCallLanguage(context.Background(), state.ID, lang); !errors.Is(err, ErrInvalidLanguage) {
+			t.Errorf("SetCallLanguage(%q) error = %v, want ErrInvalidLanguage", lang, err)
+		}
+	}
+	if _, err := m.SetCallLanguage(context.Background(), "nope", "es"); !errors.Is(err, ErrCallNotFound) {
+		t.Errorf("SetCallLanguage() on an unknown call error = %v, want ErrCallNotFound", err)
+	}
+}
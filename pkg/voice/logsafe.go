@@ -0,0 +1,30 @@
+package voice
+
+import (
+	"strings"
+	"unicode"
+)
+
+// maxLogValueLen bounds a request value written to the log. Values Twilio
+// sends, such as call SIDs and statuses, are far shorter.
+const maxLogValueLen = 256
+
+// logSafe returns a value taken from a webhook request, ready to log. Control
+// and format characters and line and paragraph separators, which could
+// forge or hide log lines, are removed, invalid UTF-8 is replaced, and long
+// values are cut short. Only the logged copy is changed; lookups use the
+// value as received.
+func logSafe(s string) string {
+	// Map also replaces invalid UTF-8 with U+FFFD
+	s = strings.Map(func(r rune) rune {
+		if unicode.In(r, unicode.Cc, unicode.Cf, unicode.Zl, unicode.Zp) {
+			return -1
+		}
+		return r
+	}, s)
+	if len(s) > maxLogValueLen {
+		// Drop a rune the cut splits
+		s = strings.ToValidUTF8(s[:maxLogValueLen], "") + "..."
+	}
+	return s
+}
@@ -0,0 +1,45 @@
+package voice
+
+import (
+	"strings"
+	"testing"
+	"unicode"
+	"unicode/utf8"
+)
+
+func TestLogSafe(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"plain", "CA1234567890abcdef", "CA1234567890abcdef"},
+		{"line breaks", "CA123\r\nINFO forged entry", "CA123INFO forged entry"},
+		{"escape sequence", "CA123\x1b[2K", "CA123[2K"},
+		{"separators", "CA123\u2028\u2029x", "CA123x"},
+		{"bidi override", "CA\u202e321", "CA321"},
+		{"invalid UTF-8", "CA\xff123", "CA\uFFFD123"},
+		{"non-ASCII", "Zoë", "Zoë"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := logSafe(tt.in); got != tt.want {
+				t.Errorf("logSafe(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+
+	long := logSafe(strings.Repeat("é", maxLogValueLen))
+	if !strings.HasSuffix(long, "...") || len(long) > maxLogValueLen+3 || !utf8.ValidString(long) {
+		t.Errorf("logSafe(long) = %d bytes, want it cut to %d plus an ellipsis", len(long), maxLogValueLen)
+	}
+}
+
+// unsafeForLog reports whether s has characters logSafe removes, or is
+// longer than it allows.
+func unsafeForLog(s string) bool {
+	return !utf8.ValidString(s) || len(s) > maxLogValueLen+len("...") ||
+		strings.ContainsFunc(s, func(r rune) bool {
+			return unicode.In(r, unicode.Cc, unicode.Cf, unicode.Zl, unicode.Zp)
+		})
+}
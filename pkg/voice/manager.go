@@ -3,10 +3,14 @@ package voice
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
+	"maps"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/plexusone/omnivoice"
@@ -26,6 +30,111 @@ type CallState struct {
 	Conversation    []ConversationTurn
 	LastUserMessage string
 	mu              sync.RWMutex
+
+	// statusCh receives status changes pushed by the provider's status
+	// callbacks (see Manager.NotifyStatus).
+	statusCh chan omnivoice.CallStatus
+
+	// digitsCh receives DTMF key presses pushed by Manager.NotifyDigits.
+	digitsCh chan string
+
+	// speaking is set while TTS audio is being sent; spokeAt is when the
+	// last playback finished, estimated from the audio sent. Both guard
+	// against transcribing our own echo.
+	speaking bool
+	spokeAt  time.Time
+
+	// listening is set while waiting for the user's reply; status is the
+	// latest status pushed by the provider. Both are reported by CallStatus.
+	listening bool
+	status    omnivoice.CallStatus
+
+	// answeredBy is the answering machine detection result, delivered
+	// asynchronously by Manager.NotifyAnsweredBy and also sent on amdCh.
+	answeredBy AnsweredBy
+	amdCh      chan AnsweredBy
+
+	// acceptCh receives the callee's answer to the accept prompt when
+	// RequireAccept is enabled (see Manager.NotifyAccepted).
+	acceptCh chan bool
+
+	// sink, if set, receives each conversation turn as it is added.
+	sink TranscriptSink
+
+	// voiceID overrides the configured TTS voice for this call (see
+	// Manager.SetCallVoice).
+	voiceID string
+
+	// language overrides the configured STT language for this call (see
+	// Manager.SetCallLanguage); languageMismatch is the language the
+	// latest reply seemed to be in instead (see Manager.LanguageMismatch).
+	language         string
+	languageMismatch string
+
+	// metrics holds latency and audio measurements (see Metrics).
+	metrics callMetrics
+
+	// to is the number dialed; tenant is the tenant it was placed for, or
+	// "" for the base config.
+	to     string
+	tenant string
+
+	// metadata is the caller's context for the call (see
+	// WithCallMetadata). It is set when the call is placed and not changed.
+	metadata map[string]string
+
+	// recordingConsent is set once the callee heard the recording
+	// announcement (see Manager.announceRecording).
+	recordingConsent bool
+
+	// speakQueue orders messages sent while another is playing.
+	speakQueue speakQueue
+
+	// audioMu is held while speaking so keep-alive silence never
+	// interleaves with speech; keepAliveCancel stops the silence stream.
+	audioMu         sync.Mutex
+	keepAliveCancel context.CancelFunc
+
+	// stt is the transcription stream reused across turns when
+	// STTPersistConnection is set.
+	stt *sttSession
+
+	// opening is the background first turn of a call placed with
+	// InitiateCallAsync.
+	opening *openingTurn
+
+	// prefetch is the next message, synthesized ahead of time (see
+	// Manager.Prefetch).
+	prefetch *prefetch
+
+	// listeningMuted stops replies from being transcribed (see
+	// Manager.SetListeningMuted).
+	listeningMuted bool
+
+	// pauseAverage is the moving average of the caller's pauses, once
+	// pausesObserved (see Manager.endOfSpeechSilence).
+	pauseAverage   time.Duration
+	pausesObserved bool
+
+	// droppedAt is when the answered call ended without EndCall, while it
+	// is kept for Manager.ResumeCall; retired is set once it is removed
+	// after that, by ResumeCall, EndCall, or the window expiring.
+	droppedAt time.Time
+	retired   bool
+
+	// terminalAt is when the sweeper first saw the call's status terminal
+	// (see Manager.sweep).
+	terminalAt time.Time
+
+	// attempt is which dial of InitiateCall placed the call, from 1, and
+	// redial is set when another dial follows if it is not answered (see
+	// Manager.dialUntilAnswered). Both are set when the call is placed.
+	attempt int
+	redial  bool
+
+	// audioDump receives the call's audio when Config.AudioDumpDir is set.
+	// It is set when the call is placed and not changed.
+	audioDump *audioDump
 }
 
 // ConversationTurn represents a single turn in the conversation.
@@ -33,20 +142,79 @@ type ConversationTurn struct {
 	Role      string // "assistant" or "user"
 	Content   string
 	Timestamp time.Time
+	Sentiment Sentiment // user turns only, when analysis is on
 }
 
-// AddTurn adds a conversation turn.
+// AddTurn adds a conversation turn and sends it to the transcript sink, if
+// any.
 func (cs *CallState) AddTurn(role, content string) {
+	cs.addTurn(ConversationTurn{Role: role, Content: content})
+}
+
+// addTurn records turn, stamping it with the current time.
+func (cs *CallState) addTurn(turn ConversationTurn) {
+	turn.Timestamp = time.Now()
+
+	cs.mu.Lock()
+	cs.Conversation = append(cs.Conversation, turn)
+	if turn.Role == "user" {
+		cs.LastUserMessage = turn.Content
+	}
+	cs.mu.Unlock()
+
+	if cs.sink != nil {
+		cs.sink.Send(TranscriptEntry{
+			CallID:    cs.ID,
+			Role:      turn.Role,
+			Content:   turn.Content,
+			Timestamp: turn.Timestamp,
+			Sentiment: turn.Sentiment,
+			Metadata:  cs.metadata,
+		})
+	}
+}
+
+// setSpeaking marks the start or end of TTS playback.
+func (cs *CallState) setSpeaking(speaking bool) {
 	cs.mu.Lock()
 	defer cs.mu.Unlock()
-	cs.Conversation = append(cs.Conversation, ConversationTurn{
-		Role:      role,
-		Content:   content,
-		Timestamp: time.Now(),
-	})
-	if role == "user" {
-		cs.LastUserMessage = content
+	cs.speaking = speaking
+	if !speaking {
+		cs.spokeAt = time.Now()
+	}
+}
+
+// finishPlayback marks the end of TTS playback at playedUntil, the estimated
+// time the provider finishes playing the audio it was sent, or now if that
+// has already passed.
+func (cs *CallState) finishPlayback(playedUntil time.Time) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	cs.speaking = false
+	cs.spokeAt = time.Now()
+	if playedUntil.After(cs.spokeAt) {
+		cs.spokeAt = playedUntil
+	}
+}
+
+// playbackEnd returns when the last playback finished (zero if we have not
+// spoken).
+func (cs *CallState) playbackEnd() time.Time {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+	return cs.spokeAt
+}
+
+// inEchoWindow reports whether speech heard at t may be an echo of our own
+// playback: we are speaking, or playback ended (by estimate) less than guard
+// before t.
+func (cs *CallState) inEchoWindow(t time.Time, guard time.Duration) bool {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+	if cs.speaking {
+		return true
 	}
+	return !cs.spokeAt.IsZero() && t.Before(cs.spokeAt.Add(guard))
 }
 
 // Duration returns the call duration.
@@ -58,58 +226,269 @@ func (cs *CallState) Duration() time.Duration {
 type Manager struct {
 	config *config.Config
 
-	// omnivoice providers (using batteries-included registry)
+	// omnivoice providers (using batteries-included registry), set by
+	// Initialize. Read them through the current* accessors, since a
+	// re-initialization swaps them under providersMu.
 	callSystem  omnivoice.CallSystem
 	smsProvider callsystem.SMSProvider // Optional, set if callSystem implements SMSProvider
 	ttsProvider omnivoice.TTSProvider
 	sttProvider omnivoice.STTStreamingProvider
+	providersMu sync.RWMutex
+
+	// Audio codec negotiated with the phone provider's media stream
+	codec codec
+
+	// Tenants of a hosted deployment, nil without tenants
+	tenants *config.TenantStore
 
 	// Active calls
 	calls   map[string]*CallState
 	callsMu sync.RWMutex
 
-	// Call counter for generating IDs
+	// Call ID generator (see SetIDGenerator)
+	newCallID IDGenerator
+
+	// Counter for generating schedule IDs
 	callCounter int
 	counterMu   sync.Mutex
 
-	// Public URL for webhooks (set after ngrok starts)
+	// Public URL for webhooks (set by Initialize, guarded by providersMu)
 	publicURL string
+
+	// Serializes Initialize
+	initializeMu sync.Mutex
+
+	// Why the last Initialize failed, reported by calls until one succeeds
+	initErr error
+	initMu  sync.RWMutex
+
+	// Daily window with no calls
+	quietHours config.QuietHours
+
+	// Live transcript output, if configured
+	transcriptSink TranscriptSink
+
+	// Most recent unanswered outbound call
+	missed missedCalls
+
+	// Active conferences, keyed by the assistant's call ID. pendingBridge
+	// is the conference whose bridge leg has not arrived yet; starts are
+	// serialized by conferenceStartMu so there is at most one.
+	conferences       map[string]*ConferenceState
+	pendingBridge     *ConferenceState
+	conferencesMu     sync.RWMutex
+	conferenceStartMu sync.Mutex
+
+	// Recent initiate_call idempotency keys
+	dials dialKeys
+
+	// Calls held until confirmed (RequireConfirmation)
+	pending pendingCalls
+
+	// Async calls that did not connect
+	failed failedCalls
+
+	// Total time Close waits for active calls to hang up
+	closeTimeout time.Duration
+
+	// Sentiment analysis of user replies, if enabled
+	sentiment SentimentAnalyzer
+
+	// Spoken forms and STT keywords for domain terms, if configured
+	pronunciation *pronunciation
+
+	// TwiML that connects answered calls to the media stream, if
+	// configured (see renderTwiML)
+	twimlTemplate string
+
+	// Synthesized audio of recent messages, if enabled
+	ttsCache *ttsCache
+
+	// The TTS provider's voices, once listed
+	voices voiceList
+
+	// Do-not-disturb: no calls are placed while set
+	dnd atomic.Bool
+
+	// Calls placed in the last 24 hours
+	budget callBudget
+
+	// In-flight call-ended webhook posts
+	hooks sync.WaitGroup
+
+	// Called when the user starts speaking (see OnUserStartedSpeaking)
+	speechStart speechStartHooks
+
+	// Reaps abandoned calls from the first Initialize until Close, which
+	// closes closed
+	sweeper   sync.Once
+	closed    chan struct{}
+	closeOnce sync.Once
+
+	// Scheduled calls, persisted to store when set. Timers are only started
+	// once Initialize has run (schedulesReady); ran records what each
+	// schedule did when it fired.
+	store          CallStore
+	schedules      map[string]*scheduleEntry
+	schedulesReady bool
+	ran            map[string]scheduleRun
+	schedulesMu    sync.Mutex
 }
 
 // New creates a new call manager.
 func New(cfg *config.Config) (*Manager, error) {
+	quietHours, err := config.ParseQuietHours(cfg.QuietHours)
+	if err != nil {
+		return nil, err
+	}
+
 	m := &Manager{
-		config: cfg,
-		calls:  make(map[string]*CallState),
+		config:       cfg,
+		closeTimeout: defaultCloseTimeout,
+		newCallID:    NewUUID,
+		calls:        make(map[string]*CallState),
+		quietHours:   quietHours,
+		schedules:    make(map[string]*scheduleEntry),
+		ran:          make(map[string]scheduleRun),
+		conferences:  make(map[string]*ConferenceState),
+		codec:        negotiateCodec(cfg.PhoneProvider, cfg.PreferredCodec),
+		tenants:      config.NewTenantStore(cfg),
+		closed:       make(chan struct{}),
+	}
+	if cfg.PreferredCodec != "" && m.codec.name != cfg.PreferredCodec {
+		slog.Warn("preferred codec not supported by the phone provider; using mu-law",
+			"codec", cfg.PreferredCodec,
+			"provider", cfg.PhoneProvider,
+		)
+	}
+	if cfg.TTSCache {
+		m.ttsCache = newTTSCache(cfg.TTSCacheMaxBytes)
+	}
+	if cfg.TrimSilence && !m.codec.sampled {
+		slog.Warn("silence trimming is not supported with this codec; audio is sent untrimmed", "codec", m.codec.name)
+	}
+
+	if cfg.TranscriptSink != "" {
+		m.transcriptSink, err = NewTranscriptSink(cfg.TranscriptSink)
+		if err != nil {
+			return nil, err
+		}
+		if roles := m.transcriptRoles(); roles.renamed() {
+			m.transcriptSink = renamingSink{TranscriptSink: m.transcriptSink, roles: roles}
+		}
+	}
+
+	m.sentiment, err = NewSentimentAnalyzer(cfg.Sentiment)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.PronunciationDict != "" {
+		m.pronunciation, err = loadPronunciation(cfg.PronunciationDict)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.TwiMLTemplate != "" {
+		m.twimlTemplate, err = loadTwiMLTemplate(cfg.TwiMLTemplate)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	return m, nil
 }
 
 // Initialize sets up the omnivoice providers using the batteries-included registry.
-// Call this after ngrok is started and publicURL is known.
+// Call this after ngrok is started and publicURL is known. If it fails,
+// calls report the error until it succeeds. It can be called again, for
+// example to retry after a provider outage; providers created by a failed
+// attempt are closed first.
 func (m *Manager) Initialize(publicURL string) error {
-	m.publicURL = publicURL
+	m.initializeMu.Lock()
+	defer m.initializeMu.Unlock()
+
+	m.startSweeper()
+	err := m.initProviders(publicURL)
+
+	m.initMu.Lock()
+	m.initErr = err
+	m.initMu.Unlock()
+	return err
+}
+
+// InitError returns why the last Initialize failed, or nil if it succeeded
+// or has not run.
+func (m *Manager) InitError() error {
+	m.initMu.RLock()
+	defer m.initMu.RUnlock()
+	return m.initErr
+}
+
+// notInitialized returns the error for a call attempted without working
+// providers, explaining why they are missing.
+func (m *Manager) notInitialized() error {
+	if err := m.InitError(); err != nil {
+		return fmt.Errorf("%w: voice providers failed to start: %w", ErrNotInitialized, err)
+	}
+	return fmt.Errorf("%w; call Initialize() first", ErrNotInitialized)
+}
+
+// initProviders creates the call system and TTS and STT providers. They
+// are published together once all of them are created, so calls never see
+// a half-initialized manager.
+func (m *Manager) initProviders(publicURL string) (err error) {
+	// A new public URL, e.g. after a server restart on a new tunnel, needs
+	// new providers since the old call system sends webhooks to the old
+	// URL. Calls in progress stream there too, so they are ended first.
+	if m.currentCallSystem() != nil {
+		m.reset()
+	}
 
 	// Create CallSystem provider using registry-based lookup
 	// Supports "twilio" (default) or "telnyx" based on PhoneProvider config
-	cs, err := omnivoice.GetCallSystemProvider(
-		m.config.PhoneProvider,
+	csOpts := []omnivoice.ProviderOption{
 		omnivoice.WithAccountSID(m.config.PhoneAccountSID),
 		omnivoice.WithAuthToken(m.config.PhoneAuthToken),
 		omnivoice.WithPhoneNumber(m.config.PhoneNumber),
-		omnivoice.WithWebhookURL(publicURL+"/media-stream"),
-	)
+		omnivoice.WithWebhookURL(publicURL + MediaStreamPath),
+	}
+	// Connect through the configured Twilio region and edge for lower latency
+	if m.config.TwilioRegion != "" {
+		csOpts = append(csOpts, omnivoice.WithRegion(m.config.TwilioRegion))
+	}
+	if m.config.TwilioEdge != "" {
+		csOpts = append(csOpts, omnivoice.WithExtension("edge", m.config.TwilioEdge))
+	}
+	// Ask the transport to open media streams with a codec other than
+	// mu-law, the default everywhere
+	if m.codec != codecMulaw {
+		csOpts = append(csOpts, omnivoice.WithExtension("codec", m.codec.name))
+	}
+	cs, err := omnivoice.GetCallSystemProvider(m.config.PhoneProvider, csOpts...)
 	if err != nil {
 		return fmt.Errorf("failed to create callsystem: %w", err)
 	}
-	m.callSystem = cs
 
-	// Check if the call system supports SMS
-	if smsProvider, ok := cs.(callsystem.SMSProvider); ok {
-		m.smsProvider = smsProvider
+	// Close the call system if a later provider fails, so a retry starts clean
+	defer func() {
+		if err != nil {
+			if closeErr := closeCallSystem(cs); closeErr != nil {
+				slog.Warn("failed to close call system", "error", closeErr)
+			}
+		}
+	}()
+
+	// Key presses arrive on the media streams; the transport reads them
+	// for us and they are fed to speak_and_wait_digits
+	if tp, ok := cs.(*twiliosystem.Provider); ok && tp.Transport() != nil {
+		tp.Transport().OnDTMF(m.onStreamDTMF)
 	}
 
+	// Check if the call system supports SMS
+	smsProvider, _ := cs.(callsystem.SMSProvider)
+
 	// Create TTS provider using registry-based lookup
 	ttsProvider, err := omnivoice.GetTTSProvider(
 		m.config.TTSProvider,
@@ -118,7 +497,6 @@ func (m *Manager) Initialize(publicURL string) error {
 	if err != nil {
 		return fmt.Errorf("failed to create TTS provider: %w", err)
 	}
-	m.ttsProvider = ttsProvider
 
 	// Create STT provider using registry-based lookup
 	sttProvider, err := omnivoice.GetSTTProvider(
@@ -132,24 +510,110 @@ func (m *Manager) Initialize(publicURL string) error {
 	if !ok {
 		return fmt.Errorf("STT provider %s does not support streaming", m.config.STTProvider)
 	}
-	m.sttProvider = streamingSTT
+
+	m.providersMu.Lock()
+	m.callSystem, m.smsProvider, m.ttsProvider, m.sttProvider = cs, smsProvider, ttsProvider, streamingSTT
+	m.publicURL = publicURL
+	m.providersMu.Unlock()
+	m.voices.reset()
+
+	// Calls can be placed now; start schedules restored from the store
+	m.startSchedules()
 
 	return nil
 }
 
-// generateCallID generates a unique call ID.
-func (m *Manager) generateCallID() string {
-	m.counterMu.Lock()
-	defer m.counterMu.Unlock()
-	m.callCounter++
-	return fmt.Sprintf("call-%d-%d", m.callCounter, time.Now().Unix())
+// addCall creates the state for a newly dialed call to the given number,
+// placed for ctx's tenant, and stores it.
+func (m *Manager) addCall(ctx context.Context, call omnivoice.Call, to string, dialedAt time.Time) *CallState {
+	state := &CallState{
+		ID:        m.newCallID(),
+		Call:      call,
+		StartTime: time.Now(),
+		statusCh:  make(chan omnivoice.CallStatus, statusBufferSize),
+		digitsCh:  make(chan string, digitsBufferSize),
+		amdCh:     make(chan AnsweredBy, amdBufferSize),
+		acceptCh:  make(chan bool, 1),
+		sink:      m.transcriptSink,
+		metrics:   callMetrics{dialedAt: dialedAt},
+		to:        to,
+		tenant:    tenantID(ctx),
+		metadata:  callMetadata(ctx),
+	}
+	state.audioDump = m.openAudioDump(state.ID)
+	// A tenant's voice applies like a set_voice override
+	if cfg := m.configFor(ctx); cfg.TTSVoice != m.config.TTSVoice {
+		state.voiceID = cfg.TTSVoice
+	}
+
+	m.callsMu.Lock()
+	m.calls[state.ID] = state
+	m.callsMu.Unlock()
+
+	return state
 }
 
 // InitiateCall starts a new call to the user and speaks a message.
-// If the call is not answered and SMS fallback is enabled, sends an SMS instead.
-func (m *Manager) InitiateCall(ctx context.Context, message string) (*CallState, string, error) {
-	if m.callSystem == nil {
-		return nil, "", fmt.Errorf("call manager not initialized; call Initialize() first")
+// A call that rings out or is busy is redialed up to Config.RedialAttempts
+// times. If it is not answered and SMS fallback is enabled, sends an SMS
+// instead.
+func (m *Manager) InitiateCall(ctx context.Context, message string, opts ...SpeakOption) (*CallState, string, error) {
+	return m.initiateCall(ctx, m.configFor(ctx).UserPhoneNumber, message, opts...)
+}
+
+// InitiateCallTo is like InitiateCall but calls to, which must be the
+// user's number or one of the configured conference numbers (the tenant's,
+// for a tenant's request). An unanswered
+// call's SMS fallback goes to the same number.
+func (m *Manager) InitiateCallTo(ctx context.Context, to, message string, opts ...SpeakOption) (*CallState, string, error) {
+	if !m.conferenceNumberAllowed(m.configFor(ctx), to) {
+		return nil, "", fmt.Errorf("%w: %s", ErrNumberNotAllowed, to)
+	}
+	return m.initiateCall(ctx, to, message, opts...)
+}
+
+// initiateCall places a call to the given number and speaks a message.
+func (m *Manager) initiateCall(ctx context.Context, to, message string, opts ...SpeakOption) (*CallState, string, error) {
+	if err := m.checkMessage(message); err != nil {
+		return nil, "", err
+	}
+	state, err := m.dialUntilAnswered(ctx, to, message)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Speak the initial message
+	response, err := m.speakAndListen(ctx, state, message, nil, opts...)
+	if err != nil {
+		// Without the response the caller never learns the call ID, so a
+		// call whose request was given up on could not be continued or ended
+		if ctx.Err() != nil {
+			return nil, "", m.abandonCall(ctx, state)
+		}
+		return state, "", speechError(err)
+	}
+
+	return state, response, nil
+}
+
+// dial places a call to the given number without waiting for an answer.
+func (m *Manager) dial(ctx context.Context, to string) (*CallState, error) {
+	cs := m.currentCallSystem()
+	if cs == nil || m.InitError() != nil {
+		return nil, m.notInitialized()
+	}
+	if m.DoNotDisturb() {
+		return nil, ErrDoNotDisturb
+	}
+	if m.quietHours.Contains(time.Now()) {
+		return nil, ErrQuietHours
+	}
+	if err := ValidateCallMetadata(callMetadata(ctx)); err != nil {
+		return nil, err
+	}
+	usage, err := m.reserveCall(time.Now())
+	if err != nil {
+		return nil, err
 	}
 
 	// Build call options
@@ -157,107 +621,322 @@ func (m *Manager) InitiateCall(ctx context.Context, message string) (*CallState,
 	if m.config.EnableRecording {
 		callOpts = append(callOpts, omnivoice.WithRecording())
 	}
+	if m.config.AMDWaitMS > 0 {
+		callOpts = append(callOpts, omnivoice.WithMachineDetection())
+	}
+	// Answer with the voice webhook rather than straight into the media
+	// stream, so the accept prompt runs for calls we place
+	callOpts = append(callOpts, omnivoice.WithAnswerURL(m.answerURL()))
+	// A tenant may call from its own number
+	cfg := m.configFor(ctx)
+	if cfg.PhoneNumber != m.config.PhoneNumber {
+		callOpts = append(callOpts, omnivoice.WithFrom(cfg.PhoneNumber))
+	}
 
-	// Make the call
-	call, err := m.callSystem.MakeCall(ctx, m.config.UserPhoneNumber, callOpts...)
+	// Make the call. Caller ID names are not supported by every provider or
+	// number, so if the name is rejected, retry without it.
+	var call omnivoice.Call
+	dialedAt := time.Now()
+	if name := cfg.CallerIDName; name != "" {
+		call, err = cs.MakeCall(ctx, to, append(callOpts, omnivoice.WithCallerIDName(name))...)
+		if err != nil && callerIDNameRejected(err) {
+			slog.Warn("caller ID name rejected; retrying without it", "error", err)
+			call, err = cs.MakeCall(ctx, to, callOpts...)
+		}
+	} else {
+		call, err = cs.MakeCall(ctx, to, callOpts...)
+	}
+	if err == nil && call == nil {
+		err = errNoCall
+	}
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to make call: %w", err)
+		m.releaseCall(usage)
+		return nil, fmt.Errorf("%w: %w", ErrDialFailed, err)
 	}
 
-	// Create call state
-	callID := m.generateCallID()
-	state := &CallState{
-		ID:        callID,
-		Call:      call,
-		StartTime: time.Now(),
+	state := m.addCall(ctx, call, to, dialedAt)
+	m.assignCall(usage, state.ID)
+	if len(state.metadata) > 0 {
+		slog.Info("call placed", "call_id", state.ID, "metadata", state.metadata)
 	}
+	return state, nil
+}
 
-	// Store call state
-	m.callsMu.Lock()
-	m.calls[callID] = state
-	m.callsMu.Unlock()
+// callerIDNameRejected reports whether a dial error says the caller ID name
+// itself is unsupported or invalid. Only then is redialing without it safe:
+// other failures (bad number, auth) would fail again, and an ambiguous one
+// such as a timeout may already have rung the user.
+func callerIDNameRejected(err error) bool {
+	msg := strings.ToLower(err.Error())
+	mentionsName := false
+	for _, s := range []string{"caller id name", "callerid name", "caller name", "callername", "cnam"} {
+		if strings.Contains(msg, s) {
+			mentionsName = true
+			break
+		}
+	}
+	if !mentionsName {
+		return false
+	}
+	for _, s := range []string{"not supported", "unsupported", "not allowed", "invalid"} {
+		if strings.Contains(msg, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// connect waits for a dialed call to be answered and accepted and prepares
+// it for speech. If the call does not connect, it is hung up and removed;
+// an unanswered call falls back to SMS with message if enabled.
+func (m *Manager) connect(ctx context.Context, state *CallState, message string) error {
+	call, callID := state.Call, state.ID
 
 	// Wait for call to be answered (with timeout)
-	answered := m.waitForAnswer(ctx, call, 30*time.Second)
+	answered := m.waitForAnswer(ctx, call, state.statusCh, 30*time.Second)
 	if !answered {
+		// An async call ended with EndCall while ringing needs no fallback
+		if m.getCall(callID) == nil {
+			return fmt.Errorf("%w: %s", ErrCallNotFound, callID)
+		}
+		// A request given up on while ringing is not a missed call
+		if ctx.Err() != nil {
+			return m.abandonCall(ctx, state)
+		}
+		return m.notAnswered(ctx, state, message)
+	}
+	state.markAnswered(time.Now())
+
+	// Media only connects once the callee presses the accept digit
+	if m.config.RequireAccept && !waitForAccept(ctx, state, acceptTimeout) {
+		if ctx.Err() != nil {
+			return m.abandonCall(ctx, state)
+		}
 		_ = call.Hangup(ctx)
 		m.removeCall(callID)
+		return ErrDeclined
+	}
 
-		// Try SMS fallback if enabled
-		if m.config.SMSFallbackEnabled && m.smsProvider != nil {
-			smsErr := m.sendSMSFallback(ctx, message)
-			if smsErr != nil {
-				return nil, "", fmt.Errorf("call not answered, SMS fallback failed: %w", smsErr)
-			}
-			return nil, "", fmt.Errorf("call not answered, sent SMS instead")
+	// With answering machine detection, a machine gets the message as a
+	// voicemail once its greeting ends, and a fax is treated as no answer
+	if m.config.AMDWaitMS > 0 {
+		answeredBy := waitForAnsweredBy(ctx, state, time.Duration(m.config.AMDWaitMS)*time.Millisecond)
+		slog.Info("answering machine detection", "call_id", callID, "answered_by", answeredBy)
+		switch answeredBy {
+		case AnsweredByMachine:
+			return m.leaveVoicemail(ctx, state, message)
+		case AnsweredByFax:
+			// A fax answers a redial too
+			state.redial = false
+			return m.notAnswered(ctx, state, message)
 		}
+	}
 
-		return nil, "", fmt.Errorf("call not answered")
+	// Let the callee know the assistant is on the line. It plays after
+	// answering machine detection so voicemails don't start with a beep.
+	m.playConnectTone(state)
+
+	// Let the callee finish saying hello before the first message. A callee
+	// who pressed the accept digit has already heard the accept prompt.
+	if m.config.WaitForGreeting && !m.config.RequireAccept {
+		m.waitForGreeting(ctx, state)
 	}
 
-	// Speak the initial message
-	response, err := m.speakAndListen(ctx, state, message)
-	if err != nil {
-		return state, "", fmt.Errorf("failed to speak: %w", err)
+	// The waits above end early when the request is given up on
+	if ctx.Err() != nil {
+		return m.abandonCall(ctx, state)
 	}
 
-	return state, response, nil
+	// A recorded call must announce it before anything else is said
+	if err := m.announceRecording(ctx, state); err != nil {
+		if ctx.Err() != nil {
+			return m.abandonCall(ctx, state)
+		}
+		_ = call.Hangup(ctx)
+		m.removeCall(callID)
+		return fmt.Errorf("%w: recording announcement: %w", ErrSpeechFailed, err)
+	}
+
+	// Keep the media stream alive while the agent is busy between turns;
+	// compressed streams cannot be padded with raw silence
+	if m.config.KeepAlive && m.codec.sampled {
+		m.startKeepAlive(state)
+	}
+
+	return nil
+}
+
+// abandonCall hangs up and removes a call whose request was cancelled or
+// timed out before the call was handed back, so it is not left ringing, or
+// connected with nobody to continue or end it. The hangup gets its own
+// deadline since ctx is already done. It returns ctx's error.
+func (m *Manager) abandonCall(ctx context.Context, state *CallState) error {
+	slog.Warn("request ended before the call was handed back; hanging up", "call_id", state.ID, "error", ctx.Err())
+
+	hangupCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), m.closeTimeout)
+	defer cancel()
+	if err := state.Call.Hangup(hangupCtx); err != nil {
+		slog.Warn("failed to hang up abandoned call", "call_id", state.ID, "error", err)
+	}
+	m.removeCall(state.ID)
+
+	state.mu.RLock()
+	answered := !state.metrics.answeredAt.IsZero()
+	state.mu.RUnlock()
+	if answered {
+		m.notifyCallEnded(state)
+	}
+	return ctx.Err()
+}
+
+// notAnswered hangs up and removes a call nobody answered and falls back to
+// SMS with message if enabled. A call that will be redialed only returns
+// the reason; the last attempt gives up for all of them.
+func (m *Manager) notAnswered(ctx context.Context, state *CallState, message string) error {
+	// Check before hanging up, which changes the status
+	reason := ErrNotAnswered
+	if state.lineBusy() {
+		reason = fmt.Errorf("%w: %w", ErrNotAnswered, ErrBusy)
+	}
+
+	_ = state.Call.Hangup(ctx)
+	m.removeCall(state.ID)
+
+	if state.redial {
+		return reason
+	}
+	return m.giveUp(ctx, state, message, reason)
+}
+
+// giveUp handles a call that was not answered for reason: it is recorded
+// as missed and message falls back to SMS if enabled.
+func (m *Manager) giveUp(ctx context.Context, state *CallState, message string, reason error) error {
+	// Remember the attempt so a callback from the user can be linked to it
+	m.recordMissed(state, message)
+
+	// Try SMS fallback if enabled
+	if m.config.SMSFallbackEnabled && m.currentSMSProvider() != nil {
+		smsErr := m.sendSMSFallback(ctx, state.to, message)
+		if smsErr != nil {
+			return fmt.Errorf("%w, SMS fallback failed: %w", reason, smsErr)
+		}
+		return fmt.Errorf("%w, %w", reason, ErrSMSFallbackSent)
+	}
+
+	return reason
+}
+
+// leaveVoicemail speaks message to an answering machine whose greeting has
+// ended, waits for it to play, and hangs up. The attempt is recorded as a
+// missed call so a callback from the user can be linked to it.
+func (m *Manager) leaveVoicemail(ctx context.Context, state *CallState, message string) error {
+	speakErr := m.announceRecording(ctx, state)
+	if speakErr == nil {
+		speakErr = m.speak(ctx, state, message)
+	}
+	if wait := time.Until(state.playbackEnd()); speakErr == nil && wait > 0 {
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+		}
+	}
+
+	_ = state.Call.Hangup(ctx)
+	m.removeCall(state.ID)
+	m.notifyCallEnded(state)
+	m.recordMissed(state, message)
+
+	if speakErr != nil {
+		return fmt.Errorf("%w, %w: %w", ErrVoicemail, ErrSpeechFailed, speakErr)
+	}
+	return ErrVoicemail
 }
 
 // ContinueCall continues an existing call with a new message.
-func (m *Manager) ContinueCall(ctx context.Context, callID, message string) (string, error) {
-	state := m.getCall(callID)
-	if state == nil {
-		return "", fmt.Errorf("call not found: %s", callID)
+func (m *Manager) ContinueCall(ctx context.Context, callID, message string, opts ...SpeakOption) (string, error) {
+	return m.ContinueCallStreaming(ctx, callID, message, nil, opts...)
+}
+
+// ContinueCallStreaming is like ContinueCall, but also calls onPartial with
+// the transcript heard so far each time it changes, so the caller can act on
+// a long reply before it is complete. The final transcript is returned as
+// with ContinueCall. onPartial may be nil.
+func (m *Manager) ContinueCallStreaming(ctx context.Context, callID, message string, onPartial func(transcript string), opts ...SpeakOption) (string, error) {
+	if err := m.checkMessage(message); err != nil {
+		return "", err
+	}
+	state, err := m.readyCall(ctx, callID)
+	if err != nil {
+		return "", err
 	}
 
-	response, err := m.speakAndListen(ctx, state, message)
+	response, err := m.speakAndListen(ctx, state, message, onPartial, opts...)
 	if err != nil {
-		return "", fmt.Errorf("failed to continue call: %w", err)
+		return "", speechError(err)
 	}
 
 	return response, nil
 }
 
 // SpeakToUser speaks to the user without waiting for a response.
-func (m *Manager) SpeakToUser(ctx context.Context, callID, message string) error {
-	state := m.getCall(callID)
-	if state == nil {
-		return fmt.Errorf("call not found: %s", callID)
+func (m *Manager) SpeakToUser(ctx context.Context, callID, message string, opts ...SpeakOption) error {
+	if err := m.checkMessage(message); err != nil {
+		return err
+	}
+	state, err := m.readyCall(ctx, callID)
+	if err != nil {
+		return err
 	}
 
-	if err := m.speak(ctx, state, message); err != nil {
-		return fmt.Errorf("failed to speak: %w", err)
+	if err := m.speak(ctx, state, message, opts...); err != nil {
+		return fmt.Errorf("%w: %w", ErrSpeechFailed, err)
 	}
 
 	return nil
 }
 
-// EndCall ends an existing call with a final message.
-func (m *Manager) EndCall(ctx context.Context, callID, message string) (time.Duration, error) {
-	state := m.getCall(callID)
+// EndCall ends an existing call with a final message and returns the
+// call's metrics.
+func (m *Manager) EndCall(ctx context.Context, callID, message string, opts ...SpeakOption) (CallMetrics, error) {
+	state := m.tenantCall(ctx, callID)
 	if state == nil {
-		return 0, fmt.Errorf("call not found: %s", callID)
+		return CallMetrics{}, fmt.Errorf("%w: %s", ErrCallNotFound, callID)
+	}
+
+	// A dropped call has nothing left to say or hang up
+	if state.dropped() {
+		metrics := state.Metrics()
+		m.retireDropped(state)
+		return metrics, nil
+	}
+
+	// A final message that would go over the TTS allowance is skipped
+	if message != "" {
+		if reason := m.ttsAllowanceExceeded(state, message); reason != "" {
+			slog.Info("skipping final message over the call's usage limit", "call_id", state.ID, "reason", reason)
+			message = ""
+		}
 	}
 
 	// Speak final message
 	if message != "" {
 		// Best effort - ignore errors and continue with hangup
-		_ = m.speak(ctx, state, message)
+		_ = m.speak(ctx, state, message, opts...)
 		// Wait for audio to play
 		time.Sleep(2 * time.Second)
 	}
 
-	duration := state.Duration()
+	metrics := state.Metrics()
 
 	// Hangup
 	if err := state.Call.Hangup(ctx); err != nil {
-		return duration, fmt.Errorf("failed to hangup: %w", err)
+		return metrics, fmt.Errorf("failed to hangup: %w", err)
 	}
 
 	m.removeCall(callID)
+	m.notifyCallEnded(state)
 
-	return duration, nil
+	return metrics, nil
 }
 
 // GetCall returns the state of a call.
@@ -275,13 +954,29 @@ func (m *Manager) getCall(callID string) *CallState {
 // removeCall removes a call from the active calls map.
 func (m *Manager) removeCall(callID string) {
 	m.callsMu.Lock()
-	defer m.callsMu.Unlock()
+	state, ok := m.calls[callID]
+	if ok {
+		state.stopKeepAlive()
+		state.closeSTTSession()
+		state.setPrefetch(nil)
+		state.closeAudioDump()
+	}
 	delete(m.calls, callID)
+	m.removeConference(callID)
+	m.callsMu.Unlock()
+
+	if ok {
+		now := time.Now()
+		m.recordCallCost(state, now)
+		m.recordCall(state, now)
+	}
 }
 
-// sendSMSFallback sends an SMS message when a call is not answered.
-func (m *Manager) sendSMSFallback(ctx context.Context, message string) error {
-	if m.smsProvider == nil {
+// sendSMSFallback sends an SMS message to the given number when a call to
+// it is not answered, from the number of ctx's tenant.
+func (m *Manager) sendSMSFallback(ctx context.Context, to, message string) error {
+	smsProvider := m.currentSMSProvider()
+	if smsProvider == nil {
 		return fmt.Errorf("SMS provider not available")
 	}
 
@@ -289,36 +984,166 @@ func (m *Manager) sendSMSFallback(ctx context.Context, message string) error {
 	smsBody := m.config.SMSFallbackMessage
 	smsBody = strings.ReplaceAll(smsBody, "{message}", message)
 
-	_, err := m.smsProvider.SendSMS(ctx, m.config.UserPhoneNumber, smsBody)
+	var err error
+	if from := m.configFor(ctx).PhoneNumber; from != m.config.PhoneNumber {
+		_, err = smsProvider.SendSMSFrom(ctx, to, from, smsBody)
+	} else {
+		_, err = smsProvider.SendSMS(ctx, to, smsBody)
+	}
 	return err
 }
 
-// waitForAnswer waits for the call to be answered.
-func (m *Manager) waitForAnswer(ctx context.Context, call omnivoice.Call, timeout time.Duration) bool {
-	deadline := time.Now().Add(timeout)
-	for time.Now().Before(deadline) {
+// defaultCloseTimeout bounds how long Close waits for hangups.
+const defaultCloseTimeout = 5 * time.Second
+
+// statusBufferSize is the number of pushed status changes buffered per call.
+const statusBufferSize = 8
+
+// defaultStatusPollInterval is used when Config.StatusPollMS is unset.
+const defaultStatusPollInterval = 500 * time.Millisecond
+
+// statusPollInterval returns how often waitForAnswer re-checks the call
+// status as a fallback when the provider does not push status changes.
+func (m *Manager) statusPollInterval() time.Duration {
+	if m.config.StatusPollMS <= 0 {
+		return defaultStatusPollInterval
+	}
+	return time.Duration(m.config.StatusPollMS) * time.Millisecond
+}
+
+// statusCall is the subset of omnivoice.Call needed to wait for an answer.
+type statusCall interface {
+	Status() omnivoice.CallStatus
+}
+
+// NotifyStatus delivers a status change for the call with the given
+// provider call ID (e.g., a Twilio CallSid). It never blocks; if the call is
+// unknown or its buffer is full the update is dropped.
+func (m *Manager) NotifyStatus(providerCallID string, status omnivoice.CallStatus) {
+	m.callsMu.RLock()
+	defer m.callsMu.RUnlock()
+
+	for _, state := range m.calls {
+		if state.Call == nil || state.Call.ID() != providerCallID {
+			continue
+		}
+		state.setStatus(status)
 		select {
-		case <-ctx.Done():
-			return false
+		case state.statusCh <- status:
 		default:
 		}
+		m.noteDropped(state, status)
+		return
+	}
 
-		status := call.Status()
-		if status == omnivoice.StatusAnswered {
-			return true
-		}
-		if status == omnivoice.StatusEnded || status == omnivoice.StatusFailed ||
-			status == omnivoice.StatusBusy || status == omnivoice.StatusNoAnswer {
+	m.notifyParticipantStatus(providerCallID, status)
+}
+
+// StatusFromTwilio maps a Twilio CallStatus callback value to a call status.
+// It returns false for values that do not map to a known status.
+func StatusFromTwilio(status string) (omnivoice.CallStatus, bool) {
+	switch status {
+	case "queued", "initiated", "ringing":
+		return omnivoice.StatusRinging, true
+	case "in-progress", "answered":
+		return omnivoice.StatusAnswered, true
+	case "completed", "canceled":
+		return omnivoice.StatusEnded, true
+	case "busy":
+		return omnivoice.StatusBusy, true
+	case "no-answer":
+		return omnivoice.StatusNoAnswer, true
+	case "failed":
+		return omnivoice.StatusFailed, true
+	default:
+		return "", false
+	}
+}
+
+// answerResult reports whether a status means the call was answered and
+// whether waiting is over.
+func answerResult(status omnivoice.CallStatus) (answered, done bool) {
+	switch status {
+	case omnivoice.StatusAnswered:
+		return true, true
+	case omnivoice.StatusEnded, omnivoice.StatusFailed,
+		omnivoice.StatusBusy, omnivoice.StatusNoAnswer:
+		return false, true
+	default:
+		return false, false
+	}
+}
+
+// waitForAnswer waits for the call to be answered. It returns as soon as a
+// status change arrives on events, falling back to polling call.Status()
+// for providers that do not push status changes.
+func (m *Manager) waitForAnswer(ctx context.Context, call statusCall, events <-chan omnivoice.CallStatus, timeout time.Duration) bool {
+	if answered, done := answerResult(call.Status()); done {
+		return answered
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	poll := time.NewTicker(m.statusPollInterval())
+	defer poll.Stop()
+
+	for {
+		var status omnivoice.CallStatus
+		select {
+		case <-ctx.Done():
+			return false
+		case <-timer.C:
 			return false
+		case s, ok := <-events:
+			if !ok {
+				events = nil
+				continue
+			}
+			status = s
+		case <-poll.C:
+			status = call.Status()
 		}
 
-		time.Sleep(500 * time.Millisecond)
+		if answered, done := answerResult(status); done {
+			return answered
+		}
 	}
-	return false
 }
 
-// speak generates TTS and streams it to the call.
-func (m *Manager) speak(ctx context.Context, state *CallState, message string) error {
+// speak generates TTS and streams it to the call, or plays the audio
+// given with WithAudio. If the TTS stream fails
+// partway, the rest of the message is re-synthesized up to TTSStreamRetries
+// times; if that fails too, a short apology is spoken instead of silence.
+// Messages on a call play in the order speak is called.
+func (m *Manager) speak(ctx context.Context, state *CallState, message string, opts ...SpeakOption) error {
+	o := newSpeakOptions(opts)
+
+	// Wait for earlier messages so turns are recorded and played in order
+	leave, err := state.speakQueue.enter(ctx)
+	if err != nil {
+		return err
+	}
+
+	// A message that would go over the call's TTS allowance is not spoken;
+	// the call is ended instead, once out of the queue so the goodbye can
+	// play
+	if o.audio == nil {
+		if reason := m.ttsAllowanceExceeded(state, message); reason != "" {
+			leave()
+			m.endAtUsageLimit(ctx, state, reason)
+			return fmt.Errorf("%w: %s", ErrUsageLimit, reason)
+		}
+	}
+	defer leave()
+
+	// The whole turn uses one TTS provider, so re-initializing voice while
+	// it plays cannot swap the provider out from under it
+	ttsProvider := m.currentTTSProvider()
+
+	// Earlier assistant turns give the provider context for intonation
+	previous := state.previousAssistantText(m.config.TTSContinuityTurns)
+
 	// Record the assistant turn
 	state.AddTurn("assistant", message)
 
@@ -328,26 +1153,146 @@ func (m *Manager) speak(ctx context.Context, state *CallState, message string) e
 		return fmt.Errorf("no transport connection available")
 	}
 
-	// Synthesize using streaming TTS with native ulaw output for Twilio
-	stream, err := m.ttsProvider.SynthesizeStream(ctx, message, omnivoice.SynthesisConfig{
-		VoiceID:      m.config.TTSVoice,
+	state.audioMu.Lock()
+	defer state.audioMu.Unlock()
+
+	// Writes return once audio is handed to the provider, which plays it
+	// in real time; the playback end is estimated from the bytes sent
+	state.setSpeaking(true)
+	start := time.Now()
+	sent := 0
+	defer func() { state.finishPlayback(start.Add(m.codec.playbackDuration(sent))) }()
+
+	// Models without audio tag support would read tags aloud
+	if !m.config.TTSSupportsTags() {
+		message = stripAudioTags(message)
+		previous = stripAudioTags(previous)
+	}
+	message, previous = m.pronunciation.apply(message), m.pronunciation.apply(previous)
+
+	out := state.teeAssistantAudio(transport.AudioIn())
+	if m.config.TTSFrameMS > 0 {
+		if m.codec.sampled {
+			frameMS := time.Duration(m.config.TTSFrameMS) * time.Millisecond
+			framer := newFrameWriter(ctx, out, m.codec.bytesPerSecond*m.config.TTSFrameMS/1000, frameMS)
+			out = framer
+			// Runs after the trimmer's final write, and before the
+			// playback end is recorded, which includes the padding
+			defer func() { sent += framer.flush() }()
+		} else {
+			slog.Warn("TTS framing is not supported with this codec; sending audio as it arrives", "call_id", state.ID, "codec", m.codec.name)
+		}
+	}
+	var audioIn io.Writer = audioOutWriter{w: out, state: state}
+	if o.volume < 1 {
+		if m.codec.sampled {
+			audioIn = newGainWriter(audioIn, o.volume)
+		} else {
+			slog.Warn("volume is not supported with this codec; speaking at full volume", "call_id", state.ID, "codec", m.codec.name)
+		}
+	}
+	if m.config.TrimSilence && m.codec.sampled {
+		trim := newSilenceTrimmer(audioIn, m.config.TrimSilenceThreshold, m.codec.bytesPerSecond*m.config.TrimSilencePadMS/1000)
+		audioIn = trim
+		// Runs before the playback end is recorded, which excludes the
+		// dropped audio
+		defer func() { sent -= trim.finish() }()
+	}
+
+	if o.audio != nil {
+		written, err := playAudio(ctx, audioIn, o.audio.ulaw, m.codec.bytesPerSecond/10)
+		sent += written
+		return err
+	}
+
+	if ttsProvider == nil {
+		return m.notInitialized()
+	}
+	synthCfg := m.synthesisConfig(state, previous)
+	if o.rate > 0 {
+		synthCfg.Speed = ttsSpeed(m.config.TTSProvider, o.rate)
+	}
+	if o.voiceID != "" {
+		synthCfg.VoiceID = o.voiceID
+	}
+	text := message
+	for attempt := 0; ; attempt++ {
+		written, err := m.playOrSynthesize(ctx, ttsProvider, state, audioIn, text, synthCfg)
+		sent += written
+		if err == nil {
+			return nil
+		}
+		if !errors.Is(err, errTTSStream) {
+			return err
+		}
+
+		if attempt < m.config.TTSStreamRetries && ctx.Err() == nil {
+			text = remainingText(text, m.codec.playbackDuration(written))
+			slog.Warn("TTS stream failed, retrying remaining text", "call_id", state.ID, "attempt", attempt+1, "error", err)
+			continue
+		}
+
+		// Best effort so the user is not left with silence
+		synthCfg.Extensions = nil
+		written, _ = m.synthesizeCached(ctx, ttsProvider, state, audioIn, ttsApology, synthCfg)
+		sent += written
+		return err
+	}
+}
+
+// ttsApology is spoken when a message cannot be synthesized.
+const ttsApology = "Sorry, I had trouble speaking just now."
+
+// errTTSStream marks a TTS failure after the stream started, which is
+// worth retrying with a fresh synthesis call.
+var errTTSStream = errors.New("TTS stream error")
+
+// errSTTClosed marks an STT stream the provider closed before the turn
+// ended, which is a transcription failure rather than silence.
+var errSTTClosed = errors.New("STT stream closed unexpectedly")
+
+// synthesisConfig returns the TTS settings for the call, using its voice
+// override if set and previous as continuity context.
+func (m *Manager) synthesisConfig(state *CallState, previous string) omnivoice.SynthesisConfig {
+	voiceID := state.voice()
+	if voiceID == "" {
+		voiceID = m.config.TTSVoice
+	}
+
+	// Streaming TTS in the call's codec, so audio is sent as is
+	return omnivoice.SynthesisConfig{
+		VoiceID:      voiceID,
 		Model:        m.config.TTSModel,
-		OutputFormat: "ulaw", // Native mu-law for Twilio
-		SampleRate:   8000,   // Telephony sample rate
-	})
+		OutputFormat: m.codec.ttsFormat,
+		SampleRate:   m.codec.sampleRate,
+		Speed:        ttsSpeed(m.config.TTSProvider, m.config.SpeakingRate),
+		Extensions:   m.synthesisExtensions(previous),
+	}
+}
+
+// synthesizeTo synthesizes text with ttsProvider and writes the audio to w.
+// It returns the number of audio bytes written.
+func (m *Manager) synthesizeTo(ctx context.Context, ttsProvider omnivoice.TTSProvider, w io.Writer, text string, cfg omnivoice.SynthesisConfig) (int, error) {
+	stream, err := ttsProvider.SynthesizeStream(ctx, text, cfg)
 	if err != nil {
-		return fmt.Errorf("TTS synthesis failed: %w", err)
+		return 0, fmt.Errorf("TTS synthesis failed: %w", err)
+	}
+	// Ranging over a nil channel would block the call forever
+	if stream == nil {
+		return 0, fmt.Errorf("TTS synthesis failed: %s returned no audio stream", m.config.TTSProvider)
 	}
 
 	// Stream audio to the transport
-	audioIn := transport.AudioIn()
+	written := 0
 	for chunk := range stream {
 		if chunk.Error != nil {
-			return fmt.Errorf("TTS stream error: %w", chunk.Error)
+			return written, fmt.Errorf("%w: %w", errTTSStream, chunk.Error)
 		}
 		if len(chunk.Audio) > 0 {
-			if _, err := audioIn.Write(chunk.Audio); err != nil {
-				return fmt.Errorf("failed to write audio: %w", err)
+			n, err := w.Write(chunk.Audio)
+			written += n
+			if err != nil {
+				return written, fmt.Errorf("failed to write audio: %w", err)
 			}
 		}
 		if chunk.IsFinal {
@@ -355,18 +1300,56 @@ func (m *Manager) speak(ctx context.Context, state *CallState, message string) e
 		}
 	}
 
-	return nil
+	return written, nil
 }
 
-// speakAndListen speaks a message and waits for user response.
-func (m *Manager) speakAndListen(ctx context.Context, state *CallState, message string) (string, error) {
+// Playback estimates used to find where a failed TTS stream stopped.
+const (
+	ulawBytesPerSecond = 8000 // 8 kHz, one byte per sample
+	spokenCharsPerSec  = 15   // typical TTS pace at normal rate
+)
+
+// remainingText estimates how much of text was spoken from how long its
+// audio played and returns the rest, starting from the beginning of the
+// sentence in progress. The estimate is too rough to resume mid-sentence,
+// so the part of that sentence already heard is deliberately repeated.
+func remainingText(text string, played time.Duration) string {
+	if text == "" {
+		return ""
+	}
+	spoken := int(played * spokenCharsPerSec / time.Second)
+	if spoken <= 0 {
+		return text
+	}
+	if spoken >= len(text) {
+		spoken = len(text) - 1
+	}
+
+	start := 0
+	for _, sep := range []string{". ", "! ", "? "} {
+		if i := strings.LastIndex(text[:spoken], sep); i >= 0 && i+len(sep) > start {
+			start = i + len(sep)
+		}
+	}
+	return text[start:]
+}
+
+// speakAndListen speaks a message and waits for user response, reporting
+// interim transcripts to onPartial if set.
+func (m *Manager) speakAndListen(ctx context.Context, state *CallState, message string, onPartial func(string), opts ...SpeakOption) (string, error) {
+	// Don't ask a question whose answer would not be heard
+	if state.ListeningMuted() {
+		return "", ErrListeningMuted
+	}
+
 	// Speak the message
-	if err := m.speak(ctx, state, message); err != nil {
+	if err := m.speak(ctx, state, message, opts...); err != nil {
 		return "", err
 	}
+	m.startPrefetch(state, newSpeakOptions(opts).nextMessage)
 
 	// Listen for response using STT
-	response, err := m.listen(ctx, state)
+	response, err := m.listen(ctx, state, onPartial)
 	if err != nil {
 		return "", fmt.Errorf("failed to listen: %w", err)
 	}
@@ -374,25 +1357,69 @@ func (m *Manager) speakAndListen(ctx context.Context, state *CallState, message
 	return response, nil
 }
 
-// listen waits for and transcribes user speech.
-func (m *Manager) listen(ctx context.Context, state *CallState) (string, error) {
+// speechError wraps a speak or listen failure in ErrSpeechFailed. ErrNoSpeech
+// and ErrListeningMuted are returned as is since the call is still healthy.
+func speechError(err error) error {
+	if errors.Is(err, ErrNoSpeech) {
+		return ErrNoSpeech
+	}
+	if errors.Is(err, ErrListeningMuted) {
+		return ErrListeningMuted
+	}
+	return fmt.Errorf("%w: %w", ErrSpeechFailed, err)
+}
+
+// listen waits for and transcribes user speech. If onPartial is set, it
+// receives the transcript so far as interim results arrive.
+func (m *Manager) listen(ctx context.Context, state *CallState, onPartial func(string)) (string, error) {
 	// Get the transport connection from the call
 	transport := state.Call.Transport()
 	if transport == nil {
 		return "", fmt.Errorf("no transport connection available")
 	}
 
+	if state.ListeningMuted() {
+		return "", ErrListeningMuted
+	}
+	state.setListening(true)
+	defer state.setListening(false)
+
+	// Audio received during the post-speech grace period, counted from the
+	// estimated end of playback, is the tail of our own TTS and is dropped
+	// so it is not transcribed as the user.
+	ignoreUntil := time.Now()
+	if delay := time.Duration(m.config.PostSpeechDelayMS) * time.Millisecond; delay > 0 {
+		if t := state.playbackEnd().Add(delay); t.After(ignoreUntil) {
+			ignoreUntil = t
+		}
+	}
+
+	if m.config.STTPersistConnection {
+		session, err := m.sttSession(state, transport)
+		if err != nil {
+			return "", err
+		}
+		// Muted while this turn was in progress; stop the stream after it
+		defer func() {
+			if state.ListeningMuted() {
+				state.closeSTTSession()
+			}
+		}()
+		session.startTurn(ignoreUntil)
+		defer session.endTurn()
+
+		text, err := m.awaitTranscript(ctx, state, session.events, onPartial)
+		if err != nil && !errors.Is(err, ErrNoSpeech) && ctx.Err() == nil {
+			// The provider reported an error; reconnect on the next turn
+			state.closeSTTSession()
+		}
+		return text, err
+	}
+
 	// Create a streaming transcription session
-	writer, events, err := m.sttProvider.TranscribeStream(ctx, omnivoice.TranscriptionConfig{
-		Language:          m.config.STTLanguage,
-		Model:             m.config.STTModel,
-		Encoding:          "mulaw",
-		SampleRate:        8000,
-		Channels:          1,
-		EnablePunctuation: true,
-	})
+	writer, events, err := m.transcribeStream(ctx, state)
 	if err != nil {
-		return "", fmt.Errorf("failed to start transcription: %w", err)
+		return "", err
 	}
 	defer func() { _ = writer.Close() }()
 
@@ -400,64 +1427,204 @@ func (m *Manager) listen(ctx context.Context, state *CallState) (string, error)
 	audioCtx, audioCancel := context.WithCancel(ctx)
 	defer audioCancel()
 
-	go func() {
-		audioOut := transport.AudioOut()
-		buf := make([]byte, 1024)
-		for {
-			select {
-			case <-audioCtx.Done():
+	go pumpAudio(audioCtx, audioInReader{r: state.teeUserAudio(transport.AudioOut()), state: state}, writer, ignoreUntil)
+
+	return m.awaitTranscript(ctx, state, events, onPartial)
+}
+
+// pumpAudio copies caller audio from r to the STT writer w until ctx is done
+// or r is exhausted, discarding anything read before ignoreUntil.
+func pumpAudio(ctx context.Context, r io.Reader, w io.Writer, ignoreUntil time.Time) {
+	buf := make([]byte, 1024)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+			n, err := r.Read(buf)
+			if err != nil {
 				return
-			default:
-				n, err := audioOut.Read(buf)
-				if err != nil {
-					if err == io.EOF {
-						return
-					}
-					return
-				}
-				if n > 0 {
-					_, _ = writer.Write(buf[:n])
-				}
+			}
+			if n > 0 && !time.Now().Before(ignoreUntil) {
+				_, _ = w.Write(buf[:n])
 			}
 		}
-	}()
+	}
+}
+
+// recordUserTurn records a transcript as a user turn and checks it for a
+// change of language.
+func (m *Manager) recordUserTurn(ctx context.Context, state *CallState, text string) {
+	state.addTurn(ConversationTurn{
+		Role:      "user",
+		Content:   text,
+		Sentiment: m.analyzeSentiment(ctx, state, text),
+	})
+	m.checkLanguage(state, text)
+}
 
-	// Set up timeout
+// awaitTranscript consumes transcription events until the user's turn is
+// complete and records it on the call state.
+//
+// By default the first final transcript ends the turn. When
+// AggregateFinalsMS is set, listening continues for that window after each
+// final result (reset by further speech) and all finals are joined into a
+// single turn, so follow-on clauses are not lost. With AdaptiveSilence the
+// window is learned from the caller's pauses instead.
+//
+// Three limits end a turn: TranscriptTimeoutMS bounds the whole wait,
+// including before the user starts speaking; the aggregation window is the
+// silence allowed after a final result; MaxUtteranceMS caps how long the
+// user may speak, counted from the first word, and force-finalizes
+// whatever has been heard so a long reply does not run up STT costs.
+func (m *Manager) awaitTranscript(ctx context.Context, state *CallState, events <-chan omnivoice.StreamEvent, onPartial func(string)) (string, error) {
+	// Set up timeout, cut short by what is left of the STT allowance
 	timeout := time.Duration(m.config.TranscriptTimeoutMS) * time.Millisecond
+	if allowance, ok := m.sttAllowance(state); ok {
+		timeout = min(timeout, allowance)
+	}
 	timer := time.NewTimer(timeout)
 	defer timer.Stop()
 
-	var transcript string
+	maxUtterance := time.Duration(m.config.MaxUtteranceMS) * time.Millisecond
+	var utteranceTimer *time.Timer
+	var utterance <-chan time.Time
+	defer func() {
+		if utteranceTimer != nil {
+			utteranceTimer.Stop()
+		}
+	}()
+
+	aggregateWindow := m.endOfSpeechSilence(state)
+	echoGuard := time.Duration(m.config.EchoGuardMS) * time.Millisecond
+	var aggregateTimer *time.Timer
+	var aggregate <-chan time.Time
+	defer func() {
+		if aggregateTimer != nil {
+			aggregateTimer.Stop()
+		}
+	}()
+
+	var finals []string       // final segments collected so far
+	var partial string        // latest interim transcript
+	var utteranceAt time.Time // first result of the utterance in progress
+	heard := false            // whether any user speech has arrived yet
+
+	// The longest pause the caller went on speaking after, for
+	// AdaptiveSilence; lastFinalAt is cleared when speech resumes
+	var lastFinalAt time.Time
+	var longestPause time.Duration
+
+	// transcript returns everything heard so far.
+	transcript := func() string {
+		parts := finals
+		if partial != "" {
+			parts = append(parts[:len(parts):len(parts)], partial)
+		}
+		return strings.Join(parts, " ")
+	}
+
+	// finish records what was heard as a user turn, unless the caller
+	// records it, and returns it, or ErrNoSpeech if nothing was heard.
+	finish := func() (string, error) {
+		text := transcript()
+		if text == "" {
+			return "", ErrNoSpeech
+		}
+		if !turnDeferred(ctx) {
+			m.recordUserTurn(ctx, state, text)
+		}
+		return text, nil
+	}
+
 	for {
 		select {
 		case <-ctx.Done():
-			return transcript, ctx.Err()
+			return transcript(), ctx.Err()
 		case <-timer.C:
-			if transcript != "" {
-				state.AddTurn("user", transcript)
+			return finish()
+		case <-aggregate:
+			if m.config.AdaptiveSilence {
+				m.observePause(state, longestPause)
 			}
-			return transcript, nil
+			return finish()
+		case <-utterance:
+			slog.Debug("utterance reached maximum length; finalizing", "call_id", state.ID, "max", maxUtterance)
+			return finish()
 		case event, ok := <-events:
 			if !ok {
-				if transcript != "" {
-					state.AddTurn("user", transcript)
+				// Keep what was heard; with nothing heard the user wasn't silent,
+				// the provider went away
+				if transcript() == "" {
+					return "", errSTTClosed
 				}
-				return transcript, nil
+				return finish()
 			}
 
 			if event.Error != nil {
-				return transcript, event.Error
+				return transcript(), event.Error
+			}
+
+			if event.Transcript == "" {
+				continue
+			}
+
+			// Half-duplex guard: an utterance that began during our playback,
+			// or within the guard after it, is echo. It is judged by its first
+			// result rather than each result's arrival, so a final that shows
+			// up after the window still goes with the rest of the utterance.
+			if utteranceAt.IsZero() {
+				utteranceAt = time.Now()
+			}
+			if echoGuard > 0 && state.inEchoWindow(utteranceAt, echoGuard) {
+				if event.IsFinal {
+					utteranceAt = time.Time{}
+				}
+				continue
+			}
+			if !lastFinalAt.IsZero() {
+				longestPause = max(longestPause, time.Since(lastFinalAt))
+				lastFinalAt = time.Time{}
+			}
+			if !heard {
+				heard = true
+				state.markHeard(time.Now())
+				if maxUtterance > 0 {
+					utteranceTimer = time.NewTimer(maxUtterance)
+					utterance = utteranceTimer.C
+				}
+				m.notifySpeechStart(state, event.Transcript)
 			}
 
-			if event.IsFinal && event.Transcript != "" {
-				transcript = event.Transcript
-				state.AddTurn("user", transcript)
-				return transcript, nil
+			if !event.IsFinal {
+				// Update partial transcript; ongoing speech extends the aggregation window
+				partial = event.Transcript
+				if onPartial != nil {
+					onPartial(transcript())
+				}
+				if aggregateTimer != nil {
+					aggregateTimer.Reset(aggregateWindow)
+				}
+				continue
+			}
+
+			finals = append(finals, event.Transcript)
+			partial = ""
+			utteranceAt = time.Time{}
+			lastFinalAt = time.Now()
+			if aggregateWindow <= 0 {
+				return finish()
+			}
+			if onPartial != nil {
+				onPartial(transcript())
 			}
 
-			// Update partial transcript
-			if event.Transcript != "" {
-				transcript = event.Transcript
+			// Keep listening briefly for follow-on speech
+			if aggregateTimer == nil {
+				aggregateTimer = time.NewTimer(aggregateWindow)
+				aggregate = aggregateTimer.C
+			} else {
+				aggregateTimer.Reset(aggregateWindow)
 			}
 		}
 	}
@@ -465,27 +1632,162 @@ func (m *Manager) listen(ctx context.Context, state *CallState) (string, error)
 
 // Close shuts down the call manager.
 func (m *Manager) Close() error {
-	m.callsMu.Lock()
-	defer m.callsMu.Unlock()
+	m.closeOnce.Do(func() { close(m.closed) })
 
-	// Hangup all active calls
-	ctx := context.Background()
-	for _, state := range m.calls {
-		_ = state.Call.Hangup(ctx)
+	// Stop scheduled call timers; persisted schedules are re-armed on restart
+	m.schedulesMu.Lock()
+	for id, entry := range m.schedules {
+		entry.stop()
+		delete(m.schedules, id)
 	}
+	m.schedulesMu.Unlock()
 
+	m.callsMu.Lock()
+	calls := m.calls
 	m.calls = make(map[string]*CallState)
+	m.callsMu.Unlock()
+
+	m.hangupAll(calls)
+
+	if m.transcriptSink != nil {
+		_ = m.transcriptSink.Close()
+	}
+
+	m.hooks.Wait()
 
-	if cs, ok := m.callSystem.(interface{ Close() error }); ok {
-		return cs.Close()
+	if m.ttsCache != nil {
+		stats := m.ttsCache.stats()
+		slog.Info("TTS cache", "hits", stats.Hits, "misses", stats.Misses, "hit_rate", stats.HitRate(), "bytes", stats.Bytes)
 	}
 
+	return closeCallSystem(m.currentCallSystem())
+}
+
+// reset ends all calls and drops the providers created by Initialize so it
+// can run again. Schedules are kept; they are re-armed by Initialize.
+func (m *Manager) reset() {
+	m.callsMu.RLock()
+	calls := maps.Clone(m.calls)
+	m.callsMu.RUnlock()
+
+	if len(calls) > 0 {
+		slog.Warn("ending calls in progress to re-initialize voice", "calls", len(calls))
+	}
+	m.hangupAll(calls)
+	for id := range calls {
+		m.removeCall(id)
+	}
+
+	m.dropProviders()
+}
+
+// dropProviders clears and closes the providers created by Initialize.
+func (m *Manager) dropProviders() {
+	m.providersMu.Lock()
+	cs := m.callSystem
+	m.callSystem, m.smsProvider, m.ttsProvider, m.sttProvider = nil, nil, nil, nil
+	m.providersMu.Unlock()
+
+	if err := closeCallSystem(cs); err != nil {
+		slog.Warn("failed to close call system", "error", err)
+	}
+}
+
+// closeCallSystem closes cs, if it needs closing.
+func closeCallSystem(cs omnivoice.CallSystem) error {
+	if c, ok := cs.(interface{ Close() error }); ok {
+		return c.Close()
+	}
 	return nil
 }
 
+// currentCallSystem returns the call system, or nil until Initialize succeeds.
+func (m *Manager) currentCallSystem() omnivoice.CallSystem {
+	m.providersMu.RLock()
+	defer m.providersMu.RUnlock()
+	return m.callSystem
+}
+
+// currentSMSProvider returns the SMS provider, or nil if the call system
+// cannot send SMS or Initialize has not succeeded.
+func (m *Manager) currentSMSProvider() callsystem.SMSProvider {
+	m.providersMu.RLock()
+	defer m.providersMu.RUnlock()
+	return m.smsProvider
+}
+
+// currentTTSProvider returns the TTS provider, or nil until Initialize succeeds.
+func (m *Manager) currentTTSProvider() omnivoice.TTSProvider {
+	m.providersMu.RLock()
+	defer m.providersMu.RUnlock()
+	return m.ttsProvider
+}
+
+// currentSTTProvider returns the STT provider, or nil until Initialize succeeds.
+func (m *Manager) currentSTTProvider() omnivoice.STTStreamingProvider {
+	m.providersMu.RLock()
+	defer m.providersMu.RUnlock()
+	return m.sttProvider
+}
+
+// currentPublicURL returns the public base URL given to Initialize.
+func (m *Manager) currentPublicURL() string {
+	m.providersMu.RLock()
+	defer m.providersMu.RUnlock()
+	return m.publicURL
+}
+
+// hangupAll hangs up calls concurrently, giving up after m.closeTimeout so
+// a provider that never returns cannot stall shutdown. Calls still hanging
+// up at the deadline are logged.
+func (m *Manager) hangupAll(calls map[string]*CallState) {
+	ctx, cancel := context.WithTimeout(context.Background(), m.closeTimeout)
+	defer cancel()
+
+	var mu sync.Mutex
+	pending := make(map[string]bool, len(calls))
+	var wg sync.WaitGroup
+	for id, state := range calls {
+		state.stopKeepAlive()
+		state.closeSTTSession()
+		state.closeAudioDump()
+		if state.Call == nil {
+			continue
+		}
+
+		pending[id] = true
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := state.Call.Hangup(ctx); err != nil {
+				slog.Warn("failed to hang up call on close", "call_id", id, "error", err)
+			}
+			mu.Lock()
+			delete(pending, id)
+			mu.Unlock()
+		}()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		mu.Lock()
+		for id := range pending {
+			slog.Warn("gave up hanging up call on close", "call_id", id, "timeout", m.closeTimeout)
+		}
+		mu.Unlock()
+	}
+}
+
 // Transport returns the Twilio transport provider for WebSocket handling.
 func (m *Manager) Transport() *twiliotransport.Provider {
-	if cs, ok := m.callSystem.(*twiliosystem.Provider); ok {
+	if cs, ok := m.currentCallSystem().(*twiliosystem.Provider); ok {
 		return cs.Transport()
 	}
 	return nil
@@ -0,0 +1,1092 @@
+package voice
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/plexusone/omnivoice"
+
+	"github.com/plexusone/agentcomms/pkg/config"
+)
+
+// fakeCall is a minimal omnivoice.Call for exercising the manager.
+// Methods not overridden here panic if called.
+type fakeCall struct {
+	omnivoice.Call
+
+	mu        sync.Mutex
+	id        string
+	status    omnivoice.CallStatus
+	transport omnivoice.Transport
+}
+
+func (c *fakeCall) ID() string {
+	return c.id
+}
+
+func (c *fakeCall) Transport() omnivoice.Transport {
+	return c.transport
+}
+
+// fakeConn is a transport connection that records the audio sent to the call.
+type fakeConn struct {
+	omnivoice.Transport
+
+	audio bytes.Buffer
+}
+
+func (c *fakeConn) AudioIn() io.WriteCloser {
+	return nopWriteCloser{&c.audio}
+}
+
+type nopWriteCloser struct{ io.Writer }
+
+func (nopWriteCloser) Close() error { return nil }
+
+// fakeTTS replays scripted streams, one per SynthesizeStream call, and
+// records the text and settings it was asked to synthesize.
+type fakeTTS struct {
+	omnivoice.TTSProvider
+
+	streams  [][]omnivoice.StreamChunk
+	texts    []string
+	configs  []omnivoice.SynthesisConfig
+	voices   map[string]omnivoice.Voice
+	voiceErr error // returned by GetVoice and ListVoices when set
+	lists    int   // ListVoices calls
+
+	nilStream bool // SynthesizeStream returns a nil channel and no error
+}
+
+func (p *fakeTTS) ListVoices(context.Context) ([]omnivoice.Voice, error) {
+	p.lists++
+	if p.voiceErr != nil {
+		return nil, p.voiceErr
+	}
+	var voices []omnivoice.Voice
+	for _, v := range p.voices {
+		voices = append(voices, v)
+	}
+	return voices, nil
+}
+
+func (p *fakeTTS) GetVoice(_ context.Context, voiceID string) (*omnivoice.Voice, error) {
+	if p.voiceErr != nil {
+		return nil, p.voiceErr
+	}
+	v, ok := p.voices[voiceID]
+	if !ok {
+		return nil, errors.New("voice not found")
+	}
+	return &v, nil
+}
+
+func (p *fakeTTS) SynthesizeStream(_ context.Context, text string, cfg omnivoice.SynthesisConfig) (<-chan omnivoice.StreamChunk, error) {
+	p.texts = append(p.texts, text)
+	p.configs = append(p.configs, cfg)
+	if p.nilStream {
+		return nil, nil
+	}
+
+	var chunks []omnivoice.StreamChunk
+	if len(p.streams) > 0 {
+		chunks, p.streams = p.streams[0], p.streams[1:]
+	}
+
+	ch := make(chan omnivoice.StreamChunk, len(chunks))
+	for _, c := range chunks {
+		ch <- c
+	}
+	close(ch)
+	return ch, nil
+}
+
+func (c *fakeCall) Status() omnivoice.CallStatus {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.status
+}
+
+func (c *fakeCall) setStatus(status omnivoice.CallStatus) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.status = status
+}
+
+// newTestManager creates a manager with default config for tests.
+func newTestManager(t testing.TB) *Manager {
+	t.Helper()
+
+	m, err := New(config.DefaultConfig())
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	return m
+}
+
+func TestWaitForAnswer_Event(t *testing.T) {
+	m := newTestManager(t)
+	call := &fakeCall{status: omnivoice.StatusRinging}
+	events := make(chan omnivoice.CallStatus, 1)
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		events <- omnivoice.StatusAnswered
+	}()
+
+	start := time.Now()
+	answered := m.waitForAnswer(context.Background(), call, events, 5*time.Second)
+	elapsed := time.Since(start)
+
+	if !answered {
+		t.Fatal("expected call to be answered")
+	}
+	if elapsed > 100*time.Millisecond {
+		t.Errorf("answer detected after %v, want < 100ms", elapsed)
+	}
+}
+
+func TestWaitForAnswer_TerminalEvent(t *testing.T) {
+	m := newTestManager(t)
+	call := &fakeCall{status: omnivoice.StatusRinging}
+	events := make(chan omnivoice.CallStatus, 1)
+	events <- omnivoice.StatusBusy
+
+	if m.waitForAnswer(context.Background(), call, events, 5*time.Second) {
+		t.Error("expected busy call not to be answered")
+	}
+}
+
+func TestWaitForAnswer_PollFallback(t *testing.T) {
+	m := newTestManager(t)
+	call := &fakeCall{status: omnivoice.StatusRinging}
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		call.setStatus(omnivoice.StatusAnswered)
+	}()
+
+	if !m.waitForAnswer(context.Background(), call, nil, 5*time.Second) {
+		t.Error("expected polled call to be answered")
+	}
+}
+
+func TestWaitForAnswer_PollInterval(t *testing.T) {
+	m := newTestManager(t)
+	m.config.StatusPollMS = 50
+	call := &fakeCall{status: omnivoice.StatusRinging}
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		call.setStatus(omnivoice.StatusAnswered)
+	}()
+
+	start := time.Now()
+	if !m.waitForAnswer(context.Background(), call, nil, 5*time.Second) {
+		t.Fatal("expected polled call to be answered")
+	}
+	if elapsed := time.Since(start); elapsed >= defaultStatusPollInterval {
+		t.Errorf("answer noticed after %v, want the 50ms poll interval used", elapsed)
+	}
+}
+
+func TestWaitForAnswer_Timeout(t *testing.T) {
+	m := newTestManager(t)
+	call := &fakeCall{status: omnivoice.StatusRinging}
+
+	if m.waitForAnswer(context.Background(), call, nil, 20*time.Millisecond) {
+		t.Error("expected timeout")
+	}
+}
+
+func TestWaitForAnswer_ContextCancelled(t *testing.T) {
+	m := newTestManager(t)
+	call := &fakeCall{status: omnivoice.StatusRinging}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if m.waitForAnswer(ctx, call, nil, 5*time.Second) {
+		t.Error("expected cancelled context to stop waiting")
+	}
+}
+
+func TestNotifyStatus(t *testing.T) {
+	m := newTestManager(t)
+	call := &fakeCall{id: "CA123", status: omnivoice.StatusRinging}
+	state := &CallState{
+		ID:       "call-1",
+		Call:     call,
+		statusCh: make(chan omnivoice.CallStatus, statusBufferSize),
+	}
+	m.calls[state.ID] = state
+
+	m.NotifyStatus("CA123", omnivoice.StatusAnswered)
+	m.NotifyStatus("CA999", omnivoice.StatusEnded) // unknown call is ignored
+
+	select {
+	case status := <-state.statusCh:
+		if status != omnivoice.StatusAnswered {
+			t.Errorf("status = %q, want %q", status, omnivoice.StatusAnswered)
+		}
+	default:
+		t.Fatal("expected status to be delivered")
+	}
+
+	select {
+	case status := <-state.statusCh:
+		t.Errorf("unexpected status %q", status)
+	default:
+	}
+}
+
+func TestStatusFromTwilio(t *testing.T) {
+	tests := []struct {
+		input string
+		want  omnivoice.CallStatus
+		ok    bool
+	}{
+		{"ringing", omnivoice.StatusRinging, true},
+		{"in-progress", omnivoice.StatusAnswered, true},
+		{"completed", omnivoice.StatusEnded, true},
+		{"busy", omnivoice.StatusBusy, true},
+		{"no-answer", omnivoice.StatusNoAnswer, true},
+		{"failed", omnivoice.StatusFailed, true},
+		{"bogus", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, ok := StatusFromTwilio(tt.input)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("StatusFromTwilio(%q) = (%q, %v), want (%q, %v)", tt.input, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+// sendEvents returns a closed channel pre-loaded with the given events.
+func sendEvents(events ...omnivoice.StreamEvent) <-chan omnivoice.StreamEvent {
+	ch := make(chan omnivoice.StreamEvent, len(events))
+	for _, e := range events {
+		ch <- e
+	}
+	close(ch)
+	return ch
+}
+
+func TestAwaitTranscript_FirstFinal(t *testing.T) {
+	m := newTestManager(t)
+	state := &CallState{ID: "call-1"}
+
+	events := make(chan omnivoice.StreamEvent, 3)
+	events <- omnivoice.StreamEvent{Transcript: "Yes"}
+	events <- omnivoice.StreamEvent{Transcript: "Yes.", IsFinal: true}
+	events <- omnivoice.StreamEvent{Transcript: "Actually, wait", IsFinal: true}
+
+	got, err := m.awaitTranscript(context.Background(), state, events, nil)
+	if err != nil {
+		t.Fatalf("awaitTranscript() error = %v", err)
+	}
+	if got != "Yes." {
+		t.Errorf("transcript = %q, want %q", got, "Yes.")
+	}
+	if len(state.Conversation) != 1 || state.LastUserMessage != "Yes." {
+		t.Errorf("expected one user turn %q, got %+v", "Yes.", state.Conversation)
+	}
+}
+
+func TestAwaitTranscript_AggregateFinals(t *testing.T) {
+	m := newTestManager(t)
+	m.config.AggregateFinalsMS = 50
+	state := &CallState{ID: "call-1"}
+
+	events := make(chan omnivoice.StreamEvent)
+	go func() {
+		events <- omnivoice.StreamEvent{Transcript: "Yes.", IsFinal: true}
+		time.Sleep(10 * time.Millisecond)
+		events <- omnivoice.StreamEvent{Transcript: "Actually"}
+		time.Sleep(10 * time.Millisecond)
+		events <- omnivoice.StreamEvent{Transcript: "Actually, wait.", IsFinal: true}
+	}()
+
+	start := time.Now()
+	got, err := m.awaitTranscript(context.Background(), state, events, nil)
+	if err != nil {
+		t.Fatalf("awaitTranscript() error = %v", err)
+	}
+	if want := "Yes. Actually, wait."; got != want {
+		t.Errorf("transcript = %q, want %q", got, want)
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("returned after %v, want to wait out the aggregation window", elapsed)
+	}
+	if len(state.Conversation) != 1 {
+		t.Errorf("expected a single user turn, got %d", len(state.Conversation))
+	}
+}
+
+func TestAwaitTranscript_MaxUtterance(t *testing.T) {
+	m := newTestManager(t)
+	m.config.AggregateFinalsMS = 1000
+	m.config.MaxUtteranceMS = 50
+	state := &CallState{ID: "call-1"}
+
+	// Speech keeps extending the aggregation window; only the utterance
+	// cap ends the turn
+	events := make(chan omnivoice.StreamEvent)
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		for i := 0; ; i++ {
+			e := omnivoice.StreamEvent{Transcript: "and another thing", IsFinal: i == 0}
+			select {
+			case events <- e:
+			case <-stop:
+				return
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}()
+
+	start := time.Now()
+	got, err := m.awaitTranscript(context.Background(), state, events, nil)
+	if err != nil {
+		t.Fatalf("awaitTranscript() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("returned after %v, want the 50ms utterance cap", elapsed)
+	}
+	if want := "and another thing and another thing"; got != want {
+		t.Errorf("transcript = %q, want %q", got, want)
+	}
+}
+
+func TestAwaitTranscript_MaxUtteranceStartsAtFirstWord(t *testing.T) {
+	m := newTestManager(t)
+	m.config.MaxUtteranceMS = 30
+	state := &CallState{ID: "call-1"}
+
+	// Waiting for the user to start does not count against the cap
+	events := make(chan omnivoice.StreamEvent)
+	go func() {
+		time.Sleep(60 * time.Millisecond)
+		events <- omnivoice.StreamEvent{Transcript: "Okay.", IsFinal: true}
+	}()
+
+	got, err := m.awaitTranscript(context.Background(), state, events, nil)
+	if err != nil {
+		t.Fatalf("awaitTranscript() error = %v", err)
+	}
+	if got != "Okay." {
+		t.Errorf("transcript = %q, want %q", got, "Okay.")
+	}
+}
+
+func TestAwaitTranscript_ChannelClosed(t *testing.T) {
+	m := newTestManager(t)
+	m.config.AggregateFinalsMS = 1000
+	state := &CallState{ID: "call-1"}
+
+	got, err := m.awaitTranscript(context.Background(), state, sendEvents(
+		omnivoice.StreamEvent{Transcript: "Sounds good.", IsFinal: true},
+		omnivoice.StreamEvent{Transcript: "Thanks"},
+	), nil)
+	if err != nil {
+		t.Fatalf("awaitTranscript() error = %v", err)
+	}
+	if want := "Sounds good. Thanks"; got != want {
+		t.Errorf("transcript = %q, want %q", got, want)
+	}
+}
+
+func TestListen_OnlyPartialsThenClosed(t *testing.T) {
+	// Some providers close the stream on silence without a final result
+	for _, persist := range []bool{false, true} {
+		m := newTestManager(t)
+		m.config.STTPersistConnection = persist
+		stt := &fakeSTT{}
+		m.sttProvider = stt
+		state := &CallState{ID: "call-1", Call: &fakeCall{transport: &audioSource{r: strings.NewReader("")}}}
+
+		go func() {
+			for {
+				stt.mu.Lock()
+				if len(stt.streams) > 0 {
+					events := stt.streams[0]
+					stt.mu.Unlock()
+					events <- omnivoice.StreamEvent{Transcript: "Ship"}
+					events <- omnivoice.StreamEvent{Transcript: "Ship it today"}
+					close(events)
+					return
+				}
+				stt.mu.Unlock()
+				time.Sleep(time.Millisecond)
+			}
+		}()
+
+		got, err := m.listen(context.Background(), state, nil)
+		if err != nil {
+			t.Fatalf("listen() with persist %v error = %v", persist, err)
+		}
+		if got != "Ship it today" {
+			t.Errorf("listen() with persist %v = %q, want the last partial", persist, got)
+		}
+		if len(state.Conversation) != 1 || state.Conversation[0].Role != "user" || state.Conversation[0].Content != "Ship it today" {
+			t.Errorf("conversation with persist %v = %+v, want the last partial as a user turn", persist, state.Conversation)
+		}
+		state.closeSTTSession()
+	}
+}
+
+func TestAwaitTranscript_ChannelClosedEarly(t *testing.T) {
+	m := newTestManager(t)
+	state := &CallState{ID: "call-1"}
+
+	_, err := m.awaitTranscript(context.Background(), state, sendEvents(), nil)
+	if !errors.Is(err, errSTTClosed) {
+		t.Fatalf("awaitTranscript() error = %v, want errSTTClosed", err)
+	}
+	if errors.Is(speechError(err), ErrNoSpeech) {
+		t.Error("closed STT stream reported as no speech")
+	}
+}
+
+func TestAwaitTranscript_NoSpeech(t *testing.T) {
+	m := newTestManager(t)
+	m.config.TranscriptTimeoutMS = 20
+	state := &CallState{ID: "call-1"}
+
+	events := make(chan omnivoice.StreamEvent, 1)
+	events <- omnivoice.StreamEvent{Transcript: ""}
+
+	_, err := m.awaitTranscript(context.Background(), state, events, nil)
+	if !errors.Is(err, ErrNoSpeech) {
+		t.Fatalf("awaitTranscript() error = %v, want ErrNoSpeech", err)
+	}
+	if len(state.Conversation) != 0 {
+		t.Errorf("expected no user turn, got %+v", state.Conversation)
+	}
+}
+
+func TestSpeechError(t *testing.T) {
+	if err := speechError(fmt.Errorf("failed to listen: %w", ErrNoSpeech)); err != ErrNoSpeech {
+		t.Errorf("speechError(no speech) = %v, want ErrNoSpeech", err)
+	}
+
+	err := speechError(errors.New("tts down"))
+	if !errors.Is(err, ErrSpeechFailed) {
+		t.Errorf("speechError() = %v, want ErrSpeechFailed", err)
+	}
+}
+
+func TestPumpAudio_DropsSelfEcho(t *testing.T) {
+	r, w := io.Pipe()
+	var got bytes.Buffer
+	done := make(chan struct{})
+
+	go func() {
+		pumpAudio(context.Background(), r, &got, time.Now().Add(30*time.Millisecond))
+		close(done)
+	}()
+
+	// Tail of our own TTS playback arrives during the grace period
+	_, _ = w.Write([]byte("echo"))
+	time.Sleep(50 * time.Millisecond)
+	_, _ = w.Write([]byte("user"))
+	_ = w.Close()
+	<-done
+
+	if got.String() != "user" {
+		t.Errorf("STT received %q, want only %q", got.String(), "user")
+	}
+}
+
+func TestInEchoWindow(t *testing.T) {
+	state := &CallState{}
+	now := time.Now()
+
+	if state.inEchoWindow(now, time.Second) {
+		t.Error("fresh call should not be in the echo window")
+	}
+
+	state.setSpeaking(true)
+	if !state.inEchoWindow(now, 0) {
+		t.Error("expected echo window while speaking")
+	}
+
+	state.setSpeaking(false)
+	if !state.inEchoWindow(time.Now(), time.Second) {
+		t.Error("expected echo window right after speaking")
+	}
+	if state.inEchoWindow(time.Now().Add(2*time.Second), time.Second) {
+		t.Error("expected echo window to close after the guard")
+	}
+}
+
+func TestAwaitTranscript_DiscardsEcho(t *testing.T) {
+	m := newTestManager(t)
+	m.config.EchoGuardMS = 30
+	state := &CallState{ID: "call-1"}
+	state.setSpeaking(true)
+
+	events := make(chan omnivoice.StreamEvent)
+	go func() {
+		// Our own prompt picked up on speakerphone
+		events <- omnivoice.StreamEvent{Transcript: "Should I deploy now?", IsFinal: true}
+		state.setSpeaking(false)
+		events <- omnivoice.StreamEvent{Transcript: "deploy now", IsFinal: true}
+		time.Sleep(50 * time.Millisecond)
+		events <- omnivoice.StreamEvent{Transcript: "Yes, go ahead.", IsFinal: true}
+	}()
+
+	got, err := m.awaitTranscript(context.Background(), state, events, nil)
+	if err != nil {
+		t.Fatalf("awaitTranscript() error = %v", err)
+	}
+	if got != "Yes, go ahead." {
+		t.Errorf("transcript = %q, want the user's reply only", got)
+	}
+}
+
+func TestAwaitTranscript_DiscardsLateEchoFinal(t *testing.T) {
+	m := newTestManager(t)
+	m.config.EchoGuardMS = 30
+	state := &CallState{ID: "call-1"}
+	state.setSpeaking(true)
+
+	events := make(chan omnivoice.StreamEvent)
+	go func() {
+		// Echo starts during playback; its final arrives after the guard
+		events <- omnivoice.StreamEvent{Transcript: "Should I"}
+		state.finishPlayback(time.Now())
+		time.Sleep(50 * time.Millisecond)
+		events <- omnivoice.StreamEvent{Transcript: "Should I deploy now?", IsFinal: true}
+		events <- omnivoice.StreamEvent{Transcript: "Yes.", IsFinal: true}
+	}()
+
+	got, err := m.awaitTranscript(context.Background(), state, events, nil)
+	if err != nil {
+		t.Fatalf("awaitTranscript() error = %v", err)
+	}
+	if got != "Yes." {
+		t.Errorf("transcript = %q, want the user's reply only", got)
+	}
+}
+
+func TestAwaitTranscript_EchoGuardOff(t *testing.T) {
+	m := newTestManager(t)
+	state := &CallState{ID: "call-1"}
+	state.finishPlayback(time.Now().Add(time.Second))
+
+	got, err := m.awaitTranscript(context.Background(), state, sendEvents(
+		omnivoice.StreamEvent{Transcript: "Yes.", IsFinal: true},
+	), nil)
+	if err != nil {
+		t.Fatalf("awaitTranscript() error = %v", err)
+	}
+	if got != "Yes." {
+		t.Errorf("transcript = %q, want a reply during playback kept", got)
+	}
+}
+
+func TestSpeak_RetriesAfterStreamError(t *testing.T) {
+	m := newTestManager(t)
+	tts := &fakeTTS{streams: [][]omnivoice.StreamChunk{
+		{{Audio: []byte("aaaa")}, {Error: errors.New("connection reset")}},
+		{{Audio: []byte("bbbb"), IsFinal: true}},
+	}}
+	m.ttsProvider = tts
+	conn := &fakeConn{}
+	state := &CallState{ID: "call-1", Call: &fakeCall{transport: conn}}
+
+	if err := m.speak(context.Background(), state, "Hello there."); err != nil {
+		t.Fatalf("speak() error = %v", err)
+	}
+	if got := conn.audio.String(); got != "aaaabbbb" {
+		t.Errorf("audio = %q, want %q", got, "aaaabbbb")
+	}
+	if len(tts.texts) != 2 || tts.texts[1] != "Hello there." {
+		t.Errorf("synthesized %q, want a retry of the message", tts.texts)
+	}
+}
+
+func TestSpeak_ApologizesWhenRetriesExhausted(t *testing.T) {
+	m := newTestManager(t)
+	m.config.TTSStreamRetries = 0
+	tts := &fakeTTS{streams: [][]omnivoice.StreamChunk{
+		{{Audio: []byte("aaaa")}, {Error: errors.New("connection reset")}},
+		{{Audio: []byte("sorry"), IsFinal: true}},
+	}}
+	m.ttsProvider = tts
+	conn := &fakeConn{}
+	state := &CallState{ID: "call-1", Call: &fakeCall{transport: conn}}
+
+	if err := m.speak(context.Background(), state, "Hello there."); err == nil {
+		t.Fatal("speak() expected error")
+	}
+	if len(tts.texts) != 2 || tts.texts[1] != ttsApology {
+		t.Errorf("synthesized %q, want an apology after the failure", tts.texts)
+	}
+	if got := conn.audio.String(); got != "aaaasorry" {
+		t.Errorf("audio = %q, want %q", got, "aaaasorry")
+	}
+}
+
+func TestSpeak_NilStream(t *testing.T) {
+	m := newTestManager(t)
+	m.ttsProvider = &fakeTTS{nilStream: true}
+	state := &CallState{ID: "call-1", Call: &fakeCall{transport: &fakeConn{}}}
+
+	done := make(chan error, 1)
+	go func() { done <- m.speak(context.Background(), state, "Hello there.") }()
+	select {
+	case err := <-done:
+		if err == nil || !strings.Contains(err.Error(), "no audio stream") {
+			t.Errorf("speak() error = %v, want no audio stream", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("speak() blocked on a nil TTS stream")
+	}
+}
+
+// resettingTTS drops the manager's providers after its first stream, as a
+// re-initialization would in the middle of a turn.
+type resettingTTS struct {
+	*fakeTTS
+	m *Manager
+}
+
+func (p *resettingTTS) SynthesizeStream(ctx context.Context, text string, cfg omnivoice.SynthesisConfig) (<-chan omnivoice.StreamChunk, error) {
+	stream, err := p.fakeTTS.SynthesizeStream(ctx, text, cfg)
+	if len(p.texts) == 1 {
+		p.m.dropProviders()
+	}
+	return stream, err
+}
+
+func TestSpeak_ResetDuringTurn(t *testing.T) {
+	m := newTestManager(t)
+	m.config.TTSStreamRetries = 1
+	tts := &resettingTTS{fakeTTS: &fakeTTS{streams: [][]omnivoice.StreamChunk{
+		{{Error: errors.New("stream dropped")}},
+		{{Audio: []byte("rest"), IsFinal: true}},
+	}}, m: m}
+	m.ttsProvider = tts
+	conn := &fakeConn{}
+	state := &CallState{ID: "call-1", Call: &fakeCall{transport: conn}}
+
+	// The retry runs after the providers were dropped and still uses the
+	// provider the turn started with
+	if err := m.speak(context.Background(), state, "Hello there."); err != nil {
+		t.Fatalf("speak() error = %v", err)
+	}
+	if len(tts.texts) != 2 || conn.audio.String() != "rest" {
+		t.Errorf("synthesized %q and sent %q, want a retry on the same provider", tts.texts, conn.audio.String())
+	}
+
+	// Later turns report that voice is not initialized instead of panicking
+	if err := m.speak(context.Background(), state, "Still there?"); !errors.Is(err, ErrNotInitialized) {
+		t.Errorf("speak() after reset error = %v, want ErrNotInitialized", err)
+	}
+	if _, err := m.listen(context.Background(), state, nil); !errors.Is(err, ErrNotInitialized) {
+		t.Errorf("listen() after reset error = %v, want ErrNotInitialized", err)
+	}
+}
+
+func TestRemainingText(t *testing.T) {
+	text := "First sentence here. Second one is a bit longer! Third?"
+	oneSecond := time.Second
+
+	tests := []struct {
+		name   string
+		played time.Duration
+		want   string
+	}{
+		{"nothing played", 0, text},
+		{"inside first sentence", oneSecond / 2, text},
+		{"inside second sentence", 2 * oneSecond, "Second one is a bit longer! Third?"},
+		{"past the end", 100 * oneSecond, "Third?"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := remainingText(text, tt.played); got != tt.want {
+				t.Errorf("remainingText() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if got := remainingText("", oneSecond); got != "" {
+		t.Errorf("remainingText(\"\") = %q, want empty", got)
+	}
+}
+
+func TestAwaitTranscript_ReportsPartials(t *testing.T) {
+	m := newTestManager(t)
+	m.config.AggregateFinalsMS = 1000
+	state := &CallState{ID: "call-1"}
+
+	var partials []string
+	got, err := m.awaitTranscript(context.Background(), state, sendEvents(
+		omnivoice.StreamEvent{Transcript: "I think"},
+		omnivoice.StreamEvent{Transcript: "I think we should", IsFinal: true},
+		omnivoice.StreamEvent{Transcript: "ship it"},
+	), func(text string) { partials = append(partials, text) })
+	if err != nil {
+		t.Fatalf("awaitTranscript() error = %v", err)
+	}
+
+	want := []string{"I think", "I think we should", "I think we should ship it"}
+	if len(partials) != len(want) {
+		t.Fatalf("partials = %q, want %q", partials, want)
+	}
+	for i := range want {
+		if partials[i] != want[i] {
+			t.Errorf("partials[%d] = %q, want %q", i, partials[i], want[i])
+		}
+	}
+	if got != want[2] {
+		t.Errorf("transcript = %q, want %q", got, want[2])
+	}
+}
+
+func TestInitiateCall_NilCall(t *testing.T) {
+	m := newTestManager(t)
+	m.config.MaxCallsPerDay = 1
+	m.config.CallerIDName = "Agent"
+
+	// A misbehaving provider reports success without a call
+	m.callSystem = &dialCallSystem{}
+
+	_, _, err := m.InitiateCall(context.Background(), "Hello")
+	if !errors.Is(err, ErrDialFailed) {
+		t.Fatalf("InitiateCall() error = %v, want ErrDialFailed", err)
+	}
+	if len(m.calls) != 0 {
+		t.Errorf("%d calls registered, want 0", len(m.calls))
+	}
+
+	// The failed dial does not use up the budget
+	if _, err := m.reserveCall(time.Now()); err != nil {
+		t.Errorf("reserveCall() error = %v", err)
+	}
+}
+
+// liveHangupCall is a fakeCall that sends the error of the context it is
+// hung up with, so tests can check the hangup was not already cancelled.
+type liveHangupCall struct {
+	*fakeCall
+
+	hungUp chan error
+}
+
+func (c liveHangupCall) Hangup(ctx context.Context) error {
+	c.hungUp <- ctx.Err()
+	return nil
+}
+
+func TestInitiateCall_CancelledWhileRinging(t *testing.T) {
+	m := newTestManager(t)
+	m.config.SMSFallbackEnabled = true
+	call := liveHangupCall{&fakeCall{id: "CA-1", status: omnivoice.StatusRinging}, make(chan error, 1)}
+	m.callSystem = &dialCallSystem{call: call}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	state, _, err := m.InitiateCall(ctx, "The build is done.")
+	if !errors.Is(err, context.Canceled) || state != nil {
+		t.Fatalf("InitiateCall() = %v, %v; want no call and context.Canceled", state, err)
+	}
+	select {
+	case hangupErr := <-call.hungUp:
+		if hangupErr != nil {
+			t.Errorf("hung up with a done context: %v", hangupErr)
+		}
+	default:
+		t.Fatal("call left ringing")
+	}
+	if len(m.calls) != 0 {
+		t.Errorf("%d calls registered, want 0", len(m.calls))
+	}
+
+	// Giving up is not the user missing the call
+	if _, ok := m.missed.take(time.Now()); ok {
+		t.Error("cancelled call was recorded as missed")
+	}
+}
+
+func TestInitiateCall_CancelledWhileWaitingToAccept(t *testing.T) {
+	m := newTestManager(t)
+	m.config.RequireAccept = true
+	call := liveHangupCall{&fakeCall{id: "CA-1", status: omnivoice.StatusAnswered}, make(chan error, 1)}
+	m.callSystem = &dialCallSystem{call: call}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, _, err := m.InitiateCall(ctx, "The build is done.")
+	if !errors.Is(err, context.DeadlineExceeded) || errors.Is(err, ErrDeclined) {
+		t.Fatalf("InitiateCall() error = %v, want context.DeadlineExceeded", err)
+	}
+	if hangupErr := <-call.hungUp; hangupErr != nil {
+		t.Errorf("hung up with a done context: %v", hangupErr)
+	}
+	if len(m.calls) != 0 {
+		t.Errorf("%d calls registered, want 0", len(m.calls))
+	}
+}
+
+// errDialCallSystem fails every MakeCall with err and counts the attempts.
+type errDialCallSystem struct {
+	omnivoice.CallSystem
+
+	err   error
+	dials int
+}
+
+func (d *errDialCallSystem) MakeCall(context.Context, string, ...omnivoice.CallOption) (omnivoice.Call, error) {
+	d.dials++
+	return nil, d.err
+}
+
+func TestDial_CallerIDNameRetry(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		wantDials int
+	}{
+		{"name unsupported", errors.New("caller ID name is not supported for this number"), 2},
+		{"invalid CNAM", errors.New("invalid CNAM value"), 2},
+		{"invalid number", errors.New("invalid 'To' phone number"), 1},
+		{"auth", errors.New("authenticate: 401 unauthorized"), 1},
+		{"timeout", context.DeadlineExceeded, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestManager(t)
+			m.config.CallerIDName = "Agent"
+			cs := &errDialCallSystem{err: tt.err}
+			m.callSystem = cs
+
+			if _, err := m.dial(context.Background(), "+15550001111"); !errors.Is(err, ErrDialFailed) {
+				t.Fatalf("dial() error = %v, want ErrDialFailed", err)
+			}
+			if cs.dials != tt.wantDials {
+				t.Errorf("dials = %d, want %d", cs.dials, tt.wantDials)
+			}
+		})
+	}
+}
+
+// blockingCall is a call whose Hangup ignores its context and never
+// returns until released.
+type blockingCall struct {
+	*fakeCall
+
+	release chan struct{}
+}
+
+func (c blockingCall) Hangup(context.Context) error {
+	<-c.release
+	return nil
+}
+
+func TestClose_StuckHangup(t *testing.T) {
+	m := newTestManager(t)
+	m.closeTimeout = 50 * time.Millisecond
+
+	stuck := blockingCall{fakeCall: &fakeCall{id: "CA-stuck"}, release: make(chan struct{})}
+	defer close(stuck.release)
+	hungUp := make(chan struct{})
+	m.calls["call-1"] = &CallState{ID: "call-1", Call: stuck}
+	m.calls["call-2"] = &CallState{ID: "call-2", Call: notifyHangupCall{&fakeCall{id: "CA-ok"}, hungUp}}
+
+	start := time.Now()
+	if err := m.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Close() took %v with a stuck hangup, want about %v", elapsed, m.closeTimeout)
+	}
+
+	select {
+	case <-hungUp:
+	default:
+		t.Error("healthy call was not hung up")
+	}
+	if len(m.calls) != 0 {
+		t.Errorf("%d calls left after Close, want 0", len(m.calls))
+	}
+}
+
+func TestReset(t *testing.T) {
+	m := newTestManager(t)
+	cs := &closingCallSystem{}
+	m.callSystem = cs
+	m.ttsProvider = &fakeTTS{}
+
+	hungUp := make(chan struct{})
+	state := &CallState{ID: "call-1", Call: notifyHangupCall{&fakeCall{id: "CA-1"}, hungUp}}
+	m.calls[state.ID] = state
+	m.conferences[state.ID] = &ConferenceState{Name: "agentcomms-call-1", Call: state}
+
+	m.reset()
+
+	select {
+	case <-hungUp:
+	default:
+		t.Error("call in progress was not hung up")
+	}
+	if len(m.calls) != 0 || len(m.conferences) != 0 {
+		t.Errorf("%d calls and %d conferences left after reset, want 0", len(m.calls), len(m.conferences))
+	}
+	if !cs.closed {
+		t.Error("old call system was not closed")
+	}
+	if m.callSystem != nil || m.ttsProvider != nil {
+		t.Error("providers kept after reset")
+	}
+}
+
+// registerFakeProviders registers a call system that fails every dial and
+// fake TTS and STT providers under name, and selects them in cfg.
+func registerFakeProviders(cfg *config.Config, name string) {
+	omnivoice.RegisterCallSystemProvider(name, func(omnivoice.ProviderConfig) (omnivoice.CallSystem, error) {
+		return &closingCallSystem{CallSystem: &errDialCallSystem{err: errors.New("line down")}}, nil
+	})
+	omnivoice.RegisterTTSProvider(name, func(omnivoice.ProviderConfig) (omnivoice.TTSProvider, error) {
+		return &fakeTTS{}, nil
+	})
+	omnivoice.RegisterSTTProvider(name, func(omnivoice.ProviderConfig) (omnivoice.STTProvider, error) {
+		return &fakeSTT{}, nil
+	})
+	cfg.PhoneProvider, cfg.TTSProvider, cfg.STTProvider = name, name, name
+}
+
+// Run with -race: Initialize publishes providers while calls read them.
+func TestInitialize_ConcurrentWithCalls(t *testing.T) {
+	m := newTestManager(t)
+	m.config.UserPhoneNumber = "+15559876543"
+	registerFakeProviders(m.config, "fake-concurrent")
+	defer func() { _ = m.Close() }()
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := range 20 {
+			if err := m.Initialize(fmt.Sprintf("https://%d.example.com", i)); err != nil {
+				t.Errorf("Initialize() error = %v", err)
+			}
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for range 20 {
+			// Dials fail whether or not the providers are published yet
+			_, _, err := m.InitiateCall(context.Background(), "hi")
+			if !errors.Is(err, ErrNotInitialized) && !errors.Is(err, ErrDialFailed) {
+				t.Errorf("InitiateCall() error = %v, want ErrNotInitialized or ErrDialFailed", err)
+			}
+		}
+	}()
+	wg.Wait()
+
+	if m.currentCallSystem() == nil || m.currentPublicURL() != "https://19.example.com" {
+		t.Errorf("providers after Initialize = %v at %q, want the last ones", m.currentCallSystem(), m.currentPublicURL())
+	}
+}
+
+func TestInitialize_PublishesOnlyOnSuccess(t *testing.T) {
+	m := newTestManager(t)
+	registerFakeProviders(m.config, "fake-partial")
+	defer func() { _ = m.Close() }()
+
+	var created *closingCallSystem
+	omnivoice.RegisterCallSystemProvider("fake-partial", func(omnivoice.ProviderConfig) (omnivoice.CallSystem, error) {
+		created = &closingCallSystem{}
+		return created, nil
+	})
+	m.config.STTProvider = "fake-missing"
+
+	if err := m.Initialize("https://example.com"); err == nil {
+		t.Fatal("Initialize() succeeded without an STT provider")
+	}
+	if m.currentCallSystem() != nil || m.currentTTSProvider() != nil || m.currentPublicURL() != "" {
+		t.Error("providers published after a failed Initialize")
+	}
+	if created == nil || !created.closed {
+		t.Error("call system created by the failed Initialize was not closed")
+	}
+}
+
+// closingCallSystem records whether it was closed.
+type closingCallSystem struct {
+	omnivoice.CallSystem
+	closed bool
+}
+
+func (c *closingCallSystem) Close() error {
+	c.closed = true
+	return nil
+}
+
+// notifyHangupCall closes hungUp when hung up.
+type notifyHangupCall struct {
+	*fakeCall
+
+	hungUp chan struct{}
+}
+
+func (c notifyHangupCall) Hangup(context.Context) error {
+	close(c.hungUp)
+	return nil
+}
+
+func TestFinishPlayback(t *testing.T) {
+	state := &CallState{}
+	state.setSpeaking(true)
+
+	// Two seconds of audio handed over just now is still playing
+	playedUntil := time.Now().Add(codecMulaw.playbackDuration(2 * ulawBytesPerSecond))
+	state.finishPlayback(playedUntil)
+	if state.speaking {
+		t.Error("still speaking after finishPlayback")
+	}
+	if got := state.playbackEnd(); !got.Equal(playedUntil) {
+		t.Errorf("playbackEnd() = %v, want %v", got, playedUntil)
+	}
+
+	// An estimate in the past never moves the end before now
+	before := time.Now()
+	state.finishPlayback(before.Add(-time.Minute))
+	if got := state.playbackEnd(); got.Before(before) {
+		t.Errorf("playbackEnd() = %v, want >= %v", got, before)
+	}
+}
+
+func TestNotInitialized_ReportsInitError(t *testing.T) {
+	m := newTestManager(t)
+	if _, _, err := m.InitiateCall(context.Background(), "hello"); !errors.Is(err, ErrNotInitialized) {
+		t.Fatalf("InitiateCall() before Initialize error = %v, want ErrNotInitialized", err)
+	}
+
+	m.initErr = errors.New("failed to create TTS provider: 401 Unauthorized")
+	_, _, err := m.InitiateCall(context.Background(), "hello")
+	if !errors.Is(err, ErrNotInitialized) || !strings.Contains(err.Error(), "401 Unauthorized") {
+		t.Errorf("InitiateCall() error = %v, want ErrNotInitialized with the cause", err)
+	}
+	if _, err := m.ContinueCall(context.Background(), "call-1", "hello"); !errors.Is(err, ErrNotInitialized) {
+		t.Errorf("ContinueCall() error = %v, want ErrNotInitialized", err)
+	}
+}
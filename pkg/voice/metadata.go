@@ -0,0 +1,58 @@
+package voice
+
+import (
+	"context"
+	"fmt"
+	"maps"
+)
+
+// Call metadata limits, so metadata stays small enough to repeat in logs,
+// transcript entries, and webhooks.
+const (
+	MaxCallMetadataEntries  = 20
+	MaxCallMetadataKeyLen   = 64
+	MaxCallMetadataValueLen = 256
+)
+
+// metadataKey is the context key for call metadata.
+type metadataKey struct{}
+
+// WithCallMetadata returns a context that attaches metadata, such as the
+// repository or task a call is about, to calls placed with it. The
+// metadata is logged, added to transcript entries, and included in the
+// call-ended webhook, so calls can be matched to the work they were about.
+func WithCallMetadata(ctx context.Context, metadata map[string]string) context.Context {
+	if len(metadata) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, metadataKey{}, maps.Clone(metadata))
+}
+
+// callMetadata returns the metadata attached to ctx, or nil.
+func callMetadata(ctx context.Context) map[string]string {
+	metadata, _ := ctx.Value(metadataKey{}).(map[string]string)
+	return metadata
+}
+
+// ValidateCallMetadata checks metadata against the size limits.
+func ValidateCallMetadata(metadata map[string]string) error {
+	if len(metadata) > MaxCallMetadataEntries {
+		return fmt.Errorf("%w: %d entries (limit %d)", ErrInvalidMetadata, len(metadata), MaxCallMetadataEntries)
+	}
+	for k, v := range metadata {
+		switch {
+		case k == "":
+			return fmt.Errorf("%w: empty key", ErrInvalidMetadata)
+		case len(k) > MaxCallMetadataKeyLen:
+			return fmt.Errorf("%w: a key is longer than %d bytes", ErrInvalidMetadata, MaxCallMetadataKeyLen)
+		case len(v) > MaxCallMetadataValueLen:
+			return fmt.Errorf("%w: value of %q is longer than %d bytes", ErrInvalidMetadata, k, MaxCallMetadataValueLen)
+		}
+	}
+	return nil
+}
+
+// Metadata returns a copy of the metadata the call was placed with, or nil.
+func (cs *CallState) Metadata() map[string]string {
+	return maps.Clone(cs.metadata)
+}
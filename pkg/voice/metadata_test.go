@@ -0,0 +1,98 @@
+package voice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/plexusone/omnivoice"
+)
+
+func TestValidateCallMetadata(t *testing.T) {
+	many := make(map[string]string)
+	for i := range MaxCallMetadataEntries + 1 {
+		many[fmt.Sprintf("k%d", i)] = "v"
+	}
+
+	tests := []struct {
+		name     string
+		metadata map[string]string
+		wantErr  bool
+	}{
+		{"nil", nil, false},
+		{"repo and task", map[string]string{"repo": "org/app", "task": "JIRA-123"}, false},
+		{"empty value", map[string]string{"task": ""}, false},
+		{"empty key", map[string]string{"": "x"}, true},
+		{"long key", map[string]string{strings.Repeat("k", MaxCallMetadataKeyLen+1): "x"}, true},
+		{"long value", map[string]string{"k": strings.Repeat("v", MaxCallMetadataValueLen+1)}, true},
+		{"too many", many, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateCallMetadata(tt.metadata)
+			if (err != nil) != tt.wantErr || (err != nil && !errors.Is(err, ErrInvalidMetadata)) {
+				t.Errorf("ValidateCallMetadata() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDial_StoresMetadata(t *testing.T) {
+	m := newTestManager(t)
+	m.callSystem = &dialCallSystem{call: &fakeCall{id: "CA-1", status: omnivoice.StatusRinging}}
+
+	metadata := map[string]string{"repo": "org/app"}
+	ctx := WithCallMetadata(context.Background(), metadata)
+	metadata["repo"] = "changed" // the call keeps its own copy
+
+	state, err := m.dial(ctx, "+15559876543")
+	if err != nil {
+		t.Fatalf("dial() error = %v", err)
+	}
+	if got := state.Metadata()["repo"]; got != "org/app" {
+		t.Errorf("Metadata()[repo] = %q, want org/app", got)
+	}
+}
+
+func TestDial_InvalidMetadata(t *testing.T) {
+	m := newTestManager(t)
+	m.callSystem = &dialCallSystem{call: &fakeCall{id: "CA-1"}}
+
+	ctx := WithCallMetadata(context.Background(), map[string]string{"": "x"})
+	if _, err := m.dial(ctx, "+15559876543"); !errors.Is(err, ErrInvalidMetadata) {
+		t.Fatalf("dial() error = %v, want ErrInvalidMetadata", err)
+	}
+	if len(m.calls) != 0 {
+		t.Errorf("%d calls registered, want 0", len(m.calls))
+	}
+}
+
+func TestMetadata_TranscriptAndCallEnded(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "calls.jsonl")
+	sink, err := NewTranscriptSink(path)
+	if err != nil {
+		t.Fatalf("NewTranscriptSink() error = %v", err)
+	}
+
+	start := time.Now()
+	state := &CallState{ID: "call-1", StartTime: start, sink: sink, metadata: map[string]string{"task": "JIRA-123"}}
+	state.AddTurn("assistant", "The deploy is done.")
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	entries := readTranscript(t, path)
+	if len(entries) != 1 || entries[0].Metadata["task"] != "JIRA-123" {
+		t.Errorf("transcript entries = %+v, want task metadata", entries)
+	}
+
+	event := newCallEndedEvent(state, start.Add(time.Minute), 0.03, transcriptRoles{})
+	if event.Metadata["task"] != "JIRA-123" {
+		t.Errorf("call-ended Metadata = %v, want task metadata", event.Metadata)
+	}
+}
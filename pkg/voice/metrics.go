@@ -0,0 +1,219 @@
+package voice
+
+import (
+	"io"
+	"log/slog"
+	"sync/atomic"
+	"time"
+)
+
+// CallMetrics are latency and audio volume measurements for a call, used to
+// diagnose calls that feel slow.
+type CallMetrics struct {
+	Duration time.Duration
+
+	// TimeToAnswer is from dialing to the call being answered.
+	TimeToAnswer time.Duration
+
+	// FirstWordLatency is from dialing to the first audio sent to the user.
+	FirstWordLatency time.Duration
+
+	// RoundTrips counts user turns that followed a spoken message;
+	// AvgRoundTrip and MaxRoundTrip measure the time from the end of our
+	// speech to the first transcript of the user's reply.
+	RoundTrips   int
+	AvgRoundTrip time.Duration
+	MaxRoundTrip time.Duration
+
+	// AudioBytesIn and AudioBytesOut count mu-law audio received from and
+	// sent to the user. With a persistent STT stream, AudioBytesIn only
+	// counts audio heard while listening, not the silence sent between turns.
+	AudioBytesIn  int64
+	AudioBytesOut int64
+
+	// STTSetups counts transcription streams opened; AvgSTTSetup and
+	// MaxSTTSetup measure how long opening one took.
+	STTSetups   int
+	AvgSTTSetup time.Duration
+	MaxSTTSetup time.Duration
+
+	// PrefetchHits counts messages played from audio synthesized ahead of
+	// time (see Manager.Prefetch); PrefetchSaved is the TTS latency this
+	// saved in total.
+	PrefetchHits  int
+	PrefetchSaved time.Duration
+
+	// TTSCacheHits counts messages played from the TTS cache and
+	// TTSCacheMisses those synthesized because they were not cached.
+	// Both stay zero unless Config.TTSCache is set.
+	TTSCacheHits   int
+	TTSCacheMisses int
+
+	// TTSChars counts the characters sent to the TTS provider, including
+	// prefetched messages and retries but not cached audio. STTTime is the
+	// time spent listening to the user. Config.MaxTTSCharsPerCall and
+	// Config.MaxSTTSecondsPerCall cap them.
+	TTSChars int
+	STTTime  time.Duration
+}
+
+// callMetrics holds the raw measurements behind CallMetrics. Timestamps are
+// guarded by CallState.mu; byte counts are updated atomically from the
+// audio paths.
+type callMetrics struct {
+	dialedAt     time.Time
+	answeredAt   time.Time
+	firstAudioAt time.Time
+
+	roundTrips    int
+	roundTripSum  time.Duration
+	roundTripMax  time.Duration
+	audioBytesIn  atomic.Int64
+	audioBytesOut atomic.Int64
+
+	sttSetups   int
+	sttSetupSum time.Duration
+	sttSetupMax time.Duration
+
+	prefetchHits  int
+	prefetchSaved time.Duration
+
+	ttsCacheHits   int
+	ttsCacheMisses int
+
+	ttsChars   int
+	sttTime    time.Duration // excluding the current turn
+	listenedAt time.Time     // start of the current turn's listening
+}
+
+// Metrics returns the call's metrics so far.
+func (cs *CallState) Metrics() CallMetrics {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+
+	m := &cs.metrics
+	out := CallMetrics{
+		Duration:      cs.Duration(),
+		RoundTrips:    m.roundTrips,
+		MaxRoundTrip:  m.roundTripMax,
+		AudioBytesIn:  m.audioBytesIn.Load(),
+		AudioBytesOut: m.audioBytesOut.Load(),
+		STTSetups:     m.sttSetups,
+		MaxSTTSetup:   m.sttSetupMax,
+		PrefetchHits:  m.prefetchHits,
+		PrefetchSaved: m.prefetchSaved,
+
+		TTSCacheHits:   m.ttsCacheHits,
+		TTSCacheMisses: m.ttsCacheMisses,
+
+		TTSChars: m.ttsChars,
+		STTTime:  m.sttTime,
+	}
+	if cs.listening {
+		out.STTTime += time.Since(m.listenedAt)
+	}
+	if !m.dialedAt.IsZero() {
+		if !m.answeredAt.IsZero() {
+			out.TimeToAnswer = m.answeredAt.Sub(m.dialedAt)
+		}
+		if !m.firstAudioAt.IsZero() {
+			out.FirstWordLatency = m.firstAudioAt.Sub(m.dialedAt)
+		}
+	}
+	if m.roundTrips > 0 {
+		out.AvgRoundTrip = m.roundTripSum / time.Duration(m.roundTrips)
+	}
+	if m.sttSetups > 0 {
+		out.AvgSTTSetup = m.sttSetupSum / time.Duration(m.sttSetups)
+	}
+	return out
+}
+
+// markAnswered records when the call was answered.
+func (cs *CallState) markAnswered(t time.Time) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	cs.metrics.answeredAt = t
+}
+
+// markHeard records the first transcript of a user turn at t, measured
+// from the end of our last playback.
+func (cs *CallState) markHeard(t time.Time) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	if cs.spokeAt.IsZero() {
+		return
+	}
+
+	rt := t.Sub(cs.spokeAt)
+	cs.metrics.roundTrips++
+	cs.metrics.roundTripSum += rt
+	cs.metrics.roundTripMax = max(cs.metrics.roundTripMax, rt)
+}
+
+// markPrefetchHit records that a prefetched message was played, saving d of
+// TTS latency.
+func (cs *CallState) markPrefetchHit(d time.Duration) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	cs.metrics.prefetchHits++
+	cs.metrics.prefetchSaved += d
+}
+
+// markTTSCache records a TTS cache lookup for a message.
+func (cs *CallState) markTTSCache(hit bool) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	if hit {
+		cs.metrics.ttsCacheHits++
+	} else {
+		cs.metrics.ttsCacheMisses++
+	}
+}
+
+// markSTTSetup records that a transcription stream took d to open.
+func (cs *CallState) markSTTSetup(d time.Duration) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	cs.metrics.sttSetups++
+	cs.metrics.sttSetupSum += d
+	cs.metrics.sttSetupMax = max(cs.metrics.sttSetupMax, d)
+}
+
+// audioOutWriter counts audio sent to the user and records when the first
+// audio went out.
+type audioOutWriter struct {
+	w     io.Writer
+	state *CallState
+}
+
+func (a audioOutWriter) Write(p []byte) (int, error) {
+	n, err := a.w.Write(p)
+	if n > 0 {
+		if a.state.metrics.audioBytesOut.Add(int64(n)) == int64(n) {
+			now := time.Now()
+			a.state.mu.Lock()
+			a.state.metrics.firstAudioAt = now
+			dialedAt, answeredAt := a.state.metrics.dialedAt, a.state.metrics.answeredAt
+			a.state.mu.Unlock()
+
+			// Logged so slow starts can be tracked across calls
+			if !dialedAt.IsZero() && !answeredAt.IsZero() {
+				slog.Info("first audio sent", "call_id", a.state.ID, "first_word_latency", now.Sub(dialedAt), "since_answer", now.Sub(answeredAt))
+			}
+		}
+	}
+	return n, err
+}
+
+// audioInReader counts audio received from the user.
+type audioInReader struct {
+	r     io.Reader
+	state *CallState
+}
+
+func (a audioInReader) Read(p []byte) (int, error) {
+	n, err := a.r.Read(p)
+	a.state.metrics.audioBytesIn.Add(int64(n))
+	return n, err
+}
@@ -0,0 +1,74 @@
+package voice
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/plexusone/omnivoice"
+)
+
+func TestCallMetrics(t *testing.T) {
+	m := newTestManager(t)
+	m.ttsProvider = &fakeTTS{streams: [][]omnivoice.StreamChunk{
+		{{Audio: []byte("aaaa"), IsFinal: true}},
+	}}
+	conn := &fakeConn{}
+
+	dialedAt := time.Now().Add(-3 * time.Second)
+	state := &CallState{
+		ID:        "call-1",
+		Call:      &fakeCall{transport: conn},
+		StartTime: dialedAt,
+		metrics:   callMetrics{dialedAt: dialedAt},
+	}
+	state.markAnswered(dialedAt.Add(2 * time.Second))
+
+	if err := m.speak(context.Background(), state, "Hello."); err != nil {
+		t.Fatalf("speak() error = %v", err)
+	}
+
+	// The user replies 400ms after we stop speaking
+	state.markHeard(state.spokeAt.Add(400 * time.Millisecond))
+
+	n, _ := io.Copy(io.Discard, audioInReader{r: bytes.NewReader(make([]byte, 160)), state: state})
+	if n != 160 {
+		t.Fatalf("copied %d bytes, want 160", n)
+	}
+
+	got := state.Metrics()
+	if got.TimeToAnswer != 2*time.Second {
+		t.Errorf("TimeToAnswer = %v, want 2s", got.TimeToAnswer)
+	}
+	if got.FirstWordLatency < 3*time.Second {
+		t.Errorf("FirstWordLatency = %v, want at least 3s", got.FirstWordLatency)
+	}
+	if got.RoundTrips != 1 || got.AvgRoundTrip != 400*time.Millisecond || got.MaxRoundTrip != 400*time.Millisecond {
+		t.Errorf("round trips = %d avg %v max %v, want 1 at 400ms", got.RoundTrips, got.AvgRoundTrip, got.MaxRoundTrip)
+	}
+	if got.AudioBytesOut != 4 || got.AudioBytesIn != 160 {
+		t.Errorf("audio bytes out/in = %d/%d, want 4/160", got.AudioBytesOut, got.AudioBytesIn)
+	}
+}
+
+func TestMarkSTTSetup(t *testing.T) {
+	state := &CallState{}
+	state.markSTTSetup(100 * time.Millisecond)
+	state.markSTTSetup(300 * time.Millisecond)
+
+	got := state.Metrics()
+	if got.STTSetups != 2 || got.AvgSTTSetup != 200*time.Millisecond || got.MaxSTTSetup != 300*time.Millisecond {
+		t.Errorf("STT setups = %d avg %v max %v, want 2 at 200ms avg, 300ms max", got.STTSetups, got.AvgSTTSetup, got.MaxSTTSetup)
+	}
+}
+
+func TestMarkHeard_NothingSpoken(t *testing.T) {
+	state := &CallState{}
+	state.markHeard(time.Now())
+
+	if got := state.Metrics().RoundTrips; got != 0 {
+		t.Errorf("RoundTrips = %d, want 0 before anything was spoken", got)
+	}
+}
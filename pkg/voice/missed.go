@@ -0,0 +1,113 @@
+package voice
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// callbackWindow is how long after a missed call an inbound call from the
+// user is treated as a callback.
+const callbackWindow = 24 * time.Hour
+
+// maxCallbacks bounds the callbacks kept until the agent reads them.
+const maxCallbacks = 10
+
+// MissedCall is an outbound call the user did not answer.
+type MissedCall struct {
+	CallID  string    // ID the attempt would have had
+	Message string    // what the agent wanted to say
+	At      time.Time // when the attempt was given up
+
+	// CalledBackAt is when the user called back, for missed calls returned
+	// by TakeCallbacks.
+	CalledBackAt time.Time
+}
+
+// missedCalls remembers the most recent unanswered outbound attempt and the
+// callbacks the agent has not been told about yet.
+type missedCalls struct {
+	mu        sync.Mutex
+	last      *MissedCall
+	callbacks []MissedCall
+}
+
+// record stores an unanswered attempt, replacing any earlier one.
+func (mc *missedCalls) record(c MissedCall) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	mc.last = &c
+}
+
+// take returns and clears the most recent missed call if it happened within
+// callbackWindow of now.
+func (mc *missedCalls) take(now time.Time) (MissedCall, bool) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	if mc.last == nil || now.Sub(mc.last.At) > callbackWindow {
+		return MissedCall{}, false
+	}
+	c := *mc.last
+	mc.last = nil
+	return c, true
+}
+
+// calledBack matches a callback to the most recent missed call and keeps it
+// until takeCallbacks, dropping the oldest beyond maxCallbacks.
+func (mc *missedCalls) calledBack(now time.Time) (MissedCall, bool) {
+	c, ok := mc.take(now)
+	if !ok {
+		return MissedCall{}, false
+	}
+	c.CalledBackAt = now
+
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	mc.callbacks = append(mc.callbacks, c)
+	if len(mc.callbacks) > maxCallbacks {
+		mc.callbacks = mc.callbacks[len(mc.callbacks)-maxCallbacks:]
+	}
+	return c, true
+}
+
+// takeCallbacks returns and clears the undelivered callbacks, oldest first.
+func (mc *missedCalls) takeCallbacks() []MissedCall {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	c := mc.callbacks
+	mc.callbacks = nil
+	return c
+}
+
+// recordMissed remembers an unanswered call so a callback can be linked to
+// it. Only calls to the user are recorded, since callbacks are matched by
+// the user's number.
+func (m *Manager) recordMissed(state *CallState, message string) {
+	if state.to != m.config.UserPhoneNumber {
+		return
+	}
+	m.missed.record(MissedCall{CallID: state.ID, Message: message, At: time.Now()})
+}
+
+// NotifyInboundCall correlates an inbound call with the most recent missed
+// outbound call. If the caller is the user and an attempt went unanswered
+// within the last 24 hours, it returns that attempt so the conversation can
+// be resumed. Each missed call is matched at most once, and is kept for
+// TakeCallbacks until the agent has read it.
+func (m *Manager) NotifyInboundCall(from string) (MissedCall, bool) {
+	if from == "" || from != m.config.UserPhoneNumber {
+		return MissedCall{}, false
+	}
+	return m.missed.calledBack(time.Now())
+}
+
+// TakeCallbacks returns the missed calls the user has called back since the
+// last time it was called, oldest first, and clears them. Callbacks are
+// only tracked for the base config's user, so tenants get none.
+func (m *Manager) TakeCallbacks(ctx context.Context) []MissedCall {
+	if tenantID(ctx) != "" {
+		return nil
+	}
+	return m.missed.takeCallbacks()
+}
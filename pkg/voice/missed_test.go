@@ -0,0 +1,63 @@
+package voice
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestNotifyInboundCall(t *testing.T) {
+	m := newTestManager(t)
+	m.config.UserPhoneNumber = "+15559876543"
+
+	if _, ok := m.NotifyInboundCall("+15559876543"); ok {
+		t.Fatal("matched a callback with no missed call")
+	}
+
+	m.missed.record(MissedCall{CallID: "call-1", Message: "Build is done", At: time.Now()})
+
+	if _, ok := m.NotifyInboundCall("+15550000000"); ok {
+		t.Error("matched a callback from someone other than the user")
+	}
+
+	missed, ok := m.NotifyInboundCall("+15559876543")
+	if !ok || missed.CallID != "call-1" || missed.Message != "Build is done" {
+		t.Fatalf("NotifyInboundCall() = %+v, %v; want the missed call", missed, ok)
+	}
+
+	if _, ok := m.NotifyInboundCall("+15559876543"); ok {
+		t.Error("matched the same missed call twice")
+	}
+
+	// The callback is kept until the agent reads it
+	callbacks := m.TakeCallbacks(context.Background())
+	if len(callbacks) != 1 || callbacks[0].CallID != "call-1" || callbacks[0].CalledBackAt.IsZero() {
+		t.Fatalf("TakeCallbacks() = %+v, want the called-back call", callbacks)
+	}
+	if callbacks := m.TakeCallbacks(context.Background()); len(callbacks) != 0 {
+		t.Errorf("TakeCallbacks() again = %+v, want none", callbacks)
+	}
+}
+
+func TestMissedCalls_CallbacksBounded(t *testing.T) {
+	var mc missedCalls
+	for i := range maxCallbacks + 2 {
+		mc.record(MissedCall{CallID: fmt.Sprintf("call-%d", i), At: time.Now()})
+		mc.calledBack(time.Now())
+	}
+
+	callbacks := mc.takeCallbacks()
+	if len(callbacks) != maxCallbacks || callbacks[0].CallID != "call-2" {
+		t.Errorf("callbacks = %d starting at %q, want %d starting at call-2", len(callbacks), callbacks[0].CallID, maxCallbacks)
+	}
+}
+
+func TestMissedCalls_Expire(t *testing.T) {
+	var mc missedCalls
+	mc.record(MissedCall{CallID: "call-1", At: time.Now().Add(-callbackWindow - time.Minute)})
+
+	if _, ok := mc.take(time.Now()); ok {
+		t.Error("matched a missed call older than the callback window")
+	}
+}
@@ -0,0 +1,36 @@
+package voice
+
+import "context"
+
+// SetListeningMuted mutes or unmutes listening on a call, e.g. while the
+// user talks to someone else, and returns whether it is now muted. While
+// muted, replies are not transcribed: ContinueCall and the other methods
+// that wait for a reply fail with ErrListeningMuted before speaking, and a
+// persistent transcription stream is closed so it is not billed. The call
+// and its audio stay up, and SpeakToUser still works.
+func (m *Manager) SetListeningMuted(ctx context.Context, callID string, muted bool) (bool, error) {
+	state := m.tenantCall(ctx, callID)
+	if state == nil {
+		return false, m.callNotFound(callID)
+	}
+
+	state.mu.Lock()
+	state.listeningMuted = muted
+	listening := state.listening
+	state.mu.Unlock()
+
+	// A reply already being listened for is finished first; listen closes
+	// the stream when it returns
+	if muted && !listening {
+		state.closeSTTSession()
+	}
+	return muted, nil
+}
+
+// ListeningMuted reports whether listening on the call is muted (see
+// Manager.SetListeningMuted).
+func (cs *CallState) ListeningMuted() bool {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+	return cs.listeningMuted
+}
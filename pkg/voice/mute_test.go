@@ -0,0 +1,74 @@
+package voice
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/plexusone/omnivoice"
+)
+
+func TestSetListeningMuted(t *testing.T) {
+	m := newTestManager(t)
+	tts := &fakeTTS{streams: [][]omnivoice.StreamChunk{
+		{{Audio: []byte("one moment"), IsFinal: true}},
+	}}
+	m.ttsProvider = tts
+	conn := &fakeConn{}
+	state := m.addCall(context.Background(), &fakeCall{transport: conn}, "+15551234567", time.Now())
+
+	muted, err := m.SetListeningMuted(context.Background(), state.ID, true)
+	if err != nil || !muted {
+		t.Fatalf("SetListeningMuted(true) = %v, %v; want muted", muted, err)
+	}
+
+	if _, err := m.ContinueCall(context.Background(), state.ID, "Are you back?"); !errors.Is(err, ErrListeningMuted) {
+		t.Errorf("ContinueCall() error = %v, want ErrListeningMuted", err)
+	}
+	if _, err := m.SpeakAndWaitDigits(context.Background(), state.ID, "Press 1.", 1); !errors.Is(err, ErrListeningMuted) {
+		t.Errorf("SpeakAndWaitDigits() error = %v, want ErrListeningMuted", err)
+	}
+	if len(tts.texts) != 0 {
+		t.Errorf("spoke %q while muted, want the question held back", tts.texts)
+	}
+
+	// Speaking without waiting for a reply still works
+	if err := m.SpeakToUser(context.Background(), state.ID, "Take your time."); err != nil {
+		t.Errorf("SpeakToUser() error = %v", err)
+	}
+	if got := conn.audio.String(); got != "one moment" {
+		t.Errorf("audio = %q", got)
+	}
+
+	info, err := m.CallStatus(context.Background(), state.ID)
+	if err != nil || !info.Muted {
+		t.Errorf("CallStatus() = %+v, %v; want Muted", info, err)
+	}
+
+	muted, err = m.SetListeningMuted(context.Background(), state.ID, false)
+	if err != nil || muted || state.ListeningMuted() {
+		t.Errorf("SetListeningMuted(false) = %v, %v; want unmuted", muted, err)
+	}
+}
+
+func TestSetListeningMuted_ClosesPersistentStream(t *testing.T) {
+	m := newTestManager(t)
+	closed := false
+	state := m.addCall(context.Background(), &fakeCall{}, "+15551234567", time.Now())
+	state.stt = &sttSession{cancel: func() { closed = true }}
+
+	if _, err := m.SetListeningMuted(context.Background(), state.ID, true); err != nil {
+		t.Fatalf("SetListeningMuted() error = %v", err)
+	}
+	if !closed || state.stt != nil {
+		t.Error("persistent STT stream left open while muted")
+	}
+}
+
+func TestSetListeningMuted_UnknownCall(t *testing.T) {
+	m := newTestManager(t)
+	if _, err := m.SetListeningMuted(context.Background(), "nope", true); !errors.Is(err, ErrCallNotFound) {
+		t.Errorf("SetListeningMuted() error = %v, want ErrCallNotFound", err)
+	}
+}
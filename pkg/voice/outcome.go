@@ -0,0 +1,54 @@
+package voice
+
+import (
+	"errors"
+
+	"github.com/plexusone/omnivoice"
+)
+
+// Outcome classifies how a call placed to speak a message turned out.
+type Outcome string
+
+// Call outcomes. Every outcome except OutcomeFailed means the call was
+// placed; only OutcomeAnswered and OutcomeNoSpeech leave it connected.
+const (
+	OutcomeAnswered    Outcome = "answered"     // the user answered and replied
+	OutcomeNoSpeech    Outcome = "no_speech"    // the user answered but said nothing
+	OutcomeVoicemail   Outcome = "voicemail"    // a machine answered; the message was left
+	OutcomeNotAnswered Outcome = "not_answered" // it rang out, or a fax answered
+	OutcomeBusy        Outcome = "busy"         // the line was busy
+	OutcomeDeclined    Outcome = "declined"     // the callee did not press the accept digit
+	OutcomeFailed      Outcome = "failed"       // the call could not be placed or spoken on
+)
+
+// OutcomeOf classifies the error returned by InitiateCall and its variants.
+func OutcomeOf(err error) Outcome {
+	switch {
+	case err == nil:
+		return OutcomeAnswered
+	case errors.Is(err, ErrNoSpeech):
+		return OutcomeNoSpeech
+	case errors.Is(err, ErrVoicemail):
+		return OutcomeVoicemail
+	case errors.Is(err, ErrBusy):
+		return OutcomeBusy
+	case errors.Is(err, ErrNotAnswered):
+		return OutcomeNotAnswered
+	case errors.Is(err, ErrDeclined):
+		return OutcomeDeclined
+	default:
+		return OutcomeFailed
+	}
+}
+
+// lineBusy reports whether the last status of an unanswered call, pushed by
+// the provider or polled from it, was busy.
+func (cs *CallState) lineBusy() bool {
+	cs.mu.RLock()
+	status := cs.status
+	cs.mu.RUnlock()
+	if status == omnivoice.StatusBusy {
+		return true
+	}
+	return cs.Call != nil && cs.Call.Status() == omnivoice.StatusBusy
+}
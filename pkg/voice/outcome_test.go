@@ -0,0 +1,79 @@
+package voice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/plexusone/omnivoice"
+)
+
+func TestOutcomeOf(t *testing.T) {
+	tests := []struct {
+		err  error
+		want Outcome
+	}{
+		{nil, OutcomeAnswered},
+		{ErrNoSpeech, OutcomeNoSpeech},
+		{ErrVoicemail, OutcomeVoicemail},
+		{fmt.Errorf("%w, %w: %w", ErrVoicemail, ErrSpeechFailed, errors.New("tts down")), OutcomeVoicemail},
+		{ErrNotAnswered, OutcomeNotAnswered},
+		{fmt.Errorf("%w, %w", ErrNotAnswered, ErrSMSFallbackSent), OutcomeNotAnswered},
+		{fmt.Errorf("%w: %w", ErrNotAnswered, ErrBusy), OutcomeBusy},
+		{ErrDeclined, OutcomeDeclined},
+		{fmt.Errorf("%w: %w", ErrDialFailed, errors.New("invalid number")), OutcomeFailed},
+		{fmt.Errorf("%w: %w", ErrSpeechFailed, errors.New("stt down")), OutcomeFailed},
+		{ErrQuietHours, OutcomeFailed},
+	}
+	for _, tt := range tests {
+		if got := OutcomeOf(tt.err); got != tt.want {
+			t.Errorf("OutcomeOf(%v) = %q, want %q", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestInitiateCall_NotAnsweredOutcome(t *testing.T) {
+	tests := []struct {
+		name   string
+		status omnivoice.CallStatus // polled from the provider
+		pushed omnivoice.CallStatus // sent as a status callback
+		want   Outcome
+	}{
+		{"no answer", omnivoice.StatusNoAnswer, "", OutcomeNotAnswered},
+		{"busy polled", omnivoice.StatusBusy, "", OutcomeBusy},
+		{"busy pushed", omnivoice.StatusRinging, omnivoice.StatusBusy, OutcomeBusy},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestManager(t)
+			call := &fakeCall{id: "CA-1", status: tt.status}
+			m.callSystem = &dialCallSystem{call: hangupCall{call}}
+
+			if tt.pushed != "" {
+				// Push the status once the call is registered
+				go func() {
+					for {
+						m.callsMu.RLock()
+						n := len(m.calls)
+						m.callsMu.RUnlock()
+						if n > 0 {
+							break
+						}
+						time.Sleep(time.Millisecond)
+					}
+					m.NotifyStatus("CA-1", tt.pushed)
+				}()
+			}
+
+			_, _, err := m.InitiateCall(context.Background(), "The build is done.")
+			if !errors.Is(err, ErrNotAnswered) {
+				t.Fatalf("InitiateCall() error = %v, want ErrNotAnswered", err)
+			}
+			if got := OutcomeOf(err); got != tt.want {
+				t.Errorf("OutcomeOf(%v) = %q, want %q", err, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,216 @@
+package voice
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/plexusone/omnivoice"
+)
+
+// prefetch is a message synthesized ahead of time, while the user is
+// still answering, so it can play as soon as the agent sends it.
+type prefetch struct {
+	text      string
+	voiceID   string
+	speed     float64
+	startedAt time.Time
+	cancel    context.CancelFunc
+
+	mu      sync.Mutex
+	chunks  [][]byte
+	firstAt time.Time // when the first chunk arrived
+	done    bool
+	err     error
+	updated chan struct{} // signaled after each change
+}
+
+// WithNextMessageHint synthesizes text while listening for the reply, so it
+// plays without TTS delay if it is the next message spoken on the call.
+// The audio is discarded if a different message is sent. It has no effect
+// unless Config.TTSPrefetch is set.
+func WithNextMessageHint(text string) SpeakOption {
+	return func(o *speakOptions) {
+		o.nextMessage = text
+	}
+}
+
+// Prefetch starts synthesizing text, the message the agent expects to send
+// next on the call, so it plays without TTS delay if it is. A later
+// Prefetch replaces it. It has no effect unless Config.TTSPrefetch is set.
+func (m *Manager) Prefetch(ctx context.Context, callID, text string) error {
+	state := m.tenantCall(ctx, callID)
+	if state == nil {
+		return m.callNotFound(callID)
+	}
+	m.startPrefetch(state, text)
+	return nil
+}
+
+// startPrefetch synthesizes text in the background and keeps the audio on
+// the call for speak, discarding any earlier prefetch.
+func (m *Manager) startPrefetch(state *CallState, text string) {
+	if !m.config.TTSPrefetch || text == "" {
+		return
+	}
+	ttsProvider := m.currentTTSProvider()
+	if ttsProvider == nil {
+		return // nothing to prefetch with; speak reports why
+	}
+
+	previous := state.previousAssistantText(m.config.TTSContinuityTurns)
+	synthText := text
+	if !m.config.TTSSupportsTags() {
+		synthText = stripAudioTags(text)
+		previous = stripAudioTags(previous)
+	}
+	synthText, previous = m.pronunciation.apply(synthText), m.pronunciation.apply(previous)
+	cfg := m.synthesisConfig(state, previous)
+	if m.ttsCache != nil && m.ttsCache.contains(newTTSCacheKey(synthText, cfg)) {
+		// Plays from the cache without delay anyway
+		state.setPrefetch(nil)
+		return
+	}
+	if err := m.chargeTTS(state, synthText); err != nil {
+		// Would go over the TTS allowance; speak refuses it anyway
+		state.setPrefetch(nil)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &prefetch{
+		text:      synthText,
+		voiceID:   cfg.VoiceID,
+		speed:     cfg.Speed,
+		startedAt: time.Now(),
+		cancel:    cancel,
+		updated:   make(chan struct{}, 1),
+	}
+	state.setPrefetch(p)
+
+	go func() {
+		_, err := m.synthesizeTo(ctx, ttsProvider, p, synthText, cfg)
+		p.finish(err)
+	}()
+}
+
+// setPrefetch replaces the call's prefetched message. p may be nil.
+func (cs *CallState) setPrefetch(p *prefetch) {
+	cs.mu.Lock()
+	old := cs.prefetch
+	cs.prefetch = p
+	cs.mu.Unlock()
+
+	if old != nil {
+		old.cancel()
+	}
+}
+
+// takePrefetch removes and returns the call's prefetched message if it is
+// text (as synthesized) spoken with cfg. Any other prefetch is discarded.
+func (cs *CallState) takePrefetch(text string, cfg omnivoice.SynthesisConfig) *prefetch {
+	cs.mu.Lock()
+	p := cs.prefetch
+	cs.prefetch = nil
+	cs.mu.Unlock()
+
+	if p == nil {
+		return nil
+	}
+	if p.text != text || p.voiceID != cfg.VoiceID || p.speed != cfg.Speed {
+		p.cancel()
+		return nil
+	}
+	return p
+}
+
+// Write stores a chunk of synthesized audio.
+func (p *prefetch) Write(b []byte) (int, error) {
+	p.mu.Lock()
+	if p.firstAt.IsZero() {
+		p.firstAt = time.Now()
+	}
+	p.chunks = append(p.chunks, append([]byte(nil), b...))
+	p.mu.Unlock()
+	p.notify()
+	return len(b), nil
+}
+
+// finish marks synthesis as ended.
+func (p *prefetch) finish(err error) {
+	p.mu.Lock()
+	p.done = true
+	p.err = err
+	p.mu.Unlock()
+	p.notify()
+}
+
+func (p *prefetch) notify() {
+	select {
+	case p.updated <- struct{}{}:
+	default:
+	}
+}
+
+// playTo writes the prefetched audio to w as it becomes available and
+// returns the number of bytes written and how much TTS latency was saved:
+// the time from the start of synthesis to the first audio, less any wait
+// for it. If synthesis failed before any audio was written, the error is
+// returned so the caller can synthesize the message itself.
+func (p *prefetch) playTo(ctx context.Context, w io.Writer) (int, time.Duration, error) {
+	defer p.cancel()
+
+	requestedAt := time.Now()
+	written, next := 0, 0
+	for {
+		p.mu.Lock()
+		chunks, done, err, firstAt := p.chunks[next:], p.done, p.err, p.firstAt
+		p.mu.Unlock()
+
+		for _, chunk := range chunks {
+			n, err := w.Write(chunk)
+			written += n
+			if err != nil {
+				return written, 0, err
+			}
+		}
+		next += len(chunks)
+
+		if done {
+			var saved time.Duration
+			if !firstAt.IsZero() {
+				saved = min(firstAt.Sub(p.startedAt), requestedAt.Sub(p.startedAt))
+			}
+			return written, saved, err
+		}
+
+		select {
+		case <-p.updated:
+		case <-ctx.Done():
+			return written, 0, ctx.Err()
+		}
+	}
+}
+
+// playOrSynthesize writes text's audio to w, from the call's prefetch if
+// there is one for it and from the TTS cache or synthesis otherwise. It
+// returns the number of audio bytes written. Synthesis uses ttsProvider.
+func (m *Manager) playOrSynthesize(ctx context.Context, ttsProvider omnivoice.TTSProvider, state *CallState, w io.Writer, text string, cfg omnivoice.SynthesisConfig) (int, error) {
+	p := state.takePrefetch(text, cfg)
+	if p == nil {
+		return m.synthesizeCached(ctx, ttsProvider, state, w, text, cfg)
+	}
+
+	written, saved, err := p.playTo(ctx, w)
+	switch {
+	case err == nil:
+		state.markPrefetchHit(saved)
+		slog.Debug("played prefetched message", "call_id", state.ID, "saved_ms", saved.Milliseconds())
+	case written == 0 && ctx.Err() == nil:
+		slog.Warn("prefetched TTS failed; synthesizing again", "call_id", state.ID, "error", err)
+		return m.synthesizeCached(ctx, ttsProvider, state, w, text, cfg)
+	}
+	return written, err
+}
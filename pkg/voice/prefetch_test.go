@@ -0,0 +1,91 @@
+package voice
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/plexusone/omnivoice"
+)
+
+// waitPrefetched waits for the call's prefetch to finish synthesizing.
+func waitPrefetched(t *testing.T, state *CallState) {
+	t.Helper()
+
+	state.mu.RLock()
+	p := state.prefetch
+	state.mu.RUnlock()
+	if p == nil {
+		t.Fatal("no prefetch started")
+	}
+	deadline := time.Now().Add(time.Second)
+	for {
+		p.mu.Lock()
+		done := p.done
+		p.mu.Unlock()
+		if done {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("prefetch did not finish")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestPrefetch(t *testing.T) {
+	tests := []struct {
+		name     string
+		message  string
+		wantText []string
+		want     string
+		wantHits int
+	}{
+		{"hint matches", "See you soon.", []string{"See you soon."}, "prefetched", 1},
+		{"different message", "Goodbye.", []string{"See you soon.", "Goodbye."}, "fresh", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestManager(t)
+			m.config.TTSPrefetch = true
+			tts := &fakeTTS{streams: [][]omnivoice.StreamChunk{
+				{{Audio: []byte("pre")}, {Audio: []byte("fetched"), IsFinal: true}},
+				{{Audio: []byte("fresh"), IsFinal: true}},
+			}}
+			m.ttsProvider = tts
+			conn := &fakeConn{}
+			state := &CallState{ID: "call-1", Call: &fakeCall{transport: conn}}
+
+			m.startPrefetch(state, "See you soon.")
+			waitPrefetched(t, state)
+
+			if err := m.speak(context.Background(), state, tt.message); err != nil {
+				t.Fatalf("speak() error = %v", err)
+			}
+			if got := conn.audio.String(); got != tt.want {
+				t.Errorf("audio = %q, want %q", got, tt.want)
+			}
+			if len(tts.texts) != len(tt.wantText) {
+				t.Errorf("synthesized %q, want %q", tts.texts, tt.wantText)
+			}
+			if got := state.Metrics().PrefetchHits; got != tt.wantHits {
+				t.Errorf("PrefetchHits = %d, want %d", got, tt.wantHits)
+			}
+			if state.prefetch != nil {
+				t.Error("prefetch kept after speaking")
+			}
+		})
+	}
+}
+
+func TestPrefetch_Disabled(t *testing.T) {
+	m := newTestManager(t)
+	m.ttsProvider = &fakeTTS{}
+	state := &CallState{ID: "call-1"}
+
+	m.startPrefetch(state, "See you soon.")
+	if state.prefetch != nil {
+		t.Error("prefetch started without TTSPrefetch")
+	}
+}
@@ -0,0 +1,99 @@
+package voice
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// pronunciation rewrites domain terms the TTS provider mispronounces and
+// lists them for keyword boosting in STT. It is loaded from a JSON object
+// mapping each term to how it should be spoken, e.g. {"nginx": "engine x"};
+// a term mapped to "" is only boosted. Terms match whole words, ignoring
+// case.
+type pronunciation struct {
+	say      map[string]string // lowercased term to its spoken form
+	re       *regexp.Regexp    // matches terms with a spoken form
+	keywords []string
+}
+
+// loadPronunciation reads the pronunciation dictionary at path.
+func loadPronunciation(path string) (*pronunciation, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pronunciation dictionary: %w", err)
+	}
+	var terms map[string]string
+	if err := json.Unmarshal(raw, &terms); err != nil {
+		return nil, fmt.Errorf("failed to parse pronunciation dictionary %s: %w", path, err)
+	}
+	return newPronunciation(terms), nil
+}
+
+// newPronunciation builds a dictionary from terms mapped to spoken forms.
+func newPronunciation(terms map[string]string) *pronunciation {
+	p := &pronunciation{say: make(map[string]string)}
+	var patterns []string
+	for term, say := range terms {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+		p.keywords = append(p.keywords, term)
+		if say == "" {
+			continue
+		}
+		p.say[strings.ToLower(term)] = say
+		patterns = append(patterns, wordPattern(term))
+	}
+	slices.Sort(p.keywords)
+
+	if len(patterns) > 0 {
+		// Longest first, so "gRPC-web" wins over "gRPC"
+		slices.SortFunc(patterns, func(a, b string) int { return cmp.Compare(len(b), len(a)) })
+		p.re = regexp.MustCompile(`(?i)` + strings.Join(patterns, "|"))
+	}
+	return p
+}
+
+// wordPattern matches term as a whole word. Word boundaries only apply at
+// ends that are ASCII word characters, as \b does, so terms such as "C++"
+// still match.
+func wordPattern(term string) string {
+	pattern := regexp.QuoteMeta(term)
+	if r, _ := utf8.DecodeRuneInString(term); isWordRune(r) {
+		pattern = `\b` + pattern
+	}
+	if r, _ := utf8.DecodeLastRuneInString(term); isWordRune(r) {
+		pattern += `\b`
+	}
+	return pattern
+}
+
+func isWordRune(r rune) bool {
+	return r < utf8.RuneSelf && (r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r))
+}
+
+// apply replaces each term in text with its spoken form.
+func (p *pronunciation) apply(text string) string {
+	if p == nil || p.re == nil {
+		return text
+	}
+	return p.re.ReplaceAllStringFunc(text, func(term string) string {
+		return p.say[strings.ToLower(term)]
+	})
+}
+
+// sttKeywords returns the terms to boost in speech recognition.
+func (p *pronunciation) sttKeywords() []string {
+	if p == nil {
+		return nil
+	}
+	return p.keywords
+}
@@ -0,0 +1,66 @@
+package voice
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestPronunciation(t *testing.T) {
+	p := newPronunciation(map[string]string{
+		"nginx":    "engine x",
+		"gRPC":     "gee R P C",
+		"gRPC-web": "gee R P C web",
+		"C++":      "C plus plus",
+		"Deepgram": "",
+	})
+
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"Restart nginx now.", "Restart engine x now."},
+		{"NGINX and Nginx", "engine x and engine x"},
+		{"The nginxconf file", "The nginxconf file"},
+		{"Use gRPC-web or gRPC.", "Use gee R P C web or gee R P C."},
+		{"Written in C++, not C.", "Written in C plus plus, not C."},
+		{"Deepgram is unchanged", "Deepgram is unchanged"},
+	}
+	for _, tt := range tests {
+		if got := p.apply(tt.text); got != tt.want {
+			t.Errorf("apply(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+
+	want := []string{"C++", "Deepgram", "gRPC", "gRPC-web", "nginx"}
+	if got := p.sttKeywords(); !slices.Equal(got, want) {
+		t.Errorf("sttKeywords() = %q, want %q", got, want)
+	}
+
+	var none *pronunciation
+	if got := none.apply("nginx"); got != "nginx" {
+		t.Errorf("nil apply() = %q, want the text unchanged", got)
+	}
+}
+
+func TestLoadPronunciation(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "dict.json")
+	if err := os.WriteFile(path, []byte(`{"kubectl": "cube control"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	p, err := loadPronunciation(path)
+	if err != nil {
+		t.Fatalf("loadPronunciation() error = %v", err)
+	}
+	if got := p.apply("run kubectl"); got != "run cube control" {
+		t.Errorf("apply() = %q", got)
+	}
+
+	bad := filepath.Join(dir, "bad.json")
+	_ = os.WriteFile(bad, []byte(`["kubectl"]`), 0600)
+	if _, err := loadPronunciation(bad); err == nil {
+		t.Error("loadPronunciation() with a list, want an error")
+	}
+}
@@ -0,0 +1,35 @@
+package voice
+
+import "github.com/plexusone/agentcomms/pkg/config"
+
+// speedRange is the speed range a TTS provider accepts.
+type speedRange struct {
+	min, max float64
+}
+
+// providerSpeedRanges lists providers that honor a speaking rate. Providers
+// not listed (e.g., Deepgram Aura) always speak at their normal rate.
+var providerSpeedRanges = map[string]speedRange{
+	config.ProviderElevenLabs: {min: 0.7, max: 1.2},
+	config.ProviderOpenAI:     {min: 0.25, max: 4.0},
+}
+
+// WithSpeakingRate speaks a message at rate (1.0 = normal) instead of the
+// configured SpeakingRate. Like SpeakingRate, it is clamped to the
+// provider's range and ignored by providers without rate control.
+func WithSpeakingRate(rate float64) SpeakOption {
+	return func(o *speakOptions) {
+		o.rate = rate
+	}
+}
+
+// ttsSpeed returns the synthesis speed for the provider, clamped to the
+// provider's range, or 0 (provider default) when the provider does not
+// support rate control or the rate is normal.
+func ttsSpeed(provider string, rate float64) float64 {
+	r, ok := providerSpeedRanges[provider]
+	if !ok || rate == 0 || rate == 1.0 {
+		return 0
+	}
+	return min(max(rate, r.min), r.max)
+}
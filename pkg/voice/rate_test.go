@@ -0,0 +1,28 @@
+package voice
+
+import (
+	"testing"
+
+	"github.com/plexusone/agentcomms/pkg/config"
+)
+
+func TestTTSSpeed(t *testing.T) {
+	tests := []struct {
+		provider string
+		rate     float64
+		want     float64
+	}{
+		{config.ProviderElevenLabs, 1.0, 0},
+		{config.ProviderElevenLabs, 0.9, 0.9},
+		{config.ProviderElevenLabs, 0.5, 0.7},
+		{config.ProviderElevenLabs, 1.5, 1.2},
+		{config.ProviderOpenAI, 1.5, 1.5},
+		{config.ProviderDeepgram, 0.8, 0},
+	}
+
+	for _, tt := range tests {
+		if got := ttsSpeed(tt.provider, tt.rate); got != tt.want {
+			t.Errorf("ttsSpeed(%q, %g) = %g, want %g", tt.provider, tt.rate, got, tt.want)
+		}
+	}
+}
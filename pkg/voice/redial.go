@@ -0,0 +1,96 @@
+package voice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// dialUntilAnswered places a call to the given number and connects it,
+// dialing again up to Config.RedialAttempts times, Config.RedialDelayMS
+// apart, while it rings out or is busy. Each dial is checked against do
+// not disturb, quiet hours, and the call budget like the first; a redial
+// they block gives up on the previous attempt. Only the last attempt is
+// recorded as missed and falls back to SMS. Errors after more than one
+// attempt report the count (see AttemptsOf).
+func (m *Manager) dialUntilAnswered(ctx context.Context, to, message string) (*CallState, error) {
+	attempts := 1 + max(m.config.RedialAttempts, 0)
+	delay := time.Duration(m.config.RedialDelayMS) * time.Millisecond
+
+	var last *CallState
+	var lastErr error
+	for attempt := 1; ; attempt++ {
+		state, err := m.dial(ctx, to)
+		if err != nil {
+			if last == nil {
+				return nil, err
+			}
+			err = fmt.Errorf("%w; not redialed: %w", m.giveUp(ctx, last, message, lastErr), err)
+			return nil, withAttempts(err, attempt-1)
+		}
+		state.attempt = attempt
+		state.redial = attempt < attempts
+
+		err = m.connect(ctx, state, message)
+		if err == nil {
+			return state, nil
+		}
+		if !state.redial || !errors.Is(err, ErrNotAnswered) {
+			return nil, withAttempts(err, attempt)
+		}
+		last, lastErr = state, err
+
+		slog.Info("call not answered, redialing", "call_id", state.ID, "attempt", attempt, "delay", delay, "reason", err)
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			// A request given up on between attempts is not a missed call
+			timer.Stop()
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// Attempts returns how many times InitiateCall dialed to place the call.
+func (cs *CallState) Attempts() int {
+	return max(cs.attempt, 1)
+}
+
+// attemptsError is a call failure after the number was dialed more than
+// once.
+type attemptsError struct {
+	err      error
+	attempts int
+}
+
+func (e *attemptsError) Error() string {
+	return fmt.Sprintf("%v (after %d attempts)", e.err, e.attempts)
+}
+
+func (e *attemptsError) Unwrap() error { return e.err }
+
+// withAttempts adds the attempt count to err if the number was redialed.
+func withAttempts(err error, attempts int) error {
+	if attempts <= 1 {
+		return err
+	}
+	return &attemptsError{err: err, attempts: attempts}
+}
+
+// AttemptsOf returns how many times the number was dialed for the call
+// behind an InitiateCall result: the count when it was redialed, 0 for a
+// call that failed first time, and 1 otherwise.
+func AttemptsOf(err error) int {
+	var ae *attemptsError
+	switch {
+	case errors.As(err, &ae):
+		return ae.attempts
+	case OutcomeOf(err) == OutcomeFailed:
+		return 0
+	default:
+		return 1
+	}
+}
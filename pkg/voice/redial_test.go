@@ -0,0 +1,109 @@
+package voice
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/plexusone/omnivoice"
+)
+
+// seqCallSystem returns its calls in order, repeating the last, and counts
+// the dials.
+type seqCallSystem struct {
+	omnivoice.CallSystem
+
+	calls []omnivoice.Call
+	dials int
+}
+
+func (s *seqCallSystem) MakeCall(context.Context, string, ...omnivoice.CallOption) (omnivoice.Call, error) {
+	call := s.calls[min(s.dials, len(s.calls)-1)]
+	s.dials++
+	return call, nil
+}
+
+func TestInitiateCall_Redial(t *testing.T) {
+	m := newTestManager(t)
+	m.config.RedialAttempts = 3
+	m.config.RedialDelayMS = 1
+	calls := &seqCallSystem{calls: []omnivoice.Call{
+		hangupCall{&fakeCall{id: "CA-1", status: omnivoice.StatusNoAnswer}},
+		hangupCall{&fakeCall{id: "CA-2", status: omnivoice.StatusBusy}},
+		hangupCall{&fakeCall{id: "CA-3", status: omnivoice.StatusAnswered}},
+	}}
+	m.callSystem = calls
+
+	// Without a TTS provider the message fails to play, after the third
+	// dial is answered
+	state, _, err := m.InitiateCall(context.Background(), "The build is done.")
+	if !errors.Is(err, ErrSpeechFailed) || state == nil {
+		t.Fatalf("InitiateCall() = %v, %v; want the answered call and ErrSpeechFailed", state, err)
+	}
+	if calls.dials != 3 || state.Attempts() != 3 {
+		t.Errorf("dials, Attempts() = %d, %d; want 3, 3", calls.dials, state.Attempts())
+	}
+	if _, ok := m.missed.take(time.Now()); ok {
+		t.Error("redialed attempts recorded as missed")
+	}
+}
+
+func TestInitiateCall_RedialGivesUp(t *testing.T) {
+	m := newTestManager(t)
+	m.config.RedialAttempts = 1
+	m.config.RedialDelayMS = 1
+	calls := &seqCallSystem{calls: []omnivoice.Call{
+		hangupCall{&fakeCall{id: "CA-1", status: omnivoice.StatusNoAnswer}},
+	}}
+	m.callSystem = calls
+
+	_, _, err := m.InitiateCall(context.Background(), "The build is done.")
+	if OutcomeOf(err) != OutcomeNotAnswered || AttemptsOf(err) != 2 {
+		t.Fatalf("InitiateCall() error = %v, want not answered after 2 attempts", err)
+	}
+	if calls.dials != 2 {
+		t.Errorf("dialed %d times, want 2", calls.dials)
+	}
+	if missed, ok := m.missed.take(time.Now()); !ok || missed.CallID == "" {
+		t.Error("last attempt not recorded as missed")
+	}
+}
+
+func TestInitiateCall_RedialBlocked(t *testing.T) {
+	m := newTestManager(t)
+	m.config.RedialAttempts = 2
+	m.config.RedialDelayMS = 1
+	m.config.MaxCallsPerDay = 1
+	calls := &seqCallSystem{calls: []omnivoice.Call{
+		hangupCall{&fakeCall{id: "CA-1", status: omnivoice.StatusBusy}},
+	}}
+	m.callSystem = calls
+
+	// The budget stops the redial, and the first attempt is given up on
+	_, _, err := m.InitiateCall(context.Background(), "The build is done.")
+	if !errors.Is(err, ErrBudgetExceeded) || OutcomeOf(err) != OutcomeBusy || AttemptsOf(err) != 1 {
+		t.Fatalf("InitiateCall() error = %v, want busy and the budget exceeded after 1 attempt", err)
+	}
+	if _, ok := m.missed.take(time.Now()); !ok {
+		t.Error("given-up attempt not recorded as missed")
+	}
+}
+
+func TestAttemptsOf(t *testing.T) {
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{nil, 1},
+		{ErrNotAnswered, 1},
+		{ErrQuietHours, 0},
+		{withAttempts(ErrNotAnswered, 3), 3},
+		{withAttempts(ErrSpeechFailed, 2), 2},
+	}
+	for _, tt := range tests {
+		if got := AttemptsOf(tt.err); got != tt.want {
+			t.Errorf("AttemptsOf(%v) = %d, want %d", tt.err, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package voice
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/plexusone/agentcomms/pkg/config"
+)
+
+// repeatSlowdown scales the speaking rate when a repeat is requested slower.
+const repeatSlowdown = 0.8
+
+// lastAssistantText returns the most recent assistant turn, if any.
+func (cs *CallState) lastAssistantText() (string, bool) {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+	for i := len(cs.Conversation) - 1; i >= 0; i-- {
+		if turn := cs.Conversation[i]; turn.Role == "assistant" {
+			return turn.Content, true
+		}
+	}
+	return "", false
+}
+
+// RepeatLast speaks the last assistant message again, verbatim, and
+// listens for the user's reply, for when the user asks "say that again".
+// With slower, the message is spoken at a reduced rate where the TTS
+// provider supports it. It returns the repeated message and the reply, or
+// ErrNothingToRepeat if nothing has been said on the call yet.
+func (m *Manager) RepeatLast(ctx context.Context, callID string, slower bool, opts ...SpeakOption) (message, response string, err error) {
+	state, err := m.readyCall(ctx, callID)
+	if err != nil {
+		return "", "", err
+	}
+
+	message, ok := state.lastAssistantText()
+	if !ok {
+		return "", "", fmt.Errorf("%w on call %s", ErrNothingToRepeat, callID)
+	}
+
+	if slower {
+		rate := max(m.config.SpeakingRate*repeatSlowdown, config.MinSpeakingRate)
+		opts = append(opts, WithSpeakingRate(rate))
+	}
+
+	response, err = m.speakAndListen(ctx, state, message, nil, opts...)
+	if err != nil {
+		return message, "", speechError(err)
+	}
+	return message, response, nil
+}
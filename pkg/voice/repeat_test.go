@@ -0,0 +1,61 @@
+package voice
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/plexusone/omnivoice"
+)
+
+func TestRepeatLast_NothingToRepeat(t *testing.T) {
+	m := newTestManager(t)
+	state := &CallState{ID: "call-1", Call: &fakeCall{}}
+	state.AddTurn("user", "Hello?")
+	m.calls[state.ID] = state
+
+	if _, _, err := m.RepeatLast(context.Background(), state.ID, false); !errors.Is(err, ErrNothingToRepeat) {
+		t.Errorf("RepeatLast() error = %v, want ErrNothingToRepeat", err)
+	}
+	if _, _, err := m.RepeatLast(context.Background(), "call-missing", false); !errors.Is(err, ErrCallNotFound) {
+		t.Errorf("RepeatLast() error = %v, want ErrCallNotFound", err)
+	}
+}
+
+func TestLastAssistantText(t *testing.T) {
+	state := &CallState{}
+	if _, ok := state.lastAssistantText(); ok {
+		t.Error("lastAssistantText() ok on an empty conversation")
+	}
+
+	state.AddTurn("assistant", "The deploy finished.")
+	state.AddTurn("assistant", "Should I merge the PR?")
+	state.AddTurn("user", "What?")
+	if got, ok := state.lastAssistantText(); !ok || got != "Should I merge the PR?" {
+		t.Errorf("lastAssistantText() = %q, %v, want the last assistant message", got, ok)
+	}
+}
+
+func TestSpeak_WithSpeakingRate(t *testing.T) {
+	m := newTestManager(t)
+	tts := &fakeTTS{streams: [][]omnivoice.StreamChunk{
+		{{Audio: []byte("aaaa"), IsFinal: true}},
+		{{Audio: []byte("bbbb"), IsFinal: true}},
+	}}
+	m.ttsProvider = tts
+	state := &CallState{ID: "call-1", Call: &fakeCall{transport: &fakeConn{}}}
+
+	if err := m.speak(context.Background(), state, "Hello."); err != nil {
+		t.Fatalf("speak() error = %v", err)
+	}
+	if err := m.speak(context.Background(), state, "Hello.", WithSpeakingRate(0.8)); err != nil {
+		t.Fatalf("speak() error = %v", err)
+	}
+
+	if got := tts.configs[0].Speed; got != 0 {
+		t.Errorf("default speed = %g, want 0 (provider default)", got)
+	}
+	if got := tts.configs[1].Speed; got != 0.8 {
+		t.Errorf("slower speed = %g, want 0.8", got)
+	}
+}
@@ -0,0 +1,115 @@
+package voice
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/plexusone/omnivoice"
+)
+
+// dropped reports whether the call dropped and is kept for ResumeCall.
+func (cs *CallState) dropped() bool {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+	return !cs.droppedAt.IsZero()
+}
+
+// noteDropped keeps an answered call that ended without EndCall for
+// Config.ResumeWindowMS, so it can be resumed, and removes it once the
+// window ends. Calls hung up on our side are removed right after the
+// hangup, so a late status for them only starts a timer that finds
+// nothing to remove.
+func (m *Manager) noteDropped(state *CallState, status omnivoice.CallStatus) {
+	window := time.Duration(m.config.ResumeWindowMS) * time.Millisecond
+	if window <= 0 || (status != omnivoice.StatusEnded && status != omnivoice.StatusFailed) {
+		return
+	}
+
+	state.mu.Lock()
+	if state.metrics.answeredAt.IsZero() || !state.droppedAt.IsZero() {
+		state.mu.Unlock()
+		return
+	}
+	state.droppedAt = time.Now()
+	state.mu.Unlock()
+
+	slog.Info("call dropped; keeping it to resume", "call_id", state.ID, "window", window)
+	time.AfterFunc(window, func() { m.retireDropped(state) })
+}
+
+// retireDropped removes a dropped call once, whichever of ResumeCall,
+// EndCall, or the end of its window comes first.
+func (m *Manager) retireDropped(state *CallState) {
+	state.mu.Lock()
+	retired := state.retired
+	state.retired = true
+	state.mu.Unlock()
+	if retired || m.getCall(state.ID) != state {
+		return
+	}
+
+	m.removeCall(state.ID)
+	m.notifyCallEnded(state)
+}
+
+// ResumeCall re-dials the number of a call that dropped within the last
+// Config.ResumeWindowMS and continues its conversation: the new call keeps
+// the dropped one's conversation, voice, language, and metadata, speaks
+// message, and returns the user's reply. The new call has its own ID and
+// replaces the dropped one. If it does not connect, the dropped call can
+// be resumed again until its window ends.
+func (m *Manager) ResumeCall(ctx context.Context, callID, message string, opts ...SpeakOption) (*CallState, string, error) {
+	if err := m.checkMessage(message); err != nil {
+		return nil, "", err
+	}
+	old := m.tenantCall(ctx, callID)
+	if old == nil {
+		return nil, "", m.callNotFound(callID)
+	}
+	if !old.dropped() {
+		return nil, "", fmt.Errorf("%w: %s", ErrCallActive, callID)
+	}
+
+	state, err := m.dial(WithCallMetadata(ctx, old.metadata), old.to)
+	if err != nil {
+		return nil, "", err
+	}
+	state.restoreFrom(old)
+	if err := m.connect(ctx, state, message); err != nil {
+		return nil, "", err
+	}
+	m.retireDropped(old)
+	slog.Info("call resumed", "call_id", state.ID, "resumed_from", old.ID)
+
+	response, err := m.speakAndListen(ctx, state, message, nil, opts...)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, "", m.abandonCall(ctx, state)
+		}
+		return state, "", speechError(err)
+	}
+
+	return state, response, nil
+}
+
+// restoreFrom carries a dropped call's conversation and per-call settings
+// over to the call that resumes it. The turns are not sent to the
+// transcript sink again.
+func (cs *CallState) restoreFrom(old *CallState) {
+	old.mu.RLock()
+	conversation := slices.Clone(old.Conversation)
+	lastUserMessage := old.LastUserMessage
+	voiceID, language := old.voiceID, old.language
+	pauseAverage, pausesObserved := old.pauseAverage, old.pausesObserved
+	old.mu.RUnlock()
+
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	cs.Conversation = conversation
+	cs.LastUserMessage = lastUserMessage
+	cs.voiceID, cs.language = voiceID, language
+	cs.pauseAverage, cs.pausesObserved = pauseAverage, pausesObserved
+}
@@ -0,0 +1,115 @@
+package voice
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/plexusone/omnivoice"
+)
+
+// droppedCall adds an answered call to m and reports it ended, as when the
+// user hangs up.
+func droppedCall(t *testing.T, m *Manager) *CallState {
+	t.Helper()
+
+	state := m.addCall(context.Background(), &fakeCall{id: "CA-1", status: omnivoice.StatusAnswered}, "+15551234567", time.Now())
+	state.markAnswered(time.Now())
+	m.NotifyStatus("CA-1", omnivoice.StatusEnded)
+	return state
+}
+
+func TestNoteDropped(t *testing.T) {
+	m := newTestManager(t)
+	m.config.ResumeWindowMS = 50
+	state := droppedCall(t, m)
+
+	if !state.dropped() {
+		t.Fatal("call not kept after dropping")
+	}
+	if _, err := m.ContinueCall(context.Background(), state.ID, "Still there?"); !errors.Is(err, ErrCallDropped) {
+		t.Errorf("ContinueCall() error = %v, want ErrCallDropped", err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for m.getCall(state.ID) != nil {
+		if time.Now().After(deadline) {
+			t.Fatal("dropped call still registered after its window")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestNoteDropped_Off(t *testing.T) {
+	m := newTestManager(t)
+	state := droppedCall(t, m)
+	if state.dropped() {
+		t.Error("call kept with ResumeWindowMS off")
+	}
+
+	// A call that never connected is not a drop
+	m.config.ResumeWindowMS = 60000
+	ringing := m.addCall(context.Background(), &fakeCall{id: "CA-2", status: omnivoice.StatusRinging}, "+15551234567", time.Now())
+	m.NotifyStatus("CA-2", omnivoice.StatusFailed)
+	if ringing.dropped() {
+		t.Error("unanswered call kept to resume")
+	}
+}
+
+func TestResumeCall(t *testing.T) {
+	m := newTestManager(t)
+	m.config.ResumeWindowMS = 60000
+	old := droppedCall(t, m)
+	old.AddTurn("assistant", "The deploy needs your approval.")
+	old.AddTurn("user", "Which environ—")
+	old.voiceID = "voice-2"
+	old.language = "es"
+	m.callSystem = &dialCallSystem{call: hangupCall{&fakeCall{id: "CA-2", status: omnivoice.StatusAnswered}}}
+
+	// Without a TTS provider the message fails to play, after the call
+	// is connected
+	state, _, err := m.ResumeCall(context.Background(), old.ID, "Sorry, we got cut off.")
+	if !errors.Is(err, ErrSpeechFailed) || state == nil {
+		t.Fatalf("ResumeCall() = %v, %v; want the new call and ErrSpeechFailed", state, err)
+	}
+	if state.ID == old.ID || state.to != old.to {
+		t.Errorf("resumed call %s to %s, want a new call to %s", state.ID, state.to, old.to)
+	}
+	if len(state.Conversation) != 3 || state.LastUserMessage != "Which environ—" {
+		t.Errorf("Conversation = %+v, want the dropped call's turns, then the message", state.Conversation)
+	}
+	if state.voiceID != "voice-2" || state.language != "es" {
+		t.Errorf("voice, language = %q, %q; want the dropped call's", state.voiceID, state.language)
+	}
+	if m.getCall(old.ID) != nil || m.getCall(state.ID) != state {
+		t.Error("dropped call not replaced by the resumed one")
+	}
+}
+
+func TestResumeCall_NotDropped(t *testing.T) {
+	m := newTestManager(t)
+	m.config.ResumeWindowMS = 60000
+	state := m.addCall(context.Background(), &fakeCall{id: "CA-1"}, "+15551234567", time.Now())
+
+	if _, _, err := m.ResumeCall(context.Background(), state.ID, "Hello again."); !errors.Is(err, ErrCallActive) {
+		t.Errorf("ResumeCall() error = %v, want ErrCallActive", err)
+	}
+	if _, _, err := m.ResumeCall(context.Background(), "nope", "Hello again."); !errors.Is(err, ErrCallNotFound) {
+		t.Errorf("ResumeCall() on an unknown call error = %v, want ErrCallNotFound", err)
+	}
+}
+
+func TestEndCall_Dropped(t *testing.T) {
+	m := newTestManager(t)
+	m.config.ResumeWindowMS = 60000
+	state := droppedCall(t, m)
+
+	// fakeCall panics if hung up, so this also checks nothing is hung up
+	if _, err := m.EndCall(context.Background(), state.ID, "Bye!"); err != nil {
+		t.Fatalf("EndCall() error = %v", err)
+	}
+	if m.getCall(state.ID) != nil {
+		t.Error("dropped call still registered after EndCall")
+	}
+}
@@ -0,0 +1,237 @@
+package voice
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/plexusone/agentcomms/internal/tenant"
+)
+
+// scheduleMaxLateness is how late a scheduled call may still be placed, for
+// example after a restart. Older schedules are dropped.
+const scheduleMaxLateness = 15 * time.Minute
+
+// ScheduledCall is a call to be placed at a future time.
+type ScheduledCall struct {
+	ID        string    `json:"id"`
+	At        time.Time `json:"at"`
+	Message   string    `json:"message"`
+	CreatedAt time.Time `json:"created_at"`
+	Tenant    string    `json:"tenant,omitempty"` // tenant the call is placed for
+}
+
+// SetStore sets the store used to persist scheduled calls and the daily
+// call budget, restores the budget, and re-arms any schedules saved by a
+// previous run. Schedules more than scheduleMaxLateness overdue are dropped.
+// Restored schedules do not fire before Initialize; one that came due in
+// the meantime is placed as soon as Initialize succeeds.
+func (m *Manager) SetStore(store CallStore) error {
+	schedules, err := store.Schedules()
+	if err != nil {
+		return err
+	}
+	usage, err := store.Usage()
+	if err != nil {
+		return err
+	}
+	m.loadUsage(usage)
+
+	m.schedulesMu.Lock()
+	m.store = store
+	m.schedulesMu.Unlock()
+
+	for _, s := range schedules {
+		if time.Since(s.At) > scheduleMaxLateness {
+			slog.Warn("dropping overdue scheduled call", "schedule_id", s.ID, "at", s.At)
+			_ = store.DeleteSchedule(s.ID)
+			continue
+		}
+		m.armSchedule(s)
+	}
+
+	return nil
+}
+
+// ScheduleCall registers a call to be placed at the given time with the
+// given opening message. The time must be in the future and outside quiet
+// hours.
+func (m *Manager) ScheduleCall(ctx context.Context, at time.Time, message string) (*ScheduledCall, error) {
+	if err := m.checkMessage(message); err != nil {
+		return nil, err
+	}
+	if !at.After(time.Now()) {
+		return nil, fmt.Errorf("%w: %s is in the past", ErrInvalidSchedule, at.Format(time.RFC3339))
+	}
+	if m.quietHours.Contains(at.Local()) {
+		return nil, fmt.Errorf("%w: %s", ErrQuietHours, at.Local().Format(time.Kitchen))
+	}
+
+	s := ScheduledCall{
+		ID:        m.generateScheduleID(),
+		At:        at,
+		Message:   message,
+		CreatedAt: time.Now(),
+		Tenant:    tenantID(ctx),
+	}
+
+	m.schedulesMu.Lock()
+	store := m.store
+	m.schedulesMu.Unlock()
+
+	if store != nil {
+		if err := store.SaveSchedule(s); err != nil {
+			return nil, fmt.Errorf("failed to save schedule: %w", err)
+		}
+	}
+
+	m.armSchedule(s)
+	return &s, nil
+}
+
+// CancelScheduledCall cancels a scheduled call of ctx's tenant that has
+// not been placed yet.
+func (m *Manager) CancelScheduledCall(ctx context.Context, id string) error {
+	m.schedulesMu.Lock()
+	entry, ok := m.schedules[id]
+	ok = ok && entry.call.Tenant == tenantID(ctx)
+	if ok {
+		entry.stop()
+		delete(m.schedules, id)
+	}
+	store := m.store
+	m.schedulesMu.Unlock()
+
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrScheduleNotFound, id)
+	}
+
+	if store != nil {
+		if err := store.DeleteSchedule(id); err != nil {
+			return fmt.Errorf("failed to delete schedule: %w", err)
+		}
+	}
+	return nil
+}
+
+// scheduleEntry is a pending schedule. timer is nil until the manager is
+// initialized.
+type scheduleEntry struct {
+	call  ScheduledCall
+	timer *time.Timer
+}
+
+// stop stops the entry's timer, if started.
+func (e *scheduleEntry) stop() {
+	if e.timer != nil {
+		e.timer.Stop()
+	}
+}
+
+// scheduleRun is what a schedule did when it fired: the call it placed, or
+// why no call could be placed.
+type scheduleRun struct {
+	callID string
+	err    error
+}
+
+// armSchedule registers a scheduled call and starts its timer if calls can
+// be placed yet.
+func (m *Manager) armSchedule(s ScheduledCall) {
+	m.schedulesMu.Lock()
+	defer m.schedulesMu.Unlock()
+
+	entry := &scheduleEntry{call: s}
+	if m.schedulesReady {
+		entry.timer = m.scheduleTimer(s)
+	}
+	m.schedules[s.ID] = entry
+}
+
+// startSchedules starts the timers of schedules registered before
+// Initialize. Overdue ones fire right away.
+func (m *Manager) startSchedules() {
+	m.schedulesMu.Lock()
+	defer m.schedulesMu.Unlock()
+
+	m.schedulesReady = true
+	for _, entry := range m.schedules {
+		if entry.timer == nil {
+			entry.timer = m.scheduleTimer(entry.call)
+		}
+	}
+}
+
+// scheduleTimer returns a timer that places s when due.
+func (m *Manager) scheduleTimer(s ScheduledCall) *time.Timer {
+	return time.AfterFunc(time.Until(s.At), func() { m.runScheduledCall(s.ID) })
+}
+
+// runScheduledCall places a scheduled call. The opening message and the
+// user's reply are handled as for InitiateCallAsync, so the agent finds the
+// call with ScheduledCallID and continues or ends it like any other. The
+// schedule is removed from the store only once the call is dialed; if
+// dialing fails it stays persisted and is retried on the next start while
+// within scheduleMaxLateness.
+func (m *Manager) runScheduledCall(id string) {
+	m.schedulesMu.Lock()
+	entry, ok := m.schedules[id]
+	delete(m.schedules, id)
+	store := m.store
+	m.schedulesMu.Unlock()
+
+	if !ok {
+		return // cancelled
+	}
+
+	ctx := context.Background()
+	if entry.call.Tenant != "" {
+		ctx = tenant.WithTenantID(ctx, entry.call.Tenant)
+	}
+	state, err := m.InitiateCallAsync(ctx, entry.call.Message)
+
+	m.schedulesMu.Lock()
+	if err != nil {
+		m.ran[id] = scheduleRun{err: err}
+	} else {
+		m.ran[id] = scheduleRun{callID: state.ID}
+	}
+	m.schedulesMu.Unlock()
+
+	if err != nil {
+		slog.Warn("scheduled call failed", "schedule_id", id, "error", err)
+		return
+	}
+	if store != nil {
+		_ = store.DeleteSchedule(id)
+	}
+	slog.Info("scheduled call placed", "schedule_id", id, "call_id", state.ID)
+}
+
+// ScheduledCallID returns the ID of the call placed for a schedule, for use
+// with CallStatus, ContinueCall, and EndCall. It returns "" while the
+// schedule is pending, and the reason if the call could not be placed.
+func (m *Manager) ScheduledCallID(scheduleID string) (string, error) {
+	m.schedulesMu.Lock()
+	defer m.schedulesMu.Unlock()
+
+	if run, ok := m.ran[scheduleID]; ok {
+		if run.err != nil {
+			return "", fmt.Errorf("scheduled call %s could not be placed: %w", scheduleID, run.err)
+		}
+		return run.callID, nil
+	}
+	if _, ok := m.schedules[scheduleID]; ok {
+		return "", nil
+	}
+	return "", fmt.Errorf("%w: %s", ErrScheduleNotFound, scheduleID)
+}
+
+// generateScheduleID returns a unique schedule ID.
+func (m *Manager) generateScheduleID() string {
+	m.counterMu.Lock()
+	defer m.counterMu.Unlock()
+	m.callCounter++
+	return fmt.Sprintf("sched-%d-%d", m.callCounter, time.Now().Unix())
+}
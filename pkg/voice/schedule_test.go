@@ -0,0 +1,190 @@
+package voice
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/plexusone/omnivoice"
+
+	"github.com/plexusone/agentcomms/pkg/config"
+)
+
+func TestFileCallStore(t *testing.T) {
+	store := NewFileCallStore(filepath.Join(t.TempDir(), "nested", "calls.json"))
+
+	schedules, err := store.Schedules()
+	if err != nil || len(schedules) != 0 {
+		t.Fatalf("Schedules() on missing file = %v, %v; want empty", schedules, err)
+	}
+
+	at := time.Now().Add(time.Hour).Truncate(time.Second)
+	for _, id := range []string{"sched-1", "sched-2"} {
+		if err := store.SaveSchedule(ScheduledCall{ID: id, At: at, Message: "hi"}); err != nil {
+			t.Fatalf("SaveSchedule(%s) error = %v", id, err)
+		}
+	}
+	if err := store.DeleteSchedule("sched-1"); err != nil {
+		t.Fatalf("DeleteSchedule() error = %v", err)
+	}
+
+	schedules, err = store.Schedules()
+	if err != nil {
+		t.Fatalf("Schedules() error = %v", err)
+	}
+	if len(schedules) != 1 || schedules[0].ID != "sched-2" || !schedules[0].At.Equal(at) {
+		t.Errorf("Schedules() = %+v, want only sched-2", schedules)
+	}
+}
+
+func TestScheduleCall(t *testing.T) {
+	m := newTestManager(t)
+	store := NewFileCallStore(filepath.Join(t.TempDir(), "calls.json"))
+	if err := m.SetStore(store); err != nil {
+		t.Fatalf("SetStore() error = %v", err)
+	}
+	defer func() { _ = m.Close() }()
+
+	s, err := m.ScheduleCall(context.Background(), time.Now().Add(time.Hour), "Results are in")
+	if err != nil {
+		t.Fatalf("ScheduleCall() error = %v", err)
+	}
+
+	saved, _ := store.Schedules()
+	if len(saved) != 1 || saved[0].ID != s.ID {
+		t.Fatalf("store = %+v, want schedule %s", saved, s.ID)
+	}
+
+	if err := m.CancelScheduledCall(context.Background(), s.ID); err != nil {
+		t.Fatalf("CancelScheduledCall() error = %v", err)
+	}
+	if saved, _ := store.Schedules(); len(saved) != 0 {
+		t.Errorf("store = %+v after cancel, want empty", saved)
+	}
+	if err := m.CancelScheduledCall(context.Background(), s.ID); !errors.Is(err, ErrScheduleNotFound) {
+		t.Errorf("second CancelScheduledCall() error = %v, want ErrScheduleNotFound", err)
+	}
+}
+
+func TestScheduleCall_Rejects(t *testing.T) {
+	m := newTestManager(t)
+	m.quietHours = mustQuietHours(t, "00:00-23:59")
+
+	if _, err := m.ScheduleCall(context.Background(), time.Now().Add(-time.Minute), "late"); !errors.Is(err, ErrInvalidSchedule) {
+		t.Errorf("past time error = %v, want ErrInvalidSchedule", err)
+	}
+	at := time.Date(2099, 1, 1, 12, 0, 0, 0, time.Local)
+	if _, err := m.ScheduleCall(context.Background(), at, "noon"); !errors.Is(err, ErrQuietHours) {
+		t.Errorf("quiet hours error = %v, want ErrQuietHours", err)
+	}
+}
+
+func TestSetStore_RestoresSchedules(t *testing.T) {
+	store := NewFileCallStore(filepath.Join(t.TempDir(), "calls.json"))
+	_ = store.SaveSchedule(ScheduledCall{ID: "sched-future", At: time.Now().Add(time.Hour)})
+	_ = store.SaveSchedule(ScheduledCall{ID: "sched-stale", At: time.Now().Add(-time.Hour)})
+
+	m := newTestManager(t)
+	if err := m.SetStore(store); err != nil {
+		t.Fatalf("SetStore() error = %v", err)
+	}
+	defer func() { _ = m.Close() }()
+
+	if _, ok := m.schedules["sched-future"]; !ok {
+		t.Error("expected future schedule to be re-armed")
+	}
+	if _, ok := m.schedules["sched-stale"]; ok {
+		t.Error("expected stale schedule to be dropped")
+	}
+	if saved, _ := store.Schedules(); len(saved) != 1 {
+		t.Errorf("store = %+v, want only the future schedule", saved)
+	}
+}
+
+func TestScheduledCall_WaitsForInitialize(t *testing.T) {
+	store := NewFileCallStore(filepath.Join(t.TempDir(), "calls.json"))
+	_ = store.SaveSchedule(ScheduledCall{ID: "sched-due", At: time.Now().Add(-time.Minute), Message: "hi"})
+
+	m := newTestManager(t)
+	if err := m.SetStore(store); err != nil {
+		t.Fatalf("SetStore() error = %v", err)
+	}
+	defer func() { _ = m.Close() }()
+
+	if entry := m.schedules["sched-due"]; entry == nil || entry.timer != nil {
+		t.Fatalf("schedule = %+v, want registered without a timer", entry)
+	}
+	if callID, err := m.ScheduledCallID("sched-due"); callID != "" || err != nil {
+		t.Errorf("ScheduledCallID() = %q, %v; want pending", callID, err)
+	}
+
+	// A failed dial keeps the schedule persisted for the next start
+	m.callSystem = &errDialCallSystem{err: errors.New("provider down")}
+	m.startSchedules()
+	waitForScheduleRun(t, m, "sched-due")
+
+	if _, err := m.ScheduledCallID("sched-due"); !errors.Is(err, ErrDialFailed) {
+		t.Errorf("ScheduledCallID() error = %v, want ErrDialFailed", err)
+	}
+	if saved, _ := store.Schedules(); len(saved) != 1 {
+		t.Errorf("store = %+v, want the schedule kept", saved)
+	}
+}
+
+func TestScheduledCall_Placed(t *testing.T) {
+	store := NewFileCallStore(filepath.Join(t.TempDir(), "calls.json"))
+	m := newTestManager(t)
+	if err := m.SetStore(store); err != nil {
+		t.Fatalf("SetStore() error = %v", err)
+	}
+	defer func() { _ = m.Close() }()
+
+	m.callSystem = &dialCallSystem{call: hangupCall{&fakeCall{id: "CA-1", status: omnivoice.StatusRinging}}}
+	m.startSchedules()
+
+	s, err := m.ScheduleCall(context.Background(), time.Now().Add(10*time.Millisecond), "Results are in")
+	if err != nil {
+		t.Fatalf("ScheduleCall() error = %v", err)
+	}
+	waitForScheduleRun(t, m, s.ID)
+
+	callID, err := m.ScheduledCallID(s.ID)
+	if err != nil || callID == "" {
+		t.Fatalf("ScheduledCallID() = %q, %v; want the placed call", callID, err)
+	}
+	if info, err := m.CallStatus(context.Background(), callID); err != nil || !info.Pending {
+		t.Errorf("CallStatus() = %+v, %v; want a pending opening turn", info, err)
+	}
+	if saved, _ := store.Schedules(); len(saved) != 0 {
+		t.Errorf("store = %+v, want the schedule removed once dialed", saved)
+	}
+}
+
+// waitForScheduleRun waits until the schedule has fired.
+func waitForScheduleRun(t *testing.T, m *Manager, id string) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		m.schedulesMu.Lock()
+		_, ran := m.ran[id]
+		m.schedulesMu.Unlock()
+		if ran {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("schedule %s did not run", id)
+}
+
+func mustQuietHours(t *testing.T, s string) config.QuietHours {
+	t.Helper()
+
+	q, err := config.ParseQuietHours(s)
+	if err != nil {
+		t.Fatalf("ParseQuietHours(%q) error = %v", s, err)
+	}
+	return q
+}
@@ -0,0 +1,223 @@
+package voice
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+// sentimentTimeout bounds sentiment analysis of a single reply, so a slow
+// analyzer delays the turn by at most this long.
+const sentimentTimeout = 2 * time.Second
+
+// Sentiment is the detected tone of a user reply.
+type Sentiment string
+
+// Sentiments.
+const (
+	SentimentPositive   Sentiment = "positive"
+	SentimentNeutral    Sentiment = "neutral"
+	SentimentNegative   Sentiment = "negative"
+	SentimentFrustrated Sentiment = "frustrated"
+)
+
+// valid reports whether s is one of the known sentiments.
+func (s Sentiment) valid() bool {
+	switch s {
+	case SentimentPositive, SentimentNeutral, SentimentNegative, SentimentFrustrated:
+		return true
+	}
+	return false
+}
+
+// SentimentAnalyzer classifies the sentiment of a user reply.
+type SentimentAnalyzer interface {
+	Analyze(ctx context.Context, text string) (Sentiment, error)
+}
+
+// NewSentimentAnalyzer returns the analyzer for a Sentiment setting:
+// "local" for the built-in keyword heuristic, or an http(s) URL for an
+// external API. An empty setting returns nil (analysis off).
+func NewSentimentAnalyzer(setting string) (SentimentAnalyzer, error) {
+	switch {
+	case setting == "":
+		return nil, nil
+	case setting == "local":
+		return HeuristicSentiment{}, nil
+	case strings.HasPrefix(setting, "http://") || strings.HasPrefix(setting, "https://"):
+		return &httpSentiment{url: setting, client: &http.Client{Timeout: sentimentTimeout}}, nil
+	default:
+		return nil, fmt.Errorf("invalid sentiment setting %q (must be \"local\" or an http(s) URL)", setting)
+	}
+}
+
+// SetSentimentAnalyzer replaces the analyzer used for user replies; nil
+// turns analysis off. Call it before placing calls.
+func (m *Manager) SetSentimentAnalyzer(a SentimentAnalyzer) {
+	m.sentiment = a
+}
+
+// LastSentiment returns the sentiment of the latest user reply on a call,
+// or "" if analysis is off, failed, or the call is unknown.
+func (m *Manager) LastSentiment(callID string) Sentiment {
+	state := m.getCall(callID)
+	if state == nil {
+		return ""
+	}
+
+	state.mu.RLock()
+	defer state.mu.RUnlock()
+	for i := len(state.Conversation) - 1; i >= 0; i-- {
+		if state.Conversation[i].Role == "user" {
+			return state.Conversation[i].Sentiment
+		}
+	}
+	return ""
+}
+
+// analyzeSentiment classifies a user reply. Failures are logged and yield
+// "", so analysis never fails the turn. It runs even if ctx was cancelled
+// as the reply arrived.
+func (m *Manager) analyzeSentiment(ctx context.Context, state *CallState, text string) Sentiment {
+	if m.sentiment == nil {
+		return ""
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), sentimentTimeout)
+	defer cancel()
+
+	s, err := m.sentiment.Analyze(ctx, text)
+	if err != nil {
+		slog.Warn("sentiment analysis failed", "call_id", state.ID, "error", err)
+		return ""
+	}
+	return s
+}
+
+// Keyword lists for HeuristicSentiment.
+var (
+	frustratedPhrases = []string{
+		"frustrated", "frustrating", "annoyed", "annoying", "ridiculous",
+		"come on", "still broken", "still not", "not again", "waste of time", "useless",
+		"fed up", "sick of", "how many times",
+	}
+	negativeWords = []string{
+		"no", "bad", "wrong", "broken", "problem", "issue", "fail", "failed", "failing",
+		"hate", "worse", "worst", "unfortunately", "terrible", "awful", "confused",
+	}
+	positiveWords = []string{
+		"yes", "great", "good", "thanks", "thank", "awesome", "perfect", "nice", "love",
+		"excellent", "cool", "amazing", "fantastic", "happy", "sure",
+	}
+	negations = []string{"not", "don't", "dont", "never", "isn't", "isnt", "no"}
+)
+
+// HeuristicSentiment is a local, keyword-based analyzer. It needs no
+// network access and is good enough to spot clear frustration.
+type HeuristicSentiment struct{}
+
+// Analyze classifies text by counting positive and negative words. A
+// preceding negation flips the word that follows it, so "not good" counts
+// as negative and "no problem" as positive; a negation that modifies
+// another word ("no" in "no problem") is not scored itself.
+func (HeuristicSentiment) Analyze(_ context.Context, text string) (Sentiment, error) {
+	words := normalizeWords(text)
+	for _, phrase := range frustratedPhrases {
+		if containsWords(words, normalizeWords(phrase)) {
+			return SentimentFrustrated, nil
+		}
+	}
+
+	score := 0
+	for i, w := range words {
+		if slices.Contains(negations, w) && i+1 < len(words) && wordPolarity(words[i+1]) != 0 {
+			continue
+		}
+		p := wordPolarity(w)
+		if i > 0 && slices.Contains(negations, words[i-1]) {
+			p = -p
+		}
+		score += p
+	}
+
+	switch {
+	case score > 0:
+		return SentimentPositive, nil
+	case score < 0:
+		return SentimentNegative, nil
+	default:
+		return SentimentNeutral, nil
+	}
+}
+
+// wordPolarity is 1 for a positive word, -1 for a negative one, and 0
+// otherwise.
+func wordPolarity(w string) int {
+	switch {
+	case slices.Contains(positiveWords, w):
+		return 1
+	case slices.Contains(negativeWords, w):
+		return -1
+	}
+	return 0
+}
+
+// containsWords reports whether phrase appears as consecutive words.
+func containsWords(words, phrase []string) bool {
+	if len(phrase) == 0 {
+		return false
+	}
+	for i := 0; i+len(phrase) <= len(words); i++ {
+		if slices.Equal(words[i:i+len(phrase)], phrase) {
+			return true
+		}
+	}
+	return false
+}
+
+// httpSentiment asks an external API. It posts {"text": ...} and expects
+// {"sentiment": "positive" | "neutral" | "negative" | "frustrated"}.
+type httpSentiment struct {
+	url    string
+	client *http.Client
+}
+
+func (h *httpSentiment) Analyze(ctx context.Context, text string) (Sentiment, error) {
+	body, err := json.Marshal(map[string]string{"text": text})
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.url, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode >= 300 {
+		return "", fmt.Errorf("sentiment API returned %s", resp.Status)
+	}
+
+	var out struct {
+		Sentiment Sentiment `json:"sentiment"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return "", fmt.Errorf("invalid sentiment API response: %w", err)
+	}
+	if !out.Sentiment.valid() {
+		return "", fmt.Errorf("unknown sentiment %q", out.Sentiment)
+	}
+	return out.Sentiment, nil
+}
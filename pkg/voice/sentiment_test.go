@@ -0,0 +1,148 @@
+package voice
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/plexusone/omnivoice"
+)
+
+func TestHeuristicSentiment(t *testing.T) {
+	tests := []struct {
+		text string
+		want Sentiment
+	}{
+		{"Great, thanks!", SentimentPositive},
+		{"Yes, that sounds perfect.", SentimentPositive},
+		{"Okay.", SentimentNeutral},
+		{"Deploy it to staging.", SentimentNeutral},
+		{"No, that's wrong.", SentimentNegative},
+		{"That's not good.", SentimentNegative},
+		{"No problem, thanks!", SentimentPositive},
+		{"No.", SentimentNegative},
+		{"That was seriously great.", SentimentPositive},
+		{"Seriously, it's still broken.", SentimentFrustrated},
+		{"I'm so frustrated with this.", SentimentFrustrated},
+		{"How many times do I have to say it?", SentimentFrustrated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			got, err := HeuristicSentiment{}.Analyze(context.Background(), tt.text)
+			if err != nil {
+				t.Fatalf("Analyze() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Analyze(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewSentimentAnalyzer(t *testing.T) {
+	tests := []struct {
+		setting string
+		wantNil bool
+		wantErr bool
+	}{
+		{setting: "", wantNil: true},
+		{setting: "local"},
+		{setting: "https://sentiment.example.com/analyze"},
+		{setting: "vader", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.setting, func(t *testing.T) {
+			a, err := NewSentimentAnalyzer(tt.setting)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewSentimentAnalyzer(%q) error = %v, wantErr %v", tt.setting, err, tt.wantErr)
+			}
+			if !tt.wantErr && (a == nil) != tt.wantNil {
+				t.Errorf("NewSentimentAnalyzer(%q) = %v, wantNil %v", tt.setting, a, tt.wantNil)
+			}
+		})
+	}
+}
+
+func TestHTTPSentiment(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var in struct{ Text string }
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		switch in.Text {
+		case "unknown":
+			_, _ = w.Write([]byte(`{"sentiment":"ecstatic"}`))
+		case "fail":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			_, _ = w.Write([]byte(`{"sentiment":"frustrated"}`))
+		}
+	}))
+	defer srv.Close()
+
+	a, err := NewSentimentAnalyzer(srv.URL)
+	if err != nil {
+		t.Fatalf("NewSentimentAnalyzer() error = %v", err)
+	}
+
+	got, err := a.Analyze(context.Background(), "this is still broken")
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+	if got != SentimentFrustrated {
+		t.Errorf("Analyze() = %q, want %q", got, SentimentFrustrated)
+	}
+
+	for _, text := range []string{"unknown", "fail"} {
+		if _, err := a.Analyze(context.Background(), text); err == nil {
+			t.Errorf("Analyze(%q) error = nil, want error", text)
+		}
+	}
+}
+
+type failingSentiment struct{}
+
+func (failingSentiment) Analyze(context.Context, string) (Sentiment, error) {
+	return "", errors.New("unavailable")
+}
+
+func TestAwaitTranscript_Sentiment(t *testing.T) {
+	tests := []struct {
+		name     string
+		analyzer SentimentAnalyzer
+		want     Sentiment
+	}{
+		{name: "off"},
+		{name: "local", analyzer: HeuristicSentiment{}, want: SentimentPositive},
+		{name: "failure does not block the turn", analyzer: failingSentiment{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestManager(t)
+			m.SetSentimentAnalyzer(tt.analyzer)
+			state := &CallState{ID: "call-1"}
+			m.calls[state.ID] = state
+
+			events := make(chan omnivoice.StreamEvent, 1)
+			events <- omnivoice.StreamEvent{Transcript: "great, thanks", IsFinal: true}
+			close(events)
+
+			got, err := m.awaitTranscript(context.Background(), state, events, nil)
+			if err != nil {
+				t.Fatalf("awaitTranscript() error = %v", err)
+			}
+			if got != "great, thanks" {
+				t.Errorf("transcript = %q, want %q", got, "great, thanks")
+			}
+			if s := m.LastSentiment(state.ID); s != tt.want {
+				t.Errorf("LastSentiment() = %q, want %q", s, tt.want)
+			}
+		})
+	}
+}
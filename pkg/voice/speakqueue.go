@@ -0,0 +1,57 @@
+package voice
+
+import (
+	"context"
+	"sync"
+)
+
+// speakQueue makes messages on a call play one at a time, in the order
+// speak was called. The audio lock alone would keep messages from
+// interleaving, but a sync.Mutex does not hand off in FIFO order, so two
+// acknowledgments sent back to back could play in the wrong order.
+type speakQueue struct {
+	mu   sync.Mutex
+	tail chan struct{} // closed when the last queued message is done
+	n    int           // messages queued or playing
+}
+
+// enter waits for the messages queued before it and returns a function to
+// call when this message is done. If ctx ends while waiting, the message
+// gives up its place and ctx's error is returned; later messages still
+// wait for the earlier ones.
+func (q *speakQueue) enter(ctx context.Context) (func(), error) {
+	q.mu.Lock()
+	prev := q.tail
+	done := make(chan struct{})
+	q.tail = done
+	q.n++
+	q.mu.Unlock()
+
+	leave := func() {
+		q.mu.Lock()
+		q.n--
+		q.mu.Unlock()
+		close(done)
+	}
+
+	if prev == nil {
+		return leave, nil
+	}
+	select {
+	case <-prev:
+		return leave, nil
+	case <-ctx.Done():
+		go func() {
+			<-prev
+			leave()
+		}()
+		return nil, ctx.Err()
+	}
+}
+
+// len returns the number of messages queued or playing.
+func (q *speakQueue) len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.n
+}
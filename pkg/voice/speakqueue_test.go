@@ -0,0 +1,101 @@
+package voice
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/plexusone/omnivoice"
+)
+
+// gatedTTS speaks each text as its own audio. The first synthesis waits
+// until gate is closed.
+type gatedTTS struct {
+	omnivoice.TTSProvider
+
+	gate  chan struct{}
+	calls atomic.Int32
+}
+
+func (p *gatedTTS) SynthesizeStream(_ context.Context, text string, _ omnivoice.SynthesisConfig) (<-chan omnivoice.StreamChunk, error) {
+	if p.calls.Add(1) == 1 {
+		<-p.gate
+	}
+	ch := make(chan omnivoice.StreamChunk, 1)
+	ch <- omnivoice.StreamChunk{Audio: []byte(text), IsFinal: true}
+	close(ch)
+	return ch, nil
+}
+
+func TestSpeak_PlaysInSubmissionOrder(t *testing.T) {
+	m := newTestManager(t)
+	tts := &gatedTTS{gate: make(chan struct{})}
+	m.ttsProvider = tts
+	conn := &fakeConn{}
+	state := &CallState{ID: "call-1", Call: &fakeCall{transport: conn}}
+
+	var wg sync.WaitGroup
+	for i, msg := range []string{"one.", "two.", "three."} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := m.speak(context.Background(), state, msg); err != nil {
+				t.Errorf("speak(%q) error = %v", msg, err)
+			}
+		}()
+		// Submit the next message only once this one is queued
+		waitFor(t, func() bool { return state.speakQueue.len() == i+1 })
+	}
+	close(tts.gate)
+	wg.Wait()
+
+	if got := conn.audio.String(); got != "one.two.three." {
+		t.Errorf("audio = %q, want the messages in submission order", got)
+	}
+	var turns []string
+	for _, turn := range state.Conversation {
+		turns = append(turns, turn.Content)
+	}
+	if len(turns) != 3 || turns[0] != "one." || turns[1] != "two." || turns[2] != "three." {
+		t.Errorf("turns = %q, want the messages in submission order", turns)
+	}
+}
+
+func TestSpeakQueue_CanceledWaitKeepsOrder(t *testing.T) {
+	var q speakQueue
+	leaveFirst, _ := q.enter(context.Background())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := q.enter(ctx); err == nil {
+		t.Fatal("enter() with a canceled context succeeded while queued")
+	}
+
+	entered := make(chan struct{})
+	go func() {
+		leave, _ := q.enter(context.Background())
+		close(entered)
+		leave()
+	}()
+	select {
+	case <-entered:
+		t.Fatal("third message entered before the first was done")
+	case <-time.After(20 * time.Millisecond):
+	}
+	leaveFirst()
+	<-entered
+}
+
+// waitFor polls cond until it is true or a second has passed.
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met within a second")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,37 @@
+package voice
+
+import "sync"
+
+// SpeechStartFunc is called when the user starts speaking on a call, with
+// the first words heard. It runs on the listen loop, so it must return
+// quickly; start a goroutine for anything slow.
+type SpeechStartFunc func(callID, transcript string)
+
+// speechStartHooks are the functions registered with OnUserStartedSpeaking.
+type speechStartHooks struct {
+	mu  sync.RWMutex
+	fns []SpeechStartFunc
+}
+
+// OnUserStartedSpeaking registers fn to be called once per user turn, at
+// the first transcript of it, partial or final, that is not discarded as
+// echo. It lets features such as barge-in react to the user speaking
+// without waiting for the turn to end; the reply listening returns is not
+// affected. Registered functions are called in order and stay registered
+// for the life of the manager.
+func (m *Manager) OnUserStartedSpeaking(fn SpeechStartFunc) {
+	m.speechStart.mu.Lock()
+	defer m.speechStart.mu.Unlock()
+	m.speechStart.fns = append(m.speechStart.fns, fn)
+}
+
+// notifySpeechStart calls the OnUserStartedSpeaking functions.
+func (m *Manager) notifySpeechStart(state *CallState, transcript string) {
+	m.speechStart.mu.RLock()
+	fns := m.speechStart.fns
+	m.speechStart.mu.RUnlock()
+
+	for _, fn := range fns {
+		fn(state.ID, transcript)
+	}
+}
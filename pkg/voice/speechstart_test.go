@@ -0,0 +1,44 @@
+package voice
+
+import (
+	"context"
+	"testing"
+
+	"github.com/plexusone/omnivoice"
+)
+
+func TestOnUserStartedSpeaking(t *testing.T) {
+	m := newTestManager(t)
+	m.config.AggregateFinalsMS = 50
+	var calls []string
+	m.OnUserStartedSpeaking(func(callID, transcript string) {
+		calls = append(calls, callID+": "+transcript)
+	})
+	state := &CallState{ID: "call-1"}
+
+	events := make(chan omnivoice.StreamEvent, 4)
+	events <- omnivoice.StreamEvent{Transcript: ""}
+	events <- omnivoice.StreamEvent{Transcript: "Yes"}
+	events <- omnivoice.StreamEvent{Transcript: "Yes, go", IsFinal: true}
+	events <- omnivoice.StreamEvent{Transcript: "ahead.", IsFinal: true}
+
+	got, err := m.awaitTranscript(context.Background(), state, events, nil)
+	if err != nil {
+		t.Fatalf("awaitTranscript() error = %v", err)
+	}
+	if got != "Yes, go ahead." {
+		t.Errorf("transcript = %q, want the whole reply", got)
+	}
+	if len(calls) != 1 || calls[0] != "call-1: Yes" {
+		t.Errorf("hook calls = %q, want one at the first partial", calls)
+	}
+
+	// Each turn starts again
+	events <- omnivoice.StreamEvent{Transcript: "No.", IsFinal: true}
+	if _, err := m.awaitTranscript(context.Background(), state, events, nil); err != nil {
+		t.Fatalf("second awaitTranscript() error = %v", err)
+	}
+	if len(calls) != 2 || calls[1] != "call-1: No." {
+		t.Errorf("hook calls = %q, want a second for the next turn", calls)
+	}
+}
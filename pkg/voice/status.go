@@ -0,0 +1,193 @@
+package voice
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/plexusone/omnivoice"
+)
+
+// CallStatusInfo is a read-only snapshot of a call.
+type CallStatusInfo struct {
+	CallID    string
+	Status    omnivoice.CallStatus
+	Duration  time.Duration
+	Speaking  bool // TTS audio is being sent
+	Listening bool // waiting for the user's reply
+	Muted     bool // listening is muted (see Manager.SetListeningMuted)
+	Metrics   CallMetrics
+
+	// For calls placed with InitiateCallAsync: Pending is set until the
+	// opening message was spoken and answered, then Response holds the
+	// user's reply, or NoSpeech is set if nothing was heard.
+	Pending  bool
+	Response string
+	NoSpeech bool
+}
+
+// CallStatus returns the current status of a call without affecting it,
+// so the agent can check that a call is still live before continuing it.
+func (m *Manager) CallStatus(ctx context.Context, callID string) (CallStatusInfo, error) {
+	state := m.tenantCall(ctx, callID)
+	if state == nil {
+		return CallStatusInfo{}, m.callNotFound(callID)
+	}
+
+	state.mu.RLock()
+	info := CallStatusInfo{
+		CallID:    state.ID,
+		Status:    state.status,
+		Duration:  state.Duration(),
+		Speaking:  state.speaking,
+		Listening: state.listening,
+		Muted:     state.listeningMuted,
+	}
+	state.mu.RUnlock()
+	info.Metrics = state.Metrics()
+
+	// Fall back to the provider when no status callback has arrived
+	if info.Status == "" && state.Call != nil {
+		info.Status = state.Call.Status()
+	}
+
+	pending, response, err := state.openingResult()
+	switch {
+	case errors.Is(err, ErrNoSpeech):
+		info.NoSpeech = true
+	case err != nil:
+		return CallStatusInfo{}, err
+	}
+	info.Pending = pending
+	info.Response = response
+	return info, nil
+}
+
+// CallElapsed returns how long a call has lasted so far and its cost
+// estimated the same way as for the call-ended webhook, so the agent can
+// keep an eye on both between turns. It returns zeros for unknown calls.
+func (m *Manager) CallElapsed(callID string) (time.Duration, float64) {
+	state := m.getCall(callID)
+	if state == nil {
+		return 0, 0
+	}
+	d := state.Duration()
+	return d, estimatedCost(d, m.config.CallCostPerMinuteUSD)
+}
+
+// IsNumberActive reports whether a call placed for ctx's tenant to number,
+// or the user's number if number is empty, is ringing or in progress, and
+// returns the newest such call's ID, so the agent can continue it instead of
+// dialing again. A participant in a conference counts, with the
+// conference's call ID. Dropped calls kept for ResumeCall do not.
+func (m *Manager) IsNumberActive(ctx context.Context, number string) (string, bool) {
+	if number == "" {
+		number = m.configFor(ctx).UserPhoneNumber
+	}
+	if number == "" {
+		return "", false
+	}
+	tenant := tenantID(ctx)
+
+	var active *CallState
+	newer := func(state *CallState) {
+		if active == nil || state.StartTime.After(active.StartTime) {
+			active = state
+		}
+	}
+
+	m.callsMu.RLock()
+	calls := make([]*CallState, 0, len(m.calls))
+	for _, state := range m.calls {
+		if state.tenant == tenant && state.to == number {
+			calls = append(calls, state)
+		}
+	}
+	m.callsMu.RUnlock()
+	// Statuses may be polled from the provider, so not under callsMu
+	for _, state := range calls {
+		if state.Call != nil && !state.dropped() && !terminalStatus(state.currentStatus()) {
+			newer(state)
+		}
+	}
+
+	m.conferencesMu.RLock()
+	for _, conf := range m.conferences {
+		if conf.Call == nil || conf.Call.tenant != tenant {
+			continue
+		}
+		for _, p := range conf.Participants() {
+			if p.Number == number && p.CallSID != "" && !terminalStatus(p.Status) {
+				newer(conf.Call)
+				break
+			}
+		}
+	}
+	m.conferencesMu.RUnlock()
+
+	if active == nil {
+		return "", false
+	}
+	return active.ID, true
+}
+
+// currentStatus returns the call's latest status callback, or the
+// provider's status if none has arrived.
+func (cs *CallState) currentStatus() omnivoice.CallStatus {
+	cs.mu.RLock()
+	status := cs.status
+	cs.mu.RUnlock()
+	if status == "" && cs.Call != nil {
+		status = cs.Call.Status()
+	}
+	return status
+}
+
+// StatusHandler serves Twilio's call status callbacks at StatusPath,
+// passing statuses to NotifyStatus and answering machine detection
+// results to NotifyAnsweredBy.
+func (m *Manager) StatusHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.Body = http.MaxBytesReader(w, r.Body, 1<<20)
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, "Bad request", http.StatusBadRequest)
+			return
+		}
+
+		callSID := r.Form.Get("CallSid")
+		callStatus := r.Form.Get("CallStatus")
+		slog.Info("call status update", "call_sid", logSafe(callSID), "status", logSafe(callStatus))
+		if status, ok := StatusFromTwilio(callStatus); ok {
+			m.NotifyStatus(callSID, status)
+		}
+		// AnsweredBy is present when answering machine detection is enabled
+		if answeredBy, ok := AnsweredByFromTwilio(r.Form.Get("AnsweredBy")); ok {
+			slog.Info("answering machine detection", "call_sid", logSafe(callSID), "answered_by", answeredBy)
+			m.NotifyAnsweredBy(callSID, answeredBy)
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+// setStatus records the latest status reported for the call.
+func (cs *CallState) setStatus(status omnivoice.CallStatus) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	cs.status = status
+}
+
+// setListening marks the start or end of waiting for the user's reply,
+// adding the time spent to the call's STT usage.
+func (cs *CallState) setListening(listening bool) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	switch {
+	case listening && !cs.listening:
+		cs.metrics.listenedAt = time.Now()
+	case !listening && cs.listening:
+		cs.metrics.sttTime += time.Since(cs.metrics.listenedAt)
+	}
+	cs.listening = listening
+}
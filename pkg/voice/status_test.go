@@ -0,0 +1,174 @@
+package voice
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/plexusone/omnivoice"
+
+	"github.com/plexusone/agentcomms/internal/tenant"
+)
+
+func TestCallStatus(t *testing.T) {
+	m := newTestManager(t)
+	call := &fakeCall{id: "CA123", status: omnivoice.StatusAnswered}
+	state := &CallState{
+		ID:       "call-1",
+		Call:     call,
+		statusCh: make(chan omnivoice.CallStatus, 1),
+	}
+	m.calls[state.ID] = state
+
+	info, err := m.CallStatus(context.Background(), "call-1")
+	if err != nil {
+		t.Fatalf("CallStatus() error = %v", err)
+	}
+	if info.Status != omnivoice.StatusAnswered {
+		t.Errorf("Status = %q, want %q from the provider", info.Status, omnivoice.StatusAnswered)
+	}
+
+	m.NotifyStatus("CA123", omnivoice.StatusEnded)
+	state.setListening(true)
+
+	info, err = m.CallStatus(context.Background(), "call-1")
+	if err != nil {
+		t.Fatalf("CallStatus() error = %v", err)
+	}
+	if info.Status != omnivoice.StatusEnded {
+		t.Errorf("Status = %q, want %q from the status callback", info.Status, omnivoice.StatusEnded)
+	}
+	if !info.Listening || info.Speaking {
+		t.Errorf("Listening, Speaking = %v, %v, want true, false", info.Listening, info.Speaking)
+	}
+
+	state.metrics.audioBytesIn.Add(8000)
+	info, err = m.CallStatus(context.Background(), "call-1")
+	if err != nil {
+		t.Fatalf("CallStatus() error = %v", err)
+	}
+	if info.Metrics.AudioBytesIn != 8000 {
+		t.Errorf("Metrics.AudioBytesIn = %d, want 8000 while the call is live", info.Metrics.AudioBytesIn)
+	}
+}
+
+func TestCallStatus_NotFound(t *testing.T) {
+	m := newTestManager(t)
+
+	if _, err := m.CallStatus(context.Background(), "missing"); !errors.Is(err, ErrCallNotFound) {
+		t.Errorf("CallStatus() error = %v, want ErrCallNotFound", err)
+	}
+}
+
+func TestIsNumberActive(t *testing.T) {
+	m := newTestManager(t)
+	m.config.UserPhoneNumber = "+15551234567"
+	ctx := context.Background()
+
+	if id, ok := m.IsNumberActive(ctx, ""); ok {
+		t.Errorf("IsNumberActive() = %q, true with no calls", id)
+	}
+
+	ended := m.addCall(ctx, &fakeCall{id: "CA-1", status: omnivoice.StatusEnded}, "+15551234567", time.Now())
+	m.addCall(tenant.WithTenantID(ctx, "acme"), &fakeCall{id: "CA-2", status: omnivoice.StatusAnswered}, "+15551234567", time.Now())
+	if id, ok := m.IsNumberActive(ctx, ""); ok {
+		t.Errorf("IsNumberActive() = %q, true with only an ended call and another tenant's", id)
+	}
+	ended.droppedAt = time.Now()
+
+	ringing := m.addCall(ctx, &fakeCall{id: "CA-3", status: omnivoice.StatusRinging}, "+15551234567", time.Now())
+	if id, ok := m.IsNumberActive(ctx, ""); !ok || id != ringing.ID {
+		t.Errorf("IsNumberActive() = %q, %v; want %q, true while ringing", id, ok, ringing.ID)
+	}
+	if id, ok := m.IsNumberActive(ctx, "+15557654321"); ok {
+		t.Errorf("IsNumberActive(other number) = %q, true", id)
+	}
+}
+
+func TestIsNumberActive_Conference(t *testing.T) {
+	m := newTestManager(t)
+	ctx := context.Background()
+	state := m.addCall(ctx, &fakeCall{id: "CA-1", status: omnivoice.StatusAnswered}, m.config.PhoneNumber, time.Now())
+	m.conferences[state.ID] = &ConferenceState{
+		Name: "agentcomms-call-1",
+		Call: state,
+		participants: []Participant{
+			{Number: "+15552223333", CallSID: "CA-alice", Status: omnivoice.StatusAnswered},
+			{Number: "+15554445555", Status: omnivoice.StatusFailed},
+		},
+	}
+
+	if id, ok := m.IsNumberActive(ctx, "+15552223333"); !ok || id != state.ID {
+		t.Errorf("IsNumberActive(participant) = %q, %v; want %q, true", id, ok, state.ID)
+	}
+	if id, ok := m.IsNumberActive(ctx, "+15554445555"); ok {
+		t.Errorf("IsNumberActive(failed participant) = %q, true", id)
+	}
+}
+
+func TestCallElapsed(t *testing.T) {
+	m := newTestManager(t)
+	m.config.CallCostPerMinuteUSD = 0.05
+	m.calls["call-1"] = &CallState{ID: "call-1", StartTime: time.Now().Add(-90 * time.Second)}
+
+	d, cost := m.CallElapsed("call-1")
+	if d < 90*time.Second || d > 100*time.Second {
+		t.Errorf("duration = %v, want about 90s", d)
+	}
+	if cost != 0.1 {
+		t.Errorf("cost = %v, want 0.1 for two started minutes", cost)
+	}
+	if d, cost := m.CallElapsed("unknown"); d != 0 || cost != 0 {
+		t.Errorf("CallElapsed(unknown) = %v, %v, want zeros", d, cost)
+	}
+}
+
+func FuzzStatusHandler(f *testing.F) {
+	f.Add("CallSid=CA123&CallStatus=completed")
+	f.Add("CallSid=CA123&CallStatus=in-progress&AnsweredBy=machine_end_beep")
+	f.Add("CallSid=CA123%0D%0AINFO+forged&CallStatus=ringing%0A")
+	f.Add("CallSid=CA%E2%80%A8123&CallStatus=%1B%5B2K&AnsweredBy=human")
+	f.Add("CallSid=%FF%FE&CallStatus=busy")
+	f.Add("CallSid=%zz&CallStatus=")
+	f.Add("CallSid=" + strings.Repeat("A", 4096))
+
+	m := newTestManager(f)
+	handler := m.StatusHandler()
+
+	var logs bytes.Buffer
+	prev := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&logs, nil)))
+	f.Cleanup(func() { slog.SetDefault(prev) })
+
+	f.Fuzz(func(t *testing.T, body string) {
+		logs.Reset()
+		req := httptest.NewRequest(http.MethodPost, StatusPath, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		rec := httptest.NewRecorder()
+
+		handler.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusOK && rec.Code != http.StatusBadRequest {
+			t.Fatalf("status = %d, want 200 or 400", rec.Code)
+		}
+		dec := json.NewDecoder(&logs)
+		for dec.More() {
+			var entry map[string]any
+			if err := dec.Decode(&entry); err != nil {
+				t.Fatalf("log entry is not JSON: %v", err)
+			}
+			for _, key := range []string{"call_sid", "status"} {
+				if v, ok := entry[key].(string); ok && unsafeForLog(v) {
+					t.Errorf("logged %s = %q, want it sanitized", key, v)
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,214 @@
+package voice
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+)
+
+// CallStore persists call data that must survive a restart.
+type CallStore interface {
+	// SaveSchedule adds or replaces a scheduled call.
+	SaveSchedule(s ScheduledCall) error
+
+	// DeleteSchedule removes a scheduled call. Unknown IDs are ignored.
+	DeleteSchedule(id string) error
+
+	// Schedules returns all scheduled calls.
+	Schedules() ([]ScheduledCall, error)
+
+	// SaveUsage replaces the calls counted against the daily budget.
+	SaveUsage(usage []CallUsage) error
+
+	// Usage returns the calls counted against the daily budget.
+	Usage() ([]CallUsage, error)
+
+	// SaveCall adds a completed call to the call history.
+	SaveCall(r CallRecord) error
+
+	// List returns the completed calls matching q, newest first.
+	List(q CallQuery) ([]CallRecord, error)
+}
+
+// maxCallRecords bounds the call history kept by FileCallStore; the oldest
+// calls are dropped first.
+const maxCallRecords = 1000
+
+// FileCallStore is a CallStore backed by a single JSON file.
+type FileCallStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// storeData is the on-disk layout of a FileCallStore.
+type storeData struct {
+	Schedules []ScheduledCall `json:"schedules"`
+	Usage     []CallUsage     `json:"usage,omitempty"`
+	Calls     []CallRecord    `json:"calls,omitempty"`
+}
+
+// NewFileCallStore creates a store that reads and writes the JSON file at
+// path. The file and its directory are created on first write.
+func NewFileCallStore(path string) *FileCallStore {
+	return &FileCallStore{path: path}
+}
+
+// SaveSchedule adds or replaces a scheduled call.
+func (s *FileCallStore) SaveSchedule(sc ScheduledCall) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	replaced := false
+	for i := range data.Schedules {
+		if data.Schedules[i].ID == sc.ID {
+			data.Schedules[i] = sc
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		data.Schedules = append(data.Schedules, sc)
+	}
+
+	return s.save(data)
+}
+
+// DeleteSchedule removes a scheduled call. Unknown IDs are ignored.
+func (s *FileCallStore) DeleteSchedule(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	kept := data.Schedules[:0]
+	for _, sc := range data.Schedules {
+		if sc.ID != id {
+			kept = append(kept, sc)
+		}
+	}
+	data.Schedules = kept
+
+	return s.save(data)
+}
+
+// Schedules returns all scheduled calls.
+func (s *FileCallStore) Schedules() ([]ScheduledCall, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	return data.Schedules, nil
+}
+
+// SaveUsage replaces the calls counted against the daily budget.
+func (s *FileCallStore) SaveUsage(usage []CallUsage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := s.load()
+	if err != nil {
+		return err
+	}
+	data.Usage = usage
+
+	return s.save(data)
+}
+
+// Usage returns the calls counted against the daily budget.
+func (s *FileCallStore) Usage() ([]CallUsage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	return data.Usage, nil
+}
+
+// SaveCall adds a completed call to the call history, dropping the oldest
+// calls beyond maxCallRecords.
+func (s *FileCallStore) SaveCall(r CallRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := s.load()
+	if err != nil {
+		return err
+	}
+	data.Calls = append(data.Calls, r)
+	if len(data.Calls) > maxCallRecords {
+		slices.SortStableFunc(data.Calls, func(a, b CallRecord) int {
+			return a.EndedAt.Compare(b.EndedAt)
+		})
+		data.Calls = data.Calls[len(data.Calls)-maxCallRecords:]
+	}
+
+	return s.save(data)
+}
+
+// List returns the completed calls matching q, newest first.
+func (s *FileCallStore) List(q CallQuery) ([]CallRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	return q.filter(data.Calls), nil
+}
+
+// load reads the store file. A missing file is an empty store.
+func (s *FileCallStore) load() (*storeData, error) {
+	raw, err := os.ReadFile(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &storeData{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read call store: %w", err)
+	}
+
+	var data storeData
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return nil, fmt.Errorf("failed to parse call store %s: %w", s.path, err)
+	}
+	return &data, nil
+}
+
+// save writes the store file atomically.
+func (s *FileCallStore) save(data *storeData) error {
+	raw, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode call store: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return fmt.Errorf("failed to create call store directory: %w", err)
+	}
+
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, raw, 0600); err != nil {
+		return fmt.Errorf("failed to write call store: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("failed to write call store: %w", err)
+	}
+	return nil
+}
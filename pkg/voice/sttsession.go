@@ -0,0 +1,178 @@
+package voice
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/plexusone/omnivoice"
+)
+
+// sttSession is a transcription stream kept open across the turns of a
+// call when STTPersistConnection is set, so each turn skips connection
+// setup. Caller audio is pumped continuously; outside a turn it is replaced
+// by silence so the provider neither times out nor transcribes stale speech.
+type sttSession struct {
+	events <-chan omnivoice.StreamEvent
+	cancel context.CancelFunc
+
+	// listenFrom is the time (UnixNano) from which caller audio is
+	// forwarded, or 0 between turns.
+	listenFrom atomic.Int64
+
+	// dropIdle drops caller audio between turns instead of replacing it
+	// with silence, for compressed codecs that cannot be padded.
+	dropIdle bool
+
+	// language is the STT language the stream was opened with.
+	language string
+}
+
+// transcriptionConfig returns the streaming STT settings for the call's
+// next reply.
+func (m *Manager) transcriptionConfig(state *CallState) omnivoice.TranscriptionConfig {
+	return omnivoice.TranscriptionConfig{
+		Language:          m.sttLanguage(state),
+		Model:             m.config.STTModel,
+		Encoding:          m.codec.sttEncoding,
+		SampleRate:        m.codec.sampleRate,
+		Channels:          1,
+		EnablePunctuation: true,
+		Keywords:          m.keywords(state),
+	}
+}
+
+// transcribeStream opens a transcription stream and records how long setup
+// took in the call's metrics; this is the per-turn cost STTPersistConnection
+// avoids. The stream keeps the STT provider it was opened with, even if
+// voice is re-initialized while it runs.
+func (m *Manager) transcribeStream(ctx context.Context, state *CallState) (io.WriteCloser, <-chan omnivoice.StreamEvent, error) {
+	sttProvider := m.currentSTTProvider()
+	if sttProvider == nil {
+		return nil, nil, m.notInitialized()
+	}
+	start := time.Now()
+	writer, events, err := sttProvider.TranscribeStream(ctx, m.transcriptionConfig(state))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to start transcription: %w", err)
+	}
+	setup := time.Since(start)
+	state.markSTTSetup(setup)
+	slog.Debug("transcription stream opened", "call_id", state.ID, "setup", setup)
+	return writer, events, nil
+}
+
+// sttSession returns the call's open transcription session, starting a new
+// one if there is none, the previous one was closed by the provider, or the
+// call's language has changed since it was opened.
+func (m *Manager) sttSession(state *CallState, transport omnivoice.Transport) (*sttSession, error) {
+	state.mu.RLock()
+	s := state.stt
+	state.mu.RUnlock()
+
+	if s != nil {
+		if s.drain() && s.language == m.sttLanguage(state) {
+			return s, nil
+		}
+		state.closeSTTSession()
+	}
+
+	// The stream outlives the tool call that opened it, so it is tied to
+	// the call rather than the request context
+	ctx, cancel := context.WithCancel(context.Background())
+	language := m.sttLanguage(state)
+	writer, events, err := m.transcribeStream(ctx, state)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	s = &sttSession{
+		events: events,
+		cancel: func() {
+			cancel()
+			_ = writer.Close()
+		},
+		dropIdle: !m.codec.sampled,
+		language: language,
+	}
+	go s.pump(ctx, state.teeUserAudio(transport.AudioOut()), writer, state)
+
+	state.mu.Lock()
+	state.stt = s
+	state.mu.Unlock()
+	return s, nil
+}
+
+// drain discards events left over from the previous turn so the next turn
+// starts at a clean utterance boundary. It returns false if the provider
+// has closed the stream.
+func (s *sttSession) drain() bool {
+	for {
+		select {
+		case _, ok := <-s.events:
+			if !ok {
+				return false
+			}
+		default:
+			return true
+		}
+	}
+}
+
+// startTurn forwards caller audio from ignoreUntil until endTurn.
+func (s *sttSession) startTurn(ignoreUntil time.Time) {
+	s.listenFrom.Store(ignoreUntil.UnixNano())
+}
+
+// endTurn replaces caller audio with silence until the next turn.
+func (s *sttSession) endTurn() {
+	s.listenFrom.Store(0)
+}
+
+// pump copies caller audio from r to the STT writer w until ctx is done or
+// r is exhausted. Outside a turn, the audio is replaced by silence of the
+// same length so the stream stays in real time, or dropped with dropIdle.
+// Only audio forwarded in a
+// turn counts toward the call's AudioBytesIn.
+func (s *sttSession) pump(ctx context.Context, r io.Reader, w io.Writer, state *CallState) {
+	buf := make([]byte, 1024)
+	silence := bytes.Repeat([]byte{ulawSilence}, len(buf))
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+			n, err := r.Read(buf)
+			if err != nil {
+				return
+			}
+			if n == 0 {
+				continue
+			}
+			from := s.listenFrom.Load()
+			if from != 0 && time.Now().UnixNano() >= from {
+				state.metrics.audioBytesIn.Add(int64(n))
+				_, _ = w.Write(buf[:n])
+			} else if !s.dropIdle {
+				_, _ = w.Write(silence[:n])
+			}
+		}
+	}
+}
+
+// closeSTTSession closes the call's persistent transcription stream, if any.
+func (cs *CallState) closeSTTSession() {
+	cs.mu.Lock()
+	s := cs.stt
+	cs.stt = nil
+	cs.mu.Unlock()
+
+	if s != nil {
+		s.cancel()
+	}
+}
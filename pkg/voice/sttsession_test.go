@@ -0,0 +1,147 @@
+package voice
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/plexusone/omnivoice"
+)
+
+// fakeSTT opens a new event stream for each TranscribeStream call.
+type fakeSTT struct {
+	omnivoice.STTStreamingProvider
+
+	mu      sync.Mutex
+	streams []chan omnivoice.StreamEvent
+	writers []*fakeSTTWriter
+	configs []omnivoice.TranscriptionConfig
+}
+
+func (p *fakeSTT) TranscribeStream(_ context.Context, cfg omnivoice.TranscriptionConfig) (io.WriteCloser, <-chan omnivoice.StreamEvent, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	events := make(chan omnivoice.StreamEvent, 4)
+	w := &fakeSTTWriter{}
+	p.streams = append(p.streams, events)
+	p.writers = append(p.writers, w)
+	p.configs = append(p.configs, cfg)
+	return w, events, nil
+}
+
+type fakeSTTWriter struct {
+	mu     sync.Mutex
+	closed bool
+}
+
+func (w *fakeSTTWriter) Write(p []byte) (int, error) { return len(p), nil }
+
+func (w *fakeSTTWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.closed = true
+	return nil
+}
+
+// audioSource is a transport whose caller audio is read from r.
+type audioSource struct {
+	omnivoice.Transport
+
+	r io.Reader
+}
+
+func (a *audioSource) AudioOut() io.Reader {
+	return a.r
+}
+
+func TestSTTSession_ReusedAcrossTurns(t *testing.T) {
+	m := newTestManager(t)
+	stt := &fakeSTT{}
+	m.sttProvider = stt
+	transport := &audioSource{r: strings.NewReader("")}
+	state := &CallState{ID: "call-1", Call: &fakeCall{transport: transport}}
+
+	first, err := m.sttSession(state, transport)
+	if err != nil {
+		t.Fatalf("sttSession() error = %v", err)
+	}
+
+	// A stale event from the previous turn is discarded, not reconnected
+	stt.streams[0] <- omnivoice.StreamEvent{Transcript: "stale", IsFinal: true}
+	second, err := m.sttSession(state, transport)
+	if err != nil {
+		t.Fatalf("sttSession() error = %v", err)
+	}
+	if second != first || len(stt.streams) != 1 {
+		t.Fatalf("opened %d streams, want the first one reused", len(stt.streams))
+	}
+	if len(first.events) != 0 {
+		t.Error("stale event was not drained")
+	}
+
+	// A stream closed by the provider is replaced
+	close(stt.streams[0])
+	third, err := m.sttSession(state, transport)
+	if err != nil {
+		t.Fatalf("sttSession() error = %v", err)
+	}
+	if third == first || len(stt.streams) != 2 {
+		t.Fatalf("opened %d streams, want a reconnect", len(stt.streams))
+	}
+
+	state.closeSTTSession()
+	if !stt.writers[1].closed {
+		t.Error("closeSTTSession() did not close the stream")
+	}
+}
+
+// recordingWriter captures everything written to it.
+type recordingWriter struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (w *recordingWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.Write(p)
+}
+
+func TestSTTSession_PumpSilencesBetweenTurns(t *testing.T) {
+	audio := []byte("hello")
+
+	tests := []struct {
+		name      string
+		start     func(*sttSession)
+		want      []byte
+		wantBytes int64 // counted as audio in
+	}{
+		{"between turns", func(*sttSession) {}, bytes.Repeat([]byte{ulawSilence}, len(audio)), 0},
+		{"in a turn", func(s *sttSession) { s.startTurn(time.Now().Add(-time.Second)) }, audio, int64(len(audio))},
+		{"grace period", func(s *sttSession) { s.startTurn(time.Now().Add(time.Hour)) }, bytes.Repeat([]byte{ulawSilence}, len(audio)), 0},
+		{"between turns, compressed", func(s *sttSession) { s.dropIdle = true }, nil, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &sttSession{}
+			tt.start(s)
+
+			var w recordingWriter
+			state := &CallState{}
+			s.pump(context.Background(), bytes.NewReader(audio), &w, state)
+
+			if !bytes.Equal(w.buf.Bytes(), tt.want) {
+				t.Errorf("forwarded %q, want %q", w.buf.Bytes(), tt.want)
+			}
+			if got := state.Metrics().AudioBytesIn; got != tt.wantBytes {
+				t.Errorf("AudioBytesIn = %d, want %d", got, tt.wantBytes)
+			}
+		})
+	}
+}
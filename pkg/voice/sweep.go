@@ -0,0 +1,110 @@
+package voice
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/plexusone/omnivoice"
+)
+
+// sweepInterval is how often calls are checked for ones to reap. A call
+// whose status is terminal is reaped on the sweep after the one that first
+// saw it, so the code that placed or is using it has had at least this
+// long to clean it up itself.
+const sweepInterval = 30 * time.Second
+
+// startSweeper starts sweeping calls every sweepInterval until Close. It
+// only starts once, however often Initialize runs.
+func (m *Manager) startSweeper() {
+	m.sweeper.Do(func() {
+		go func() {
+			ticker := time.NewTicker(sweepInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-m.closed:
+					return
+				case now := <-ticker.C:
+					m.sweep(now)
+				}
+			}
+		}()
+	})
+}
+
+// sweep hangs up and removes calls that were left behind: ones older than
+// Config.MaxCallLifetimeMS, such as a call whose turn is stuck, and ones the
+// provider reports ended while nothing removed them. A dropped call kept
+// for ResumeCall is left to its own window.
+func (m *Manager) sweep(now time.Time) {
+	maxLifetime := time.Duration(m.config.MaxCallLifetimeMS) * time.Millisecond
+
+	m.callsMu.RLock()
+	calls := make([]*CallState, 0, len(m.calls))
+	for _, state := range m.calls {
+		calls = append(calls, state)
+	}
+	m.callsMu.RUnlock()
+
+	// Statuses may be polled from the provider, so not under callsMu
+	for _, state := range calls {
+		switch {
+		case state.Call == nil || state.dropped():
+		case maxLifetime > 0 && now.Sub(state.StartTime) > maxLifetime:
+			m.reapCall(state, "exceeded maximum lifetime")
+		case state.sweptTerminal(now):
+			m.reapCall(state, "provider reported it ended")
+		}
+	}
+}
+
+// sweptTerminal notes whether the call's status is terminal at this sweep
+// and reports whether it also was at an earlier one.
+func (cs *CallState) sweptTerminal(now time.Time) bool {
+	status := cs.Call.Status()
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	if !terminalStatus(status) && !terminalStatus(cs.status) {
+		cs.terminalAt = time.Time{}
+		return false
+	}
+	if cs.terminalAt.IsZero() {
+		cs.terminalAt = now
+		return false
+	}
+	return true
+}
+
+// terminalStatus reports whether a call with status is over.
+func terminalStatus(status omnivoice.CallStatus) bool {
+	switch status {
+	case omnivoice.StatusEnded, omnivoice.StatusFailed, omnivoice.StatusBusy, omnivoice.StatusNoAnswer:
+		return true
+	default:
+		return false
+	}
+}
+
+// reapCall hangs up and removes a call found by sweep. A call that was
+// answered is reported like one ended with EndCall.
+func (m *Manager) reapCall(state *CallState, reason string) {
+	if m.getCall(state.ID) != state {
+		return
+	}
+	slog.Warn("reaping abandoned call", "call_id", state.ID, "reason", reason, "age", time.Since(state.StartTime))
+
+	ctx, cancel := context.WithTimeout(context.Background(), m.closeTimeout)
+	defer cancel()
+	if err := state.Call.Hangup(ctx); err != nil {
+		slog.Warn("failed to hang up abandoned call", "call_id", state.ID, "error", err)
+	}
+	m.removeCall(state.ID)
+
+	state.mu.RLock()
+	answered := !state.metrics.answeredAt.IsZero()
+	state.mu.RUnlock()
+	if answered {
+		m.notifyCallEnded(state)
+	}
+}
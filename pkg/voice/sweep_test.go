@@ -0,0 +1,64 @@
+package voice
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/plexusone/omnivoice"
+)
+
+func TestSweep_MaxLifetime(t *testing.T) {
+	m := newTestManager(t)
+	m.config.MaxCallLifetimeMS = 60000
+	call := liveHangupCall{&fakeCall{id: "CA-1", status: omnivoice.StatusAnswered}, make(chan error, 1)}
+	stale := m.addCall(context.Background(), call, "+15551234567", time.Now())
+	fresh := m.addCall(context.Background(), hangupCall{&fakeCall{id: "CA-2", status: omnivoice.StatusAnswered}}, "+15551234567", time.Now())
+	fresh.StartTime = time.Now().Add(2 * time.Minute)
+
+	m.sweep(time.Now().Add(2 * time.Minute))
+	if m.getCall(stale.ID) != nil {
+		t.Error("call past its lifetime still registered")
+	}
+	select {
+	case err := <-call.hungUp:
+		if err != nil {
+			t.Errorf("hung up with a done context: %v", err)
+		}
+	default:
+		t.Error("call past its lifetime not hung up")
+	}
+	if m.getCall(fresh.ID) == nil {
+		t.Error("call within its lifetime reaped")
+	}
+}
+
+func TestSweep_TerminalStatus(t *testing.T) {
+	m := newTestManager(t)
+	call := &fakeCall{id: "CA-1", status: omnivoice.StatusAnswered}
+	state := m.addCall(context.Background(), hangupCall{call}, "+15551234567", time.Now())
+
+	m.sweep(time.Now())
+	call.setStatus(omnivoice.StatusEnded)
+	m.sweep(time.Now())
+	if m.getCall(state.ID) == nil {
+		t.Fatal("call reaped the first time its status was seen ended")
+	}
+	m.sweep(time.Now())
+	if m.getCall(state.ID) != nil {
+		t.Error("ended call still registered on the next sweep")
+	}
+}
+
+func TestSweep_KeepsDroppedCalls(t *testing.T) {
+	m := newTestManager(t)
+	m.config.ResumeWindowMS = 60000
+	state := droppedCall(t, m)
+
+	// fakeCall panics if hung up, so this also checks nothing is hung up
+	m.sweep(time.Now())
+	m.sweep(time.Now())
+	if m.getCall(state.ID) == nil {
+		t.Error("dropped call reaped within its resume window")
+	}
+}
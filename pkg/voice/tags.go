@@ -0,0 +1,43 @@
+package voice
+
+import (
+	"regexp"
+	"strings"
+)
+
+// audioTagPattern matches bracketed words such as [excited] or
+// [laughs harder], including surrounding spaces. Only those naming a known
+// audio tag are stripped.
+var audioTagPattern = regexp.MustCompile(`\s*\[([A-Za-z][A-Za-z' -]*)\]\s*`)
+
+// audioTags is the tag vocabulary the agent is told it may use. Other
+// bracketed text, such as [Note] or markdown link text, is left alone.
+var audioTags = map[string]bool{
+	// Emotion
+	"excited": true, "curious": true, "sarcastic": true, "calm": true,
+	"happy": true, "sad": true, "angry": true, "annoyed": true,
+	"nervous": true, "frustrated": true, "thoughtful": true,
+	"surprised": true, "cheerfully": true, "playfully": true,
+	"mischievously": true, "warmly": true, "resigned": true,
+	// Reactions
+	"laughs": true, "laughs harder": true, "starts laughing": true,
+	"chuckles": true, "giggles": true, "sighs": true, "exhales": true,
+	"gasps": true, "gulps": true, "groans": true, "snorts": true,
+	"clears throat": true, "crying": true,
+	// Delivery
+	"whispers": true, "shouts": true, "pauses": true, "short pause": true,
+	"long pause": true, "hesitates": true, "stammers": true,
+}
+
+// stripAudioTags removes inline audio tags for TTS models that would
+// otherwise read them aloud.
+func stripAudioTags(text string) string {
+	stripped := audioTagPattern.ReplaceAllStringFunc(text, func(match string) string {
+		tag := audioTagPattern.FindStringSubmatch(match)[1]
+		if !audioTags[strings.ToLower(strings.TrimSpace(tag))] {
+			return match
+		}
+		return " "
+	})
+	return strings.TrimSpace(stripped)
+}
@@ -0,0 +1,26 @@
+package voice
+
+import "testing"
+
+func TestStripAudioTags(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"No tags here.", "No tags here."},
+		{"[excited] We shipped it!", "We shipped it!"},
+		{"That's great [laughs harder] really.", "That's great really."},
+		{"Done.[sighs]", "Done."},
+		{"Step [1] is next.", "Step [1] is next."},
+		{"[Note] The build is red.", "[Note] The build is red."},
+		{"See [PR 12] for details.", "See [PR 12] for details."},
+		{"Read [the docs](https://example.com) first.", "Read [the docs](https://example.com) first."},
+		{"[Whispers] It's done.", "It's done."},
+	}
+
+	for _, tt := range tests {
+		if got := stripAudioTags(tt.input); got != tt.want {
+			t.Errorf("stripAudioTags(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
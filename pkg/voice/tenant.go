@@ -0,0 +1,55 @@
+package voice
+
+import (
+	"context"
+
+	"github.com/plexusone/agentcomms/internal/tenant"
+	"github.com/plexusone/agentcomms/pkg/config"
+)
+
+// tenantID returns the tenant a request is made for, or "" for the base
+// config.
+func tenantID(ctx context.Context) string {
+	if id := tenant.FromContext(ctx); id != tenant.DefaultTenantID {
+		return id
+	}
+	return ""
+}
+
+// MultiTenant reports whether the config defines tenants, in which case
+// every request must carry a tenant's API key.
+func (m *Manager) MultiTenant() bool {
+	return m.tenants != nil
+}
+
+// Authenticate returns ctx for the tenant with the given API key, so calls
+// placed with it use the tenant's config.
+func (m *Manager) Authenticate(ctx context.Context, apiKey string) (context.Context, error) {
+	if m.tenants == nil {
+		return ctx, nil
+	}
+	id, ok := m.tenants.Authenticate(apiKey)
+	if !ok {
+		return ctx, ErrUnauthorized
+	}
+	return tenant.WithTenantID(ctx, id), nil
+}
+
+// configFor returns the config for calls placed for ctx's tenant: the base
+// config with the tenant's numbers and voice.
+func (m *Manager) configFor(ctx context.Context) *config.Config {
+	if cfg := m.tenants.Config(tenantID(ctx)); cfg != nil {
+		return cfg
+	}
+	return m.config
+}
+
+// tenantCall returns the active call with the given ID if it was placed for
+// ctx's tenant, so tenants cannot reach each other's calls.
+func (m *Manager) tenantCall(ctx context.Context, callID string) *CallState {
+	state := m.getCall(callID)
+	if state == nil || state.tenant != tenantID(ctx) {
+		return nil
+	}
+	return state
+}
@@ -0,0 +1,109 @@
+package voice
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/plexusone/omnivoice"
+
+	"github.com/plexusone/agentcomms/pkg/config"
+)
+
+func newTenantManager(t *testing.T) *Manager {
+	t.Helper()
+
+	cfg := config.DefaultConfig()
+	cfg.PhoneNumber = "+15558888888"
+	cfg.UserPhoneNumber = "+15559999999"
+	cfg.Tenants = []config.Tenant{
+		{ID: "acme", APIKey: "key-acme", UserPhoneNumber: "+15550001111", PhoneNumber: "+15550004444", Voice: "voice-acme"},
+		{ID: "globex", APIKey: "key-globex", UserPhoneNumber: "+15550002222"},
+	}
+	m, err := New(cfg)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	return m
+}
+
+func TestAuthenticate(t *testing.T) {
+	m := newTenantManager(t)
+	if !m.MultiTenant() {
+		t.Fatal("MultiTenant() = false, want true")
+	}
+
+	ctx, err := m.Authenticate(context.Background(), "key-acme")
+	if err != nil {
+		t.Fatalf("Authenticate() error = %v", err)
+	}
+	if got := m.configFor(ctx).UserPhoneNumber; got != "+15550001111" {
+		t.Errorf("configFor() user number = %q, want the tenant's", got)
+	}
+	if _, err := m.Authenticate(context.Background(), "key-unknown"); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("Authenticate(unknown) error = %v, want ErrUnauthorized", err)
+	}
+
+	single := newTestManager(t)
+	if single.MultiTenant() {
+		t.Error("MultiTenant() without tenants = true")
+	}
+	if _, err := single.Authenticate(context.Background(), ""); err != nil {
+		t.Errorf("Authenticate() without tenants error = %v", err)
+	}
+}
+
+func TestTenantCallIsolation(t *testing.T) {
+	m := newTenantManager(t)
+	acme, _ := m.Authenticate(context.Background(), "key-acme")
+	globex, _ := m.Authenticate(context.Background(), "key-globex")
+
+	state := m.addCall(acme, &fakeCall{id: "CA-1"}, "+15550001111", time.Now())
+	if state.voiceID != "voice-acme" {
+		t.Errorf("voiceID = %q, want the tenant's voice", state.voiceID)
+	}
+
+	if _, err := m.CallStatus(acme, state.ID); err != nil {
+		t.Errorf("CallStatus() for the owning tenant error = %v", err)
+	}
+	for name, ctx := range map[string]context.Context{"other tenant": globex, "base": context.Background()} {
+		if _, err := m.CallStatus(ctx, state.ID); !errors.Is(err, ErrCallNotFound) {
+			t.Errorf("CallStatus() for %s error = %v, want ErrCallNotFound", name, err)
+		}
+	}
+}
+
+// fromCallSystem records the number each call is placed from.
+type fromCallSystem struct {
+	omnivoice.CallSystem
+
+	from []string
+}
+
+func (f *fromCallSystem) MakeCall(_ context.Context, to string, opts ...omnivoice.CallOption) (omnivoice.Call, error) {
+	var o omnivoice.CallOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	f.from = append(f.from, o.From)
+	return &fakeCall{id: "CA-" + to}, nil
+}
+
+func TestDial_TenantPhoneNumber(t *testing.T) {
+	m := newTenantManager(t)
+	cs := &fromCallSystem{}
+	m.callSystem = cs
+	acme, _ := m.Authenticate(context.Background(), "key-acme")
+	globex, _ := m.Authenticate(context.Background(), "key-globex")
+
+	for _, ctx := range []context.Context{acme, globex} {
+		if _, err := m.dial(ctx, m.configFor(ctx).UserPhoneNumber); err != nil {
+			t.Fatalf("dial() error = %v", err)
+		}
+	}
+	// Without its own number a tenant calls from the provider's default
+	if len(cs.from) != 2 || cs.from[0] != "+15550004444" || cs.from[1] != "" {
+		t.Errorf("calls placed from %q, want the tenant's number, then the default", cs.from)
+	}
+}
@@ -0,0 +1,66 @@
+package voice
+
+import (
+	"log/slog"
+	"math"
+	"time"
+)
+
+const (
+	// connectToneAmplitude is the tone's peak 16-bit amplitude, about
+	// -12 dBFS: clearly audible without being startling.
+	connectToneAmplitude = 8000
+
+	// connectToneRamp fades the tone in and out so it starts and stops
+	// without a click.
+	connectToneRamp = 10 * time.Millisecond
+)
+
+// playConnectTone plays Config.ConnectTone on a call that was just
+// answered. It is generated as mu-law, so it is skipped with compressed
+// codecs. A failure is logged and does not fail the call.
+func (m *Manager) playConnectTone(state *CallState) {
+	if !m.config.ConnectTone {
+		return
+	}
+	if !m.codec.sampled {
+		slog.Warn("connect tone is not supported with this codec; skipping it", "call_id", state.ID, "codec", m.codec.name)
+		return
+	}
+	transport := state.Call.Transport()
+	if transport == nil {
+		return
+	}
+
+	tone := ulawTone(m.config.ConnectToneHz, time.Duration(m.config.ConnectToneMS)*time.Millisecond, m.codec.sampleRate)
+
+	state.audioMu.Lock()
+	defer state.audioMu.Unlock()
+
+	// Count the tone as playback so echo handling covers it, but not as
+	// speech in the audio metrics
+	state.setSpeaking(true)
+	start := time.Now()
+	if _, err := state.teeAssistantAudio(transport.AudioIn()).Write(tone); err != nil {
+		slog.Warn("failed to play connect tone", "call_id", state.ID, "error", err)
+	}
+	state.finishPlayback(start.Add(m.codec.playbackDuration(len(tone))))
+}
+
+// ulawTone returns a sine tone of hz lasting d as mu-law samples at
+// sampleRate, faded in and out over connectToneRamp.
+func ulawTone(hz int, d time.Duration, sampleRate int) []byte {
+	n := int(d.Seconds() * float64(sampleRate))
+	ramp := min(int(connectToneRamp.Seconds()*float64(sampleRate)), n/2)
+
+	tone := make([]byte, n)
+	for i := range tone {
+		gain := 1.0
+		if ramp > 0 {
+			gain = min(float64(i)/float64(ramp), float64(n-1-i)/float64(ramp), 1)
+		}
+		sample := gain * connectToneAmplitude * math.Sin(2*math.Pi*float64(hz)*float64(i)/float64(sampleRate))
+		tone[i] = linearToULaw(int(sample))
+	}
+	return tone
+}
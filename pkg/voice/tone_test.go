@@ -0,0 +1,61 @@
+package voice
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestULawTone(t *testing.T) {
+	tone := ulawTone(1000, 200*time.Millisecond, 8000)
+	if len(tone) != 1600 {
+		t.Fatalf("len = %d, want 1600 samples", len(tone))
+	}
+
+	// Faded in from silence, at full level in the middle
+	if first := ulawToLinear(tone[0]); first != 0 {
+		t.Errorf("first sample = %d, want silence", first)
+	}
+	peak := 0
+	for _, b := range tone[400:1200] {
+		peak = max(peak, ulawToLinear(b))
+	}
+	if peak < connectToneAmplitude*9/10 || peak > connectToneAmplitude*11/10 {
+		t.Errorf("peak = %d, want about %d", peak, connectToneAmplitude)
+	}
+	for _, b := range tone[len(tone)-2:] {
+		if s := ulawToLinear(b); s > 500 || s < -500 {
+			t.Errorf("end sample = %d, want faded out", s)
+		}
+	}
+}
+
+func TestPlayConnectTone(t *testing.T) {
+	m := newTestManager(t)
+	conn := &fakeConn{}
+	state := m.addCall(context.Background(), &fakeCall{transport: conn}, "+15551234567", time.Now())
+
+	m.playConnectTone(state)
+	if conn.audio.Len() != 0 {
+		t.Fatal("tone played with ConnectTone off")
+	}
+
+	m.config.ConnectTone = true
+	m.playConnectTone(state)
+	if got, want := conn.audio.Len(), 8000*m.config.ConnectToneMS/1000; got != want {
+		t.Errorf("played %d bytes, want %d", got, want)
+	}
+	if state.playbackEnd().IsZero() {
+		t.Error("tone not counted as playback")
+	}
+	if metrics := state.Metrics(); metrics.AudioBytesOut != 0 {
+		t.Errorf("AudioBytesOut = %d, want the tone not counted as speech", metrics.AudioBytesOut)
+	}
+
+	conn.audio.Reset()
+	m.codec = codecOpus
+	m.playConnectTone(state)
+	if conn.audio.Len() != 0 {
+		t.Error("tone played with a compressed codec")
+	}
+}
@@ -0,0 +1,262 @@
+package voice
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// transcriptBufferSize is how many turns may be queued for a slow sink
+	// before new turns are dropped.
+	transcriptBufferSize = 256
+
+	// transcriptMaxBytes is the size at which a transcript file is rotated
+	// to <path>.1, replacing any previous rotation.
+	transcriptMaxBytes = 10 << 20
+
+	// transcriptPostTimeout bounds each HTTP post to a transcript webhook.
+	transcriptPostTimeout = 5 * time.Second
+)
+
+// TranscriptEntry is one conversation turn as written to a transcript sink.
+type TranscriptEntry struct {
+	CallID    string    `json:"call_id"`
+	Role      string    `json:"role"`
+	Content   string    `json:"content"`
+	Timestamp time.Time `json:"timestamp"`
+	Sentiment Sentiment `json:"sentiment,omitempty"`
+
+	// Metadata is the call's metadata (see WithCallMetadata). It is only
+	// set on entries sent to a transcript sink.
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// TranscriptSink receives conversation turns as they happen. Send must not
+// block the call.
+type TranscriptSink interface {
+	Send(entry TranscriptEntry)
+	Close() error
+}
+
+// NewTranscriptSink returns a sink for target: an http(s) URL receives each
+// turn as a JSON POST, anything else is a file path that turns are appended
+// to as JSON lines.
+func NewTranscriptSink(target string) (TranscriptSink, error) {
+	if strings.HasPrefix(target, "http://") || strings.HasPrefix(target, "https://") {
+		client := &http.Client{Timeout: transcriptPostTimeout}
+		return newAsyncSink(func(e TranscriptEntry) error {
+			return postTranscriptEntry(client, target, e)
+		}, nil), nil
+	}
+
+	f, err := newTranscriptFile(target)
+	if err != nil {
+		return nil, err
+	}
+	return newAsyncSink(f.write, f.close), nil
+}
+
+// transcriptRoles renames the internal "assistant" and "user" roles in
+// exported transcripts, for systems that expect other names. Empty names
+// keep the internal ones.
+type transcriptRoles struct {
+	assistant, user string
+}
+
+// transcriptRoles returns the role names configured for exported
+// transcripts.
+func (m *Manager) transcriptRoles() transcriptRoles {
+	return transcriptRoles{assistant: m.config.TranscriptAssistantRole, user: m.config.TranscriptUserRole}
+}
+
+// name returns the exported name of role.
+func (r transcriptRoles) name(role string) string {
+	switch {
+	case role == "assistant" && r.assistant != "":
+		return r.assistant
+	case role == "user" && r.user != "":
+		return r.user
+	default:
+		return role
+	}
+}
+
+// renamed reports whether any role is exported under another name.
+func (r transcriptRoles) renamed() bool {
+	return r.name("assistant") != "assistant" || r.name("user") != "user"
+}
+
+// renamingSink sends entries to a sink with their roles renamed.
+type renamingSink struct {
+	TranscriptSink
+	roles transcriptRoles
+}
+
+func (s renamingSink) Send(entry TranscriptEntry) {
+	entry.Role = s.roles.name(entry.Role)
+	s.TranscriptSink.Send(entry)
+}
+
+// asyncSink queues entries and writes them from a single goroutine, so a
+// slow file system or webhook never stalls the call. Entries are dropped
+// when the queue is full.
+type asyncSink struct {
+	entries chan TranscriptEntry
+	done    chan struct{}
+	closeFn func() error
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+func newAsyncSink(write func(TranscriptEntry) error, closeFn func() error) *asyncSink {
+	s := &asyncSink{
+		entries: make(chan TranscriptEntry, transcriptBufferSize),
+		done:    make(chan struct{}),
+		closeFn: closeFn,
+	}
+	go func() {
+		defer close(s.done)
+		for e := range s.entries {
+			if err := write(e); err != nil {
+				slog.Warn("failed to write transcript entry", "call_id", e.CallID, "error", err)
+			}
+		}
+	}()
+	return s
+}
+
+// Send queues an entry without blocking.
+func (s *asyncSink) Send(entry TranscriptEntry) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return
+	}
+
+	select {
+	case s.entries <- entry:
+	default:
+		slog.Warn("transcript sink is falling behind; dropping entry", "call_id", entry.CallID)
+	}
+}
+
+// Close flushes queued entries and releases the sink.
+func (s *asyncSink) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	close(s.entries)
+	s.mu.Unlock()
+
+	<-s.done
+	if s.closeFn != nil {
+		return s.closeFn()
+	}
+	return nil
+}
+
+// postTranscriptEntry posts an entry to a webhook as JSON.
+func postTranscriptEntry(client *http.Client, url string, e TranscriptEntry) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("transcript webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+// transcriptFile appends JSON lines to a file, rotating it when it grows
+// past transcriptMaxBytes. It is only used from the sink goroutine.
+type transcriptFile struct {
+	path string
+	f    *os.File
+	size int64
+}
+
+func newTranscriptFile(path string) (*transcriptFile, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create transcript directory: %w", err)
+	}
+
+	t := &transcriptFile{path: path}
+	if err := t.open(); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (t *transcriptFile) open() error {
+	f, err := os.OpenFile(t.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open transcript file: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to open transcript file: %w", err)
+	}
+	t.f = f
+	t.size = info.Size()
+	return nil
+}
+
+func (t *transcriptFile) write(e TranscriptEntry) error {
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	if t.size > 0 && t.size+int64(len(line)) > transcriptMaxBytes {
+		if err := t.rotate(); err != nil {
+			return err
+		}
+	}
+
+	n, err := t.f.Write(line)
+	t.size += int64(n)
+	return err
+}
+
+// rotate moves the current file to <path>.1 and starts a new one.
+func (t *transcriptFile) rotate() error {
+	if err := t.f.Close(); err != nil {
+		return fmt.Errorf("failed to rotate transcript file: %w", err)
+	}
+	if err := os.Rename(t.path, t.path+".1"); err != nil {
+		// Keep appending to the current file rather than losing turns
+		slog.Warn("failed to rotate transcript file", "path", t.path, "error", err)
+	}
+	return t.open()
+}
+
+func (t *transcriptFile) close() error {
+	return t.f.Close()
+}
@@ -0,0 +1,163 @@
+package voice
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func readTranscript(t *testing.T, path string) []TranscriptEntry {
+	t.Helper()
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("failed to open transcript: %v", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	var entries []TranscriptEntry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e TranscriptEntry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			t.Fatalf("invalid transcript line %q: %v", scanner.Text(), err)
+		}
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+func TestTranscriptSink_File(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "captions", "calls.jsonl")
+	sink, err := NewTranscriptSink(path)
+	if err != nil {
+		t.Fatalf("NewTranscriptSink() error = %v", err)
+	}
+
+	state := &CallState{ID: "call-1", sink: sink}
+	state.AddTurn("assistant", "Hi, the build is done.")
+	state.AddTurn("user", "Great, thanks.")
+
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	entries := readTranscript(t, path)
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(entries))
+	}
+	if entries[0].CallID != "call-1" || entries[0].Role != "assistant" || entries[1].Content != "Great, thanks." {
+		t.Errorf("unexpected entries: %+v", entries)
+	}
+}
+
+func TestTranscriptSink_FileRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "calls.jsonl")
+	if err := os.WriteFile(path, make([]byte, transcriptMaxBytes-10), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	sink, err := NewTranscriptSink(path)
+	if err != nil {
+		t.Fatalf("NewTranscriptSink() error = %v", err)
+	}
+	sink.Send(TranscriptEntry{CallID: "call-1", Role: "user", Content: "hello"})
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	if info, err := os.Stat(path + ".1"); err != nil || info.Size() != transcriptMaxBytes-10 {
+		t.Errorf("rotated file missing or wrong size: %v", err)
+	}
+	if entries := readTranscript(t, path); len(entries) != 1 {
+		t.Errorf("got %d entries in new file, want 1", len(entries))
+	}
+}
+
+func TestTranscriptSink_HTTP(t *testing.T) {
+	received := make(chan TranscriptEntry, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var e TranscriptEntry
+		if err := json.NewDecoder(r.Body).Decode(&e); err != nil {
+			t.Errorf("invalid body: %v", err)
+		}
+		received <- e
+	}))
+	defer srv.Close()
+
+	sink, err := NewTranscriptSink(srv.URL)
+	if err != nil {
+		t.Fatalf("NewTranscriptSink() error = %v", err)
+	}
+	defer func() { _ = sink.Close() }()
+
+	sink.Send(TranscriptEntry{CallID: "call-1", Role: "user", Content: "hello"})
+
+	select {
+	case e := <-received:
+		if e.Content != "hello" {
+			t.Errorf("Content = %q, want %q", e.Content, "hello")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("webhook was not called")
+	}
+}
+
+func TestTranscriptSink_SlowSinkDoesNotBlock(t *testing.T) {
+	release := make(chan struct{})
+	sink := newAsyncSink(func(TranscriptEntry) error {
+		<-release
+		return nil
+	}, nil)
+
+	start := time.Now()
+	for i := 0; i < 2*transcriptBufferSize; i++ {
+		sink.Send(TranscriptEntry{CallID: "call-1"})
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Send blocked for %v", elapsed)
+	}
+
+	close(release)
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+}
+
+func TestTranscriptRoles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "calls.jsonl")
+	sink, err := NewTranscriptSink(path)
+	if err != nil {
+		t.Fatalf("NewTranscriptSink() error = %v", err)
+	}
+	roles := transcriptRoles{assistant: "agent", user: "customer"}
+	if !roles.renamed() || (transcriptRoles{assistant: "assistant"}).renamed() {
+		t.Error("renamed() does not match the configured names")
+	}
+
+	state := &CallState{ID: "call-1", StartTime: time.Now(), sink: renamingSink{TranscriptSink: sink, roles: roles}}
+	state.AddTurn("assistant", "Hi, the build is done.")
+	state.AddTurn("user", "Great, thanks.")
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	entries := readTranscript(t, path)
+	if len(entries) != 2 || entries[0].Role != "agent" || entries[1].Role != "customer" {
+		t.Errorf("entries = %+v, want the roles renamed", entries)
+	}
+	// Internal roles are unchanged
+	if state.Conversation[0].Role != "assistant" || state.LastUserMessage != "Great, thanks." {
+		t.Errorf("Conversation = %+v, want the internal roles", state.Conversation)
+	}
+
+	event := newCallEndedEvent(state, time.Now(), 0.03, roles)
+	if event.Transcript[0].Role != "agent" || event.Transcript[1].Role != "customer" {
+		t.Errorf("call-ended transcript = %+v, want the roles renamed", event.Transcript)
+	}
+}
@@ -0,0 +1,147 @@
+package voice
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+// TriggerCallPath is where external systems such as CI place calls with
+// TriggerCallHandler.
+const TriggerCallPath = "/trigger-call"
+
+// triggerCallRequest is the JSON body of a trigger-call request.
+type triggerCallRequest struct {
+	Message string `json:"message"`
+	To      string `json:"to,omitempty"` // default: the user's number
+}
+
+// triggerCallResponse is the JSON result of a trigger-call request.
+type triggerCallResponse struct {
+	CallID   string `json:"call_id,omitempty"`
+	Outcome  string `json:"outcome"`
+	Response string `json:"response,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// Trigger-call outcomes. Every outcome except "failed" means the call was
+// placed.
+const (
+	triggerAnswered    = "answered"
+	triggerNoSpeech    = "no_speech"
+	triggerNotAnswered = "not_answered"
+	triggerDeclined    = "declined"
+	triggerVoicemail   = "voicemail"
+	triggerFailed      = "failed"
+)
+
+// TriggerCallHandler serves TriggerCallPath. A POST with a bearer token
+// matching token and a JSON body {"message": ..., "to": ...} calls to (the
+// user if empty), speaks message, and responds with the call ID, outcome,
+// and the user's reply. Nobody continues the conversation, so the call is
+// hung up once the reply is in. An empty token rejects every request.
+func (m *Manager) TriggerCallHandler(token string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if !bearerTokenValid(r, token) {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		r.Body = http.MaxBytesReader(w, r.Body, 1<<20)
+		var req triggerCallRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeTriggerResponse(w, http.StatusBadRequest, triggerCallResponse{Outcome: triggerFailed, Error: "invalid JSON body"})
+			return
+		}
+		if strings.TrimSpace(req.Message) == "" {
+			writeTriggerResponse(w, http.StatusBadRequest, triggerCallResponse{Outcome: triggerFailed, Error: "message is required"})
+			return
+		}
+
+		// The call goes on if the client disconnects while waiting
+		ctx := context.WithoutCancel(r.Context())
+
+		var (
+			state    *CallState
+			response string
+			err      error
+		)
+		if req.To == "" {
+			state, response, err = m.InitiateCall(ctx, req.Message)
+		} else {
+			state, response, err = m.InitiateCallTo(ctx, req.To, req.Message)
+		}
+
+		resp := triggerCallResponse{Response: response}
+		if state != nil {
+			resp.CallID = state.ID
+			if m.getCall(state.ID) != nil {
+				if _, hangupErr := m.EndCall(ctx, state.ID, ""); hangupErr != nil {
+					slog.Warn("failed to end triggered call", "call_id", state.ID, "error", hangupErr)
+				}
+			}
+		}
+		var status int
+		resp.Outcome, status = triggerOutcome(err)
+		if err != nil {
+			resp.Error = err.Error()
+		}
+
+		slog.Info("triggered call", "call_id", resp.CallID, "outcome", resp.Outcome)
+		writeTriggerResponse(w, status, resp)
+	})
+}
+
+// triggerOutcome maps the result of placing a triggered call to its
+// outcome and HTTP status. Calls that were placed report 200 whether or not
+// the user answered.
+func triggerOutcome(err error) (string, int) {
+	switch {
+	case err == nil:
+		return triggerAnswered, http.StatusOK
+	case errors.Is(err, ErrNoSpeech):
+		return triggerNoSpeech, http.StatusOK
+	case errors.Is(err, ErrNotAnswered):
+		return triggerNotAnswered, http.StatusOK
+	case errors.Is(err, ErrDeclined):
+		return triggerDeclined, http.StatusOK
+	case errors.Is(err, ErrVoicemail):
+		return triggerVoicemail, http.StatusOK
+	case errors.Is(err, ErrNumberNotAllowed):
+		return triggerFailed, http.StatusForbidden
+	case errors.Is(err, ErrBudgetExceeded):
+		return triggerFailed, http.StatusTooManyRequests
+	case errors.Is(err, ErrNotInitialized), errors.Is(err, ErrQuietHours), errors.Is(err, ErrDoNotDisturb):
+		return triggerFailed, http.StatusServiceUnavailable
+	case errors.Is(err, ErrDialFailed), errors.Is(err, ErrSpeechFailed):
+		return triggerFailed, http.StatusBadGateway
+	default:
+		return triggerFailed, http.StatusInternalServerError
+	}
+}
+
+// bearerTokenValid reports whether r carries token as its bearer token.
+func bearerTokenValid(r *http.Request, token string) bool {
+	got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || token == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(got), []byte(token)) == 1
+}
+
+// writeTriggerResponse writes resp as JSON with the given status.
+func writeTriggerResponse(w http.ResponseWriter, status int, resp triggerCallResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,98 @@
+package voice
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestTriggerCallHandler(t *testing.T) {
+	tests := []struct {
+		name        string
+		method      string
+		auth        string
+		body        string
+		dial        bool // with a call system that fails to dial
+		wantStatus  int
+		wantOutcome string
+	}{
+		{"wrong method", http.MethodGet, "Bearer secret", "", false, http.StatusMethodNotAllowed, ""},
+		{"no token", http.MethodPost, "", `{"message":"Deploy done"}`, false, http.StatusUnauthorized, ""},
+		{"wrong token", http.MethodPost, "Bearer nope", `{"message":"Deploy done"}`, false, http.StatusUnauthorized, ""},
+		{"bad JSON", http.MethodPost, "Bearer secret", `{"message":`, false, http.StatusBadRequest, triggerFailed},
+		{"no message", http.MethodPost, "Bearer secret", `{"message":"  "}`, false, http.StatusBadRequest, triggerFailed},
+		{"number not allowed", http.MethodPost, "Bearer secret", `{"message":"Deploy done","to":"+15550009999"}`, true, http.StatusForbidden, triggerFailed},
+		{"not initialized", http.MethodPost, "Bearer secret", `{"message":"Deploy done"}`, false, http.StatusServiceUnavailable, triggerFailed},
+		{"dial failed", http.MethodPost, "Bearer secret", `{"message":"Deploy done","to":"+15550001111"}`, true, http.StatusBadGateway, triggerFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestManager(t)
+			m.config.UserPhoneNumber = "+15550001111"
+			if tt.dial {
+				m.callSystem = &errDialCallSystem{err: errors.New("provider down")}
+			}
+
+			req := httptest.NewRequest(tt.method, TriggerCallPath, strings.NewReader(tt.body))
+			if tt.auth != "" {
+				req.Header.Set("Authorization", tt.auth)
+			}
+			rec := httptest.NewRecorder()
+			m.TriggerCallHandler("secret").ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+			if tt.wantOutcome == "" {
+				return
+			}
+			var resp triggerCallResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("response %q is not JSON: %v", rec.Body, err)
+			}
+			if resp.Outcome != tt.wantOutcome || resp.Error == "" {
+				t.Errorf("response = %+v, want outcome %q with an error", resp, tt.wantOutcome)
+			}
+		})
+	}
+}
+
+func TestTriggerCallHandler_EmptyToken(t *testing.T) {
+	m := newTestManager(t)
+	req := httptest.NewRequest(http.MethodPost, TriggerCallPath, strings.NewReader(`{"message":"hi"}`))
+	req.Header.Set("Authorization", "Bearer ")
+	rec := httptest.NewRecorder()
+	m.TriggerCallHandler("").ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestTriggerOutcome(t *testing.T) {
+	tests := []struct {
+		err         error
+		wantOutcome string
+		wantStatus  int
+	}{
+		{nil, triggerAnswered, http.StatusOK},
+		{ErrNoSpeech, triggerNoSpeech, http.StatusOK},
+		{errors.Join(ErrNotAnswered, ErrSMSFallbackSent), triggerNotAnswered, http.StatusOK},
+		{ErrDeclined, triggerDeclined, http.StatusOK},
+		{ErrVoicemail, triggerVoicemail, http.StatusOK},
+		{ErrBudgetExceeded, triggerFailed, http.StatusTooManyRequests},
+		{ErrQuietHours, triggerFailed, http.StatusServiceUnavailable},
+		{errors.New("boom"), triggerFailed, http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		outcome, status := triggerOutcome(tt.err)
+		if outcome != tt.wantOutcome || status != tt.wantStatus {
+			t.Errorf("triggerOutcome(%v) = %q, %d, want %q, %d", tt.err, outcome, status, tt.wantOutcome, tt.wantStatus)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package voice
+
+import "io"
+
+// silenceTrimmer drops near-silent mu-law audio at the start and end of a
+// message. TTS providers sometimes pad speech with silence, which sounds
+// like the assistant hesitating. Leading silence is dropped as it arrives;
+// silence after speech is held back until more speech follows or the
+// message ends. pad bytes of silence are kept at each end so quiet onsets
+// and trailing sounds are not clipped.
+type silenceTrimmer struct {
+	w       io.Writer
+	silent  [256]bool
+	pad     int
+	speech  bool   // speech has been written
+	held    []byte // silence not yet written
+	dropped int
+}
+
+// newSilenceTrimmer returns a writer that trims silence before passing audio
+// to w. Samples with an amplitude up to threshold count as silent.
+func newSilenceTrimmer(w io.Writer, threshold, pad int) *silenceTrimmer {
+	t := &silenceTrimmer{w: w, pad: pad}
+	for i := range t.silent {
+		t.silent[i] = abs(ulawToLinear(byte(i))) <= threshold
+	}
+	return t
+}
+
+func (t *silenceTrimmer) Write(p []byte) (int, error) {
+	first, last := -1, -1
+	for i, b := range p {
+		if !t.silent[b] {
+			if first < 0 {
+				first = i
+			}
+			last = i
+		}
+	}
+
+	if first < 0 {
+		t.hold(p)
+		return len(p), nil
+	}
+
+	t.hold(p[:first])
+	if len(t.held) > 0 {
+		if _, err := t.w.Write(t.held); err != nil {
+			return 0, err
+		}
+	}
+	if _, err := t.w.Write(p[first : last+1]); err != nil {
+		return 0, err
+	}
+	t.speech = true
+	t.held = append(t.held[:0], p[last+1:]...)
+	return len(p), nil
+}
+
+// hold adds silence to the held audio. Before any speech only the last pad
+// bytes are kept.
+func (t *silenceTrimmer) hold(p []byte) {
+	t.held = append(t.held, p...)
+	if !t.speech && len(t.held) > t.pad {
+		t.dropped += len(t.held) - t.pad
+		t.held = append(t.held[:0], t.held[len(t.held)-t.pad:]...)
+	}
+}
+
+// finish ends the message, writing pad bytes of any trailing silence, and
+// returns how many bytes were dropped. The audio is best effort at this
+// point, so write errors are ignored.
+func (t *silenceTrimmer) finish() int {
+	keep := 0
+	if t.speech {
+		keep = min(len(t.held), t.pad)
+		if keep > 0 {
+			_, _ = t.w.Write(t.held[:keep])
+		}
+	}
+	t.dropped += len(t.held) - keep
+	t.held = t.held[:0]
+	return t.dropped
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,72 @@
+package voice
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSilenceTrimmer(t *testing.T) {
+	quiet := linearToULaw(0)
+	speech := linearToULaw(4000)
+	seq := func(b byte, n int) []byte { return bytes.Repeat([]byte{b}, n) }
+
+	tests := []struct {
+		name        string
+		writes      [][]byte
+		want        []byte
+		wantDropped int
+	}{
+		{
+			name:        "leading and trailing silence",
+			writes:      [][]byte{seq(quiet, 10), append(seq(speech, 3), seq(quiet, 10)...)},
+			want:        append(append(seq(quiet, 2), seq(speech, 3)...), seq(quiet, 2)...),
+			wantDropped: 16,
+		},
+		{
+			name:        "silence and speech in one write",
+			writes:      [][]byte{append(append(seq(quiet, 5), seq(speech, 2)...), seq(quiet, 1)...)},
+			want:        append(append(seq(quiet, 2), seq(speech, 2)...), quiet),
+			wantDropped: 3,
+		},
+		{
+			name:   "pauses between words are kept",
+			writes: [][]byte{seq(speech, 1), seq(quiet, 6), seq(speech, 1)},
+			want:   append(append(seq(speech, 1), seq(quiet, 6)...), speech),
+		},
+		{
+			name:        "only silence",
+			writes:      [][]byte{seq(quiet, 8)},
+			wantDropped: 8,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			trim := newSilenceTrimmer(&buf, 100, 2)
+			for _, p := range tt.writes {
+				if n, err := trim.Write(p); err != nil || n != len(p) {
+					t.Fatalf("Write() = %d, %v; want %d, nil", n, err, len(p))
+				}
+			}
+			if dropped := trim.finish(); dropped != tt.wantDropped {
+				t.Errorf("finish() = %d, want %d", dropped, tt.wantDropped)
+			}
+			if !bytes.Equal(buf.Bytes(), tt.want) {
+				t.Errorf("wrote %v, want %v", buf.Bytes(), tt.want)
+			}
+		})
+	}
+}
+
+func TestSilenceTrimmer_Threshold(t *testing.T) {
+	// A quiet onset above the threshold is speech and is not clipped
+	var buf bytes.Buffer
+	trim := newSilenceTrimmer(&buf, 100, 0)
+	onset := linearToULaw(300)
+	_, _ = trim.Write([]byte{linearToULaw(50), onset, linearToULaw(4000)})
+	trim.finish()
+	if got := buf.Bytes(); len(got) != 2 || got[0] != onset {
+		t.Errorf("wrote %v, want the onset and the speech", got)
+	}
+}
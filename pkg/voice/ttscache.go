@@ -0,0 +1,201 @@
+package voice
+
+import (
+	"container/list"
+	"context"
+	"io"
+	"sync"
+
+	"github.com/plexusone/omnivoice"
+)
+
+// ttsCacheKey identifies synthesized audio: the text as sent to the
+// provider and the settings that change how it sounds. Continuity context
+// is not part of the key, so a cached phrase may have been spoken after
+// different messages; for the short fixed phrases that repeat this makes
+// no audible difference.
+type ttsCacheKey struct {
+	text       string
+	voiceID    string
+	model      string
+	format     string
+	sampleRate int
+	speed      float64
+}
+
+func newTTSCacheKey(text string, cfg omnivoice.SynthesisConfig) ttsCacheKey {
+	return ttsCacheKey{
+		text:       text,
+		voiceID:    cfg.VoiceID,
+		model:      cfg.Model,
+		format:     cfg.OutputFormat,
+		sampleRate: cfg.SampleRate,
+		speed:      cfg.Speed,
+	}
+}
+
+// ttsAudio is synthesized audio in the chunks it arrived in, so it is
+// replayed the way a live stream would be written.
+type ttsAudio struct {
+	chunks [][]byte
+	size   int
+}
+
+// Write stores a copy of a chunk.
+func (a *ttsAudio) Write(b []byte) (int, error) {
+	a.chunks = append(a.chunks, append([]byte(nil), b...))
+	a.size += len(b)
+	return len(b), nil
+}
+
+// writeTo writes the audio to w and returns the number of bytes written.
+func (a *ttsAudio) writeTo(w io.Writer) (int, error) {
+	written := 0
+	for _, chunk := range a.chunks {
+		n, err := w.Write(chunk)
+		written += n
+		if err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
+
+type ttsCacheEntry struct {
+	key   ttsCacheKey
+	audio *ttsAudio
+}
+
+// ttsCache holds synthesized audio in memory, dropping the least recently
+// used once it holds more than maxBytes.
+type ttsCache struct {
+	maxBytes int
+
+	mu      sync.Mutex
+	size    int
+	order   *list.List // of *ttsCacheEntry, most recently used first
+	entries map[ttsCacheKey]*list.Element
+	hits    int
+	misses  int
+}
+
+func newTTSCache(maxBytes int) *ttsCache {
+	return &ttsCache{
+		maxBytes: maxBytes,
+		order:    list.New(),
+		entries:  make(map[ttsCacheKey]*list.Element),
+	}
+}
+
+// get returns the cached audio for key and counts the lookup.
+func (c *ttsCache) get(key ttsCacheKey) (*ttsAudio, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[key]
+	if !ok {
+		c.misses++
+		return nil, false
+	}
+	c.hits++
+	c.order.MoveToFront(e)
+	return e.Value.(*ttsCacheEntry).audio, true
+}
+
+// contains reports whether key is cached without counting a lookup.
+func (c *ttsCache) contains(key ttsCacheKey) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, ok := c.entries[key]
+	return ok
+}
+
+// put caches audio for key. Audio larger than the whole cache is not kept.
+func (c *ttsCache) put(key ttsCacheKey, audio *ttsAudio) {
+	if audio.size == 0 || audio.size > c.maxBytes {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.entries[key]; ok {
+		entry := e.Value.(*ttsCacheEntry)
+		c.size += audio.size - entry.audio.size
+		entry.audio = audio
+		c.order.MoveToFront(e)
+	} else {
+		c.entries[key] = c.order.PushFront(&ttsCacheEntry{key: key, audio: audio})
+		c.size += audio.size
+	}
+
+	for c.size > c.maxBytes {
+		oldest := c.order.Back()
+		entry := oldest.Value.(*ttsCacheEntry)
+		c.order.Remove(oldest)
+		delete(c.entries, entry.key)
+		c.size -= entry.audio.size
+	}
+}
+
+// TTSCacheStats report how well the TTS cache is working.
+type TTSCacheStats struct {
+	Hits    int // messages played from cached audio
+	Misses  int // messages synthesized because they were not cached
+	Entries int
+	Bytes   int
+}
+
+// HitRate returns the share of lookups served from the cache, 0 before
+// any lookup.
+func (s TTSCacheStats) HitRate() float64 {
+	if total := s.Hits + s.Misses; total > 0 {
+		return float64(s.Hits) / float64(total)
+	}
+	return 0
+}
+
+func (c *ttsCache) stats() TTSCacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return TTSCacheStats{Hits: c.hits, Misses: c.misses, Entries: len(c.entries), Bytes: c.size}
+}
+
+// TTSCacheStats returns the TTS cache counters since the manager was
+// created. It is zero unless Config.TTSCache is set.
+func (m *Manager) TTSCacheStats() TTSCacheStats {
+	if m.ttsCache == nil {
+		return TTSCacheStats{}
+	}
+	return m.ttsCache.stats()
+}
+
+// synthesizeCached writes text's audio to w from the TTS cache, or
+// synthesizes it and caches the audio if the whole message was
+// synthesized. Without a cache it just synthesizes, with ttsProvider.
+func (m *Manager) synthesizeCached(ctx context.Context, ttsProvider omnivoice.TTSProvider, state *CallState, w io.Writer, text string, cfg omnivoice.SynthesisConfig) (int, error) {
+	if m.ttsCache == nil {
+		if err := m.chargeTTS(state, text); err != nil {
+			return 0, err
+		}
+		return m.synthesizeTo(ctx, ttsProvider, w, text, cfg)
+	}
+
+	key := newTTSCacheKey(text, cfg)
+	if audio, ok := m.ttsCache.get(key); ok {
+		state.markTTSCache(true)
+		return audio.writeTo(w)
+	}
+	state.markTTSCache(false)
+	if err := m.chargeTTS(state, text); err != nil {
+		return 0, err
+	}
+
+	audio := &ttsAudio{}
+	written, err := m.synthesizeTo(ctx, ttsProvider, io.MultiWriter(w, audio), text, cfg)
+	// A cancelled stream may end without an error but cut short
+	if err == nil && ctx.Err() == nil {
+		m.ttsCache.put(key, audio)
+	}
+	return written, err
+}
@@ -0,0 +1,178 @@
+package voice
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/plexusone/omnivoice"
+)
+
+func cachedAudio(s string) *ttsAudio {
+	a := &ttsAudio{}
+	_, _ = a.Write([]byte(s))
+	return a
+}
+
+func TestTTSCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	c := newTTSCache(10)
+	a, b, d := ttsCacheKey{text: "a"}, ttsCacheKey{text: "b"}, ttsCacheKey{text: "d"}
+
+	c.put(a, cachedAudio("aaaa"))
+	c.put(b, cachedAudio("bbbb"))
+	c.get(a) // a is now the most recently used
+	c.put(d, cachedAudio("dddd"))
+
+	if !c.contains(a) || !c.contains(d) {
+		t.Error("recently used entries evicted")
+	}
+	if c.contains(b) {
+		t.Error("least recently used entry kept")
+	}
+	if got := c.stats().Bytes; got != 8 {
+		t.Errorf("Bytes = %d, want 8", got)
+	}
+}
+
+func TestTTSCache_SkipsOversizedAndEmpty(t *testing.T) {
+	c := newTTSCache(4)
+	c.put(ttsCacheKey{text: "big"}, cachedAudio("too long"))
+	c.put(ttsCacheKey{text: "empty"}, &ttsAudio{})
+
+	if got := c.stats().Entries; got != 0 {
+		t.Errorf("Entries = %d, want 0", got)
+	}
+}
+
+func TestTTSCache_ReplaceKeepsSize(t *testing.T) {
+	c := newTTSCache(10)
+	key := ttsCacheKey{text: "a"}
+	c.put(key, cachedAudio("aaaa"))
+	c.put(key, cachedAudio("aa"))
+
+	if got := c.stats(); got.Entries != 1 || got.Bytes != 2 {
+		t.Errorf("stats = %+v, want 1 entry of 2 bytes", got)
+	}
+}
+
+func TestTTSCacheStats_HitRate(t *testing.T) {
+	tests := []struct {
+		stats TTSCacheStats
+		want  float64
+	}{
+		{TTSCacheStats{}, 0},
+		{TTSCacheStats{Hits: 3, Misses: 1}, 0.75},
+		{TTSCacheStats{Misses: 2}, 0},
+	}
+
+	for _, tt := range tests {
+		if got := tt.stats.HitRate(); got != tt.want {
+			t.Errorf("%+v.HitRate() = %g, want %g", tt.stats, got, tt.want)
+		}
+	}
+}
+
+func TestSpeak_TTSCache(t *testing.T) {
+	tests := []struct {
+		name       string
+		cache      bool
+		wantSynth  int
+		wantHits   int
+		wantMisses int
+	}{
+		{"enabled", true, 1, 1, 1},
+		{"disabled", false, 2, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestManager(t)
+			if tt.cache {
+				m.ttsCache = newTTSCache(1 << 10)
+			}
+			tts := &fakeTTS{streams: [][]omnivoice.StreamChunk{
+				{{Audio: []byte("are you ")}, {Audio: []byte("there?"), IsFinal: true}},
+				{{Audio: []byte("are you there?"), IsFinal: true}},
+			}}
+			m.ttsProvider = tts
+			conn := &fakeConn{}
+			state := &CallState{ID: "call-1", Call: &fakeCall{transport: conn}}
+
+			for range 2 {
+				if err := m.speak(context.Background(), state, "Are you still there?"); err != nil {
+					t.Fatalf("speak() error = %v", err)
+				}
+			}
+
+			if got := conn.audio.String(); got != "are you there?are you there?" {
+				t.Errorf("audio = %q", got)
+			}
+			if len(tts.texts) != tt.wantSynth {
+				t.Errorf("synthesized %d times, want %d", len(tts.texts), tt.wantSynth)
+			}
+			metrics := state.Metrics()
+			if metrics.TTSCacheHits != tt.wantHits || metrics.TTSCacheMisses != tt.wantMisses {
+				t.Errorf("cache hits, misses = %d, %d; want %d, %d", metrics.TTSCacheHits, metrics.TTSCacheMisses, tt.wantHits, tt.wantMisses)
+			}
+		})
+	}
+}
+
+func TestSpeak_TTSCacheKeyedByVoice(t *testing.T) {
+	m := newTestManager(t)
+	m.ttsCache = newTTSCache(1 << 10)
+	tts := &fakeTTS{streams: [][]omnivoice.StreamChunk{
+		{{Audio: []byte("rachel"), IsFinal: true}},
+		{{Audio: []byte("adam"), IsFinal: true}},
+	}}
+	m.ttsProvider = tts
+	conn := &fakeConn{}
+	state := &CallState{ID: "call-1", Call: &fakeCall{transport: conn}}
+
+	if err := m.speak(context.Background(), state, "Hello."); err != nil {
+		t.Fatalf("speak() error = %v", err)
+	}
+	state.voiceID = "adam"
+	if err := m.speak(context.Background(), state, "Hello."); err != nil {
+		t.Fatalf("speak() error = %v", err)
+	}
+
+	if got := conn.audio.String(); got != "racheladam" {
+		t.Errorf("audio = %q, want each voice synthesized", got)
+	}
+}
+
+func TestSynthesizeCached_FailedStreamNotCached(t *testing.T) {
+	m := newTestManager(t)
+	m.ttsCache = newTTSCache(1 << 10)
+	m.ttsProvider = &fakeTTS{streams: [][]omnivoice.StreamChunk{
+		{{Audio: []byte("half")}, {Error: errors.New("connection reset")}},
+	}}
+	state := &CallState{ID: "call-1"}
+
+	var buf bytes.Buffer
+	if _, err := m.synthesizeCached(context.Background(), m.ttsProvider, state, &buf, "Goodbye.", omnivoice.SynthesisConfig{}); !errors.Is(err, errTTSStream) {
+		t.Fatalf("synthesizeCached() error = %v, want errTTSStream", err)
+	}
+	if got := m.TTSCacheStats().Entries; got != 0 {
+		t.Errorf("Entries = %d, want failed audio not cached", got)
+	}
+}
+
+func TestPrefetch_SkippedWhenCached(t *testing.T) {
+	m := newTestManager(t)
+	m.config.TTSPrefetch = true
+	m.ttsCache = newTTSCache(1 << 10)
+	tts := &fakeTTS{}
+	m.ttsProvider = tts
+	state := &CallState{ID: "call-1"}
+
+	key := newTTSCacheKey("See you soon.", m.synthesisConfig(state, ""))
+	m.ttsCache.put(key, cachedAudio("cached"))
+
+	m.startPrefetch(state, "See you soon.")
+	if state.prefetch != nil || len(tts.texts) != 0 {
+		t.Error("prefetch started for cached audio")
+	}
+}
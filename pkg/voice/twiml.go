@@ -0,0 +1,212 @@
+package voice
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// Webhook paths served for Twilio. Calls are dialed with VoicePath as their
+// answer URL, so outbound calls get the same TwiML as incoming ones;
+// conference participants add a conference query parameter. StatusPath
+// receives status callbacks.
+const (
+	VoicePath       = "/voice"
+	MediaStreamPath = "/media-stream"
+	StatusPath      = "/status"
+)
+
+// answerURL is the URL Twilio fetches TwiML from when a dialed call is
+// answered.
+func (m *Manager) answerURL() string {
+	return m.currentPublicURL() + VoicePath
+}
+
+// conferenceAnswerURL is the answer URL for a participant dialed into the
+// named conference.
+func (m *Manager) conferenceAnswerURL(name string) string {
+	return m.answerURL() + "?conference=" + url.QueryEscape(name)
+}
+
+// VoiceHandler serves the Twilio voice webhook at VoicePath for both
+// incoming calls and calls dialed by the manager. publicURL returns the
+// current public base URL. With requireAccept, outbound calls ask the
+// callee to press AcceptDigit before media connects.
+func (m *Manager) VoiceHandler(publicURL func() string, requireAccept bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.Body = http.MaxBytesReader(w, r.Body, 1<<20)
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, "Bad request", http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/xml")
+
+		direction := r.Form.Get("Direction")
+
+		// An inbound call from the user may be a callback after a missed
+		// call; the agent reads it with get_callbacks
+		if direction == "inbound" {
+			if missed, ok := m.NotifyInboundCall(r.Form.Get("From")); ok {
+				slog.Info("user called back after a missed call",
+					"missed_call_id", missed.CallID,
+					"missed_at", missed.At,
+				)
+			}
+		}
+
+		callSID := r.Form.Get("CallSid")
+
+		// The assistant's conference leg rings our own number; that end
+		// joins the conference directly
+		conference, leg := m.ConferenceLeg(callSID, r.Form.Get("From"), direction, r.URL.Query().Get("conference"))
+		if leg == ConferenceBridge {
+			writeConferenceTwiML(w, conference, true, "")
+			return
+		}
+
+		if requireAccept && strings.HasPrefix(direction, "outbound") && leg != ConferenceAssistant {
+			// First request: ask for the accept digit. Gather posts back
+			// here with gather=1, even if nothing was pressed; participants
+			// keep their conference.
+			if r.Form.Get("gather") == "" {
+				action := publicURL() + VoicePath + "?gather=1"
+				if leg == ConferenceParticipant {
+					action += "&conference=" + url.QueryEscape(conference)
+				}
+				_, _ = fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?>
+<Response>%s
+    <Gather numDigits="1" timeout="10" action="%s" actionOnEmptyResult="true">
+        <Say>You have a call from your assistant. Press %s to accept.</Say>
+    </Gather>
+</Response>`, m.recordingConsentTwiML(), html.EscapeString(action), AcceptDigit)
+				return
+			}
+
+			accepted := r.Form.Get("Digits") == AcceptDigit
+			slog.Info("call accept prompt", "call_sid", logSafe(callSID), "accepted", accepted)
+			m.NotifyAccepted(callSID, accepted)
+			if !accepted {
+				_, _ = fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+<Response>
+    <Hangup/>
+</Response>`)
+				return
+			}
+		}
+
+		// Participants who answered (and accepted) join the conference;
+		// without the accept prompt they hear the recording announcement
+		// on the way in
+		if leg == ConferenceParticipant {
+			announce := ""
+			if !requireAccept {
+				announce = m.recordingConsentTwiML()
+			}
+			if announce != "" {
+				slog.Info("recording consent played", "call_sid", logSafe(callSID), "conference", logSafe(conference))
+			}
+			writeConferenceTwiML(w, conference, false, announce)
+			return
+		}
+
+		// Connect to Media Streams
+		streamURL := publicURL() + MediaStreamPath
+		if m.twimlTemplate != "" {
+			_, _ = fmt.Fprint(w, renderTwiML(m.twimlTemplate, streamURL))
+			return
+		}
+		_, _ = fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?>
+<Response>
+    <Connect>
+        <Stream url="%s">
+            <Parameter name="direction" value="both"/>
+        </Stream>
+    </Connect>
+</Response>`, streamURL)
+	})
+}
+
+// streamURLVar is replaced with the media stream URL in a TwiML template.
+const streamURLVar = "{stream_url}"
+
+// loadTwiMLTemplate reads the TwiML template at path, which replaces the
+// default <Connect><Stream> response, and checks that it is a well-formed
+// <Response> document once the media stream URL is filled in.
+func loadTwiMLTemplate(path string) (string, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read TwiML template: %w", err)
+	}
+	tmpl := string(raw)
+	if err := checkTwiML(renderTwiML(tmpl, "wss://example.com"+MediaStreamPath)); err != nil {
+		return "", fmt.Errorf("invalid TwiML template %s: %w", path, err)
+	}
+	if !strings.Contains(tmpl, streamURLVar) {
+		slog.Warn("TwiML template does not use "+streamURLVar+"; calls only reach the assistant if it streams to this server", "path", path)
+	}
+	return tmpl, nil
+}
+
+// renderTwiML fills in a TwiML template's media stream URL.
+func renderTwiML(tmpl, streamURL string) string {
+	return strings.ReplaceAll(tmpl, streamURLVar, html.EscapeString(streamURL))
+}
+
+// checkTwiML returns an error unless twiml is well-formed XML with a single
+// <Response> root element.
+func checkTwiML(twiml string) error {
+	d := xml.NewDecoder(strings.NewReader(twiml))
+	depth, roots := 0, 0
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if depth == 0 {
+				roots++
+				if t.Name.Local != "Response" {
+					return fmt.Errorf("root element is <%s>, want <Response>", t.Name.Local)
+				}
+			}
+			depth++
+		case xml.EndElement:
+			depth--
+		case xml.CharData:
+			if depth == 0 && len(bytes.TrimSpace(t)) > 0 {
+				return errors.New("text outside <Response>")
+			}
+		}
+	}
+	switch {
+	case roots == 0:
+		return errors.New("no <Response> element")
+	case roots > 1:
+		return errors.New("more than one root element")
+	}
+	return nil
+}
+
+// writeConferenceTwiML writes TwiML that joins the call to a conference
+// after playing the TwiML in before, if any. When endOnExit is set, the
+// conference ends for everyone when this leg hangs up.
+func writeConferenceTwiML(w http.ResponseWriter, name string, endOnExit bool, before string) {
+	_, _ = fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?>
+<Response>%s
+    <Dial>
+        <Conference beep="false" startConferenceOnEnter="true" endConferenceOnExit="%t">%s</Conference>
+    </Dial>
+</Response>`, before, endOnExit, name)
+}
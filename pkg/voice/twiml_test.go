@@ -0,0 +1,161 @@
+package voice
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/plexusone/agentcomms/pkg/config"
+)
+
+// postVoice posts a Twilio voice webhook form to rawURL and returns the TwiML.
+func postVoice(t *testing.T, rawURL string, form url.Values) string {
+	t.Helper()
+
+	resp, err := http.PostForm(rawURL, form)
+	if err != nil {
+		t.Fatalf("POST %s error = %v", rawURL, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("POST %s status = %d: %s", rawURL, resp.StatusCode, body)
+	}
+	return string(body)
+}
+
+func TestVoiceHandler_OutboundAcceptRouting(t *testing.T) {
+	m := newTestManager(t)
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	publicURL := func() string { return srv.URL }
+	mux.Handle(VoicePath, m.VoiceHandler(publicURL, true))
+	m.publicURL = srv.URL
+
+	state := &CallState{ID: "call-1", Call: &fakeCall{id: "CA123"}, acceptCh: make(chan bool, 1)}
+	m.calls[state.ID] = state
+
+	// Twilio fetches the answer URL the call was dialed with
+	form := url.Values{"CallSid": {"CA123"}, "Direction": {"outbound-api"}}
+	twiml := postVoice(t, m.answerURL(), form)
+	if !strings.Contains(twiml, "<Gather") || !strings.Contains(twiml, srv.URL+VoicePath+"?gather=1") {
+		t.Fatalf("answer TwiML = %s, want the accept prompt", twiml)
+	}
+
+	form.Set("Digits", AcceptDigit)
+	twiml = postVoice(t, srv.URL+VoicePath+"?gather=1", form)
+	if !strings.Contains(twiml, `<Stream url="`+srv.URL+MediaStreamPath+`"`) {
+		t.Errorf("gather TwiML = %s, want the media stream", twiml)
+	}
+	select {
+	case accepted := <-state.acceptCh:
+		if !accepted {
+			t.Error("accept reported as declined")
+		}
+	default:
+		t.Error("accept was not reported")
+	}
+}
+
+func TestVoiceHandler_Inbound(t *testing.T) {
+	m := newTestManager(t)
+	srv := httptest.NewServer(m.VoiceHandler(func() string { return "https://example.com" }, true))
+	defer srv.Close()
+
+	twiml := postVoice(t, srv.URL+VoicePath, url.Values{"CallSid": {"CA9"}, "Direction": {"inbound"}, "From": {"+15550001111"}})
+	if strings.Contains(twiml, "<Gather") || !strings.Contains(twiml, `<Stream url="https://example.com/media-stream"`) {
+		t.Errorf("inbound TwiML = %s, want the media stream without a prompt", twiml)
+	}
+}
+
+func TestVoiceHandler_ConferenceParticipant(t *testing.T) {
+	m := newTestManager(t)
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	mux.Handle(VoicePath, m.VoiceHandler(func() string { return srv.URL }, true))
+	m.publicURL = srv.URL
+
+	state := &CallState{ID: "call-1", Call: &fakeCall{id: "CA-assistant"}}
+	conf := &ConferenceState{Name: "agentcomms-call-1", Call: state}
+	m.conferences[state.ID] = conf
+
+	// The participant's SID is not recorded yet; its answer URL names the conference
+	form := url.Values{"CallSid": {"CA-alice"}, "Direction": {"outbound-api"}}
+	twiml := postVoice(t, m.conferenceAnswerURL(conf.Name), form)
+	wantAction := srv.URL + VoicePath + "?gather=1&amp;conference=" + conf.Name
+	if !strings.Contains(twiml, `action="`+wantAction+`"`) {
+		t.Fatalf("answer TwiML = %s, want the accept prompt keeping the conference", twiml)
+	}
+
+	form.Set("Digits", AcceptDigit)
+	twiml = postVoice(t, srv.URL+VoicePath+"?gather=1&conference="+conf.Name, form)
+	if !strings.Contains(twiml, ">"+conf.Name+"</Conference>") || !strings.Contains(twiml, `endConferenceOnExit="false"`) {
+		t.Errorf("gather TwiML = %s, want the participant to join the conference", twiml)
+	}
+}
+
+func TestVoiceHandler_TwiMLTemplate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "connect.xml")
+	tmpl := `<?xml version="1.0" encoding="UTF-8"?>
+<Response>
+    <Say>Connecting you to your assistant.</Say>
+    <Connect>
+        <Stream url="{stream_url}" track="inbound_track"/>
+    </Connect>
+</Response>`
+	if err := os.WriteFile(path, []byte(tmpl), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.TwiMLTemplate = path
+	m, err := New(cfg)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	srv := httptest.NewServer(m.VoiceHandler(func() string { return "https://example.com" }, false))
+	defer srv.Close()
+
+	twiml := postVoice(t, srv.URL+VoicePath, url.Values{"CallSid": {"CA9"}, "Direction": {"inbound"}})
+	if !strings.Contains(twiml, "<Say>Connecting you") || !strings.Contains(twiml, `<Stream url="https://example.com/media-stream" track="inbound_track"/>`) {
+		t.Errorf("TwiML = %s, want the template with the media stream URL", twiml)
+	}
+}
+
+func TestLoadTwiMLTemplate_Invalid(t *testing.T) {
+	tests := []struct {
+		name, tmpl, wantErr string
+	}{
+		{"unclosed", `<Response><Connect><Stream url="{stream_url}"/></Response>`, "invalid TwiML template"},
+		{"wrong root", `<Connect><Stream url="{stream_url}"/></Connect>`, "want <Response>"},
+		{"empty", ``, "no <Response> element"},
+		{"two roots", `<Response/><Response/>`, "more than one root element"},
+		{"trailing text", `<Response/>hello`, "text outside <Response>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "connect.xml")
+			if err := os.WriteFile(path, []byte(tt.tmpl), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := loadTwiMLTemplate(path); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("loadTwiMLTemplate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	if _, err := loadTwiMLTemplate(filepath.Join(t.TempDir(), "missing.xml")); err == nil {
+		t.Error("loadTwiMLTemplate() of a missing file succeeded")
+	}
+}
@@ -0,0 +1,108 @@
+package voice
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+	"unicode/utf8"
+)
+
+// usageLimitGoodbyes are spoken to end a call that used up its TTS or STT
+// allowance, by base language code.
+var usageLimitGoodbyes = map[string]string{
+	"en": "I'm sorry, we've reached the limit for this call, so I have to go now. Goodbye.",
+	"es": "Lo siento, hemos llegado al límite de esta llamada, así que tengo que colgar. Adiós.",
+	"pt": "Desculpe, chegamos ao limite desta chamada, então preciso desligar agora. Tchau.",
+	"fr": "Désolé, nous avons atteint la limite de cet appel, je dois donc raccrocher. Au revoir.",
+	"de": "Es tut mir leid, wir haben das Limit für diesen Anruf erreicht, ich muss jetzt auflegen. Auf Wiedersehen.",
+	"it": "Mi dispiace, abbiamo raggiunto il limite di questa chiamata, quindi devo riattaccare. Arrivederci.",
+}
+
+// addTTSChars records text sent to the TTS provider for the call.
+func (cs *CallState) addTTSChars(text string) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	cs.metrics.ttsChars += utf8.RuneCountInString(text)
+}
+
+// usageExceeded returns which of Config.MaxTTSCharsPerCall and
+// Config.MaxSTTSecondsPerCall the call has reached, or "" for neither.
+func (m *Manager) usageExceeded(state *CallState) string {
+	metrics := state.Metrics()
+	if limit := m.config.MaxTTSCharsPerCall; limit > 0 && metrics.TTSChars >= limit {
+		return fmt.Sprintf("%d of %d TTS characters used", metrics.TTSChars, limit)
+	}
+	if limit := time.Duration(m.config.MaxSTTSecondsPerCall) * time.Second; limit > 0 && metrics.STTTime >= limit {
+		return fmt.Sprintf("%v of %v transcription used", metrics.STTTime.Round(time.Second), limit)
+	}
+	return ""
+}
+
+// chargeTTS records text about to be sent to the TTS provider for the
+// call, or returns ErrUsageLimit if it would go over
+// Config.MaxTTSCharsPerCall.
+func (m *Manager) chargeTTS(state *CallState, text string) error {
+	if reason := m.ttsAllowanceExceeded(state, text); reason != "" {
+		return fmt.Errorf("%w: %s", ErrUsageLimit, reason)
+	}
+	state.addTTSChars(text)
+	return nil
+}
+
+// ttsAllowanceExceeded returns why speaking text would take the call over
+// Config.MaxTTSCharsPerCall, or "" if it fits.
+func (m *Manager) ttsAllowanceExceeded(state *CallState, text string) string {
+	limit := m.config.MaxTTSCharsPerCall
+	if limit <= 0 {
+		return ""
+	}
+	used, n := state.Metrics().TTSChars, utf8.RuneCountInString(text)
+	if used+n <= limit {
+		return ""
+	}
+	return fmt.Sprintf("%d more TTS characters would exceed %d of %d used", n, used, limit)
+}
+
+// sttAllowance returns how much longer the call may listen before it uses
+// up Config.MaxSTTSecondsPerCall, and false if there is no cap.
+func (m *Manager) sttAllowance(state *CallState) (time.Duration, bool) {
+	limit := time.Duration(m.config.MaxSTTSecondsPerCall) * time.Second
+	if limit <= 0 {
+		return 0, false
+	}
+	return max(limit-state.Metrics().STTTime, 0), true
+}
+
+// usageLimitGoodbye returns the goodbye for a call ended at its usage
+// limit, in the call's language. It is "" if there is none in that
+// language or it would go over the TTS allowance, so the call is hung up
+// without one.
+func (m *Manager) usageLimitGoodbye(state *CallState) string {
+	goodbye := usageLimitGoodbyes[baseLanguage(m.sttLanguage(state))]
+	if goodbye == "" || m.ttsAllowanceExceeded(state, goodbye) != "" {
+		return ""
+	}
+	return goodbye
+}
+
+// checkUsage ends a call that has used up its TTS or STT allowance with a
+// short goodbye, so the turn that crossed a cap finishes and the next one
+// wraps the call up. It then returns ErrUsageLimit.
+func (m *Manager) checkUsage(ctx context.Context, state *CallState) error {
+	reason := m.usageExceeded(state)
+	if reason == "" {
+		return nil
+	}
+	m.endAtUsageLimit(ctx, state, reason)
+	return fmt.Errorf("%w: %s", ErrUsageLimit, reason)
+}
+
+// endAtUsageLimit ends a call that reached its usage limit for reason,
+// with the goodbye if it can afford one.
+func (m *Manager) endAtUsageLimit(ctx context.Context, state *CallState, reason string) {
+	slog.Info("call reached its usage limit; ending it", "call_id", state.ID, "reason", reason)
+	if _, err := m.EndCall(ctx, state.ID, m.usageLimitGoodbye(state)); err != nil {
+		slog.Warn("failed to end call at its usage limit", "call_id", state.ID, "error", err)
+	}
+}
@@ -0,0 +1,160 @@
+package voice
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/plexusone/omnivoice"
+)
+
+func TestSpeak_CountsTTSChars(t *testing.T) {
+	m := newTestManager(t)
+	m.ttsProvider = &fakeTTS{streams: [][]omnivoice.StreamChunk{
+		{{Audio: []byte("aaaa"), IsFinal: true}},
+	}}
+	state := &CallState{ID: "call-1", Call: &fakeCall{transport: &fakeConn{}}}
+
+	if err := m.speak(context.Background(), state, "Héllo there."); err != nil {
+		t.Fatalf("speak() error = %v", err)
+	}
+	if got := state.Metrics().TTSChars; got != 12 {
+		t.Errorf("TTSChars = %d, want 12", got)
+	}
+}
+
+func TestSetListening_CountsSTTTime(t *testing.T) {
+	state := &CallState{ID: "call-1"}
+	state.setListening(true)
+	state.metrics.listenedAt = time.Now().Add(-3 * time.Second)
+
+	if got := state.Metrics().STTTime; got < 3*time.Second {
+		t.Errorf("STTTime while listening = %v, want at least 3s", got)
+	}
+	state.setListening(false)
+	got := state.Metrics().STTTime
+	state.setListening(false)
+	if again := state.Metrics().STTTime; again != got || got < 3*time.Second {
+		t.Errorf("STTTime = %v then %v, want at least 3s counted once", got, again)
+	}
+}
+
+func TestContinueCall_EndsCallAtUsageLimit(t *testing.T) {
+	m := newTestManager(t)
+	m.config.MaxTTSCharsPerCall = 10
+	tts := &fakeTTS{}
+	m.ttsProvider = tts
+	call := &fakeCall{id: "CA-1", status: omnivoice.StatusAnswered, transport: &fakeConn{}}
+	state := m.addCall(context.Background(), hangupCall{call}, "+15551234567", time.Now())
+	state.addTTSChars("Twelve chars")
+
+	_, err := m.ContinueCall(context.Background(), state.ID, "Anything else?")
+	if !errors.Is(err, ErrUsageLimit) {
+		t.Fatalf("ContinueCall() error = %v, want ErrUsageLimit", err)
+	}
+	if len(tts.texts) != 0 {
+		t.Errorf("synthesized %q over the TTS cap, want no goodbye", tts.texts)
+	}
+	if m.GetCall(state.ID) != nil {
+		t.Error("call still active after reaching its usage limit")
+	}
+}
+
+func TestContinueCall_GoodbyeInCallLanguage(t *testing.T) {
+	m := newTestManager(t)
+	m.config.MaxSTTSecondsPerCall = 30
+	tts := &fakeTTS{streams: [][]omnivoice.StreamChunk{
+		{{Audio: []byte("bye"), IsFinal: true}},
+	}}
+	m.ttsProvider = tts
+	call := &fakeCall{id: "CA-1", status: omnivoice.StatusAnswered, transport: &fakeConn{}}
+	state := m.addCall(context.Background(), hangupCall{call}, "+15551234567", time.Now())
+	state.language = "es-MX"
+	state.metrics.sttTime = 30 * time.Second
+
+	_, err := m.ContinueCall(context.Background(), state.ID, "¿Algo más?")
+	if !errors.Is(err, ErrUsageLimit) {
+		t.Fatalf("ContinueCall() error = %v, want ErrUsageLimit", err)
+	}
+	if len(tts.texts) != 1 || tts.texts[0] != usageLimitGoodbyes["es"] {
+		t.Errorf("synthesized %q, want only the Spanish goodbye", tts.texts)
+	}
+}
+
+func TestSpeak_RefusesMessageOverTTSCap(t *testing.T) {
+	m := newTestManager(t)
+	m.config.MaxTTSCharsPerCall = 20
+	tts := &fakeTTS{}
+	m.ttsProvider = tts
+	call := &fakeCall{id: "CA-1", status: omnivoice.StatusAnswered, transport: &fakeConn{}}
+	state := m.addCall(context.Background(), hangupCall{call}, "+15551234567", time.Now())
+
+	err := m.SpeakToUser(context.Background(), state.ID, "This message is longer than the cap.")
+	if !errors.Is(err, ErrUsageLimit) {
+		t.Fatalf("SpeakToUser() error = %v, want ErrUsageLimit", err)
+	}
+	if len(tts.texts) != 0 {
+		t.Errorf("synthesized %q, want nothing over the cap", tts.texts)
+	}
+	if len(state.Conversation) != 0 {
+		t.Errorf("conversation = %+v, want the refused message left out", state.Conversation)
+	}
+	if m.GetCall(state.ID) != nil {
+		t.Error("call still active after a message over its usage limit")
+	}
+}
+
+func TestUsageLimitGoodbye(t *testing.T) {
+	m := newTestManager(t)
+	state := &CallState{ID: "call-1"}
+
+	if got := m.usageLimitGoodbye(state); got != usageLimitGoodbyes["en"] {
+		t.Errorf("usageLimitGoodbye() = %q, want the English goodbye", got)
+	}
+	state.language = "ja-JP"
+	if got := m.usageLimitGoodbye(state); got != "" {
+		t.Errorf("usageLimitGoodbye() in Japanese = %q, want none", got)
+	}
+	state.language = "fr"
+	m.config.MaxTTSCharsPerCall = 50
+	if got := m.usageLimitGoodbye(state); got != "" {
+		t.Errorf("usageLimitGoodbye() over the TTS cap = %q, want none", got)
+	}
+}
+
+func TestAwaitTranscript_BoundedBySTTAllowance(t *testing.T) {
+	m := newTestManager(t)
+	m.config.MaxSTTSecondsPerCall = 1
+	state := &CallState{ID: "call-1"}
+	state.metrics.sttTime = 950 * time.Millisecond
+
+	start := time.Now()
+	_, err := m.awaitTranscript(context.Background(), state, make(chan omnivoice.StreamEvent), nil)
+	if !errors.Is(err, ErrNoSpeech) {
+		t.Fatalf("awaitTranscript() error = %v, want ErrNoSpeech", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("listened for %v with 50ms of STT left", elapsed)
+	}
+}
+
+func TestUsageExceeded(t *testing.T) {
+	m := newTestManager(t)
+	state := &CallState{ID: "call-1"}
+	state.addTTSChars("Hello.")
+	state.metrics.sttTime = 30 * time.Second
+
+	if reason := m.usageExceeded(state); reason != "" {
+		t.Errorf("usageExceeded() = %q with no caps", reason)
+	}
+	m.config.MaxTTSCharsPerCall = 100
+	m.config.MaxSTTSecondsPerCall = 60
+	if reason := m.usageExceeded(state); reason != "" {
+		t.Errorf("usageExceeded() = %q under both caps", reason)
+	}
+	m.config.MaxSTTSecondsPerCall = 30
+	if reason := m.usageExceeded(state); reason == "" {
+		t.Error("usageExceeded() = \"\" at the STT cap")
+	}
+}
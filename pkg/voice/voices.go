@@ -0,0 +1,129 @@
+package voice
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/plexusone/omnivoice"
+)
+
+// voice returns the call's TTS voice override, or "" to use the configured
+// voice.
+func (cs *CallState) voice() string {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+	return cs.voiceID
+}
+
+// withVoice speaks a single message in voiceID instead of the call's
+// voice. An empty ID keeps the call's voice.
+func withVoice(voiceID string) SpeakOption {
+	return func(o *speakOptions) {
+		o.voiceID = voiceID
+	}
+}
+
+// SetCallVoice switches the TTS voice for the rest of a call, e.g. to a
+// clearer voice if the user has trouble understanding. The voice ID is
+// checked with the TTS provider first.
+func (m *Manager) SetCallVoice(ctx context.Context, callID, voiceID string) (*omnivoice.Voice, error) {
+	state := m.tenantCall(ctx, callID)
+	if state == nil {
+		return nil, fmt.Errorf("%w: %s", ErrCallNotFound, callID)
+	}
+	ttsProvider := m.currentTTSProvider()
+	if ttsProvider == nil || m.InitError() != nil {
+		return nil, m.notInitialized()
+	}
+	if voiceID == "" {
+		return nil, fmt.Errorf("%w: voice ID is required", ErrInvalidVoice)
+	}
+
+	v, err := ttsProvider.GetVoice(ctx, voiceID)
+	if err != nil {
+		if voiceNotFound(err) {
+			return nil, fmt.Errorf("%w: %s: %w", ErrInvalidVoice, voiceID, err)
+		}
+		return nil, fmt.Errorf("failed to look up voice %s: %w", voiceID, err)
+	}
+	if v == nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidVoice, voiceID)
+	}
+
+	state.mu.Lock()
+	state.voiceID = v.ID
+	state.mu.Unlock()
+
+	return v, nil
+}
+
+// voiceNotFound reports whether a GetVoice error says the voice does not
+// exist. Providers don't share an error type for this, so the message is
+// checked; anything else (network, auth, rate limits) is passed through so
+// it isn't reported as a bad voice ID.
+func voiceNotFound(err error) bool {
+	msg := strings.ToLower(err.Error())
+	for _, s := range []string{"not found", "404", "does not exist", "unknown voice", "invalid voice"} {
+		if strings.Contains(msg, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// voiceList caches the voices the TTS provider offers once they are
+// listed. Initialize clears it, since the provider may have changed.
+type voiceList struct {
+	mu     sync.Mutex
+	voices []omnivoice.Voice // nil until listed
+}
+
+func (vl *voiceList) reset() {
+	vl.mu.Lock()
+	defer vl.mu.Unlock()
+	vl.voices = nil
+}
+
+// ListVoices returns the voices the TTS provider offers, sorted by name, so
+// a voice ID for set_voice or Config.TTSVoice can be picked. The provider
+// is asked once; later calls return the cached list until the next
+// Initialize.
+func (m *Manager) ListVoices(ctx context.Context) ([]omnivoice.Voice, error) {
+	ttsProvider := m.currentTTSProvider()
+	if ttsProvider == nil || m.InitError() != nil {
+		return nil, m.notInitialized()
+	}
+
+	m.voices.mu.Lock()
+	defer m.voices.mu.Unlock()
+	if m.voices.voices == nil {
+		voices, err := ttsProvider.ListVoices(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list voices: %w", err)
+		}
+		voices = append([]omnivoice.Voice{}, voices...)
+		slices.SortStableFunc(voices, func(a, b omnivoice.Voice) int {
+			return cmp.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+		})
+		m.voices.voices = voices
+	}
+	return slices.Clone(m.voices.voices), nil
+}
+
+// VoiceSpeaks reports whether v is for language, a BCP-47 tag. A base code
+// such as "es" also matches regional variants like "es-MX"; a regional tag
+// only matches itself. Voices without a language never match.
+func VoiceSpeaks(v omnivoice.Voice, language string) bool {
+	have := strings.ReplaceAll(v.Language, "_", "-")
+	if have == "" {
+		return false
+	}
+	if !strings.Contains(language, "-") {
+		return baseLanguage(have) == strings.ToLower(language)
+	}
+	return strings.EqualFold(have, language)
+}
@@ -0,0 +1,113 @@
+package voice
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/plexusone/omnivoice"
+)
+
+func TestSetCallVoice(t *testing.T) {
+	m := newTestManager(t)
+	m.config.TTSVoice = "rachel"
+	tts := &fakeTTS{voices: map[string]omnivoice.Voice{
+		"adam": {ID: "adam", Name: "Adam"},
+	}}
+	m.ttsProvider = tts
+	conn := &fakeConn{}
+	state := &CallState{ID: "call-1", Call: &fakeCall{transport: conn}}
+	m.calls[state.ID] = state
+
+	if _, err := m.SetCallVoice(context.Background(), "call-1", "nobody"); !errors.Is(err, ErrInvalidVoice) {
+		t.Errorf("SetCallVoice(unknown) error = %v, want ErrInvalidVoice", err)
+	}
+	if _, err := m.SetCallVoice(context.Background(), "call-2", "adam"); !errors.Is(err, ErrCallNotFound) {
+		t.Errorf("SetCallVoice(unknown call) error = %v, want ErrCallNotFound", err)
+	}
+
+	v, err := m.SetCallVoice(context.Background(), "call-1", "adam")
+	if err != nil {
+		t.Fatalf("SetCallVoice() error = %v", err)
+	}
+	if v.Name != "Adam" {
+		t.Errorf("voice name = %q, want %q", v.Name, "Adam")
+	}
+
+	// A failed lookup is not a bad voice ID
+	tts.voiceErr = errors.New("connection refused")
+	if _, err := m.SetCallVoice(context.Background(), "call-1", "adam"); err == nil || errors.Is(err, ErrInvalidVoice) {
+		t.Errorf("SetCallVoice(lookup failure) error = %v, want a non-ErrInvalidVoice error", err)
+	}
+	tts.voiceErr = nil
+
+	if err := m.speak(context.Background(), state, "Hello."); err != nil {
+		t.Fatalf("speak() error = %v", err)
+	}
+	if got := tts.configs[0].VoiceID; got != "adam" {
+		t.Errorf("spoke with voice %q, want %q", got, "adam")
+	}
+}
+
+func TestListVoices(t *testing.T) {
+	m := newTestManager(t)
+	if _, err := m.ListVoices(context.Background()); !errors.Is(err, ErrNotInitialized) {
+		t.Errorf("ListVoices() without a provider error = %v, want ErrNotInitialized", err)
+	}
+
+	tts := &fakeTTS{voiceErr: errors.New("connection refused")}
+	m.ttsProvider = tts
+	if _, err := m.ListVoices(context.Background()); err == nil {
+		t.Error("ListVoices() error = nil, want the provider's error")
+	}
+
+	// A failure is not cached
+	tts.voiceErr = nil
+	tts.voices = map[string]omnivoice.Voice{
+		"v2": {ID: "v2", Name: "rachel", Language: "en"},
+		"v1": {ID: "v1", Name: "Adam", Language: "en"},
+		"v3": {ID: "v3", Name: "Lucia", Language: "es"},
+	}
+	voices, err := m.ListVoices(context.Background())
+	if err != nil {
+		t.Fatalf("ListVoices() error = %v", err)
+	}
+	var names []string
+	for _, v := range voices {
+		names = append(names, v.Name)
+	}
+	if want := []string{"Adam", "Lucia", "rachel"}; !slices.Equal(names, want) {
+		t.Errorf("voices = %v, want %v", names, want)
+	}
+
+	voices[0].Name = "changed"
+	again, err := m.ListVoices(context.Background())
+	if err != nil || tts.lists != 2 {
+		t.Fatalf("second ListVoices() = %v after %d provider calls, want the cached list", err, tts.lists)
+	}
+	if again[0].Name != "Adam" {
+		t.Error("cached list changed through a returned slice")
+	}
+}
+
+func TestVoiceSpeaks(t *testing.T) {
+	tests := []struct {
+		voice    string
+		language string
+		want     bool
+	}{
+		{"es", "es", true},
+		{"es-MX", "ES", true},
+		{"pt_BR", "pt-BR", true},
+		{"pt-PT", "pt-BR", false},
+		{"pt", "pt-BR", false},
+		{"en-US", "es", false},
+		{"", "en", false},
+	}
+	for _, tt := range tests {
+		if got := VoiceSpeaks(omnivoice.Voice{Language: tt.voice}, tt.language); got != tt.want {
+			t.Errorf("VoiceSpeaks(%q, %q) = %v, want %v", tt.voice, tt.language, got, tt.want)
+		}
+	}
+}
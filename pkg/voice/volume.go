@@ -0,0 +1,102 @@
+package voice
+
+import "io"
+
+// SpeakOption configures how a single message is spoken.
+type SpeakOption func(*speakOptions)
+
+// speakOptions holds per-message speech settings.
+type speakOptions struct {
+	volume float64 // 0.0-1.0
+	rate   float64 // speaking rate; 0 uses Config.SpeakingRate
+
+	// voiceID overrides the call's voice (see withVoice).
+	voiceID string
+
+	// nextMessage is synthesized while listening for the reply (see
+	// WithNextMessageHint).
+	nextMessage string
+
+	// audio is played instead of synthesizing the message (see WithAudio).
+	audio *Audio
+}
+
+// WithVolume speaks the message at a reduced volume, for example when
+// reading out a one-time code. The volume is clamped to 0.0-1.0, where 1.0
+// is normal volume.
+func WithVolume(volume float64) SpeakOption {
+	return func(o *speakOptions) {
+		o.volume = min(max(volume, 0), 1)
+	}
+}
+
+// newSpeakOptions applies opts to the defaults.
+func newSpeakOptions(opts []SpeakOption) speakOptions {
+	o := speakOptions{volume: 1}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// gainWriter scales mu-law audio before passing it on. Not every TTS
+// provider supports volume natively, so gain is applied to the samples.
+type gainWriter struct {
+	w     io.Writer
+	table [256]byte
+	buf   []byte
+}
+
+// newGainWriter returns a writer that scales mu-law samples by gain.
+func newGainWriter(w io.Writer, gain float64) *gainWriter {
+	g := &gainWriter{w: w}
+	for i := range g.table {
+		g.table[i] = linearToULaw(int(float64(ulawToLinear(byte(i))) * gain))
+	}
+	return g
+}
+
+func (g *gainWriter) Write(p []byte) (int, error) {
+	g.buf = g.buf[:0]
+	for _, b := range p {
+		g.buf = append(g.buf, g.table[b])
+	}
+	return g.w.Write(g.buf)
+}
+
+// G.711 mu-law constants.
+const (
+	ulawBias = 0x84
+	ulawClip = 32635
+)
+
+// ulawToLinear decodes a G.711 mu-law sample to 16-bit linear PCM.
+func ulawToLinear(u byte) int {
+	u = ^u
+	exponent := int(u>>4) & 0x07
+	mantissa := int(u) & 0x0F
+	sample := ((mantissa << 3) + ulawBias) << exponent
+	sample -= ulawBias
+	if u&0x80 != 0 {
+		return -sample
+	}
+	return sample
+}
+
+// linearToULaw encodes a 16-bit linear PCM sample as G.711 mu-law.
+func linearToULaw(sample int) byte {
+	var sign byte
+	if sample < 0 {
+		sample = -sample
+		sign = 0x80
+	}
+	sample = min(sample, ulawClip) + ulawBias
+
+	exponent := 7
+	for mask := 0x4000; sample&mask == 0 && exponent > 0; mask >>= 1 {
+		exponent--
+	}
+	mantissa := (sample >> (exponent + 3)) & 0x0F
+
+	return ^(sign | byte(exponent<<4) | byte(mantissa))
+}
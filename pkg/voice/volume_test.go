@@ -0,0 +1,66 @@
+package voice
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestULawRoundTrip(t *testing.T) {
+	for i := 0; i < 256; i++ {
+		sample := ulawToLinear(byte(i))
+		if got := ulawToLinear(linearToULaw(sample)); got != sample {
+			t.Errorf("round trip of 0x%02x: got %d, want %d", i, got, sample)
+		}
+	}
+}
+
+func TestGainWriter(t *testing.T) {
+	loud := linearToULaw(8000)
+
+	tests := []struct {
+		name string
+		gain float64
+		want int
+	}{
+		{"full volume", 1.0, 8000},
+		{"half volume", 0.5, 4000},
+		{"silent", 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			n, err := newGainWriter(&buf, tt.gain).Write([]byte{loud, loud})
+			if err != nil || n != 2 {
+				t.Fatalf("Write() = %d, %v", n, err)
+			}
+
+			// mu-law is lossy; allow for quantization at this amplitude
+			for _, b := range buf.Bytes() {
+				if got := ulawToLinear(b); got < tt.want-tt.want/16 || got > tt.want+tt.want/16 {
+					t.Errorf("sample = %d, want about %d", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestWithVolume_Clamps(t *testing.T) {
+	tests := []struct {
+		volume float64
+		want   float64
+	}{
+		{0.3, 0.3},
+		{-1, 0},
+		{2, 1},
+	}
+
+	for _, tt := range tests {
+		if got := newSpeakOptions([]SpeakOption{WithVolume(tt.volume)}).volume; got != tt.want {
+			t.Errorf("WithVolume(%g) = %g, want %g", tt.volume, got, tt.want)
+		}
+	}
+	if got := newSpeakOptions(nil).volume; got != 1 {
+		t.Errorf("default volume = %g, want 1", got)
+	}
+}
@@ -0,0 +1,130 @@
+package voice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// repromptPrefix is spoken before the question again after an unclear
+// answer.
+const repromptPrefix = "Sorry, I need a clear yes or no. "
+
+// yesNo is a reply classified against the yes and no phrases.
+type yesNo int
+
+const (
+	answerUnclear yesNo = iota
+	answerYes
+	answerNo
+)
+
+// ConfirmWithUser asks a yes/no question on an active call and reports
+// whether the user said yes, along with their last reply. An unclear or
+// silent answer is asked again up to ConfirmReprompts times; after that
+// ErrNoClearAnswer is returned.
+func (m *Manager) ConfirmWithUser(ctx context.Context, callID, question string, opts ...SpeakOption) (bool, string, error) {
+	if err := m.checkMessage(question); err != nil {
+		return false, "", err
+	}
+	state, err := m.readyCall(ctx, callID)
+	if err != nil {
+		return false, "", err
+	}
+
+	return m.confirm(question, func(message string) (string, error) {
+		response, err := m.speakAndListen(ctx, state, message, nil, opts...)
+		if err != nil {
+			return "", speechError(err)
+		}
+		return response, nil
+	})
+}
+
+// confirm asks question with ask until the reply is a clear yes or no or
+// the re-prompts run out.
+func (m *Manager) confirm(question string, ask func(message string) (string, error)) (bool, string, error) {
+	message := question
+	var response string
+	for range m.config.ConfirmReprompts + 1 {
+		var err error
+		response, err = ask(message)
+		if err != nil && !errors.Is(err, ErrNoSpeech) {
+			return false, response, err
+		}
+
+		switch classifyYesNo(response, m.config.YesPhrases, m.config.NoPhrases) {
+		case answerYes:
+			return true, response, nil
+		case answerNo:
+			return false, response, nil
+		}
+		message = repromptPrefix + question
+	}
+	return false, response, fmt.Errorf("%w after %d re-prompts", ErrNoClearAnswer, m.config.ConfirmReprompts)
+}
+
+// classifyYesNo reports whether transcript answers yes or no. A phrase
+// inside a longer phrase from the other set does not count, so "no
+// problem" is a yes, and a phrase right after "not" does not count
+// either, so "not sure" is unclear. A reply that says both is unclear.
+func classifyYesNo(transcript string, yes, no []string) yesNo {
+	words := normalizeWords(transcript)
+	yesSpans := phraseSpans(words, yes)
+	noSpans := phraseSpans(words, no)
+
+	saidYes := hasUncovered(yesSpans, noSpans)
+	saidNo := hasUncovered(noSpans, yesSpans)
+	switch {
+	case saidYes && !saidNo:
+		return answerYes
+	case saidNo && !saidYes:
+		return answerNo
+	default:
+		return answerUnclear
+	}
+}
+
+// span is a range of words [start, end) matched by a phrase.
+type span struct{ start, end int }
+
+// phraseSpans returns where phrases appear as whole words in words,
+// skipping matches right after "not".
+func phraseSpans(words, phrases []string) []span {
+	var spans []span
+	for _, phrase := range phrases {
+		pw := normalizeWords(phrase)
+		if len(pw) == 0 {
+			continue
+		}
+		for start := 0; start+len(pw) <= len(words); start++ {
+			if !slices.Equal(words[start:start+len(pw)], pw) {
+				continue
+			}
+			if start > 0 && words[start-1] == "not" {
+				continue
+			}
+			spans = append(spans, span{start, start + len(pw)})
+		}
+	}
+	return spans
+}
+
+// hasUncovered reports whether any of spans is not inside a longer span
+// from others.
+func hasUncovered(spans, others []span) bool {
+	for _, s := range spans {
+		covered := false
+		for _, o := range others {
+			if o.start <= s.start && s.end <= o.end && o.end-o.start > s.end-s.start {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			return true
+		}
+	}
+	return false
+}
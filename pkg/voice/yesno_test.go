@@ -0,0 +1,91 @@
+package voice
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/plexusone/agentcomms/pkg/config"
+)
+
+func TestClassifyYesNo(t *testing.T) {
+	yes, no := config.DefaultYesPhrases(), config.DefaultNoPhrases()
+
+	tests := []struct {
+		transcript string
+		want       yesNo
+	}{
+		{"Yes.", answerYes},
+		{"Yeah, go ahead and deploy it.", answerYes},
+		{"No problem, do it.", answerYes},
+		{"That’s right", answerYes},
+		{"No.", answerNo},
+		{"Nope, don't do it.", answerNo},
+		{"Hold on, not yet.", answerNo},
+		{"Yes... no, wait.", answerUnclear},
+		{"I'm not sure.", answerUnclear},
+		{"What was the question?", answerUnclear},
+		{"Nobody told me", answerUnclear},
+		{"", answerUnclear},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.transcript, func(t *testing.T) {
+			if got := classifyYesNo(tt.transcript, yes, no); got != tt.want {
+				t.Errorf("classifyYesNo(%q) = %d, want %d", tt.transcript, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfirm(t *testing.T) {
+	tests := []struct {
+		name         string
+		replies      []string // "" is silence
+		wantYes      bool
+		wantErr      error
+		wantAsked    int
+		wantReprompt bool
+	}{
+		{"yes", []string{"yes"}, true, nil, 1, false},
+		{"no after unclear", []string{"hmm", "no"}, false, nil, 2, true},
+		{"yes after silence", []string{"", "sure"}, true, nil, 2, true},
+		{"never clear", []string{"hmm", "maybe", "what?", "yes"}, false, ErrNoClearAnswer, 3, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestManager(t)
+			m.config.ConfirmReprompts = 2
+
+			var asked []string
+			ask := func(message string) (string, error) {
+				reply := tt.replies[len(asked)]
+				asked = append(asked, message)
+				if reply == "" {
+					return "", ErrNoSpeech
+				}
+				return reply, nil
+			}
+
+			got, _, err := m.confirm("Deploy to production?", ask)
+			if got != tt.wantYes || !errors.Is(err, tt.wantErr) {
+				t.Errorf("confirm() = %v, %v; want %v, %v", got, err, tt.wantYes, tt.wantErr)
+			}
+			if len(asked) != tt.wantAsked {
+				t.Fatalf("asked %d times, want %d", len(asked), tt.wantAsked)
+			}
+			if reprompted := asked[len(asked)-1] != "Deploy to production?"; reprompted != tt.wantReprompt {
+				t.Errorf("last message = %q, want re-prompt %v", asked[len(asked)-1], tt.wantReprompt)
+			}
+		})
+	}
+}
+
+func TestConfirm_SpeechError(t *testing.T) {
+	m := newTestManager(t)
+	ask := func(string) (string, error) { return "", ErrSpeechFailed }
+
+	if _, _, err := m.confirm("Deploy?", ask); !errors.Is(err, ErrSpeechFailed) {
+		t.Errorf("confirm() error = %v, want ErrSpeechFailed", err)
+	}
+}